@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/Pagga-Wallet/aqua402/internal/health"
+	"github.com/Pagga-Wallet/aqua402/internal/lifecycle"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/internal/services/credit"
+	"github.com/Pagga-Wallet/aqua402/internal/services/faucet"
+	"github.com/Pagga-Wallet/aqua402/internal/services/webhooks"
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/Pagga-Wallet/aqua402/pkg/config"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/Pagga-Wallet/aqua402/pkg/x402"
+)
+
+// Config is the API configuration, every part parsed before anything starts
+type Config struct {
+	*config.Config
+	Chains      *chains.Registry
+	EVM         evm.ClientConfig
+	Fees        evm.FeeConfig
+	Payments    x402.Config
+	Idempotency appmiddleware.IdempotencyConfig
+	Credit      credit.Config
+	Tracing     tracing.Config
+	Health      health.Config
+	Lifecycle   lifecycle.Config
+	Webhooks    webhooks.Config
+	// FaucetChain is FAUCET_CHAIN (an ID or name, the default chain when unset)
+	FaucetChain *chains.Chain
+	Faucet      faucet.Config
+	// OperatorToken authorizes liquidation approvals, they are disabled without one
+	OperatorToken string
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For is believed, see TRUSTED_PROXIES
+	TrustedProxies []*net.IPNet
+}
+
+// loadConfig resolves the settings from args and the environment and parses them, reporting every
+// invalid one. base is returned even when invalid so it can be printed.
+func loadConfig(args []string) (*Config, error) {
+	base, err := config.Load("api", args)
+	if err != nil {
+		if base == nil {
+			return nil, err
+		}
+		return &Config{Config: base}, err
+	}
+	cfg := &Config{Config: base, OperatorToken: base.Get("RISK_OPERATOR_TOKEN")}
+
+	var errs []error
+	check := func(part string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", part, err))
+		}
+	}
+	cfg.Chains, err = chains.LoadFromEnv()
+	check("chains", err)
+	cfg.EVM, err = evm.ClientConfigFromEnv()
+	check("EVM RPC", err)
+	cfg.Fees, err = evm.FeeConfigFromEnv()
+	check("EVM fees", err)
+	cfg.Payments, err = x402.ConfigFromEnv()
+	check("x402", err)
+	cfg.Idempotency, err = appmiddleware.IdempotencyConfigFromEnv()
+	check("idempotency", err)
+	cfg.Credit, err = credit.ConfigFromEnv()
+	check("credit ledger", err)
+	cfg.Tracing, err = tracing.ConfigFromEnv()
+	check("tracing", err)
+	cfg.Health, err = health.ConfigFromEnv()
+	check("health checks", err)
+	cfg.Lifecycle, err = lifecycle.ConfigFromEnv()
+	check("shutdown", err)
+	cfg.Webhooks, err = webhooks.ConfigFromEnv()
+	check("webhooks", err)
+	cfg.Faucet, err = faucet.ConfigFromEnv()
+	check("faucet", err)
+	cfg.TrustedProxies, err = appmiddleware.TrustedProxiesFromEnv()
+	check("trusted proxies", err)
+	if cfg.Chains != nil {
+		faucetChainRef := base.Get("FAUCET_CHAIN")
+		if faucetChainRef == "" {
+			faucetChainRef = base.Get("FAUCET_CHAIN_ID")
+		}
+		cfg.FaucetChain, err = cfg.Chains.Get(faucetChainRef)
+		check("faucet chain", err)
+	}
+	return cfg, errors.Join(errs...)
+}
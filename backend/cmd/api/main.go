@@ -2,13 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
-	"net/http"
 	"os"
-	"os/signal"
-	"time"
 
 	_ "github.com/Pagga-Wallet/aqua402/docs"
+	"github.com/Pagga-Wallet/aqua402/internal/lifecycle"
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
 	"go.uber.org/zap"
 )
 
@@ -37,34 +39,46 @@ func main() {
 	}
 	defer logger.Sync()
 
-	// Setup app
-	e := SetupApp()
-
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Invalid settings stop the API before anything is started
+	cfg, err := loadConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-
-	// Graceful shutdown
-	go func() {
-		if err := e.Start(":" + port); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Failed to start server", zap.Error(err))
+	if cfg != nil && cfg.PrintConfig {
+		cfg.Print(os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
-	}()
+		return
+	}
+	if err != nil {
+		logger.Fatal("Invalid configuration", zap.Error(err))
+	}
+	for _, warning := range cfg.Warnings {
+		logger.Warn(warning)
+	}
+
+	// Spans are exported as configured by OTEL_TRACES_EXPORTER, trace context is propagated regardless
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "aqua402-api")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
 
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
+	// The manager runs the faucet's transaction tracking, then the server. On SIGINT or SIGTERM the server
+	// stops taking requests and finishes those in flight, then the connections are closed and the spans
+	// flushed.
+	manager := lifecycle.New(logger, cfg.Lifecycle.ShutdownTimeout)
+	manager.OnStop("tracing", shutdownTracing)
 
-	logger.Info("Shutting down server...")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// Setup app
+	e := SetupApp(cfg, manager)
+	manager.Serve("server", func() error { return e.Start(fmt.Sprintf(":%d", cfg.Port)) }, e.Shutdown)
 
-	if err := e.Shutdown(ctx); err != nil {
-		logger.Fatal("Server forced to shutdown", zap.Error(err))
+	if err := manager.Run(context.Background()); err != nil {
+		logger.Error("Server stopped with errors", zap.Error(err))
+		logger.Sync()
+		os.Exit(1)
 	}
-
 	logger.Info("Server exited")
 }
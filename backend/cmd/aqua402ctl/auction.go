@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// auctionABI covers the Auction function aqua402ctl calls
+const auctionABI = `[
+	{"type":"function","name":"finalizeAuction","stateMutability":"nonpayable",
+	 "inputs":[{"name":"auctionId","type":"uint256"}],"outputs":[]}
+]`
+
+// auctionCmd groups the auction commands
+var auctionCmd = &cobra.Command{
+	Use:   "auction",
+	Short: "List, inspect and finalize auctions",
+}
+
+var auctionListFlags *listFlags
+
+var auctionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List auctions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		chain, err := selectedChain()
+		if err != nil {
+			return err
+		}
+		q, err := auctionListFlags.query(chain.ID)
+		if err != nil {
+			return err
+		}
+		repo, err := openRepository()
+		if err != nil {
+			return err
+		}
+		defer repo.Close()
+
+		page, err := repositories.NewAuctionRepository(repo).ListAuctions(ctx, q)
+		if err != nil {
+			return fmt.Errorf("failed to list auctions: %w", err)
+		}
+		if err := render(cmd, page, func() *cli.Table {
+			return auctionTable(page.Items)
+		}); err != nil {
+			return err
+		}
+		if flags.output == cli.FormatTable {
+			pageFooter(cmd, len(page.Items), page.Total, page.NextCursor)
+		}
+		return nil
+	},
+}
+
+// auctionDetail is an auction with its bids
+type auctionDetail struct {
+	Auction *repositories.AuctionModel
+	Bids    []*repositories.BidModel
+	// BidCount counts every bid, Bids holds the first MaxListLimit
+	BidCount uint64
+}
+
+var auctionGetCmd = &cobra.Command{
+	Use:   "get <id>",
+	Short: "Show an auction and its bids",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		id, err := parseAuctionID(args[0])
+		if err != nil {
+			return err
+		}
+		chain, err := selectedChain()
+		if err != nil {
+			return err
+		}
+		repo, err := openRepository()
+		if err != nil {
+			return err
+		}
+		defer repo.Close()
+		auctionRepo := repositories.NewAuctionRepository(repo)
+
+		auction, err := auctionRepo.GetAuction(ctx, chain.ID, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("auction %d not found on %s", id, chain.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get auction %d: %w", id, err)
+		}
+		bids, err := auctionRepo.ListBids(ctx, id, repositories.ListQuery{ChainID: chain.ID, Limit: repositories.MaxListLimit})
+		if err != nil {
+			return fmt.Errorf("failed to list the bids of auction %d: %w", id, err)
+		}
+
+		if flags.output == cli.FormatJSON {
+			return render(cmd, auctionDetail{Auction: auction, Bids: bids.Items, BidCount: bids.Total}, nil)
+		}
+		out := cmd.OutOrStdout()
+		if err := auctionFields(auction).Write(out); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nBids (%d)\n", bids.Total)
+		return bidTable(bids.Items).Write(out)
+	},
+}
+
+var finalizeNoWait bool
+
+var auctionFinalizeCmd = &cobra.Command{
+	Use:   "finalize <id>",
+	Short: "Finalize an auction on-chain",
+	Long: `Sends Auction.finalizeAuction for an auction whose bidding ended, from the CTL_SIGNER account, and
+waits for the transaction to be mined. Anyone may finalize an auction, this forces one the borrower
+left open. The contract refuses auctions still bidding, already finalized or without bids; the
+transaction is then not sent and the revert reason is reported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		id, err := parseAuctionID(args[0])
+		if err != nil {
+			return err
+		}
+		chain, err := selectedChain()
+		if err != nil {
+			return err
+		}
+		if chain.Contracts.Auction == "" {
+			return fmt.Errorf("no auction contract configured on %s", chain.Name)
+		}
+		evmClient, err := dial(ctx, chain)
+		if err != nil {
+			return err
+		}
+		defer evmClient.Close()
+
+		txm, err := ctlTxManager(ctx, evmClient, chain.ID)
+		if err != nil {
+			return err
+		}
+		parsed, err := abi.JSON(strings.NewReader(auctionABI))
+		if err != nil {
+			return fmt.Errorf("failed to parse Auction ABI: %w", err)
+		}
+		data, err := parsed.Pack("finalizeAuction", new(big.Int).SetUint64(id))
+		if err != nil {
+			return fmt.Errorf("failed to pack finalizeAuction: %w", err)
+		}
+
+		// Gas estimation runs the call, so a refused finalization fails here with its revert reason
+		record, err := txm.Send(ctx, evm.TxRequest{To: common.HexToAddress(chain.Contracts.Auction), Data: data})
+		if err != nil {
+			return fmt.Errorf("failed to finalize auction %d: %w", id, err)
+		}
+		logger.Info("Finalization sent", zap.String("tx_id", record.ID), zap.String("tx_hash", record.Hash.Hex()))
+		if !finalizeNoWait {
+			if record, err = waitMined(ctx, txm, record.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := render(cmd, record, func() *cli.Table { return txFields(record) }); err != nil {
+			return err
+		}
+		if record.Status == evm.TxReverted {
+			return fmt.Errorf("finalization of auction %d reverted", id)
+		}
+		return nil
+	},
+}
+
+func init() {
+	auctionListFlags = bindListFlags(auctionListCmd,
+		repositories.FilterStatus, repositories.FilterBorrower, repositories.FilterLender,
+		repositories.FilterAmountMin, repositories.FilterAmountMax,
+		repositories.FilterDurationMin, repositories.FilterDurationMax,
+		repositories.FilterCreatedFrom, repositories.FilterCreatedTo)
+	auctionFinalizeCmd.Flags().BoolVar(&finalizeNoWait, "no-wait", false, "return once the transaction is sent")
+
+	auctionCmd.AddCommand(auctionListCmd, auctionGetCmd, auctionFinalizeCmd)
+	rootCmd.AddCommand(auctionCmd)
+}
+
+func parseAuctionID(s string) (uint64, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid auction ID %q", s)
+	}
+	return id, nil
+}
+
+// ctlTxManager creates a transaction manager sending from CTL_SIGNER. Its transactions are stored in
+// ClickHouse when it is reachable, so GET /transactions/:id shows them too.
+func ctlTxManager(ctx context.Context, evmClient *evm.Client, chainID uint64) (*evm.TxManager, error) {
+	signerConfig := cfg.Signer
+	if signerConfig.Kind == "" {
+		if signerConfig.Production {
+			return nil, errors.New("no signer configured, set CTL_SIGNER")
+		}
+		// Hardhat account #0 is funded on the local node
+		signerConfig.Kind, signerConfig.PrivateKey = evm.SignerKey, evm.DevPrivateKey
+		logger.Warn("No CTL signer configured, using default Hardhat account #0")
+	}
+	signer, err := evm.NewSigner(ctx, signerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CTL signer: %w", err)
+	}
+
+	var store evm.TxStore = evm.NewMemoryTxStore()
+	repo, err := repositories.NewRepository(cfg.ClickHouseDSN)
+	if err == nil {
+		err = repo.Ping(ctx)
+	}
+	if err != nil {
+		logger.Warn("ClickHouse unavailable, the transaction is only tracked by this command", zap.Error(err))
+	} else {
+		store = repositories.NewTransactionRepository(repo)
+	}
+
+	txConfig := cfg.Tx
+	txConfig.ChainID = new(big.Int).SetUint64(chainID)
+	return evm.NewTxManager(evmClient, signer, store, txConfig, logger), nil
+}
+
+// waitMined polls a transaction until it is final, resending it with more gas while it is stuck
+func waitMined(ctx context.Context, txm *evm.TxManager, id string) (*evm.TxRecord, error) {
+	interval := cfg.Tx.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := txm.Poll(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("Failed to poll transaction", zap.Error(err))
+		}
+		record, err := txm.Status(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transaction %s: %w", id, err)
+		}
+		if record.Status.Final() {
+			return record, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s (%s) not mined yet: %w", id, record.Hash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func auctionTable(auctions []*repositories.AuctionModel) *cli.Table {
+	t := cli.NewTable("ID", "STATUS", "BORROWER", "AMOUNT", "DURATION", "ENDS", "CREATED")
+	for _, auction := range auctions {
+		t.Add(auction.ID, auction.Status, auction.BorrowerAddress, auction.Amount, cli.Seconds(auction.Duration),
+			cli.Time(auction.EndTime), cli.Time(auction.CreatedAt))
+	}
+	return t
+}
+
+func auctionFields(auction *repositories.AuctionModel) *cli.Table {
+	t := cli.NewTable("FIELD", "VALUE")
+	t.Add("Chain", auction.ChainID)
+	t.Add("ID", auction.ID)
+	t.Add("Status", auction.Status)
+	t.Add("Borrower", auction.BorrowerAddress)
+	t.Add("Amount", auction.Amount)
+	t.Add("Duration", cli.Seconds(auction.Duration))
+	t.Add("Ends", cli.Time(auction.EndTime))
+	t.Add("Created", cli.Time(auction.CreatedAt))
+	return t
+}
+
+func bidTable(bids []*repositories.BidModel) *cli.Table {
+	t := cli.NewTable("ID", "LENDER", "RATE_BPS", "LIMIT", "WINNING", "PLACED")
+	for _, bid := range bids {
+		t.Add(bid.ID, bid.LenderAddress, bid.RateBps, bid.Limit, bid.IsWinning == 1, cli.Time(bid.Timestamp))
+	}
+	return t
+}
+
+func txFields(record *evm.TxRecord) *cli.Table {
+	t := cli.NewTable("FIELD", "VALUE")
+	t.Add("ID", record.ID)
+	t.Add("Status", string(record.Status))
+	t.Add("Hash", record.Hash.Hex())
+	t.Add("From", record.From.Hex())
+	t.Add("To", record.To.Hex())
+	t.Add("Nonce", record.Nonce)
+	t.Add("Block", record.BlockNumber)
+	t.Add("Error", record.Error)
+	return t
+}
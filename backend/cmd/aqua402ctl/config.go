@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	eventmonitor "github.com/Pagga-Wallet/aqua402/internal/services/events"
+	"github.com/Pagga-Wallet/aqua402/internal/services/faucet"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/Pagga-Wallet/aqua402/pkg/config"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+)
+
+// Config is the aqua402ctl configuration, read from the same settings as the API and the worker
+type Config struct {
+	*config.Config
+	Chains *chains.Registry
+	EVM    evm.ClientConfig
+	Fees   evm.FeeConfig
+	Tx     evm.TxManagerConfig
+	Events eventmonitor.Config
+	// FaucetChain is FAUCET_CHAIN (an ID or name, the default chain when unset)
+	FaucetChain *chains.Chain
+	Faucet      faucet.Config
+	// Signer is CTL_SIGNER, it signs forced auction finalizations
+	Signer evm.SignerConfig
+}
+
+// loadConfig resolves the settings from the config flags and the environment and parses them,
+// reporting every invalid one
+func loadConfig(args []string) (*Config, error) {
+	base, err := config.Load("aqua402ctl", args)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{Config: base}
+
+	var errs []error
+	check := func(part string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", part, err))
+		}
+	}
+	cfg.Chains, err = chains.LoadFromEnv()
+	check("chains", err)
+	cfg.EVM, err = evm.ClientConfigFromEnv()
+	check("EVM RPC", err)
+	cfg.Fees, err = evm.FeeConfigFromEnv()
+	check("EVM fees", err)
+	cfg.Tx, err = evm.TxManagerConfigFromEnv()
+	check("transactions", err)
+	cfg.Events, err = eventmonitor.ConfigFromEnv()
+	check("event monitor", err)
+	cfg.Faucet, err = faucet.ConfigFromEnv()
+	check("faucet", err)
+	if cfg.Chains != nil {
+		faucetChainRef := base.Get("FAUCET_CHAIN")
+		if faucetChainRef == "" {
+			faucetChainRef = base.Get("FAUCET_CHAIN_ID")
+		}
+		cfg.FaucetChain, err = cfg.Chains.Get(faucetChainRef)
+		check("faucet chain", err)
+	}
+	cfg.Signer, err = evm.SignerConfigFromEnv("CTL")
+	check("ctl signer", err)
+	return cfg, errors.Join(errs...)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/Pagga-Wallet/aqua402/internal/services/faucet"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/spf13/cobra"
+)
+
+// faucetCmd groups the faucet commands
+var faucetCmd = &cobra.Command{
+	Use:   "faucet",
+	Short: "Check the faucet account",
+}
+
+var faucetStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the faucet's address, nonces and balances",
+	Long: `Shows the FAUCET_SIGNER account on FAUCET_CHAIN, or on --chain: its ETH and token balances and its
+mined and pending nonces. A pending nonce ahead of the mined one means transactions are waiting to be
+mined. The command fails when the ETH balance is below FAUCET_MIN_BALANCE, so it can serve as a check.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		chain := cfg.FaucetChain
+		if flags.chain != "" {
+			var err error
+			if chain, err = selectedChain(); err != nil {
+				return err
+			}
+		}
+		evmClient, err := dial(ctx, chain)
+		if err != nil {
+			return err
+		}
+		defer evmClient.Close()
+
+		// The service is only read from, its transactions are never sent
+		service, err := faucet.NewService(evmClient, chain.ID, evm.NewMemoryTxStore(), faucet.NewMemoryStore(), cfg.Faucet, logger)
+		if err != nil {
+			return err
+		}
+		status, err := service.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		if flags.output == cli.FormatJSON {
+			if err := render(cmd, status, nil); err != nil {
+				return err
+			}
+		} else {
+			out := cmd.OutOrStdout()
+			t := cli.NewTable("FIELD", "VALUE")
+			t.Add("Chain", chain.Name)
+			t.Add("Address", status.Address)
+			t.Add("Nonce", status.Nonce)
+			t.Add("Pending nonce", status.PendingNonce)
+			t.Add("Min balance", status.MinBalance+" ETH")
+			if err := t.Write(out); err != nil {
+				return err
+			}
+			fmt.Fprintln(out)
+			balances := cli.NewTable("ASSET", "ADDRESS", "BALANCE")
+			for _, b := range status.Balances {
+				balances.Add(b.Symbol, b.Address, b.Balance)
+			}
+			if err := balances.Write(out); err != nil {
+				return err
+			}
+		}
+		if status.Low {
+			return fmt.Errorf("faucet balance is below the minimum of %s ETH", status.MinBalance)
+		}
+		return nil
+	},
+}
+
+func init() {
+	faucetCmd.AddCommand(faucetStatusCmd)
+	rootCmd.AddCommand(faucetCmd)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/spf13/cobra"
+)
+
+// chainLag is how far the indexer of a chain is behind, as far as its checkpoint tells
+type chainLag struct {
+	ChainID uint64
+	Chain   string
+	// Head is the chain head and Confirmed the last block with enough confirmations to be indexed
+	Head      uint64
+	Confirmed uint64
+	// Checkpoint is the last block the monitor saved as processed, nil when it saved none
+	Checkpoint *uint64
+	// Lag is Confirmed minus Checkpoint, in blocks
+	Lag   uint64
+	Error string `json:",omitempty"`
+}
+
+var lagCmd = &cobra.Command{
+	Use:   "lag",
+	Short: "Print how many blocks the event monitor of each chain is behind",
+	Long: `Compares the confirmed head of each chain, or of --chain, with the checkpoint the event monitor
+saved in ClickHouse. Checkpoints are saved every EVENT_CHECKPOINT_INTERVAL, so a running monitor may
+be that far ahead of what is shown. The command fails when a chain cannot be read.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		selected := cfg.Chains.All()
+		if flags.chain != "" {
+			chain, err := selectedChain()
+			if err != nil {
+				return err
+			}
+			selected = []*chains.Chain{chain}
+		}
+		repo, err := openRepository()
+		if err != nil {
+			return err
+		}
+		defer repo.Close()
+		checkpoints := repositories.NewCheckpointRepository(repo)
+
+		lags := make([]chainLag, 0, len(selected))
+		var failed bool
+		for _, chain := range selected {
+			lag := chainLag{ChainID: chain.ID, Chain: chain.Name}
+			if err := func() error {
+				evmClient, err := dial(ctx, chain)
+				if err != nil {
+					return err
+				}
+				defer evmClient.Close()
+				if lag.Head, err = evmClient.BlockNumber(ctx); err != nil {
+					return fmt.Errorf("failed to read the head: %w", err)
+				}
+				if lag.Head > chain.Confirmations {
+					lag.Confirmed = lag.Head - chain.Confirmations
+				}
+
+				checkpoint, err := checkpoints.GetCheckpoint(ctx, chain.ID)
+				if errors.Is(err, sql.ErrNoRows) {
+					return errors.New("no checkpoint saved")
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read the checkpoint: %w", err)
+				}
+				lag.Checkpoint = &checkpoint
+				if lag.Confirmed > checkpoint {
+					lag.Lag = lag.Confirmed - checkpoint
+				}
+				return nil
+			}(); err != nil {
+				lag.Error = err.Error()
+				failed = true
+			}
+			lags = append(lags, lag)
+		}
+
+		if err := render(cmd, lags, func() *cli.Table {
+			t := cli.NewTable("CHAIN", "ID", "HEAD", "CONFIRMED", "CHECKPOINT", "LAG", "ERROR")
+			for _, lag := range lags {
+				var checkpoint interface{}
+				if lag.Checkpoint != nil {
+					checkpoint = *lag.Checkpoint
+				}
+				t.Add(lag.Chain, lag.ChainID, lag.Head, lag.Confirmed, checkpoint, lag.Lag, lag.Error)
+			}
+			return t
+		}); err != nil {
+			return err
+		}
+		if failed {
+			return errors.New("the lag of some chains could not be read")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lagCmd)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/spf13/cobra"
+)
+
+// listFlags are the filters, sort and paging of a list command. They take the query parameters of
+// the API's list endpoints and are checked the same way.
+type listFlags struct {
+	params map[string]*string
+	limit  int
+}
+
+// bindListFlags adds the list flags to cmd, filters are the repositories.Filter names it supports
+func bindListFlags(cmd *cobra.Command, filters ...string) *listFlags {
+	l := &listFlags{params: make(map[string]*string)}
+	usage := map[string]string{
+		repositories.FilterStatus:         "status to list",
+		repositories.FilterBorrower:       "borrower address",
+		repositories.FilterLender:         "lender address",
+		repositories.FilterAmountMin:      "minimum amount in wei",
+		repositories.FilterAmountMax:      "maximum amount in wei",
+		repositories.FilterDurationMin:    "minimum duration in seconds",
+		repositories.FilterDurationMax:    "maximum duration in seconds",
+		repositories.FilterCollateralType: "collateral type",
+		repositories.FilterCreatedFrom:    "created at or after, unix seconds",
+		repositories.FilterCreatedTo:      "created before, unix seconds",
+	}
+	for _, name := range append(filters, "sort", "cursor") {
+		l.params[name] = new(string)
+		help := usage[name]
+		switch name {
+		case "sort":
+			help = "sort field, prefixed with - for descending order (default -created_at)"
+		case "cursor":
+			help = "cursor printed by the previous page"
+		}
+		cmd.Flags().StringVar(l.params[name], flagName(name), "", help)
+	}
+	cmd.Flags().IntVar(&l.limit, "limit", repositories.DefaultListLimit,
+		fmt.Sprintf("page size, at most %d", repositories.MaxListLimit))
+	return l
+}
+
+// query returns the list query of the flags on a chain
+func (l *listFlags) query(chainID uint64) (repositories.ListQuery, error) {
+	params := url.Values{"limit": {strconv.Itoa(l.limit)}}
+	for name, value := range l.params {
+		if *value != "" {
+			params.Set(name, *value)
+		}
+	}
+	q, err := handlers.ParseListQuery(params)
+	if err != nil {
+		return q, err
+	}
+	q.ChainID = chainID
+	return q, nil
+}
+
+// flagName turns a query parameter such as amount_min into a flag name, amount-min
+func flagName(param string) string {
+	return strings.ReplaceAll(param, "_", "-")
+}
+
+// pageFooter reports the rows shown and how to get the next page
+func pageFooter(cmd *cobra.Command, shown int, total uint64, nextCursor string) {
+	footer := fmt.Sprintf("%d of %d", shown, total)
+	if nextCursor != "" {
+		footer += ", next page: --cursor " + nextCursor
+	}
+	fmt.Fprintln(cmd.ErrOrStderr(), footer)
+}
@@ -0,0 +1,20 @@
+// Command aqua402ctl is the operator CLI of aqua402, see docs/deployment.md
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// Interrupting a long command, such as a replay, cancels it cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
+		os.Exit(1)
+	}
+}
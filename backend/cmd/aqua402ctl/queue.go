@@ -0,0 +1,244 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/Pagga-Wallet/aqua402/internal/queues"
+	"github.com/spf13/cobra"
+)
+
+// eventQueues are the queues the event monitor and the API publish to and the worker consumes
+var eventQueues = []string{"rfq.events", "auction.events", "credit.events", "aqua.events"}
+
+// queueCmd groups the queue commands
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Inspect, tail, purge and requeue the event queues",
+	Long: `The worker consumes rfq.events, auction.events, credit.events and aqua.events. Messages its handlers
+fail on are parked in <queue>.failed, where they stay until requeued or purged.`,
+}
+
+var queueStatsCmd = &cobra.Command{
+	Use:   "stats [queue...]",
+	Short: "Show the ready messages and consumers of queues, the event queues by default",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := args
+		if len(names) == 0 {
+			for _, name := range eventQueues {
+				names = append(names, name, queues.FailedQueue(name))
+			}
+		}
+		queue, err := openQueue()
+		if err != nil {
+			return err
+		}
+		defer queue.Close()
+
+		stats := make([]queues.Stats, 0, len(names))
+		for _, name := range names {
+			s, err := queue.Stats(name)
+			// A queue is only declared once something published to or consumed from it
+			if errors.Is(err, queues.ErrNotFound) {
+				s, err = queues.Stats{Name: name}, nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			stats = append(stats, s)
+		}
+		return render(cmd, stats, func() *cli.Table {
+			t := cli.NewTable("QUEUE", "MESSAGES", "CONSUMERS")
+			for _, s := range stats {
+				t.Add(s.Name, s.Messages, s.Consumers)
+			}
+			return t
+		})
+	},
+}
+
+var tailFlags struct {
+	limit    int
+	follow   bool
+	interval time.Duration
+}
+
+// tailedMessage is a message as printed by queue tail. Body is kept as JSON when it is JSON.
+type tailedMessage struct {
+	Timestamp   time.Time
+	Redelivered bool
+	Headers     map[string]interface{}
+	Body        json.RawMessage
+}
+
+var queueTailCmd = &cobra.Command{
+	Use:   "tail <queue>",
+	Short: "Print the messages waiting in a queue without consuming them",
+	Long: `Prints the first messages ready in a queue, leaving them in it. They are held for a moment and
+returned flagged as redelivered, the worker then handles them as usual. Messages the worker holds
+already are not seen, so tail is most useful on a backlog or on a <queue>.failed queue. With --follow
+the queue is read again every --interval and messages not printed yet are printed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if tailFlags.limit <= 0 || tailFlags.interval <= 0 {
+			return errors.New("--limit and --interval must be positive")
+		}
+		queue, err := openQueue()
+		if err != nil {
+			return err
+		}
+		defer queue.Close()
+
+		seen := make(map[[sha256.Size]byte]bool)
+		ticker := time.NewTicker(tailFlags.interval)
+		defer ticker.Stop()
+		for {
+			messages, err := queue.Peek(args[0], tailFlags.limit)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+
+			var fresh []tailedMessage
+			for _, m := range messages {
+				// Messages carry no ID, the same publish time and body is the same message
+				key := sha256.Sum256(append([]byte(m.Timestamp.String()), m.Body...))
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				fresh = append(fresh, tailed(m))
+			}
+			if len(fresh) > 0 || !tailFlags.follow {
+				if err := render(cmd, fresh, func() *cli.Table { return messageTable(fresh) }); err != nil {
+					return err
+				}
+			}
+
+			if !tailFlags.follow {
+				return nil
+			}
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+var purgeYes bool
+
+var queuePurgeCmd = &cobra.Command{
+	Use:   "purge <queue>",
+	Short: "Delete the ready messages of a queue",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		queue, err := openQueue()
+		if err != nil {
+			return err
+		}
+		defer queue.Close()
+
+		if !purgeYes {
+			stats, err := queue.Stats(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+			return fmt.Errorf("purging deletes the %d messages of %s, pass --yes to confirm", stats.Messages, args[0])
+		}
+		purged, err := queue.Purge(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to purge %s: %w", args[0], err)
+		}
+		result := queueResult{Queue: args[0], Messages: purged}
+		return render(cmd, result, func() *cli.Table {
+			t := cli.NewTable("QUEUE", "PURGED")
+			t.Add(result.Queue, result.Messages)
+			return t
+		})
+	},
+}
+
+var requeueFlags struct {
+	from  string
+	limit int
+}
+
+var queueRequeueCmd = &cobra.Command{
+	Use:   "requeue <queue>",
+	Short: "Move the parked messages of a queue back to it",
+	Long: `Moves the messages of <queue>.failed, or of --from, to <queue> so the worker handles them again.
+Each message is removed from its source once the broker has the copy, an interrupted requeue loses
+nothing. Messages failing again are parked again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		from := requeueFlags.from
+		if from == "" {
+			from = queues.FailedQueue(args[0])
+		}
+		queue, err := openQueue()
+		if err != nil {
+			return err
+		}
+		defer queue.Close()
+
+		moved, err := queue.Requeue(ctx, from, args[0], requeueFlags.limit)
+		if err != nil {
+			return fmt.Errorf("requeued %d messages from %s to %s, then failed: %w", moved, from, args[0], err)
+		}
+		result := queueResult{Queue: args[0], From: from, Messages: moved}
+		return render(cmd, result, func() *cli.Table {
+			t := cli.NewTable("FROM", "TO", "REQUEUED")
+			t.Add(result.From, result.Queue, result.Messages)
+			return t
+		})
+	},
+}
+
+// queueResult is the outcome of a purge or requeue
+type queueResult struct {
+	Queue    string
+	From     string `json:",omitempty"`
+	Messages int
+}
+
+func init() {
+	queueTailCmd.Flags().IntVarP(&tailFlags.limit, "limit", "n", 20, "messages to read")
+	queueTailCmd.Flags().BoolVarP(&tailFlags.follow, "follow", "f", false, "keep reading the queue")
+	queueTailCmd.Flags().DurationVar(&tailFlags.interval, "interval", 2*time.Second, "how often --follow reads the queue")
+	queuePurgeCmd.Flags().BoolVar(&purgeYes, "yes", false, "confirm the purge")
+	queueRequeueCmd.Flags().StringVar(&requeueFlags.from, "from", "", "queue to move the messages from (default <queue>.failed)")
+	queueRequeueCmd.Flags().IntVar(&requeueFlags.limit, "limit", 0, "messages to move, 0 for every message ready")
+
+	queueCmd.AddCommand(queueStatsCmd, queueTailCmd, queuePurgeCmd, queueRequeueCmd)
+	rootCmd.AddCommand(queueCmd)
+}
+
+func tailed(m queues.Message) tailedMessage {
+	body := json.RawMessage(m.Body)
+	if !json.Valid(m.Body) {
+		// Not JSON, print it as a string
+		body, _ = json.Marshal(string(m.Body))
+	}
+	return tailedMessage{Timestamp: m.Timestamp, Redelivered: m.Redelivered, Headers: m.Headers, Body: body}
+}
+
+func messageTable(messages []tailedMessage) *cli.Table {
+	t := cli.NewTable("PUBLISHED", "TYPE", "REDELIVERED", "ERROR", "BODY")
+	for _, m := range messages {
+		var event struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal(m.Body, &event)
+		failure, _ := m.Headers[queues.HeaderError].(string)
+		t.Add(m.Timestamp, event.Type, m.Redelivered, failure, string(m.Body))
+	}
+	return t
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	eventmonitor "github.com/Pagga-Wallet/aqua402/internal/services/events"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var replayFlags struct {
+	from uint64
+	to   uint64
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay --from <block> [--to <block>]",
+	Short: "Process a block range through the event monitor again",
+	Long: `Reads the contract logs of a block range and publishes their events to the queues as the event
+monitor did when it first saw them, so the worker indexes them again. Use it to fill a gap after
+events were lost or purged. It runs beside the worker and leaves the monitor's checkpoint alone.
+The worker stores an event under the log that emitted it, so a replayed event replaces the rows it
+wrote; credit line events also record a new balance snapshot. --to defaults to the latest
+confirmed block.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// A long range may take a while, it runs until done or interrupted
+		ctx := cmd.Context()
+
+		chain, err := selectedChain()
+		if err != nil {
+			return err
+		}
+		if chain.Contracts.RFQ == "" || chain.Contracts.Auction == "" {
+			return fmt.Errorf("contract addresses of %s not set, there are no events to replay", chain.Name)
+		}
+		evmClient, err := dial(ctx, chain)
+		if err != nil {
+			return err
+		}
+		defer evmClient.Close()
+		queue, err := openQueue()
+		if err != nil {
+			return err
+		}
+		defer queue.Close()
+
+		var rfqRepo *repositories.RFQRepository
+		if repo, err := openRepository(); err == nil {
+			defer repo.Close()
+			rfqRepo = repositories.NewRFQRepository(repo)
+		}
+
+		// Without a checkpoint store the monitor starts at the head and saves nothing
+		monitor, err := eventmonitor.NewMonitor(evmClient, queue, rfqRepo, nil, chain, cfg.Events, logger)
+		if err != nil {
+			return err
+		}
+		to := replayFlags.to
+		if !cmd.Flags().Changed("to") {
+			to = monitor.Status().Processed
+		}
+
+		result, err := monitor.Replay(ctx, replayFlags.from, to, func(progress eventmonitor.ReplayResult) {
+			logger.Info("Replayed blocks",
+				zap.Uint64("to", progress.To),
+				zap.Int("processed", progress.Processed),
+				zap.Int("failed", progress.Failed))
+		})
+		if err != nil {
+			return fmt.Errorf("replay of blocks %d-%d failed after %d events: %w", replayFlags.from, to, result.Processed, err)
+		}
+		if err := render(cmd, result, func() *cli.Table {
+			t := cli.NewTable("CHAIN", "FROM", "TO", "PROCESSED", "FAILED")
+			t.Add(chain.Name, result.From, result.To, result.Processed, result.Failed)
+			return t
+		}); err != nil {
+			return err
+		}
+		if result.Failed > 0 {
+			return fmt.Errorf("%d events failed to replay, see the log above", result.Failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	replayCmd.Flags().Uint64Var(&replayFlags.from, "from", 0, "first block to replay")
+	replayCmd.Flags().Uint64Var(&replayFlags.to, "to", 0, "last block to replay (default the latest confirmed block)")
+	_ = replayCmd.MarkFlagRequired("from")
+	rootCmd.AddCommand(replayCmd)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/spf13/cobra"
+)
+
+// rfqCmd groups the RFQ commands
+var rfqCmd = &cobra.Command{
+	Use:   "rfq",
+	Short: "List and inspect the indexed RFQs",
+}
+
+var rfqListFlags *listFlags
+
+var rfqListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List RFQs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		chain, err := selectedChain()
+		if err != nil {
+			return err
+		}
+		q, err := rfqListFlags.query(chain.ID)
+		if err != nil {
+			return err
+		}
+		repo, err := openRepository()
+		if err != nil {
+			return err
+		}
+		defer repo.Close()
+
+		page, err := repositories.NewRFQRepository(repo).ListRFQs(ctx, q)
+		if err != nil {
+			return fmt.Errorf("failed to list RFQs: %w", err)
+		}
+		if err := render(cmd, page, func() *cli.Table {
+			return rfqTable(page.Items)
+		}); err != nil {
+			return err
+		}
+		if flags.output == cli.FormatTable {
+			pageFooter(cmd, len(page.Items), page.Total, page.NextCursor)
+		}
+		return nil
+	},
+}
+
+// rfqDetail is an RFQ with its quotes
+type rfqDetail struct {
+	RFQ    *repositories.RFQModel
+	Quotes []*repositories.QuoteModel
+	// QuoteCount counts every quote, Quotes holds the first MaxListLimit
+	QuoteCount uint64
+}
+
+var rfqGetCmd = &cobra.Command{
+	Use:   "get <id>",
+	Short: "Show an RFQ and its quotes",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := timeoutContext(cmd)
+		defer cancel()
+
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid RFQ ID %q", args[0])
+		}
+		chain, err := selectedChain()
+		if err != nil {
+			return err
+		}
+		repo, err := openRepository()
+		if err != nil {
+			return err
+		}
+		defer repo.Close()
+		rfqRepo := repositories.NewRFQRepository(repo)
+
+		rfq, err := rfqRepo.GetRFQ(ctx, chain.ID, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("RFQ %d not found on %s", id, chain.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get RFQ %d: %w", id, err)
+		}
+		quotes, err := rfqRepo.ListQuotes(ctx, id, repositories.ListQuery{ChainID: chain.ID, Limit: repositories.MaxListLimit})
+		if err != nil {
+			return fmt.Errorf("failed to list the quotes of RFQ %d: %w", id, err)
+		}
+
+		if flags.output == cli.FormatJSON {
+			return render(cmd, rfqDetail{RFQ: rfq, Quotes: quotes.Items, QuoteCount: quotes.Total}, nil)
+		}
+		out := cmd.OutOrStdout()
+		if err := rfqFields(rfq).Write(out); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nQuotes (%d)\n", quotes.Total)
+		return quoteTable(quotes.Items).Write(out)
+	},
+}
+
+func init() {
+	rfqListFlags = bindListFlags(rfqListCmd,
+		repositories.FilterStatus, repositories.FilterBorrower, repositories.FilterLender,
+		repositories.FilterAmountMin, repositories.FilterAmountMax,
+		repositories.FilterDurationMin, repositories.FilterDurationMax, repositories.FilterCollateralType,
+		repositories.FilterCreatedFrom, repositories.FilterCreatedTo)
+
+	rfqCmd.AddCommand(rfqListCmd, rfqGetCmd)
+	rootCmd.AddCommand(rfqCmd)
+}
+
+func rfqTable(rfqs []*repositories.RFQModel) *cli.Table {
+	t := cli.NewTable("ID", "STATUS", "BORROWER", "AMOUNT", "DURATION", "COLLATERAL", "CREATED")
+	for _, rfq := range rfqs {
+		t.Add(rfq.ID, rfq.Status, rfq.BorrowerAddress, rfq.Amount, cli.Seconds(rfq.Duration), rfq.CollateralType, cli.Time(rfq.CreatedAt))
+	}
+	return t
+}
+
+func rfqFields(rfq *repositories.RFQModel) *cli.Table {
+	t := cli.NewTable("FIELD", "VALUE")
+	t.Add("Chain", rfq.ChainID)
+	t.Add("ID", rfq.ID)
+	t.Add("Status", rfq.Status)
+	t.Add("Borrower", rfq.BorrowerAddress)
+	t.Add("Amount", rfq.Amount)
+	t.Add("Duration", cli.Seconds(rfq.Duration))
+	t.Add("Collateral type", rfq.CollateralType)
+	t.Add("Flow", rfq.FlowDescription)
+	t.Add("Created", cli.Time(rfq.CreatedAt))
+	return t
+}
+
+func quoteTable(quotes []*repositories.QuoteModel) *cli.Table {
+	t := cli.NewTable("ID", "LENDER", "RATE_BPS", "LIMIT", "COLLATERAL", "ACCEPTED", "SUBMITTED")
+	for _, quote := range quotes {
+		t.Add(quote.ID, quote.LenderAddress, quote.RateBps, quote.Limit, quote.CollateralRequired,
+			quote.Accepted == 1, cli.Time(quote.SubmittedAt))
+	}
+	return t
+}
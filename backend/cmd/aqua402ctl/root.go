@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/Pagga-Wallet/aqua402/internal/queues"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// flags are the persistent flags shared by every command
+var flags struct {
+	env         string
+	configFiles []string
+	sets        []string
+	output      string
+	chain       string
+	timeout     time.Duration
+	verbose     bool
+}
+
+// cfg and logger are set before any command runs
+var (
+	cfg    *Config
+	logger *zap.Logger
+)
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "aqua402ctl",
+	Short: "Inspect and operate an aqua402 deployment",
+	Long: `aqua402ctl reads the indexed RFQs and auctions, inspects and repairs the event queues, replays
+blocks through the event monitor, checks the faucet and the indexer lag, and force-finalizes auctions.
+
+It reads the same settings as the API and the worker: the environment, --config files and --set
+overrides. Commands print tables, or JSON with -o json.
+
+Examples:
+
+  aqua402ctl rfq list --status open --sort -amount
+  aqua402ctl auction finalize 7 --chain base-sepolia
+  aqua402ctl queue requeue credit.events
+  aqua402ctl replay --from 1200 --to 1300 -o json
+  aqua402ctl lag
+`,
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		if flags.output, err = cli.ParseFormat(flags.output); err != nil {
+			return err
+		}
+
+		level := zapcore.WarnLevel
+		if flags.verbose {
+			level = zapcore.InfoLevel
+		}
+		logConfig := zap.NewDevelopmentConfig()
+		logConfig.Level = zap.NewAtomicLevelAt(level)
+		if logger, err = logConfig.Build(); err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		var configArgs []string
+		if flags.env != "" {
+			configArgs = append(configArgs, "--env", flags.env)
+		}
+		for _, file := range flags.configFiles {
+			configArgs = append(configArgs, "--config", file)
+		}
+		for _, set := range flags.sets {
+			configArgs = append(configArgs, "--set", set)
+		}
+		if cfg, err = loadConfig(configArgs); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+		for _, warning := range cfg.Warnings {
+			logger.Warn(warning)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&flags.env, "env", "", "profile: development, staging or production (default APP_ENV)")
+	rootCmd.PersistentFlags().StringArrayVar(&flags.configFiles, "config", nil, "file of KEY=VALUE settings, repeatable (default CONFIG_FILE)")
+	rootCmd.PersistentFlags().StringArrayVar(&flags.sets, "set", nil, "KEY=VALUE setting overriding every other source, repeatable")
+	rootCmd.PersistentFlags().StringVarP(&flags.output, "output", "o", cli.FormatTable, "output format: table or json")
+	rootCmd.PersistentFlags().StringVar(&flags.chain, "chain", "", "chain ID or name (default DEFAULT_CHAIN)")
+	rootCmd.PersistentFlags().DurationVar(&flags.timeout, "timeout", 30*time.Second, "time limit of a command, 0 for none; replay and tail --follow run until done or interrupted")
+	rootCmd.PersistentFlags().BoolVarP(&flags.verbose, "verbose", "v", false, "log progress, not only warnings")
+}
+
+// timeoutContext bounds a command by --timeout
+func timeoutContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if flags.timeout <= 0 {
+		return context.WithCancel(cmd.Context())
+	}
+	return context.WithTimeout(cmd.Context(), flags.timeout)
+}
+
+// render writes v as JSON or table as a table, following --output
+func render(cmd *cobra.Command, v interface{}, table func() *cli.Table) error {
+	return cli.Print(cmd.OutOrStdout(), flags.output, v, table)
+}
+
+// selectedChain returns the chain named by --chain, or the default chain
+func selectedChain() (*chains.Chain, error) {
+	return cfg.Chains.Get(flags.chain)
+}
+
+func openRepository() (*repositories.Repository, error) {
+	repo, err := repositories.NewRepository(cfg.ClickHouseDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ClickHouse: %w", err)
+	}
+	return repo, nil
+}
+
+func openQueue() (*queues.Queue, error) {
+	queue, err := queues.NewQueue(cfg.RabbitMQURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	return queue, nil
+}
+
+// dial connects to the RPC of a chain and checks it serves that chain
+func dial(ctx context.Context, chain *chains.Chain) (*evm.Client, error) {
+	evmClient, err := evm.Dial(chain.ClientConfig(cfg.EVM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the RPC of %s: %w", chain.Name, err)
+	}
+	chainID, err := evmClient.ChainID(ctx)
+	if err != nil {
+		evmClient.Close()
+		return nil, fmt.Errorf("failed to read the chain ID of %s: %w", chain.Name, err)
+	}
+	if chainID.Cmp(new(big.Int).SetUint64(chain.ID)) != 0 {
+		evmClient.Close()
+		return nil, fmt.Errorf("RPC of %s serves chain %s", chain.Name, chainID)
+	}
+	evmClient.SetFeeConfig(cfg.Fees)
+	return evmClient, nil
+}
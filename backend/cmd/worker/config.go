@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Pagga-Wallet/aqua402/internal/health"
+	"github.com/Pagga-Wallet/aqua402/internal/lifecycle"
+	"github.com/Pagga-Wallet/aqua402/internal/services/credit"
+	eventmonitor "github.com/Pagga-Wallet/aqua402/internal/services/events"
+	"github.com/Pagga-Wallet/aqua402/internal/services/faucet"
+	"github.com/Pagga-Wallet/aqua402/internal/services/risk"
+	"github.com/Pagga-Wallet/aqua402/internal/services/webhooks"
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/Pagga-Wallet/aqua402/pkg/config"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+)
+
+// Config is the worker configuration, every part parsed before anything starts
+type Config struct {
+	*config.Config
+	Chains    *chains.Registry
+	EVM       evm.ClientConfig
+	Fees      evm.FeeConfig
+	Tx        evm.TxManagerConfig
+	Events    eventmonitor.Config
+	Credit    credit.Config
+	Risk      risk.Config
+	Tracing   tracing.Config
+	Health    health.Config
+	Lifecycle lifecycle.Config
+	Webhooks  webhooks.Config
+	// Operator signs liquidations with RISK_OPERATOR_SIGNER, approved cases are not executed without one
+	Operator evm.SignerConfig
+	// FaucetChain is FAUCET_CHAIN (an ID or name, the default chain when unset), where the worker sends the
+	// grants the API submits with the FAUCET_SIGNER of Faucet
+	FaucetChain *chains.Chain
+	Faucet      faucet.Config
+	// MetricsAddr is METRICS_ADDR, the listen address of /metrics and the health probes
+	MetricsAddr string
+}
+
+// defaultMetricsAddr is the listen address of /metrics without METRICS_ADDR
+const defaultMetricsAddr = ":9090"
+
+// loadConfig resolves the settings from args and the environment and parses them, reporting every
+// invalid one. base is returned even when invalid so it can be printed.
+func loadConfig(args []string) (*Config, error) {
+	base, err := config.Load("worker", args)
+	if err != nil {
+		if base == nil {
+			return nil, err
+		}
+		return &Config{Config: base}, err
+	}
+	cfg := &Config{Config: base, MetricsAddr: base.Get("METRICS_ADDR")}
+	if cfg.MetricsAddr == "" {
+		cfg.MetricsAddr = defaultMetricsAddr
+	}
+
+	var errs []error
+	check := func(part string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", part, err))
+		}
+	}
+	cfg.Chains, err = chains.LoadFromEnv()
+	check("chains", err)
+	cfg.EVM, err = evm.ClientConfigFromEnv()
+	check("EVM RPC", err)
+	cfg.Fees, err = evm.FeeConfigFromEnv()
+	check("EVM fees", err)
+	cfg.Tx, err = evm.TxManagerConfigFromEnv()
+	check("transactions", err)
+	cfg.Events, err = eventmonitor.ConfigFromEnv()
+	check("event monitor", err)
+	cfg.Credit, err = credit.ConfigFromEnv()
+	check("credit ledger", err)
+	cfg.Risk, err = risk.ConfigFromEnv()
+	check("risk monitor", err)
+	cfg.Tracing, err = tracing.ConfigFromEnv()
+	check("tracing", err)
+	cfg.Health, err = health.ConfigFromEnv()
+	check("health checks", err)
+	cfg.Lifecycle, err = lifecycle.ConfigFromEnv()
+	check("shutdown", err)
+	cfg.Webhooks, err = webhooks.ConfigFromEnv()
+	check("webhooks", err)
+	cfg.Operator, err = evm.SignerConfigFromEnv("RISK_OPERATOR")
+	check("operator signer", err)
+	cfg.Faucet, err = faucet.ConfigFromEnv()
+	check("faucet", err)
+	if cfg.Chains != nil {
+		faucetChainRef := base.Get("FAUCET_CHAIN")
+		if faucetChainRef == "" {
+			faucetChainRef = base.Get("FAUCET_CHAIN_ID")
+		}
+		cfg.FaucetChain, err = cfg.Chains.Get(faucetChainRef)
+		check("faucet chain", err)
+	}
+	return cfg, errors.Join(errs...)
+}
@@ -24,9 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/aqua/liquidity": {
-            "post": {
-                "description": "Connects lender liquidity through 1inch Aqua",
+        "/accounts/{address}": {
+            "get": {
+                "description": "Aggregates the RFQs, auctions, quotes, bids, Aqua liquidity and credit lines of an address into open positions, historical fills, outstanding debt, realized interest paid and earned, and pending actions such as accepting a quote or repaying an installment due",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,54 +34,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Aqua"
+                    "Accounts"
                 ],
-                "summary": "Connect liquidity",
+                "summary": "Get account portfolio",
                 "parameters": [
                     {
-                        "description": "Liquidity data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_aqua.ConnectLiquidityRequest"
-                        }
+                        "type": "string",
+                        "description": "Borrower or lender address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Portfolio"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/aqua/liquidity/{address}": {
+        "/analytics/aqua-utilization": {
             "get": {
-                "description": "Returns available liquidity for a lender",
+                "description": "Aqua liquidity movements per bucket, running connected and reserved totals and their ratio",
                 "consumes": [
                     "application/json"
                 ],
@@ -89,41 +84,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Aqua"
+                    "Analytics"
                 ],
-                "summary": "Get liquidity",
+                "summary": "Get Aqua liquidity utilization",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Range start, unix seconds (default: to - 30 days)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Range end, unix seconds (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Lender address",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
+                        "description": "Bucket granularity: hour, day, week, month (default: day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.AquaUtilizationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/aqua/withdraw": {
-            "post": {
-                "description": "Withdraws liquidity from Aqua",
+        "/analytics/clearing-rates": {
+            "get": {
+                "description": "Average rate in bps credit lines were opened at, per bucket and duration bucket (0-7d, 7-30d, 30-90d, 90d+)",
                 "consumes": [
                     "application/json"
                 ],
@@ -131,54 +145,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Aqua"
+                    "Analytics"
                 ],
-                "summary": "Withdraw liquidity",
+                "summary": "Get clearing rates by duration",
                 "parameters": [
                     {
-                        "description": "Withdrawal data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_aqua.WithdrawLiquidityRequest"
-                        }
+                        "type": "integer",
+                        "description": "Range start, unix seconds (default: to - 30 days)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Range end, unix seconds (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket granularity: hour, day, week, month (default: day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.ClearingRateReport"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/auction": {
+        "/analytics/lender-share": {
             "get": {
-                "description": "Returns a list of all auctions with pagination",
+                "description": "Credit line volume opened per lender and its share of the bucket total",
                 "consumes": [
                     "application/json"
                 ],
@@ -186,22 +206,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auction"
+                    "Analytics"
                 ],
-                "summary": "List Auctions",
+                "summary": "Get lender market share",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "Record limit",
-                        "name": "limit",
+                        "description": "Range start, unix seconds (default: to - 30 days)",
+                        "name": "from",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 0,
-                        "description": "Offset",
-                        "name": "offset",
+                        "description": "Range end, unix seconds (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket granularity: hour, day, week, month (default: day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
                         "in": "query"
                     }
                 ],
@@ -209,25 +239,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.AuctionModel"
-                            }
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.LenderShareReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new auction for financing",
+            }
+        },
+        "/analytics/quote-fill": {
+            "get": {
+                "description": "Requests, quotes (bids for auctions) and fills per market, with quotes per request and fills per quote",
                 "consumes": [
                     "application/json"
                 ],
@@ -235,52 +267,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auction"
+                    "Analytics"
                 ],
-                "summary": "Create auction",
+                "summary": "Get quote-to-fill ratio",
                 "parameters": [
                     {
-                        "description": "Auction data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_auction.CreateAuctionRequest"
-                        }
+                        "type": "integer",
+                        "description": "Range start, unix seconds (default: to - 30 days)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Range end, unix seconds (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket granularity: hour, day, week, month (default: day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.QuoteFillReport"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/auction/{id}": {
+        "/analytics/time-to-first-quote": {
             "get": {
-                "description": "Returns information about a specific auction",
+                "description": "Average, median and p90 seconds RFQs created in each bucket waited for their first quote",
                 "consumes": [
                     "application/json"
                 ],
@@ -288,49 +328,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auction"
+                    "Analytics"
                 ],
-                "summary": "Get Auction",
+                "summary": "Get time to first quote",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Auction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Range start, unix seconds (default: to - 30 days)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Range end, unix seconds (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket granularity: hour, day, week, month (default: day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.AuctionModel"
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.FirstQuoteReport"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/auction/{id}/bid": {
-            "post": {
-                "description": "Places a bid on a specific auction",
+        "/analytics/volume": {
+            "get": {
+                "description": "Number and requested volume of RFQs and auctions, and credit line volume opened, per bucket",
                 "consumes": [
                     "application/json"
                 ],
@@ -338,61 +389,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auction"
+                    "Analytics"
                 ],
-                "summary": "Place bid",
+                "summary": "Get volume by bucket",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Auction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Range start, unix seconds (default: to - 30 days)",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
-                        "description": "Bid data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_auction.BidRequest"
-                        }
+                        "type": "integer",
+                        "description": "Range end, unix seconds (default: now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket granularity: hour, day, week, month (default: day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.VolumeReport"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/auction/{id}/finalize": {
+        "/aqua/liquidity": {
             "post": {
-                "description": "Finalizes an auction by selecting the best bid",
+                "description": "Connects lender liquidity through 1inch Aqua",
                 "consumes": [
                     "application/json"
                 ],
@@ -400,16 +450,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auction"
+                    "Aqua"
                 ],
-                "summary": "Finalize auction",
+                "summary": "Connect liquidity",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Auction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Liquidity data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_aqua.ConnectLiquidityRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -422,21 +498,81 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/aqua/liquidity/{address}": {
+            "get": {
+                "description": "Returns available liquidity for a lender",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Aqua"
+                ],
+                "summary": "Get liquidity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lender address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/faucet": {
+        "/aqua/withdraw": {
             "post": {
-                "description": "Requests test ETH tokens from the faucet for the specified address",
+                "description": "Withdraws liquidity from Aqua",
                 "consumes": [
                     "application/json"
                 ],
@@ -444,18 +580,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Faucet"
+                    "Aqua"
                 ],
-                "summary": "Request test tokens",
+                "summary": "Withdraw liquidity",
                 "parameters": [
                     {
-                        "description": "Faucet request",
+                        "description": "Withdrawal data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_faucet.RequestTokensRequest"
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_aqua.WithdrawLiquidityRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -471,27 +631,33 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/rfq": {
+        "/auction": {
             "get": {
-                "description": "Returns a list of all RFQ requests with pagination",
+                "description": "Returns a page of auctions. X-Total-Count holds the number of matching auctions and X-Next-Cursor continues the list.",
                 "consumes": [
                     "application/json"
                 ],
@@ -499,22 +665,88 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RFQ"
+                    "Auction"
                 ],
-                "summary": "List RFQs",
+                "summary": "List Auctions",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Borrower address",
+                        "name": "borrower",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lender address (auctions the lender bid on)",
+                        "name": "lender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum amount in wei",
+                        "name": "amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum amount in wei",
+                        "name": "amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum duration in seconds",
+                        "name": "duration_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum duration in seconds",
+                        "name": "duration_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Created at or after (unix seconds)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Created before (unix seconds)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Sort field (created_at, end_time, amount, duration, id), prefixed with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 20,
-                        "description": "Record limit",
+                        "description": "Page size (1-100)",
                         "name": "limit",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset",
-                        "name": "offset",
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
                         "in": "query"
                     }
                 ],
@@ -524,23 +756,26 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel"
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.AuctionModel"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates a new Request for Quote",
+                "description": "Creates a new auction for financing",
                 "consumes": [
                     "application/json"
                 ],
@@ -548,51 +783,88 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RFQ"
+                    "Auction"
                 ],
-                "summary": "Create RFQ",
+                "summary": "Create auction",
                 "parameters": [
                     {
-                        "description": "RFQ data",
+                        "description": "Auction data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_rfq.CreateRFQRequest"
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_auction.CreateAuctionRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/rfq/{id}": {
+        "/auction/{id}": {
             "get": {
-                "description": "Returns information about a specific RFQ request",
+                "description": "Returns information about a specific auction",
                 "consumes": [
                     "application/json"
                 ],
@@ -600,49 +872,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RFQ"
+                    "Auction"
                 ],
-                "summary": "Get RFQ",
+                "summary": "Get Auction",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "RFQ ID",
+                        "description": "Auction ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel"
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.AuctionModel"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handlers.Problem"
                         }
                     }
                 }
             }
         },
-        "/rfq/{id}/quote": {
+        "/auction/{id}/bid": {
             "post": {
-                "description": "Submits a quote for a specific RFQ",
+                "description": "Places a bid on a specific auction",
                 "consumes": [
                     "application/json"
                 ],
@@ -650,25 +922,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RFQ"
+                    "Auction"
                 ],
-                "summary": "Submit quote",
+                "summary": "Place bid",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "RFQ ID",
+                        "description": "Auction ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Quote data",
+                        "description": "Bid data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_rfq.QuoteRequest"
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_auction.BidRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -684,201 +986,3035 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auction/{id}/bids": {
+            "get": {
+                "description": "Returns a page of the bids placed on an auction. Status is winning or open and amounts are bid limits.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auction"
+                ],
+                "summary": "List bids",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Auction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (winning, open)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lender address",
+                        "name": "lender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum limit in wei",
+                        "name": "amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum limit in wei",
+                        "name": "amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Placed at or after (unix seconds)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Placed before (unix seconds)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Sort field (created_at, amount, rate_bps, id), prefixed with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.BidModel"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auction/{id}/finalize": {
+            "post": {
+                "description": "Finalizes an auction by selecting the best bid",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auction"
+                ],
+                "summary": "Finalize auction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Auction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/borrowers/{address}/obligations": {
+            "get": {
+                "description": "Returns outstanding principal, accrued interest and next due amounts for every credit line of a borrower",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Credit"
+                ],
+                "summary": "Get borrower obligations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Borrower address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Obligations"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/chains": {
+            "get": {
+                "description": "Returns the configured chains with their contracts, confirmation depth and tokens. Every other\nendpoint takes a chain query parameter, the ID or name of one of them, and defaults to the first\nchain listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chains"
+                ],
+                "summary": "List chains",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_pkg_chains.Chain"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/credit-lines/{id}/risk": {
+            "get": {
+                "description": "Returns whether a credit line is current, in grace, delinquent or defaulted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Get credit line risk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Credit line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.RiskStatusModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_Pagga-Wallet_aqua402_internal_repositories.AuctionModel": {
+        },
+        "/credit-lines/{id}/schedule": {
+            "get": {
+                "description": "Replays draws and repayments of a credit line and returns outstanding principal, accrued interest and upcoming installments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Credit"
+                ],
+                "summary": "Get credit line schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Credit line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/faucet": {
+            "post": {
+                "description": "Requests test ETH, or a token listed by GET /faucet/tokens, for the specified address. The amount is capped, each address\nand client IP has a cooldown and the faucet has a daily budget: refusals are 429 with Retry-After.\nA missing or wrong challenge, or a faucet at its balance floor, is 403.\nThe faucet dispenses on one chain (FAUCET_CHAIN), a chain parameter naming another one is 400.\nThe grant is followed with GET /transactions/{id} by its tx_id, tx_hash is empty until it is sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Faucet"
+                ],
+                "summary": "Request test tokens",
+                "parameters": [
+                    {
+                        "description": "Faucet request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_faucet.RequestTokensRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the faucet's chain",
+                        "name": "chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/faucet/tokens": {
+            "get": {
+                "description": "Lists ETH and the ERC20 tokens the faucet dispenses, with the most a request may ask for,\nthe daily budget and the per-address and per-IP cooldowns in seconds",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Faucet"
+                ],
+                "summary": "List faucet tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_faucet.TokenInfo"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/liquidations": {
+            "get": {
+                "description": "Returns liquidation cases opened for defaulted collateralized credit lines",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "List liquidation cases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator token",
+                        "name": "X-Operator-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case status (pending_approval, approved, rejected, executing, executed, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.LiquidationCaseModel"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/liquidations/{id}": {
+            "get": {
+                "description": "Returns the liquidation case of a credit line",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Get liquidation case",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Credit line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator token",
+                        "name": "X-Operator-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.LiquidationCaseModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/liquidations/{id}/approve": {
+            "post": {
+                "description": "Operator approval of a pending liquidation case; the worker then frees the lender's reserved liquidity through AquaIntegration.releaseLiquidity",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Approve liquidation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Credit line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator token",
+                        "name": "X-Operator-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Approval",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_risk.ApproveRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.LiquidationCaseModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/liquidations/{id}/reject": {
+            "post": {
+                "description": "Operator rejection of a pending liquidation case, leaving the collateral untouched",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Reject liquidation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Credit line ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator token",
+                        "name": "X-Operator-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Rejection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_risk.RejectRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.LiquidationCaseModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/rfq": {
+            "get": {
+                "description": "Returns a page of RFQ requests. X-Total-Count holds the number of matching RFQs and X-Next-Cursor continues the list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RFQ"
+                ],
+                "summary": "List RFQs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Borrower address",
+                        "name": "borrower",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lender address (RFQs the lender quoted on)",
+                        "name": "lender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum amount in wei",
+                        "name": "amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum amount in wei",
+                        "name": "amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum duration in seconds",
+                        "name": "duration_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum duration in seconds",
+                        "name": "duration_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Collateral type",
+                        "name": "collateral_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Created at or after (unix seconds)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Created before (unix seconds)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Sort field (created_at, amount, duration, id), prefixed with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new Request for Quote",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RFQ"
+                ],
+                "summary": "Create RFQ",
+                "parameters": [
+                    {
+                        "description": "RFQ data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_rfq.CreateRFQRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/rfq/{id}": {
+            "get": {
+                "description": "Returns information about a specific RFQ request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RFQ"
+                ],
+                "summary": "Get RFQ",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "RFQ ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/rfq/{id}/quote": {
+            "post": {
+                "description": "Submits a quote for a specific RFQ",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RFQ"
+                ],
+                "summary": "Submit quote",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "RFQ ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quote data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_rfq.QuoteRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/rfq/{id}/quotes": {
+            "get": {
+                "description": "Returns a page of the quotes submitted on an RFQ. Status is accepted or open and amounts are quote limits.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RFQ"
+                ],
+                "summary": "List quotes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "RFQ ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (accepted, open)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lender address",
+                        "name": "lender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum limit in wei",
+                        "name": "amount_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum limit in wei",
+                        "name": "amount_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Submitted at or after (unix seconds)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Submitted before (unix seconds)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Sort field (created_at, amount, rate_bps, id), prefixed with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_repositories.QuoteModel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "description": "Returns a transaction sent by the backend, e.g. a faucet grant, by the tx_id it was returned with.\nStatus is requested (waiting for the worker to send it), queued, pending, confirmed, reverted, dropped\nor failed. A stuck transaction is resent with a higher gas price, tx_hash is the latest broadcast.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get transaction status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_transactions.Transaction"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "post": {
+                "description": "Subscribes a URL to the on-chain events of an address, which must sign the request and owns the subscription. Payloads are signed with the returned secret, which is only shown once: X-Aqua402-Signature is t=\u003cunix seconds\u003e,v1=\u003chex HMAC-SHA256 of \"\u003cunix seconds\u003e.\u003cbody\u003e\"\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_webhooks.SubscribeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain ID or name, defaults to the default chain",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_webhooks.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Returns a webhook subscription of the signing wallet, without its secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_webhooks.Subscription"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stops deliveries to a subscription of the signing wallet, pending ones are marked failed",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Returns the latest deliveries of a subscription of the signing wallet with their status, attempts and last response, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of deliveries (1-200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_webhooks.Delivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "Queues a new delivery of the event of a previous delivery to a subscription of the signing wallet, whatever its status. It carries the same event ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Redeliver webhook event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signing wallet, the address the request acts for",
+                        "name": "X-Aqua402-Address",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix seconds the request was signed at",
+                        "name": "X-Aqua402-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "EIP-191 signature of the request, see WalletAuth in pkg/client",
+                        "name": "X-Aqua402-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_webhooks.Delivery"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_Pagga-Wallet_aqua402_internal_apperrors.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_repositories.AuctionModel": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "biddingDuration": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "borrowerAddress": {
+                    "type": "string"
+                },
+                "chainID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "createdAt": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "duration": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "endTime": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_repositories.BidModel": {
+            "type": "object",
+            "properties": {
+                "auctionID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "chainID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "isWinning": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "lenderAddress": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "rateBps": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "timestamp": {
+                    "type": "integer",
+                    "format": "int64"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_repositories.LiquidationCaseModel": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "borrowerAddress": {
+                    "type": "string"
+                },
+                "chainID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "collateralAmount": {
+                    "type": "string"
+                },
+                "collateralType": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "createdAt": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "creditLineID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "lenderAddress": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "outstanding": {
+                    "type": "string"
+                },
+                "releaseAmount": {
+                    "type": "string"
+                },
+                "rfqid": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "integer",
+                    "format": "int64"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_repositories.QuoteModel": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "chainID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "collateralRequired": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "lenderAddress": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "rateBps": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "rfqid": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "submittedAt": {
+                    "type": "integer",
+                    "format": "int64"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "borrowerAddress": {
+                    "type": "string"
+                },
+                "chainID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "collateralType": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "createdAt": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "duration": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "flowDescription": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_repositories.RiskStatusModel": {
+            "type": "object",
+            "properties": {
+                "borrowerAddress": {
+                    "type": "string"
+                },
+                "chainID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "creditLineID": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "expiresAt": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "lenderAddress": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "totalOwed": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "integer",
+                    "format": "int64"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Action": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID is the RFQ, auction or credit line the action is on",
+                    "type": "integer"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "overdue": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "accept_quote",
+                        "finalize_auction",
+                        "repay_due"
+                    ]
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Debt": {
+            "type": "object",
+            "properties": {
+                "interest": {
+                    "type": "string"
+                },
+                "next_due": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Installment"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Fill": {
+            "type": "object",
+            "properties": {
+                "counterparty": {
+                    "description": "Counterparty is the lender of a borrower's fill and the borrower of a lender's",
+                    "type": "string"
+                },
+                "credit_line_id": {
+                    "type": "integer"
+                },
+                "filled_at": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string",
+                    "enum": [
+                        "rfq",
+                        "auction"
+                    ]
+                },
+                "rate_bps": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "borrower",
+                        "lender"
+                    ]
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Interest": {
+            "type": "object",
+            "properties": {
+                "earned": {
+                    "type": "string"
+                },
+                "paid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Liquidity": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Available is connected and not reserved",
+                    "type": "string"
+                },
+                "connected": {
+                    "description": "Connected is connected and not withdrawn",
+                    "type": "string"
+                },
+                "reserved": {
+                    "description": "Reserved is reserved for credit lines and not released",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Offer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string",
+                    "enum": [
+                        "rfq",
+                        "auction"
+                    ]
+                },
+                "rate_bps": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.OpenPositions": {
+            "type": "object",
+            "properties": {
+                "borrowed": {
+                    "description": "Borrowed are the credit lines of the address as a borrower still owing or not expired",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Obligation"
+                    }
+                },
+                "lent": {
+                    "description": "Lent are the credit lines funded by the address still owed or not expired",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Position"
+                    }
+                },
+                "liquidity": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Liquidity"
+                },
+                "offers": {
+                    "description": "Offers are quotes and bids of the address on requests not filled yet",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Offer"
+                    }
+                },
+                "requests": {
+                    "description": "Requests are RFQs and auctions of the address not filled yet",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Request"
+                    }
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Portfolio": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "as_of": {
+                    "type": "integer"
+                },
+                "chain_id": {
+                    "type": "integer"
+                },
+                "debt": {
+                    "description": "Debt is what the address owes as a borrower",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Debt"
+                        }
+                    ]
+                },
+                "fills": {
+                    "description": "Fills are the RFQs and auctions of the address that opened a credit line, and those it won as a lender",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Fill"
+                    }
+                },
+                "interest": {
+                    "description": "Interest is the interest repaid by the address as a borrower and to it as a lender",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Interest"
+                        }
+                    ]
+                },
+                "open_positions": {
+                    "description": "OpenPositions are the requests and offers still open, the credit lines not closed and the Aqua liquidity",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.OpenPositions"
+                        }
+                    ]
+                },
+                "pending_actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_accounts.Action"
+                    }
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_accounts.Request": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "end_time": {
+                    "description": "EndTime is when the bidding of an auction ends",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "market": {
+                    "type": "string",
+                    "enum": [
+                        "rfq",
+                        "auction"
+                    ]
+                },
+                "offers": {
+                    "description": "Offers is the number of quotes or bids received",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.AquaUtilizationPoint": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "integer"
+                },
+                "connected": {
+                    "type": "string"
+                },
+                "in_use": {
+                    "type": "string"
+                },
+                "released": {
+                    "type": "string"
+                },
+                "reserved": {
+                    "type": "string"
+                },
+                "total_connected": {
+                    "type": "string"
+                },
+                "utilization": {
+                    "type": "number"
+                },
+                "withdrawn": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.AquaUtilizationReport": {
+            "type": "object",
+            "properties": {
+                "chain_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "granularity": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.AquaUtilizationPoint"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.ClearingRatePoint": {
+            "type": "object",
+            "properties": {
+                "avg_rate_bps": {
+                    "type": "number"
+                },
+                "bucket": {
+                    "type": "integer"
+                },
+                "duration_bucket": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.ClearingRateReport": {
+            "type": "object",
+            "properties": {
+                "chain_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "granularity": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.ClearingRatePoint"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.FirstQuotePoint": {
+            "type": "object",
+            "properties": {
+                "avg_seconds": {
+                    "type": "number"
+                },
+                "bucket": {
+                    "type": "integer"
+                },
+                "median_seconds": {
+                    "type": "number"
+                },
+                "p90_seconds": {
+                    "type": "number"
+                },
+                "quoted": {
+                    "type": "integer"
+                },
+                "rfqs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.FirstQuoteReport": {
+            "type": "object",
+            "properties": {
+                "chain_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "granularity": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.FirstQuotePoint"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.LenderSharePoint": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "integer"
+                },
+                "lender_address": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "integer"
+                },
+                "share": {
+                    "type": "number"
+                },
+                "volume": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.LenderShareReport": {
+            "type": "object",
+            "properties": {
+                "chain_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "granularity": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.LenderSharePoint"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.QuoteFillPoint": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "integer"
+                },
+                "fills": {
+                    "type": "integer"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "quote_to_fill": {
+                    "type": "number"
+                },
+                "quotes": {
+                    "type": "integer"
+                },
+                "quotes_per_request": {
+                    "type": "number"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.QuoteFillReport": {
+            "type": "object",
+            "properties": {
+                "chain_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "granularity": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.QuoteFillPoint"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.VolumePoint": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_analytics.VolumeReport": {
+            "type": "object",
+            "properties": {
+                "chain_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "granularity": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_analytics.VolumePoint"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_aqua.ConnectLiquidityRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "lender_address": {
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_aqua.WithdrawLiquidityRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "lender_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_auction.BidRequest": {
+            "type": "object",
+            "properties": {
+                "auction_id": {
+                    "type": "integer"
+                },
+                "lender_address": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "rate_bps": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_auction.CreateAuctionRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "bidding_duration": {
+                    "type": "integer"
+                },
+                "borrower_address": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_credit.Installment": {
+            "type": "object",
+            "properties": {
+                "due_at": {
+                    "type": "integer"
+                },
+                "interest": {
+                    "type": "string"
+                },
+                "overdue": {
+                    "type": "boolean"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_credit.InterestMode": {
+            "type": "string",
+            "enum": [
+                "simple",
+                "compound"
+            ],
+            "x-enum-varnames": [
+                "InterestSimple",
+                "InterestCompound"
+            ]
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_credit.Obligation": {
+            "type": "object",
+            "properties": {
+                "accrued_interest": {
+                    "type": "string"
+                },
+                "as_of": {
+                    "type": "integer"
+                },
+                "borrower_address": {
+                    "type": "string"
+                },
+                "chain_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "credit_line_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "interest_paid": {
+                    "type": "string"
+                },
+                "lender_address": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "next_due": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Installment"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "rate_bps": {
+                    "type": "integer"
+                },
+                "total_drawn": {
+                    "type": "string"
+                },
+                "total_owed": {
+                    "type": "string"
+                },
+                "total_repaid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_credit.Obligations": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "integer"
+                },
+                "borrower_address": {
+                    "type": "string"
+                },
+                "chain_id": {
+                    "type": "integer"
+                },
+                "credit_lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Obligation"
+                    }
+                },
+                "next_due": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Installment"
+                },
+                "total_interest": {
+                    "type": "string"
+                },
+                "total_owed": {
+                    "type": "string"
+                },
+                "total_principal": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_credit.Position": {
+            "type": "object",
+            "properties": {
+                "accrued_interest": {
+                    "type": "string"
+                },
+                "as_of": {
+                    "type": "integer"
+                },
+                "borrower_address": {
+                    "type": "string"
+                },
+                "chain_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "credit_line_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "interest_paid": {
+                    "type": "string"
+                },
+                "lender_address": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "rate_bps": {
+                    "type": "integer"
+                },
+                "total_drawn": {
+                    "type": "string"
+                },
+                "total_owed": {
+                    "type": "string"
+                },
+                "total_repaid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_credit.Schedule": {
             "type": "object",
             "properties": {
-                "amount": {
+                "accrued_interest": {
                     "type": "string"
                 },
-                "biddingDuration": {
-                    "type": "integer",
-                    "format": "int64"
+                "as_of": {
+                    "type": "integer"
                 },
-                "borrowerAddress": {
+                "borrower_address": {
                     "type": "string"
                 },
-                "createdAt": {
-                    "type": "integer",
-                    "format": "int64"
+                "chain_id": {
+                    "type": "integer"
                 },
-                "duration": {
-                    "type": "integer",
-                    "format": "int64"
+                "created_at": {
+                    "type": "integer"
                 },
-                "endTime": {
-                    "type": "integer",
-                    "format": "int64"
+                "credit_line_id": {
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "integer",
-                    "format": "int64"
+                "expires_at": {
+                    "type": "integer"
                 },
-                "status": {
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Installment"
+                    }
+                },
+                "interest_mode": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.InterestMode"
+                },
+                "interest_paid": {
+                    "type": "string"
+                },
+                "lender_address": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "next_due": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_services_credit.Installment"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "rate_bps": {
+                    "type": "integer"
+                },
+                "total_drawn": {
+                    "type": "string"
+                },
+                "total_owed": {
+                    "type": "string"
+                },
+                "total_repaid": {
                     "type": "string"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_repositories.RFQModel": {
+        "github_com_Pagga-Wallet_aqua402_internal_services_faucet.RequestTokensRequest": {
             "type": "object",
             "properties": {
+                "address": {
+                    "type": "string"
+                },
                 "amount": {
+                    "description": "Amount is in token units, e.g. \"1.5\" ETH. It defaults to the token's max amount.",
                     "type": "string"
                 },
-                "borrowerAddress": {
+                "challenge": {
+                    "description": "Challenge is the proof-of-work nonce or captcha token, required when the faucet is challenged",
                     "type": "string"
                 },
-                "collateralType": {
-                    "type": "integer",
-                    "format": "int32"
+                "token": {
+                    "description": "Token is the symbol or address of a token listed by GET /faucet/tokens, empty for ETH",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_faucet.TokenInfo": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address is the token contract, empty for ETH",
+                    "type": "string"
                 },
-                "createdAt": {
-                    "type": "integer",
-                    "format": "int64"
+                "address_cooldown": {
+                    "description": "Cooldowns are in seconds",
+                    "type": "integer"
                 },
-                "duration": {
-                    "type": "integer",
-                    "format": "int64"
+                "daily_budget": {
+                    "type": "string"
                 },
-                "flowDescription": {
+                "decimals": {
+                    "type": "integer"
+                },
+                "ip_cooldown": {
+                    "type": "integer"
+                },
+                "max_amount": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer",
-                    "format": "int64"
+                "mode": {
+                    "type": "string"
                 },
-                "status": {
+                "symbol": {
                     "type": "string"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_services_aqua.ConnectLiquidityRequest": {
+        "github_com_Pagga-Wallet_aqua402_internal_services_rfq.CreateRFQRequest": {
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "string"
                 },
-                "lender_address": {
+                "borrower_address": {
                     "type": "string"
                 },
-                "token_address": {
+                "collateral_type": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "flow_description": {
                     "type": "string"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_services_aqua.WithdrawLiquidityRequest": {
+        "github_com_Pagga-Wallet_aqua402_internal_services_rfq.QuoteRequest": {
             "type": "object",
             "properties": {
-                "amount": {
+                "collateral_required": {
                     "type": "string"
                 },
                 "lender_address": {
                     "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "rate_bps": {
+                    "type": "integer"
+                },
+                "rfq_id": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_services_auction.BidRequest": {
+        "github_com_Pagga-Wallet_aqua402_internal_services_risk.ApproveRequest": {
             "type": "object",
             "properties": {
-                "auction_id": {
+                "action": {
+                    "description": "Action defaults to claim, the only action",
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_risk.RejectRequest": {
+            "type": "object",
+            "properties": {
+                "operator": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_internal_services_transactions.Transaction": {
+            "type": "object",
+            "properties": {
+                "attempts": {
                     "type": "integer"
                 },
-                "lender_address": {
+                "block_number": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "error": {
                     "type": "string"
                 },
-                "limit": {
+                "from": {
                     "type": "string"
                 },
-                "rate_bps": {
+                "gas_fee_cap": {
+                    "type": "string"
+                },
+                "gas_limit": {
+                    "type": "integer"
+                },
+                "gas_price": {
+                    "type": "string"
+                },
+                "gas_tip_cap": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nonce": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                },
+                "tx_hashes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
                     "type": "integer"
+                },
+                "urgency": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_services_auction.CreateAuctionRequest": {
+        "github_com_Pagga-Wallet_aqua402_internal_services_webhooks.Delivery": {
             "type": "object",
             "properties": {
-                "amount": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "error": {
                     "type": "string"
                 },
-                "bidding_duration": {
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_attempt_at": {
                     "type": "integer"
                 },
-                "borrower_address": {
+                "next_attempt_at": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "redelivery_of": {
                     "type": "string"
                 },
-                "duration": {
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_services_faucet.RequestTokensRequest": {
+        "github_com_Pagga-Wallet_aqua402_internal_services_webhooks.SubscribeRequest": {
             "type": "object",
             "properties": {
                 "address": {
+                    "description": "Address is the borrower or lender whose events are delivered",
                     "type": "string"
                 },
-                "amount": {
-                    "description": "Amount in ETH (will be converted to Wei)",
+                "event_types": {
+                    "description": "EventTypes lists the event types delivered, every type when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "description": "URL receives the events as POST requests, it must be http or https and not name a private address",
                     "type": "string"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_services_rfq.CreateRFQRequest": {
+        "github_com_Pagga-Wallet_aqua402_internal_services_webhooks.Subscription": {
             "type": "object",
             "properties": {
-                "amount": {
+                "active": {
+                    "type": "boolean"
+                },
+                "address": {
                     "type": "string"
                 },
-                "borrower_address": {
+                "chain_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
                     "type": "string"
                 },
-                "collateral_type": {
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "integer"
                 },
-                "duration": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_pkg_chains.Chain": {
+            "type": "object",
+            "properties": {
+                "confirmations": {
+                    "description": "Confirmations is how many blocks an event waits for before it is processed",
                     "type": "integer"
                 },
-                "flow_description": {
+                "contracts": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_pkg_chains.Contracts"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
+                },
+                "native_token": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_pkg_chains.Token"
+                },
+                "rpc_urls": {
+                    "description": "RPCURLs are tried in turn by the chain's client, empty for EVM_RPC_URL",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stable_token": {
+                    "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_pkg_chains.Token"
                 }
             }
         },
-        "github_com_Pagga-Wallet_aqua402_internal_services_rfq.QuoteRequest": {
+        "github_com_Pagga-Wallet_aqua402_pkg_chains.Contracts": {
             "type": "object",
             "properties": {
-                "collateral_required": {
+                "aqua": {
                     "type": "string"
                 },
-                "lender_address": {
+                "auction": {
                     "type": "string"
                 },
-                "limit": {
+                "credit": {
                     "type": "string"
                 },
-                "rate_bps": {
+                "finance": {
+                    "type": "string"
+                },
+                "rfq": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Pagga-Wallet_aqua402_pkg_chains.Token": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "decimals": {
                     "type": "integer"
                 },
-                "rfq_id": {
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Pagga-Wallet_aqua402_internal_apperrors.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,119 @@
+// Package apperrors defines the typed errors services return so the API can map them to HTTP statuses
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Kind classifies an error by how the client should react to it
+type Kind uint8
+
+const (
+	// KindInternal is any error that is not the client's doing
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	// KindRateLimited is a quota or cooldown the client must wait out, see Error.RetryAfter
+	KindRateLimited
+	// KindUnavailable is a dependency (queue, RPC node, database) that failed, the request may be retried
+	KindUnavailable
+)
+
+// FieldError describes why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error of a given kind. Err is the cause, it is not shown to clients.
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  []FieldError
+	Err     error
+	// RetryAfter is how long a rate limited client should wait before retrying
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error of kind with a message
+func New(kind Kind, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// Invalid returns a validation error listing the rejected fields
+func Invalid(message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindInvalid, Message: message, Fields: fields}
+}
+
+// NotFound returns an error for a missing resource
+func NotFound(format string, args ...interface{}) *Error {
+	return New(KindNotFound, format, args...)
+}
+
+// Conflict returns an error for a request that clashes with the resource's current state
+func Conflict(format string, args ...interface{}) *Error {
+	return New(KindConflict, format, args...)
+}
+
+// Forbidden returns an error for a request the caller is not allowed to make
+func Forbidden(format string, args ...interface{}) *Error {
+	return New(KindForbidden, format, args...)
+}
+
+// Unauthorized returns an error for missing or wrong credentials
+func Unauthorized(format string, args ...interface{}) *Error {
+	return New(KindUnauthorized, format, args...)
+}
+
+// RateLimited returns an error for a client that must wait retryAfter before trying again
+func RateLimited(retryAfter time.Duration, format string, args ...interface{}) *Error {
+	e := New(KindRateLimited, format, args...)
+	e.RetryAfter = retryAfter
+	return e
+}
+
+// Unavailable wraps the failure of a dependency
+func Unavailable(err error, format string, args ...interface{}) *Error {
+	e := New(KindUnavailable, format, args...)
+	e.Err = err
+	return e
+}
+
+// KindOf returns the kind of the first Error in err's chain, KindInternal if there is none
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+// FieldsOf returns the rejected fields of the first Error in err's chain
+func FieldsOf(err error) []FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
+
+// Is reports whether err is a domain error of kind
+func Is(err error, kind Kind) bool {
+	return KindOf(err) == kind
+}
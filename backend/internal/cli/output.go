@@ -0,0 +1,107 @@
+// Package cli renders the output of aqua402ctl as aligned tables or JSON.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// ParseFormat checks an output format name
+func ParseFormat(s string) (string, error) {
+	switch s {
+	case FormatTable, FormatJSON:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid output format %q, expected table or json", s)
+}
+
+// Table is rows of text under column headers
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(columns ...string) *Table {
+	return &Table{Columns: columns}
+}
+
+// Add appends a row, values are formatted with Cell
+func (t *Table) Add(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, value := range values {
+		row[i] = Cell(value)
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// Write writes the table with its columns aligned
+func (t *Table) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.Columns, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// Print writes v as indented JSON, or table as a table. table is only built for the table format.
+func Print(w io.Writer, format string, v interface{}, table func() *Table) error {
+	if format == FormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	return table().Write(w)
+}
+
+// Cell formats a table value. Empty strings and nil show as -, unix times in seconds are left as they
+// are, use Time for them.
+func Cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+		// Tabs and newlines would break the alignment
+		return strings.Join(strings.Fields(v), " ")
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.UTC().Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// Time converts unix seconds for Cell, zero stays unset
+func Time(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
+
+// Seconds converts a duration in seconds for Cell
+func Seconds(seconds uint64) time.Duration {
+	return time.Duration(seconds) * time.Second
+}
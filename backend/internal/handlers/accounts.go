@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Pagga-Wallet/aqua402/internal/services/accounts"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AccountHandler struct {
+	service *accounts.Service
+	logger  *zap.Logger
+}
+
+func NewAccountHandler(service *accounts.Service, logger *zap.Logger) *AccountHandler {
+	return &AccountHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetPortfolio retrieves what an address is doing on the platform
+// @Summary      Get account portfolio
+// @Description  Aggregates the RFQs, auctions, quotes, bids, Aqua liquidity and credit lines of an address into open positions, historical fills, outstanding debt, realized interest paid and earned, and pending actions such as accepting a quote or repaying an installment due
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        address  path      string  true   "Borrower or lender address"
+// @Param        chain    query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200      {object}  accounts.Portfolio
+// @Failure      400      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
+// @Router       /accounts/{address} [get]
+func (h *AccountHandler) GetPortfolio(c echo.Context) error {
+	result, err := h.service.GetPortfolio(c.Request().Context(), chainID(c), c.Param("address"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
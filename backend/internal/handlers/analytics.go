@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/services/analytics"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AnalyticsHandler struct {
+	service *analytics.Service
+	logger  *zap.Logger
+}
+
+func NewAnalyticsHandler(service *analytics.Service, logger *zap.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetVolume retrieves analytics for volume
+// @Summary      Get volume by bucket
+// @Description  Number and requested volume of RFQs and auctions, and credit line volume opened, per bucket
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Param        from         query     int     false  "Range start, unix seconds (default: to - 30 days)"
+// @Param        to           query     int     false  "Range end, unix seconds (default: now)"
+// @Param        granularity  query     string  false  "Bucket granularity: hour, day, week, month (default: day)"
+// @Param        chain        query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200          {object}  analytics.VolumeReport
+// @Failure      400          {object}  handlers.Problem
+// @Failure      500          {object}  handlers.Problem
+// @Router       /analytics/volume [get]
+func (h *AnalyticsHandler) GetVolume(c echo.Context) error {
+	return h.report(c, func(ctx context.Context, r analytics.Range) (interface{}, error) {
+		return h.service.Volume(ctx, r)
+	})
+}
+
+// GetClearingRates retrieves analytics for clearing rates
+// @Summary      Get clearing rates by duration
+// @Description  Average rate in bps credit lines were opened at, per bucket and duration bucket (0-7d, 7-30d, 30-90d, 90d+)
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Param        from         query     int     false  "Range start, unix seconds (default: to - 30 days)"
+// @Param        to           query     int     false  "Range end, unix seconds (default: now)"
+// @Param        granularity  query     string  false  "Bucket granularity: hour, day, week, month (default: day)"
+// @Param        chain        query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200          {object}  analytics.ClearingRateReport
+// @Failure      400          {object}  handlers.Problem
+// @Failure      500          {object}  handlers.Problem
+// @Router       /analytics/clearing-rates [get]
+func (h *AnalyticsHandler) GetClearingRates(c echo.Context) error {
+	return h.report(c, func(ctx context.Context, r analytics.Range) (interface{}, error) {
+		return h.service.ClearingRates(ctx, r)
+	})
+}
+
+// GetLenderShare retrieves analytics for lender share
+// @Summary      Get lender market share
+// @Description  Credit line volume opened per lender and its share of the bucket total
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Param        from         query     int     false  "Range start, unix seconds (default: to - 30 days)"
+// @Param        to           query     int     false  "Range end, unix seconds (default: now)"
+// @Param        granularity  query     string  false  "Bucket granularity: hour, day, week, month (default: day)"
+// @Param        chain        query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200          {object}  analytics.LenderShareReport
+// @Failure      400          {object}  handlers.Problem
+// @Failure      500          {object}  handlers.Problem
+// @Router       /analytics/lender-share [get]
+func (h *AnalyticsHandler) GetLenderShare(c echo.Context) error {
+	return h.report(c, func(ctx context.Context, r analytics.Range) (interface{}, error) {
+		return h.service.LenderShare(ctx, r)
+	})
+}
+
+// GetQuoteFill retrieves analytics for quote fill
+// @Summary      Get quote-to-fill ratio
+// @Description  Requests, quotes (bids for auctions) and fills per market, with quotes per request and fills per quote
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Param        from         query     int     false  "Range start, unix seconds (default: to - 30 days)"
+// @Param        to           query     int     false  "Range end, unix seconds (default: now)"
+// @Param        granularity  query     string  false  "Bucket granularity: hour, day, week, month (default: day)"
+// @Param        chain        query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200          {object}  analytics.QuoteFillReport
+// @Failure      400          {object}  handlers.Problem
+// @Failure      500          {object}  handlers.Problem
+// @Router       /analytics/quote-fill [get]
+func (h *AnalyticsHandler) GetQuoteFill(c echo.Context) error {
+	return h.report(c, func(ctx context.Context, r analytics.Range) (interface{}, error) {
+		return h.service.QuoteFill(ctx, r)
+	})
+}
+
+// GetTimeToFirstQuote retrieves analytics for time to first quote
+// @Summary      Get time to first quote
+// @Description  Average, median and p90 seconds RFQs created in each bucket waited for their first quote
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Param        from         query     int     false  "Range start, unix seconds (default: to - 30 days)"
+// @Param        to           query     int     false  "Range end, unix seconds (default: now)"
+// @Param        granularity  query     string  false  "Bucket granularity: hour, day, week, month (default: day)"
+// @Param        chain        query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200          {object}  analytics.FirstQuoteReport
+// @Failure      400          {object}  handlers.Problem
+// @Failure      500          {object}  handlers.Problem
+// @Router       /analytics/time-to-first-quote [get]
+func (h *AnalyticsHandler) GetTimeToFirstQuote(c echo.Context) error {
+	return h.report(c, func(ctx context.Context, r analytics.Range) (interface{}, error) {
+		return h.service.TimeToFirstQuote(ctx, r)
+	})
+}
+
+// GetAquaUtilization retrieves analytics for aqua utilization
+// @Summary      Get Aqua liquidity utilization
+// @Description  Aqua liquidity movements per bucket, running connected and reserved totals and their ratio
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Param        from         query     int     false  "Range start, unix seconds (default: to - 30 days)"
+// @Param        to           query     int     false  "Range end, unix seconds (default: now)"
+// @Param        granularity  query     string  false  "Bucket granularity: hour, day, week, month (default: day)"
+// @Param        chain        query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200          {object}  analytics.AquaUtilizationReport
+// @Failure      400          {object}  handlers.Problem
+// @Failure      500          {object}  handlers.Problem
+// @Router       /analytics/aqua-utilization [get]
+func (h *AnalyticsHandler) GetAquaUtilization(c echo.Context) error {
+	return h.report(c, func(ctx context.Context, r analytics.Range) (interface{}, error) {
+		return h.service.AquaUtilization(ctx, r)
+	})
+}
+
+// report parses the time range of the request and renders the report built by fetch
+func (h *AnalyticsHandler) report(c echo.Context, fetch func(context.Context, analytics.Range) (interface{}, error)) error {
+	r, err := analytics.ParseRange(c.QueryParam("from"), c.QueryParam("to"), c.QueryParam("granularity"), time.Now())
+	if err != nil {
+		return err
+	}
+	r.ChainID = chainID(c)
+
+	result, err := fetch(c.Request().Context(), r)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
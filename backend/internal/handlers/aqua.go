@@ -27,23 +27,28 @@ func NewAquaHandler(service *aqua.Service, logger *zap.Logger) *AquaHandler {
 // @Accept       json
 // @Produce      json
 // @Param        request  body      aqua.ConnectLiquidityRequest  true  "Liquidity data"
+// @Param        X-Aqua402-Address    header    string  false  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     false  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  false  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Param        chain    query     string                        false  "Chain ID or name, defaults to the default chain"
 // @Success      200      {object}  map[string]string
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Failure      400      {object}  handlers.Problem
+// @Failure      401      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
 // @Router       /aqua/liquidity [post]
 func (h *AquaHandler) ConnectLiquidity(c echo.Context) error {
 	var req aqua.ConnectLiquidityRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request",
-		})
+	if err := bind(c, &req); err != nil {
+		return err
 	}
+	if err := requireSigner(c, "lender_address", req.LenderAddress); err != nil {
+		return err
+	}
+	req.ChainID = chainID(c)
 
 	if err := h.service.ConnectLiquidity(c.Request().Context(), req); err != nil {
-		h.logger.Error("Failed to connect liquidity", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
@@ -58,23 +63,28 @@ func (h *AquaHandler) ConnectLiquidity(c echo.Context) error {
 // @Accept       json
 // @Produce      json
 // @Param        request  body      aqua.WithdrawLiquidityRequest  true  "Withdrawal data"
+// @Param        X-Aqua402-Address    header    string  false  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     false  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  false  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Param        chain    query     string                         false  "Chain ID or name, defaults to the default chain"
 // @Success      200      {object}  map[string]string
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Failure      400      {object}  handlers.Problem
+// @Failure      401      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
 // @Router       /aqua/withdraw [post]
 func (h *AquaHandler) WithdrawLiquidity(c echo.Context) error {
 	var req aqua.WithdrawLiquidityRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request",
-		})
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+	if err := requireSigner(c, "lender_address", req.LenderAddress); err != nil {
+		return err
 	}
+	req.ChainID = chainID(c)
 
 	if err := h.service.WithdrawLiquidity(c.Request().Context(), req); err != nil {
-		h.logger.Error("Failed to withdraw liquidity", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
@@ -89,18 +99,16 @@ func (h *AquaHandler) WithdrawLiquidity(c echo.Context) error {
 // @Accept       json
 // @Produce      json
 // @Param        address  path      string  true  "Lender address"
+// @Param        chain    query     string  false  "Chain ID or name, defaults to the default chain"
 // @Success      200      {object}  map[string]interface{}
-// @Failure      500      {object}  map[string]string
+// @Failure      500      {object}  handlers.Problem
 // @Router       /aqua/liquidity/{address} [get]
 func (h *AquaHandler) GetAvailableLiquidity(c echo.Context) error {
 	address := c.Param("address")
 
-	result, err := h.service.GetAvailableLiquidity(c.Request().Context(), address)
+	result, err := h.service.GetAvailableLiquidity(c.Request().Context(), chainID(c), address)
 	if err != nil {
-		h.logger.Error("Failed to get liquidity", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, result)
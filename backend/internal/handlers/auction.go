@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/Pagga-Wallet/aqua402/internal/repositories"
 	"github.com/Pagga-Wallet/aqua402/internal/services/auction"
@@ -32,24 +31,30 @@ func NewAuctionHandler(service *auction.Service, logger *zap.Logger) *AuctionHan
 // @Accept       json
 // @Produce      json
 // @Param        request  body      auction.CreateAuctionRequest  true  "Auction data"
+// @Param        Idempotency-Key  header    string  false  "Retries with the same key replay the first response"
+// @Param        X-Aqua402-Address    header    string  false  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     false  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  false  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Param        chain    query     string                        false  "Chain ID or name, defaults to the default chain"
 // @Success      201      {object}  map[string]interface{}
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Failure      400      {object}  handlers.Problem
+// @Failure      401      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
 // @Router       /auction [post]
 func (h *AuctionHandler) CreateAuction(c echo.Context) error {
 	var req auction.CreateAuctionRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request",
-		})
+	if err := bind(c, &req); err != nil {
+		return err
 	}
+	if err := requireSigner(c, "borrower_address", req.BorrowerAddress); err != nil {
+		return err
+	}
+	req.ChainID = chainID(c)
 
 	result, err := h.service.CreateAuction(c.Request().Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to create auction", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, result)
@@ -63,23 +68,29 @@ func (h *AuctionHandler) CreateAuction(c echo.Context) error {
 // @Produce      json
 // @Param        id       path      int                true  "Auction ID"
 // @Param        request  body      auction.BidRequest  true  "Bid data"
+// @Param        Idempotency-Key  header    string  false  "Retries with the same key replay the first response"
+// @Param        X-Aqua402-Address    header    string  false  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     false  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  false  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Param        chain    query     string             false  "Chain ID or name, defaults to the default chain"
 // @Success      200      {object}  map[string]string
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Failure      400      {object}  handlers.Problem
+// @Failure      401      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
 // @Router       /auction/{id}/bid [post]
 func (h *AuctionHandler) PlaceBid(c echo.Context) error {
 	var req auction.BidRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request",
-		})
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+	if err := requireSigner(c, "lender_address", req.LenderAddress); err != nil {
+		return err
 	}
+	req.ChainID = chainID(c)
 
 	if err := h.service.PlaceBid(c.Request().Context(), req); err != nil {
-		h.logger.Error("Failed to place bid", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
@@ -94,25 +105,20 @@ func (h *AuctionHandler) PlaceBid(c echo.Context) error {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Auction ID"
+// @Param        chain  query     string  false  "Chain ID or name, defaults to the default chain"
 // @Success      200  {object}  repositories.AuctionModel
-// @Failure      400  {object}  map[string]string
-// @Failure      404  {object}  map[string]string
+// @Failure      400  {object}  handlers.Problem
+// @Failure      404  {object}  handlers.Problem
 // @Router       /auction/{id} [get]
 func (h *AuctionHandler) GetAuction(c echo.Context) error {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 64)
+	id, err := pathID(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid Auction ID",
-		})
+		return err
 	}
 
-	result, err := h.service.GetAuction(c.Request().Context(), id)
+	result, err := h.service.GetAuction(c.Request().Context(), chainID(c), id)
 	if err != nil {
-		h.logger.Error("Failed to get auction", zap.Error(err))
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Auction not found",
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, result)
@@ -120,42 +126,81 @@ func (h *AuctionHandler) GetAuction(c echo.Context) error {
 
 // ListAuctions retrieves a list of auctions
 // @Summary      List Auctions
-// @Description  Returns a list of all auctions with pagination
+// @Description  Returns a page of auctions. X-Total-Count holds the number of matching auctions and X-Next-Cursor continues the list.
 // @Tags         Auction
 // @Accept       json
 // @Produce      json
-// @Param        limit   query     int  false  "Record limit"  default(20)
-// @Param        offset  query     int  false  "Offset"       default(0)
-// @Success      200     {array}   repositories.AuctionModel
-// @Failure      500     {object}  map[string]string
+// @Param        status           query     string  false  "Status"
+// @Param        borrower         query     string  false  "Borrower address"
+// @Param        lender           query     string  false  "Lender address (auctions the lender bid on)"
+// @Param        amount_min       query     string  false  "Minimum amount in wei"
+// @Param        amount_max       query     string  false  "Maximum amount in wei"
+// @Param        duration_min     query     int     false  "Minimum duration in seconds"
+// @Param        duration_max     query     int     false  "Maximum duration in seconds"
+// @Param        created_from     query     int     false  "Created at or after (unix seconds)"
+// @Param        created_to       query     int     false  "Created before (unix seconds)"
+// @Param        sort             query     string  false  "Sort field (created_at, end_time, amount, duration, id), prefixed with - for descending"  default(-created_at)
+// @Param        limit            query     int     false  "Page size (1-100)"  default(20)
+// @Param        cursor           query     string  false  "X-Next-Cursor of the previous page"
+// @Param        chain            query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200              {array}   repositories.AuctionModel
+// @Failure      400              {object}  handlers.Problem
+// @Failure      500              {object}  handlers.Problem
 // @Router       /auction [get]
 func (h *AuctionHandler) ListAuctions(c echo.Context) error {
-	limitStr := c.QueryParam("limit")
-	offsetStr := c.QueryParam("offset")
+	q, err := ParseListQuery(c.QueryParams())
+	if err != nil {
+		return err
+	}
+	q.ChainID = chainID(c)
 
-	limit := 20
-	offset := 0
+	result, err := h.service.ListAuctions(c.Request().Context(), q)
+	if err != nil {
+		return err
+	}
+
+	return writePage(c, result)
+}
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
+// ListBids retrieves the bids placed on an auction
+// @Summary      List bids
+// @Description  Returns a page of the bids placed on an auction. Status is winning or open and amounts are bid limits.
+// @Tags         Auction
+// @Accept       json
+// @Produce      json
+// @Param        id               path      int     true   "Auction ID"
+// @Param        status           query     string  false  "Status (winning, open)"
+// @Param        lender           query     string  false  "Lender address"
+// @Param        amount_min       query     string  false  "Minimum limit in wei"
+// @Param        amount_max       query     string  false  "Maximum limit in wei"
+// @Param        created_from     query     int     false  "Placed at or after (unix seconds)"
+// @Param        created_to       query     int     false  "Placed before (unix seconds)"
+// @Param        sort             query     string  false  "Sort field (created_at, amount, rate_bps, id), prefixed with - for descending"  default(-created_at)
+// @Param        limit            query     int     false  "Page size (1-100)"  default(20)
+// @Param        cursor           query     string  false  "X-Next-Cursor of the previous page"
+// @Param        chain            query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200              {array}   repositories.BidModel
+// @Failure      400              {object}  handlers.Problem
+// @Failure      500              {object}  handlers.Problem
+// @Router       /auction/{id}/bids [get]
+func (h *AuctionHandler) ListBids(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
 	}
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
-		}
+
+	q, err := ParseListQuery(c.QueryParams())
+	if err != nil {
+		return err
 	}
+	q.ChainID = chainID(c)
 
-	result, err := h.service.ListAuctions(c.Request().Context(), limit, offset)
+	result, err := h.service.ListBids(c.Request().Context(), id, q)
 	if err != nil {
-		h.logger.Error("Failed to list auctions", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return writePage(c, result)
 }
 
 // FinalizeAuction finalizes an auction
@@ -165,17 +210,18 @@ func (h *AuctionHandler) ListAuctions(c echo.Context) error {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Auction ID"
+// @Param        chain  query     string  false  "Chain ID or name, defaults to the default chain"
 // @Success      200  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      500  {object}  handlers.Problem
 // @Router       /auction/{id}/finalize [post]
 func (h *AuctionHandler) FinalizeAuction(c echo.Context) error {
-	_ = c.Param("id") // TODO: Parse auctionID as uint64
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
 
-	if err := h.service.FinalizeAuction(c.Request().Context(), 0); err != nil {
-		h.logger.Error("Failed to finalize auction", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+	if err := h.service.FinalizeAuction(c.Request().Context(), chainID(c), id); err != nil {
+		return err
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/labstack/echo/v4"
+)
+
+type ChainHandler struct {
+	registry *chains.Registry
+}
+
+func NewChainHandler(registry *chains.Registry) *ChainHandler {
+	return &ChainHandler{registry: registry}
+}
+
+// ListChains lists the chains the API serves
+// @Summary      List chains
+// @Description  Returns the configured chains with their contracts, confirmation depth and tokens. Every other
+// @Description  endpoint takes a chain query parameter, the ID or name of one of them, and defaults to the first
+// @Description  chain listed.
+// @Tags         Chains
+// @Produce      json
+// @Success      200  {array}   chains.Chain
+// @Router       /chains [get]
+func (h *ChainHandler) ListChains(c echo.Context) error {
+	all := h.registry.All()
+	result := make([]chains.Chain, 0, len(all))
+	// The default chain comes first
+	result = append(result, h.registry.Default().Public())
+	for _, chain := range all {
+		if chain != h.registry.Default() {
+			result = append(result, chain.Public())
+		}
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// chainID returns the ID of the chain resolved by middleware.ChainMiddleware, the local Hardhat chain
+// when the middleware is not installed
+func chainID(c echo.Context) uint64 {
+	if chain, ok := chains.FromContext(c.Request().Context()); ok {
+		return chain.ID
+	}
+	return chains.HardhatChainID
+}
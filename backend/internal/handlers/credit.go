@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Pagga-Wallet/aqua402/internal/services/credit"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CreditHandler struct {
+	service *credit.Service
+	logger  *zap.Logger
+}
+
+func NewCreditHandler(service *credit.Service, logger *zap.Logger) *CreditHandler {
+	return &CreditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetSchedule retrieves the repayment schedule of a credit line
+// @Summary      Get credit line schedule
+// @Description  Replays draws and repayments of a credit line and returns outstanding principal, accrued interest and upcoming installments
+// @Tags         Credit
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Credit line ID"
+// @Param        chain  query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200  {object}  credit.Schedule
+// @Failure      400  {object}  handlers.Problem
+// @Failure      404  {object}  handlers.Problem
+// @Failure      500  {object}  handlers.Problem
+// @Router       /credit-lines/{id}/schedule [get]
+func (h *CreditHandler) GetSchedule(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.GetSchedule(c.Request().Context(), chainID(c), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetObligations retrieves what a borrower owes across credit lines
+// @Summary      Get borrower obligations
+// @Description  Returns outstanding principal, accrued interest and next due amounts for every credit line of a borrower
+// @Tags         Credit
+// @Accept       json
+// @Produce      json
+// @Param        address  path      string  true  "Borrower address"
+// @Param        chain    query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200      {object}  credit.Obligations
+// @Failure      500      {object}  handlers.Problem
+// @Router       /borrowers/{address}/obligations [get]
+func (h *CreditHandler) GetObligations(c echo.Context) error {
+	address := c.Param("address")
+
+	result, err := h.service.GetObligations(c.Request().Context(), chainID(c), address)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
 	"github.com/Pagga-Wallet/aqua402/internal/services/faucet"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -22,40 +24,66 @@ func NewFaucetHandler(service *faucet.Service, logger *zap.Logger) *FaucetHandle
 
 // RequestTokens requests test tokens from the faucet
 // @Summary      Request test tokens
-// @Description  Requests test ETH tokens from the faucet for the specified address
+// @Description  Requests test ETH, or a token listed by GET /faucet/tokens, for the specified address. The amount is capped, each address
+// @Description  and client IP has a cooldown and the faucet has a daily budget: refusals are 429 with Retry-After.
+// @Description  A missing or wrong challenge, or a faucet at its balance floor, is 403.
+// @Description  The faucet dispenses on one chain (FAUCET_CHAIN), a chain parameter naming another one is 400.
+// @Description  The grant is followed with GET /transactions/{id} by its tx_id, tx_hash is empty until it is sent.
 // @Tags         Faucet
 // @Accept       json
 // @Produce      json
 // @Param        request  body      faucet.RequestTokensRequest  true  "Faucet request"
-// @Success      200      {object} map[string]string
-// @Failure      400      {object} map[string]string
-// @Failure      500      {object} map[string]string
+// @Param        chain    query     string  false  "Chain ID or name, defaults to the faucet's chain"
+// @Param        Idempotency-Key  header    string  false  "Retries with the same key replay the first response"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      429      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
 // @Router       /faucet [post]
 func (h *FaucetHandler) RequestTokens(c echo.Context) error {
 	var req faucet.RequestTokensRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request",
-		})
+	if err := bind(c, &req); err != nil {
+		return err
 	}
 
-	// Default amount if not specified
-	if req.Amount == "" {
-		req.Amount = "1.0" // Default 1 ETH
+	if c.QueryParam("chain") != "" && chainID(c) != h.service.ChainID() {
+		return apperrors.Invalid("invalid query parameter", apperrors.FieldError{
+			Field:   "chain",
+			Message: fmt.Sprintf("the faucet dispenses on chain %d only", h.service.ChainID()),
+		})
 	}
+	req.IP = c.RealIP()
 
-	txHash, err := h.service.RequestTokens(c.Request().Context(), req)
+	grant, err := h.service.RequestTokens(c.Request().Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to request tokens", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"status":  "success",
-		"tx_hash": txHash,
-		"address": req.Address,
-		"amount":  req.Amount,
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":   "success",
+		"chain_id": grant.ChainID,
+		"tx_id":    grant.TxID,
+		"tx_hash":  grant.TxHash,
+		"address":  req.Address,
+		"token":    grant.Token,
+		"amount":   grant.Amount,
 	})
 }
+
+// ListTokens lists the assets the faucet dispenses
+// @Summary      List faucet tokens
+// @Description  Lists ETH and the ERC20 tokens the faucet dispenses, with the most a request may ask for,
+// @Description  the daily budget and the per-address and per-IP cooldowns in seconds
+// @Tags         Faucet
+// @Produce      json
+// @Success      200  {array}   faucet.TokenInfo
+// @Failure      503  {object}  handlers.Problem
+// @Router       /faucet/tokens [get]
+func (h *FaucetHandler) ListTokens(c echo.Context) error {
+	tokens, err := h.service.ListTokens(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+// Response headers of the list endpoints
+const (
+	HeaderTotalCount = "X-Total-Count"
+	HeaderNextCursor = "X-Next-Cursor"
+)
+
+// ParseListQuery reads the filter, sort, limit and cursor query parameters shared by the list endpoints.
+// sort is a field name, prefixed with - for descending order. It defaults to -created_at.
+func ParseListQuery(params url.Values) (repositories.ListQuery, error) {
+	q := repositories.ListQuery{
+		Status:    params.Get(repositories.FilterStatus),
+		Borrower:  params.Get(repositories.FilterBorrower),
+		Lender:    params.Get(repositories.FilterLender),
+		MinAmount: params.Get(repositories.FilterAmountMin),
+		MaxAmount: params.Get(repositories.FilterAmountMax),
+		Sort:      params.Get("sort"),
+		Desc:      true,
+	}
+
+	for name, amount := range map[string]string{repositories.FilterAmountMin: q.MinAmount, repositories.FilterAmountMax: q.MaxAmount} {
+		if amount == "" {
+			continue
+		}
+		if _, err := validation.ParseAmount(amount, validation.WeiDecimals); err != nil {
+			return q, fmt.Errorf("%w: %s %s", repositories.ErrInvalidListQuery, name, err)
+		}
+	}
+
+	var err error
+	if q.MinDuration, err = uintParam(params, repositories.FilterDurationMin, 64); err != nil {
+		return q, err
+	}
+	if q.MaxDuration, err = uintParam(params, repositories.FilterDurationMax, 64); err != nil {
+		return q, err
+	}
+	collateralType, err := uintParam(params, repositories.FilterCollateralType, 8)
+	if err != nil {
+		return q, err
+	}
+	if collateralType != nil {
+		v := uint8(*collateralType)
+		q.CollateralType = &v
+	}
+	if q.CreatedFrom, err = intParam(params, repositories.FilterCreatedFrom); err != nil {
+		return q, err
+	}
+	if q.CreatedTo, err = intParam(params, repositories.FilterCreatedTo); err != nil {
+		return q, err
+	}
+
+	if q.Sort != "" {
+		q.Desc = strings.HasPrefix(q.Sort, "-")
+		q.Sort = strings.TrimPrefix(q.Sort, "-")
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > repositories.MaxListLimit {
+			return q, fmt.Errorf("%w: limit must be between 1 and %d", repositories.ErrInvalidListQuery, repositories.MaxListLimit)
+		}
+		q.Limit = l
+	}
+
+	if cursor := params.Get("cursor"); cursor != "" {
+		if q.After, err = repositories.DecodeCursor(cursor); err != nil {
+			return q, err
+		}
+	}
+
+	return q, nil
+}
+
+// writePage writes a page of a list as a JSON array with its total count and next cursor headers
+func writePage[T any](c echo.Context, page *repositories.Page[T]) error {
+	c.Response().Header().Set(HeaderTotalCount, strconv.FormatUint(page.Total, 10))
+	if page.NextCursor != "" {
+		c.Response().Header().Set(HeaderNextCursor, page.NextCursor)
+	}
+	return c.JSON(http.StatusOK, page.Items)
+}
+
+func uintParam(params url.Values, name string, bits int) (*uint64, error) {
+	s := params.Get(name)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseUint(s, 10, bits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be a non-negative integer", repositories.ErrInvalidListQuery, name)
+	}
+	return &v, nil
+}
+
+func intParam(params url.Values, name string) (*int64, error) {
+	s := params.Get(name)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be a unix timestamp", repositories.ErrInvalidListQuery, name)
+	}
+	return &v, nil
+}
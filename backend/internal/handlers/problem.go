@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// MIMEProblemJSON is the content type of error responses (RFC 7807)
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details error response
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Errors   []apperrors.FieldError `json:"errors,omitempty"`
+}
+
+var kindStatus = map[apperrors.Kind]int{
+	apperrors.KindInvalid:      http.StatusBadRequest,
+	apperrors.KindUnauthorized: http.StatusUnauthorized,
+	apperrors.KindForbidden:    http.StatusForbidden,
+	apperrors.KindNotFound:     http.StatusNotFound,
+	apperrors.KindConflict:     http.StatusConflict,
+	apperrors.KindRateLimited:  http.StatusTooManyRequests,
+	apperrors.KindUnavailable:  http.StatusServiceUnavailable,
+}
+
+// NewProblem maps an error returned by a handler to problem details.
+// Causes of unavailable and internal errors are left out of the response.
+func NewProblem(err error) *Problem {
+	p := &Problem{Type: "about:blank", Status: http.StatusInternalServerError}
+
+	var appErr *apperrors.Error
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &appErr) && appErr.Kind != apperrors.KindInternal:
+		p.Status = kindStatus[appErr.Kind]
+		p.Errors = appErr.Fields
+		if appErr.Kind == apperrors.KindUnavailable {
+			p.Detail = appErr.Message
+		} else {
+			p.Detail = err.Error()
+		}
+	case errors.As(err, &httpErr):
+		p.Status = httpErr.Code
+		if p.Status < http.StatusInternalServerError {
+			p.Detail = fmt.Sprint(httpErr.Message)
+		}
+	}
+
+	p.Title = http.StatusText(p.Status)
+	return p
+}
+
+// ErrorHandler renders every error returned by handlers and middleware as problem+json
+func ErrorHandler(logger *zap.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		p := NewProblem(err)
+		p.Instance = c.Request().URL.Path
+		var appErr *apperrors.Error
+		if errors.As(err, &appErr) && appErr.RetryAfter > 0 {
+			c.Response().Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(appErr.RetryAfter.Seconds())), 10))
+		}
+		if p.Status >= http.StatusInternalServerError {
+			logger.Error("Request failed",
+				zap.String("method", c.Request().Method),
+				zap.String("path", c.Path()),
+				zap.Error(err))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, MIMEProblemJSON)
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(p.Status)
+		} else {
+			err = c.JSON(p.Status, p)
+		}
+		if err != nil {
+			logger.Error("Failed to write error response", zap.Error(err))
+		}
+	}
+}
+
+// bind decodes the request body into req, rejecting malformed JSON as a validation error
+func bind(c echo.Context, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return apperrors.Invalid(fmt.Sprintf("invalid request body: %v", httpErr.Message))
+		}
+		return apperrors.Invalid("invalid request body")
+	}
+	return nil
+}
+
+// pathID parses the numeric id path parameter
+func pathID(c echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, apperrors.Invalid("invalid path parameter", apperrors.FieldError{Field: "id", Message: "must be a non-negative integer"})
+	}
+	return id, nil
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/Pagga-Wallet/aqua402/internal/repositories"
 	"github.com/Pagga-Wallet/aqua402/internal/services/rfq"
@@ -32,24 +31,30 @@ func NewRFQHandler(service *rfq.Service, logger *zap.Logger) *RFQHandler {
 // @Accept       json
 // @Produce      json
 // @Param        request  body      rfq.CreateRFQRequest  true  "RFQ data"
+// @Param        Idempotency-Key  header    string  false  "Retries with the same key replay the first response"
+// @Param        X-Aqua402-Address    header    string  false  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     false  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  false  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Param        chain    query     string                false  "Chain ID or name, defaults to the default chain"
 // @Success      201      {object}  repositories.RFQModel
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Failure      400      {object}  handlers.Problem
+// @Failure      401      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
 // @Router       /rfq [post]
 func (h *RFQHandler) CreateRFQ(c echo.Context) error {
 	var req rfq.CreateRFQRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request",
-		})
+	if err := bind(c, &req); err != nil {
+		return err
 	}
+	if err := requireSigner(c, "borrower_address", req.BorrowerAddress); err != nil {
+		return err
+	}
+	req.ChainID = chainID(c)
 
 	result, err := h.service.CreateRFQ(c.Request().Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to create RFQ", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, result)
@@ -62,25 +67,20 @@ func (h *RFQHandler) CreateRFQ(c echo.Context) error {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "RFQ ID"
+// @Param        chain  query     string  false  "Chain ID or name, defaults to the default chain"
 // @Success      200  {object}  repositories.RFQModel
-// @Failure      400  {object}  map[string]string
-// @Failure      404  {object}  map[string]string
+// @Failure      400  {object}  handlers.Problem
+// @Failure      404  {object}  handlers.Problem
 // @Router       /rfq/{id} [get]
 func (h *RFQHandler) GetRFQ(c echo.Context) error {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 64)
+	id, err := pathID(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid RFQ ID",
-		})
+		return err
 	}
 
-	result, err := h.service.GetRFQ(c.Request().Context(), id)
+	result, err := h.service.GetRFQ(c.Request().Context(), chainID(c), id)
 	if err != nil {
-		h.logger.Error("Failed to get RFQ", zap.Error(err))
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "RFQ not found",
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, result)
@@ -88,42 +88,82 @@ func (h *RFQHandler) GetRFQ(c echo.Context) error {
 
 // ListRFQs retrieves a list of RFQ requests
 // @Summary      List RFQs
-// @Description  Returns a list of all RFQ requests with pagination
+// @Description  Returns a page of RFQ requests. X-Total-Count holds the number of matching RFQs and X-Next-Cursor continues the list.
 // @Tags         RFQ
 // @Accept       json
 // @Produce      json
-// @Param        limit   query     int  false  "Record limit"  default(20)
-// @Param        offset  query     int  false  "Offset"       default(0)
-// @Success      200     {array}   repositories.RFQModel
-// @Failure      500     {object}  map[string]string
+// @Param        status           query     string  false  "Status"
+// @Param        borrower         query     string  false  "Borrower address"
+// @Param        lender           query     string  false  "Lender address (RFQs the lender quoted on)"
+// @Param        amount_min       query     string  false  "Minimum amount in wei"
+// @Param        amount_max       query     string  false  "Maximum amount in wei"
+// @Param        duration_min     query     int     false  "Minimum duration in seconds"
+// @Param        duration_max     query     int     false  "Maximum duration in seconds"
+// @Param        collateral_type  query     int     false  "Collateral type"
+// @Param        created_from     query     int     false  "Created at or after (unix seconds)"
+// @Param        created_to       query     int     false  "Created before (unix seconds)"
+// @Param        sort             query     string  false  "Sort field (created_at, amount, duration, id), prefixed with - for descending"  default(-created_at)
+// @Param        limit            query     int     false  "Page size (1-100)"  default(20)
+// @Param        cursor           query     string  false  "X-Next-Cursor of the previous page"
+// @Param        chain            query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200              {array}   repositories.RFQModel
+// @Failure      400              {object}  handlers.Problem
+// @Failure      500              {object}  handlers.Problem
 // @Router       /rfq [get]
 func (h *RFQHandler) ListRFQs(c echo.Context) error {
-	limitStr := c.QueryParam("limit")
-	offsetStr := c.QueryParam("offset")
+	q, err := ParseListQuery(c.QueryParams())
+	if err != nil {
+		return err
+	}
+	q.ChainID = chainID(c)
+
+	result, err := h.service.ListRFQs(c.Request().Context(), q)
+	if err != nil {
+		return err
+	}
 
-	limit := 20
-	offset := 0
+	return writePage(c, result)
+}
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
+// ListQuotes retrieves the quotes submitted on an RFQ
+// @Summary      List quotes
+// @Description  Returns a page of the quotes submitted on an RFQ. Status is accepted or open and amounts are quote limits.
+// @Tags         RFQ
+// @Accept       json
+// @Produce      json
+// @Param        id               path      int     true   "RFQ ID"
+// @Param        status           query     string  false  "Status (accepted, open)"
+// @Param        lender           query     string  false  "Lender address"
+// @Param        amount_min       query     string  false  "Minimum limit in wei"
+// @Param        amount_max       query     string  false  "Maximum limit in wei"
+// @Param        created_from     query     int     false  "Submitted at or after (unix seconds)"
+// @Param        created_to       query     int     false  "Submitted before (unix seconds)"
+// @Param        sort             query     string  false  "Sort field (created_at, amount, rate_bps, id), prefixed with - for descending"  default(-created_at)
+// @Param        limit            query     int     false  "Page size (1-100)"  default(20)
+// @Param        cursor           query     string  false  "X-Next-Cursor of the previous page"
+// @Param        chain            query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200              {array}   repositories.QuoteModel
+// @Failure      400              {object}  handlers.Problem
+// @Failure      500              {object}  handlers.Problem
+// @Router       /rfq/{id}/quotes [get]
+func (h *RFQHandler) ListQuotes(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
 	}
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
-		}
+
+	q, err := ParseListQuery(c.QueryParams())
+	if err != nil {
+		return err
 	}
+	q.ChainID = chainID(c)
 
-	result, err := h.service.ListRFQs(c.Request().Context(), limit, offset)
+	result, err := h.service.ListQuotes(c.Request().Context(), id, q)
 	if err != nil {
-		h.logger.Error("Failed to list RFQs", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return writePage(c, result)
 }
 
 // SubmitQuote submits a quote for RFQ
@@ -134,23 +174,29 @@ func (h *RFQHandler) ListRFQs(c echo.Context) error {
 // @Produce      json
 // @Param        id       path      int              true  "RFQ ID"
 // @Param        request  body      rfq.QuoteRequest  true  "Quote data"
+// @Param        Idempotency-Key  header    string  false  "Retries with the same key replay the first response"
+// @Param        X-Aqua402-Address    header    string  false  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     false  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  false  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Param        chain    query     string           false  "Chain ID or name, defaults to the default chain"
 // @Success      200      {object}  map[string]string
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Failure      400      {object}  handlers.Problem
+// @Failure      401      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
 // @Router       /rfq/{id}/quote [post]
 func (h *RFQHandler) SubmitQuote(c echo.Context) error {
 	var req rfq.QuoteRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request",
-		})
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+	if err := requireSigner(c, "lender_address", req.LenderAddress); err != nil {
+		return err
 	}
+	req.ChainID = chainID(c)
 
 	if err := h.service.SubmitQuote(c.Request().Context(), req); err != nil {
-		h.logger.Error("Failed to submit quote", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
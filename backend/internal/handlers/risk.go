@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/risk"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// Import for Swagger generation
+var _ = repositories.LiquidationCaseModel{}
+
+type RiskHandler struct {
+	service *risk.Service
+	logger  *zap.Logger
+}
+
+func NewRiskHandler(service *risk.Service, logger *zap.Logger) *RiskHandler {
+	return &RiskHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetCreditLineRisk retrieves the risk status of a credit line
+// @Summary      Get credit line risk
+// @Description  Returns whether a credit line is current, in grace, delinquent or defaulted
+// @Tags         Risk
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Credit line ID"
+// @Param        chain  query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200  {object}  repositories.RiskStatusModel
+// @Failure      400  {object}  handlers.Problem
+// @Failure      404  {object}  handlers.Problem
+// @Router       /credit-lines/{id}/risk [get]
+func (h *RiskHandler) GetCreditLineRisk(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.GetRiskStatus(c.Request().Context(), chainID(c), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ListLiquidationCases retrieves liquidation cases
+// @Summary      List liquidation cases
+// @Description  Returns liquidation cases opened for defaulted collateralized credit lines
+// @Tags         Risk
+// @Accept       json
+// @Produce      json
+// @Param        X-Operator-Token  header    string  true   "Operator token"
+// @Param        status            query     string  false  "Case status (pending_approval, approved, rejected, executing, executed, failed)"
+// @Param        chain             query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200               {array}   repositories.LiquidationCaseModel
+// @Failure      401               {object}  handlers.Problem
+// @Failure      500               {object}  handlers.Problem
+// @Router       /liquidations [get]
+func (h *RiskHandler) ListLiquidationCases(c echo.Context) error {
+	result, err := h.service.ListCases(c.Request().Context(), chainID(c), c.QueryParam("status"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetLiquidationCase retrieves the liquidation case of a credit line
+// @Summary      Get liquidation case
+// @Description  Returns the liquidation case of a credit line
+// @Tags         Risk
+// @Accept       json
+// @Produce      json
+// @Param        id                path      int     true   "Credit line ID"
+// @Param        X-Operator-Token  header    string  true   "Operator token"
+// @Param        chain             query     string  false  "Chain ID or name, defaults to the default chain"
+// @Success      200               {object}  repositories.LiquidationCaseModel
+// @Failure      400               {object}  handlers.Problem
+// @Failure      401               {object}  handlers.Problem
+// @Failure      404               {object}  handlers.Problem
+// @Router       /liquidations/{id} [get]
+func (h *RiskHandler) GetLiquidationCase(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.GetCase(c.Request().Context(), chainID(c), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ApproveLiquidation approves a pending liquidation case
+// @Summary      Approve liquidation
+// @Description  Operator approval of a pending liquidation case; the worker then frees the lender's reserved liquidity through AquaIntegration.releaseLiquidity
+// @Tags         Risk
+// @Accept       json
+// @Produce      json
+// @Param        id                path      int                  true  "Credit line ID"
+// @Param        X-Operator-Token  header    string               true  "Operator token"
+// @Param        request           body      risk.ApproveRequest  true  "Approval"
+// @Param        chain             query     string               false  "Chain ID or name, defaults to the default chain"
+// @Success      200               {object}  repositories.LiquidationCaseModel
+// @Failure      400               {object}  handlers.Problem
+// @Failure      404               {object}  handlers.Problem
+// @Failure      409               {object}  handlers.Problem
+// @Router       /liquidations/{id}/approve [post]
+func (h *RiskHandler) ApproveLiquidation(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	var req risk.ApproveRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	result, err := h.service.ApproveCase(c.Request().Context(), chainID(c), id, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// RejectLiquidation rejects a pending liquidation case
+// @Summary      Reject liquidation
+// @Description  Operator rejection of a pending liquidation case, leaving the collateral untouched
+// @Tags         Risk
+// @Accept       json
+// @Produce      json
+// @Param        id                path      int                 true  "Credit line ID"
+// @Param        X-Operator-Token  header    string              true  "Operator token"
+// @Param        request           body      risk.RejectRequest  true  "Rejection"
+// @Param        chain             query     string              false  "Chain ID or name, defaults to the default chain"
+// @Success      200               {object}  repositories.LiquidationCaseModel
+// @Failure      400               {object}  handlers.Problem
+// @Failure      404               {object}  handlers.Problem
+// @Failure      409               {object}  handlers.Problem
+// @Router       /liquidations/{id}/reject [post]
+func (h *RiskHandler) RejectLiquidation(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	var req risk.RejectRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	result, err := h.service.RejectCase(c.Request().Context(), chainID(c), id, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
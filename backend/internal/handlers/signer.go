@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+// requireSigner checks that the request acts for the wallet that signed it, the "address" set by
+// middleware.AuthMiddleware. A malformed address is left to the request's validation, and an unsigned
+// request let through by middleware.OptionalAuthMiddleware is not checked.
+func requireSigner(c echo.Context, field, address string) error {
+	if unsigned, _ := c.Get("unsigned").(bool); unsigned || !common.IsHexAddress(address) {
+		return nil
+	}
+	signed := signer(c)
+	if signed == "" || common.HexToAddress(signed) != common.HexToAddress(address) {
+		return apperrors.Forbidden("%s must be the wallet that signed the request", field)
+	}
+	return nil
+}
+
+// signer returns the wallet that signed the request, empty on routes without middleware.AuthMiddleware
+func signer(c echo.Context) string {
+	address, _ := c.Get("address").(string)
+	return address
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Pagga-Wallet/aqua402/internal/services/transactions"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type TransactionHandler struct {
+	service *transactions.Service
+	logger  *zap.Logger
+}
+
+func NewTransactionHandler(service *transactions.Service, logger *zap.Logger) *TransactionHandler {
+	return &TransactionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetTransaction retrieves the status of a backend transaction
+// @Summary      Get transaction status
+// @Description  Returns a transaction sent by the backend, e.g. a faucet grant, by the tx_id it was returned with.
+// @Description  Status is requested (waiting for the worker to send it), queued, pending, confirmed, reverted, dropped
+// @Description  or failed. A stuck transaction is resent with a higher gas price, tx_hash is the latest broadcast.
+// @Tags         Transactions
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  transactions.Transaction
+// @Failure      404  {object}  handlers.Problem
+// @Router       /transactions/{id} [get]
+func (h *TransactionHandler) GetTransaction(c echo.Context) error {
+	tx, err := h.service.GetTransaction(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tx)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/services/webhooks"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	service *webhooks.Service
+	logger  *zap.Logger
+}
+
+func NewWebhookHandler(service *webhooks.Service, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Subscribe creates a webhook subscription
+// @Summary      Create webhook subscription
+// @Description  Subscribes a URL to the on-chain events of an address, which must sign the request and owns the subscription. Payloads are signed with the returned secret, which is only shown once: X-Aqua402-Signature is t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request  body      webhooks.SubscribeRequest  true  "Subscription"
+// @Param        Idempotency-Key  header    string  false  "Retries with the same key replay the first response"
+// @Param        X-Aqua402-Address    header    string  true  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     true  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  true  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Param        chain    query     string                     false  "Chain ID or name, defaults to the default chain"
+// @Success      201      {object}  webhooks.Subscription
+// @Failure      400      {object}  handlers.Problem
+// @Failure      401      {object}  handlers.Problem
+// @Failure      403      {object}  handlers.Problem
+// @Failure      500      {object}  handlers.Problem
+// @Router       /webhooks [post]
+func (h *WebhookHandler) Subscribe(c echo.Context) error {
+	var req webhooks.SubscribeRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+	if err := requireSigner(c, "address", req.Address); err != nil {
+		return err
+	}
+	req.ChainID = chainID(c)
+
+	result, err := h.service.Subscribe(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetSubscription retrieves a webhook subscription
+// @Summary      Get webhook subscription
+// @Description  Returns a webhook subscription of the signing wallet, without its secret
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Param        X-Aqua402-Address    header    string  true  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     true  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  true  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Success      200  {object}  webhooks.Subscription
+// @Failure      401  {object}  handlers.Problem
+// @Failure      404  {object}  handlers.Problem
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetSubscription(c echo.Context) error {
+	result, err := h.service.GetSubscription(c.Request().Context(), c.Param("id"), signer(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Unsubscribe deletes a webhook subscription
+// @Summary      Delete webhook subscription
+// @Description  Stops deliveries to a subscription of the signing wallet, pending ones are marked failed
+// @Tags         Webhooks
+// @Param        id   path      string  true  "Subscription ID"
+// @Param        X-Aqua402-Address    header    string  true  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     true  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  true  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Success      204
+// @Failure      401  {object}  handlers.Problem
+// @Failure      404  {object}  handlers.Problem
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) Unsubscribe(c echo.Context) error {
+	if err := h.service.Unsubscribe(c.Request().Context(), c.Param("id"), signer(c)); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries retrieves the delivery log of a webhook subscription
+// @Summary      List webhook deliveries
+// @Description  Returns the latest deliveries of a subscription of the signing wallet with their status, attempts and last response, newest first
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true   "Subscription ID"
+// @Param        limit  query     int     false  "Number of deliveries (1-200)"  default(50)
+// @Param        X-Aqua402-Address    header    string  true  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     true  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  true  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Success      200    {array}   webhooks.Delivery
+// @Failure      400    {object}  handlers.Problem
+// @Failure      401    {object}  handlers.Problem
+// @Failure      404    {object}  handlers.Problem
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	limit := 0
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > webhooks.MaxDeliveryLimit {
+			return apperrors.Invalid("invalid query parameter", apperrors.FieldError{Field: "limit", Message: "must be between 1 and 200"})
+		}
+		limit = n
+	}
+
+	result, err := h.service.ListDeliveries(c.Request().Context(), c.Param("id"), signer(c), limit)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Redeliver sends an event to a subscription again
+// @Summary      Redeliver webhook event
+// @Description  Queues a new delivery of the event of a previous delivery to a subscription of the signing wallet, whatever its status. It carries the same event ID.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id           path      string  true  "Subscription ID"
+// @Param        delivery_id  path      string  true  "Delivery ID"
+// @Param        X-Aqua402-Address    header    string  true  "Signing wallet, the address the request acts for"
+// @Param        X-Aqua402-Timestamp  header    int     true  "Unix seconds the request was signed at"
+// @Param        X-Aqua402-Signature  header    string  true  "EIP-191 signature of the request, see WalletAuth in pkg/client"
+// @Success      202          {object}  webhooks.Delivery
+// @Failure      401          {object}  handlers.Problem
+// @Failure      404          {object}  handlers.Problem
+// @Failure      409          {object}  handlers.Problem
+// @Router       /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	result, err := h.service.Redeliver(c.Request().Context(), c.Param("id"), signer(c), c.Param("delivery_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, result)
+}
@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/queues"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/events"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+)
+
+// notConnected explains a dependency that could not be set up at startup
+func notConnected(initErr error) error {
+	if initErr == nil {
+		return errors.New("not configured")
+	}
+	return fmt.Errorf("not connected: %w", initErr)
+}
+
+// ClickHouse pings ClickHouse. repo is nil when it could not be set up, initErr tells why.
+func ClickHouse(repo *repositories.Repository, initErr error) Check {
+	return func(ctx context.Context) (Details, error) {
+		if repo == nil {
+			return nil, notConnected(initErr)
+		}
+		return nil, repo.Ping(ctx)
+	}
+}
+
+// RabbitMQ reports the state of the queue connection. queue is nil when it could not be set up,
+// initErr tells why.
+func RabbitMQ(queue *queues.Queue, initErr error) Check {
+	return func(ctx context.Context) (Details, error) {
+		if queue == nil {
+			return Details{"connected": false}, notConnected(initErr)
+		}
+		if !queue.Connected() {
+			return Details{"connected": false}, errors.New("connection closed")
+		}
+		return Details{"connected": true}, nil
+	}
+}
+
+// RPC asks the chain's RPC endpoints for the block number. client is nil when it could not be set up,
+// initErr tells why.
+func RPC(client *evm.Client, initErr error) Check {
+	return func(ctx context.Context) (Details, error) {
+		if client == nil {
+			return nil, notConnected(initErr)
+		}
+		block, err := client.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return Details{"block_number": block}, nil
+	}
+}
+
+// Monitor reports an event monitor's progress, failing when it falls more than maxLag blocks past its
+// confirmations behind the chain head
+func Monitor(monitor *events.Monitor, maxLag uint64) Check {
+	return func(ctx context.Context) (Details, error) {
+		status := monitor.Status()
+		details := Details{
+			"head_block":        status.Head,
+			"processed_block":   status.Processed,
+			"lag_blocks":        status.Lag(),
+			"confirmations":     status.Confirmations,
+			"last_processed_at": status.LastProcessedAt.UTC().Format(time.RFC3339),
+		}
+		if status.Lag() > status.Confirmations+maxLag {
+			return details, fmt.Errorf("%d blocks behind the chain head", status.Lag()-status.Confirmations)
+		}
+		if status.Err != nil {
+			return details, status.Err
+		}
+		return details, nil
+	}
+}
@@ -0,0 +1,205 @@
+// Package health reports whether the API and the worker can serve. Liveness only says the process
+// is up; readiness runs a check per dependency and reports its status, latency and last error.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Status of a check or of the whole report
+type Status string
+
+const (
+	StatusOK Status = "ok"
+	// StatusDegraded is an optional dependency being down, the process still serves
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Details are the facts a check reports besides its status, such as a block number
+type Details map[string]interface{}
+
+// Check probes a dependency, returning an error when it cannot be used
+type Check func(ctx context.Context) (Details, error)
+
+// Config controls the readiness checks
+type Config struct {
+	// Timeout bounds each check
+	Timeout time.Duration
+	// MaxBlockLag is how many blocks past its confirmations an event monitor may fall behind the chain
+	// head before it is reported down
+	MaxBlockLag uint64
+}
+
+// ConfigFromEnv reads HEALTH_CHECK_TIMEOUT (default 2s) and HEALTH_MAX_BLOCK_LAG (default 100 blocks)
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Timeout: 2 * time.Second, MaxBlockLag: 100}
+	if v := os.Getenv("HEALTH_CHECK_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid HEALTH_CHECK_TIMEOUT %q", v)
+		}
+		cfg.Timeout = d
+	}
+	if v := os.Getenv("HEALTH_MAX_BLOCK_LAG"); v != "" {
+		lag, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid HEALTH_MAX_BLOCK_LAG %q", v)
+		}
+		cfg.MaxBlockLag = lag
+	}
+	return cfg, nil
+}
+
+// CheckResult is the outcome of a check's last run
+type CheckResult struct {
+	Status Status `json:"status"`
+	// Optional checks leave the report degraded rather than down when they fail
+	Optional  bool    `json:"optional,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	// LastError and LastErrorAt are kept after the dependency recovers
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	Details     Details    `json:"details,omitempty"`
+}
+
+// Report is the readiness of the process
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type registered struct {
+	name     string
+	check    Check
+	optional bool
+
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// Checker runs the registered checks
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.Mutex
+	checks []*registered
+}
+
+// NewChecker creates a checker whose checks each get timeout to answer
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a check the process cannot serve without
+func (c *Checker) Register(name string, check Check) {
+	c.add(name, check, false)
+}
+
+// RegisterOptional adds a check of a dependency some features need, its failure degrades the report
+func (c *Checker) RegisterOptional(name string, check Check) {
+	c.add(name, check, true)
+}
+
+func (c *Checker) add(name string, check Check, optional bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, &registered{name: name, check: check, optional: optional})
+	sort.Slice(c.checks, func(i, j int) bool { return c.checks[i].name < c.checks[j].name })
+}
+
+// Run runs every check concurrently
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	checks := append([]*registered(nil), c.checks...)
+	c.mu.Unlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, r := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, r)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	for i, r := range checks {
+		result := results[i]
+		report.Checks[r.name] = result
+		switch {
+		case result.Status == StatusOK:
+		case r.optional:
+			if report.Status == StatusOK {
+				report.Status = StatusDegraded
+			}
+		default:
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, r *registered) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := r.check(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		Optional:  r.optional,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+		r.lastError, r.lastErrorAt = err.Error(), time.Now().UTC()
+	}
+	if r.lastError != "" {
+		lastErrorAt := r.lastErrorAt
+		result.LastError, result.LastErrorAt = r.lastError, &lastErrorAt
+	}
+	return result
+}
+
+// LivenessHandler answers 200 while the process is up, it checks no dependency
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]Status{"status": StatusOK})
+	})
+}
+
+// ReadinessHandler runs the checks, answering 503 when a required one fails
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		status := http.StatusOK
+		if report.Status == StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
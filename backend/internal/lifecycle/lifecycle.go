@@ -0,0 +1,180 @@
+// Package lifecycle runs the long-running components of the API and the worker and stops them in order.
+// Components start in the order they are added and stop in reverse, so a consumer added after the
+// connection it reads from stops before it. Stop hooks then persist progress and close connections.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config controls the shutdown
+type Config struct {
+	// ShutdownTimeout bounds the whole shutdown, components and stop hooks included
+	ShutdownTimeout time.Duration
+}
+
+// ConfigFromEnv reads SHUTDOWN_TIMEOUT (default 20s)
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{ShutdownTimeout: 20 * time.Second}
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid SHUTDOWN_TIMEOUT %q", v)
+		}
+		cfg.ShutdownTimeout = d
+	}
+	return cfg, nil
+}
+
+// Manager owns the components of a process
+type Manager struct {
+	logger     *zap.Logger
+	timeout    time.Duration
+	components []component
+	hooks      []hook
+}
+
+type component struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// New creates a manager giving the shutdown timeout to complete
+func New(logger *zap.Logger, timeout time.Duration) *Manager {
+	return &Manager{logger: logger, timeout: timeout}
+}
+
+// Go adds a component. run must block until its context is cancelled, then finish the work in hand and
+// return. A component returning an error before that stops the process.
+func (m *Manager) Go(name string, run func(ctx context.Context) error) {
+	m.components = append(m.components, component{name: name, run: run})
+}
+
+// Serve adds a server component. start blocks while serving, shutdown stops it gracefully within the
+// shutdown timeout. http.ErrServerClosed is not an error.
+func (m *Manager) Serve(name string, start func() error, shutdown func(ctx context.Context) error) {
+	m.Go(name, func(ctx context.Context) error {
+		errc := make(chan error, 1)
+		go func() { errc <- start() }()
+		select {
+		case err := <-errc:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		defer cancel()
+		return shutdown(shutdownCtx)
+	})
+}
+
+// OnStop adds a hook run once every component has stopped. Hooks run in reverse order of addition.
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.hooks = append(m.hooks, hook{name: name, stop: stop})
+}
+
+// Run starts the components and blocks until SIGINT or SIGTERM, the end of ctx, or a component failing.
+// It then stops the components in reverse order, each given what is left of the shutdown timeout, and
+// runs the stop hooks. The error joins the failure that stopped the process, the components that failed
+// or did not stop in time and the hooks that failed; it is nil after a clean shutdown.
+func (m *Manager) Run(ctx context.Context) error {
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	type running struct {
+		cancel context.CancelFunc
+		done   chan struct{}
+		err    error
+	}
+	failed := make(chan error, len(m.components))
+	runs := make([]*running, len(m.components))
+	for i, c := range m.components {
+		// Components are not stopped by ctx but one by one, in reverse order
+		runCtx, cancel := context.WithCancel(context.Background())
+		r := &running{cancel: cancel, done: make(chan struct{})}
+		runs[i] = r
+		go func() {
+			defer close(r.done)
+			r.err = c.run(runCtx)
+			if runCtx.Err() != nil {
+				return
+			}
+			if r.err != nil {
+				failed <- fmt.Errorf("%s: %w", c.name, r.err)
+				return
+			}
+			m.logger.Info("Component finished", zap.String("component", c.name))
+		}()
+	}
+
+	var errs []error
+	select {
+	case <-ctx.Done():
+		m.logger.Info("Shutting down")
+	case err := <-failed:
+		m.logger.Error("Component failed, shutting down", zap.Error(err))
+		errs = append(errs, err)
+	}
+	// A second signal kills the process
+	stopSignals()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	for i := len(runs) - 1; i >= 0; i-- {
+		r, name := runs[i], m.components[i].name
+		m.logger.Info("Stopping", zap.String("component", name))
+		r.cancel()
+		select {
+		case <-r.done:
+		default:
+			select {
+			case <-r.done:
+			case <-shutdownCtx.Done():
+				m.logger.Error("Component did not stop in time", zap.String("component", name))
+				errs = append(errs, fmt.Errorf("%s did not stop within %s", name, m.timeout))
+				continue
+			}
+		}
+		if r.err != nil && !errors.Is(r.err, context.Canceled) && !containsErr(errs, r.err) {
+			m.logger.Error("Component stopped with an error", zap.String("component", name), zap.Error(r.err))
+			errs = append(errs, fmt.Errorf("%s: %w", name, r.err))
+		}
+	}
+
+	for i := len(m.hooks) - 1; i >= 0; i-- {
+		h := m.hooks[i]
+		if err := h.stop(shutdownCtx); err != nil {
+			m.logger.Error("Stop hook failed", zap.String("hook", h.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// containsErr tells whether err is already reported in errs, as the failure that stopped the process
+func containsErr(errs []error, err error) bool {
+	for _, e := range errs {
+		if errors.Is(e, err) {
+			return true
+		}
+	}
+	return false
+}
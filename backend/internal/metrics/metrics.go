@@ -0,0 +1,146 @@
+// Package metrics holds the Prometheus collectors of the API and the worker, served by Handler at /metrics.
+// Metric names start with aqua402_.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector of the process, along with the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration is the latency of API requests by echo route pattern, so /rfq/:id is one series
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "aqua402_http_request_duration_seconds",
+		Help:    "API request latency by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	QueuePublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_queue_published_total",
+		Help: "Messages published to RabbitMQ by queue.",
+	}, []string{"queue"})
+	QueuePublishErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_queue_publish_errors_total",
+		Help: "Messages that failed to publish by queue.",
+	}, []string{"queue"})
+	QueueConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_queue_consumed_total",
+		Help: "Messages consumed from RabbitMQ by queue.",
+	}, []string{"queue"})
+	QueueConsumeErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_queue_consume_errors_total",
+		Help: "Consumed messages whose handler failed by queue.",
+	}, []string{"queue"})
+	// QueueConsumeDelay is the time from publish to consume, the lag of the consumers
+	QueueConsumeDelay = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "aqua402_queue_consume_delay_seconds",
+		Help:    "Time between publishing and consuming a message by queue.",
+		Buckets: []float64{.01, .05, .1, .5, 1, 5, 15, 60, 300},
+	}, []string{"queue"})
+	// QueueMessagesReady is the backlog of each consumed queue, read periodically by the consumer
+	QueueMessagesReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aqua402_queue_messages_ready",
+		Help: "Messages waiting in a consumed queue.",
+	}, []string{"queue"})
+
+	MonitorHeadBlock = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aqua402_monitor_head_block",
+		Help: "Latest chain head seen by the event monitor.",
+	}, []string{"chain_id"})
+	MonitorProcessedBlock = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aqua402_monitor_processed_block",
+		Help: "Last block whose logs the event monitor processed.",
+	}, []string{"chain_id"})
+	// MonitorHeadLag includes the confirmations the chain waits for, alert on it exceeding them
+	MonitorHeadLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aqua402_monitor_head_lag_blocks",
+		Help: "Chain head minus the last processed block.",
+	}, []string{"chain_id"})
+	MonitorLastProcessed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aqua402_monitor_last_processed_timestamp_seconds",
+		Help: "Unix time the event monitor last advanced.",
+	}, []string{"chain_id"})
+	MonitorLogs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_monitor_logs_processed_total",
+		Help: "Contract logs processed by chain and event.",
+	}, []string{"chain_id", "event"})
+	MonitorLogErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_monitor_log_errors_total",
+		Help: "Contract logs that failed to process by chain and event.",
+	}, []string{"chain_id", "event"})
+
+	// ClickHouseQueryDuration is labelled with the statement and its table, e.g. SELECT pagga_data.rfqs
+	ClickHouseQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "aqua402_clickhouse_query_duration_seconds",
+		Help:    "ClickHouse query latency by statement and table, until the first row for reads.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 10},
+	}, []string{"statement", "table", "status"})
+
+	FaucetGrants = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_faucet_grants_total",
+		Help: "Faucet grants sent by chain and token.",
+	}, []string{"chain_id", "token"})
+	FaucetDispensed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aqua402_faucet_dispensed_total",
+		Help: "Amount given away by the faucet in token units, by chain and token.",
+	}, []string{"chain_id", "token"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		QueuePublished, QueuePublishErrors, QueueConsumed, QueueConsumeErrors, QueueConsumeDelay, QueueMessagesReady,
+		MonitorHeadBlock, MonitorProcessedBlock, MonitorHeadLag, MonitorLastProcessed, MonitorLogs, MonitorLogErrors,
+		ClickHouseQueryDuration,
+		FaucetGrants, FaucetDispensed,
+		websocketGauge("aqua402_websocket_connections", "Open WebSocket connections.",
+			func(connections, _ int) int { return connections }),
+		websocketGauge("aqua402_websocket_topics", "Distinct topics WebSocket clients are subscribed to.",
+			func(_, topics int) int { return topics }),
+	)
+}
+
+// Handler serves the collectors in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveWebSocket reports the connections and distinct subscribed topics returned by stats at every
+// scrape, replacing any earlier hub
+func ObserveWebSocket(stats func() (connections, topics int)) {
+	websocketStats.Store(&stats)
+}
+
+// websocketStats is the hub read by the WebSocket gauges, nil until ObserveWebSocket is called
+var websocketStats atomic.Pointer[func() (int, int)]
+
+func websocketGauge(name, help string, pick func(connections, topics int) int) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+		stats := websocketStats.Load()
+		if stats == nil {
+			return 0
+		}
+		return float64(pick((*stats)()))
+	})
+}
+
+// Chain formats a chain ID as a label value
+func Chain(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
+
+// Since returns the seconds elapsed since start
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
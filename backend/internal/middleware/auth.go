@@ -1,37 +1,65 @@
 package middleware
 
 import (
-	"net/http"
-	"strings"
+	"bytes"
+	"errors"
+	"io"
+	"time"
 
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/walletauth"
 	"github.com/labstack/echo/v4"
 )
 
-// AuthMiddleware validates wallet signatures (placeholder)
+// maxSignedBody caps the request bodies read to check their signature
+const maxSignedBody = 1 << 20
+
+// AuthMiddleware requires a wallet signature, see walletauth, and sets the "address" context value to
+// the checksummed signing address. Handlers check that the addresses a request acts for are that one.
 func AuthMiddleware() echo.MiddlewareFunc {
+	return walletAuth(true)
+}
+
+// OptionalAuthMiddleware checks the wallet signature of the requests that carry one as AuthMiddleware
+// does, and lets unsigned requests through with the "unsigned" context value set. It keeps routes that
+// are to require signatures open to unsigned clients for a release.
+func OptionalAuthMiddleware() echo.MiddlewareFunc {
+	return walletAuth(false)
+}
+
+func walletAuth(required bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			authHeader := c.Request().Header.Get("Authorization")
-			if authHeader == "" {
-				return c.JSON(http.StatusUnauthorized, map[string]string{
-					"error": "Missing authorization header",
-				})
+			req := c.Request()
+			var body []byte
+			if req.Body != nil {
+				var err error
+				body, err = io.ReadAll(io.LimitReader(req.Body, maxSignedBody+1))
+				req.Body.Close()
+				if err != nil {
+					return apperrors.Invalid("failed to read request body")
+				}
+				if len(body) > maxSignedBody {
+					return apperrors.Invalid("request body too large")
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
 			}
 
-			// Extract token (Bearer <token>)
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				return c.JSON(http.StatusUnauthorized, map[string]string{
-					"error": "Invalid authorization header",
-				})
+			address, err := walletauth.Verify(req, body, time.Now())
+			switch {
+			case errors.Is(err, walletauth.ErrMissingSignature) && !required:
+				c.Set("unsigned", true)
+				return next(c)
+			case errors.Is(err, walletauth.ErrMissingSignature):
+				return apperrors.Unauthorized("missing wallet signature")
+			case errors.Is(err, walletauth.ErrStaleSignature):
+				return apperrors.Unauthorized("wallet signature expired")
+			case err != nil:
+				return apperrors.Unauthorized("invalid wallet signature")
 			}
 
-			// TODO: Validate signature/token
-			// For now, just pass through
-			c.Set("address", parts[1])
-
+			c.Set("address", address.Hex())
 			return next(c)
 		}
 	}
 }
-
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/labstack/echo/v4"
+)
+
+// ChainMiddleware resolves the chain query parameter, a chain ID or name, and stores the chain in the
+// request context for handlers. Requests without it are served from the registry's default chain.
+func ChainMiddleware(registry *chains.Registry) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			chain, err := registry.Get(c.QueryParam("chain"))
+			if err != nil {
+				return apperrors.Invalid("invalid query parameter", apperrors.FieldError{Field: "chain", Message: err.Error()})
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(chains.NewContext(req.Context(), chain)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// Idempotency headers
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// Idempotency stores
+const (
+	IdempotencyStoreClickHouse = "clickhouse"
+	IdempotencyStoreMemory     = "memory"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyStore keeps Idempotency-Key reservations and responses. It is shared by API replicas
+// unless it is the in-memory store, and Reserve gives a key to one request at a time across them.
+type IdempotencyStore interface {
+	// Reserve claims record.Key, or returns the unexpired record already holding it
+	Reserve(ctx context.Context, record *repositories.IdempotencyKeyModel) (*repositories.IdempotencyKeyModel, error)
+	// Save stores the response of a reserved key
+	Save(ctx context.Context, record *repositories.IdempotencyKeyModel) error
+	// Delete releases a reserved key without a response so the request can be retried
+	Delete(ctx context.Context, record *repositories.IdempotencyKeyModel) error
+}
+
+// IdempotencyConfig selects the store and how long responses are replayed
+type IdempotencyConfig struct {
+	Store string
+	TTL   time.Duration
+}
+
+// IdempotencyConfigFromEnv reads IDEMPOTENCY_STORE (clickhouse or memory) and IDEMPOTENCY_TTL (default 24h)
+func IdempotencyConfigFromEnv() (IdempotencyConfig, error) {
+	cfg := IdempotencyConfig{Store: IdempotencyStoreClickHouse, TTL: 24 * time.Hour}
+	if v := os.Getenv("IDEMPOTENCY_STORE"); v != "" {
+		if v != IdempotencyStoreClickHouse && v != IdempotencyStoreMemory {
+			return cfg, fmt.Errorf("invalid IDEMPOTENCY_STORE %q, expected clickhouse or memory", v)
+		}
+		cfg.Store = v
+	}
+	if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return cfg, fmt.Errorf("invalid IDEMPOTENCY_TTL %q", v)
+		}
+		cfg.TTL = ttl
+	}
+	return cfg, nil
+}
+
+// IdempotencyMiddleware replays the response of the first request made with an Idempotency-Key.
+// A key reused with a different method, path, query or body, or while the first request is still running,
+// is rejected with 409. Server errors are not stored so the request can be retried.
+func IdempotencyMiddleware(store IdempotencyStore, ttl time.Duration, logger *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return apperrors.Invalid("invalid header", apperrors.FieldError{
+					Field:   HeaderIdempotencyKey,
+					Message: fmt.Sprintf("must be at most %d characters", maxIdempotencyKeyLength),
+				})
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return apperrors.Invalid("failed to read request body")
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now()
+			record := &repositories.IdempotencyKeyModel{
+				Key:         key,
+				RequestHash: requestHash(c.Request(), body),
+				Owner:       newOwner(),
+				CreatedAt:   now.Unix(),
+				ExpiresAt:   now.Add(ttl).Unix(),
+			}
+
+			ctx := c.Request().Context()
+			existing, err := store.Reserve(ctx, record)
+			if err != nil {
+				return apperrors.Unavailable(err, "failed to reserve idempotency key")
+			}
+			if existing != nil {
+				switch {
+				case existing.RequestHash != record.RequestHash:
+					return apperrors.Conflict("Idempotency-Key was already used for a different request")
+				case existing.StatusCode == 0:
+					return apperrors.Conflict("a request with this Idempotency-Key is still in progress")
+				}
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(int(existing.StatusCode), existing.ContentType, []byte(existing.Body))
+			}
+
+			recorder := &bodyRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			if err := next(c); err != nil {
+				// Render errors here so the problem response is recorded too
+				c.Error(err)
+			}
+
+			// Store the outcome even if the client has gone away
+			ctx = context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if !c.Response().Committed || status >= http.StatusInternalServerError {
+				if err := store.Delete(ctx, record); err != nil {
+					logger.Warn("Failed to release idempotency key", zap.String("key", key), zap.Error(err))
+				}
+				return nil
+			}
+
+			record.StatusCode = uint16(status)
+			record.ContentType = c.Response().Header().Get(echo.HeaderContentType)
+			record.Body = recorder.body.String()
+			if err := store.Save(ctx, record); err != nil {
+				logger.Error("Failed to save idempotent response", zap.String("key", key), zap.Error(err))
+			}
+			return nil
+		}
+	}
+}
+
+// requestHash identifies a request by method, path, query and body, so a key reused for another chain
+// (?chain=) is a different request
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newOwner() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// bodyRecorder copies the response body while it is written
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// MemoryIdempotencyStore keeps idempotency keys in process, for single replica deployments and tests
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*repositories.IdempotencyKeyModel
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: make(map[string]*repositories.IdempotencyKeyModel),
+	}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, record *repositories.IdempotencyKeyModel) (*repositories.IdempotencyKeyModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Unix()
+	for key, r := range s.records {
+		if r.ExpiresAt <= now {
+			delete(s.records, key)
+		}
+	}
+	if existing, ok := s.records[record.Key]; ok {
+		copied := *existing
+		return &copied, nil
+	}
+	copied := *record
+	s.records[record.Key] = &copied
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Save(ctx context.Context, record *repositories.IdempotencyKeyModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *record
+	s.records[record.Key] = &copied
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Delete(ctx context.Context, record *repositories.IdempotencyKeyModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Key]; ok && existing.Owner == record.Owner {
+		delete(s.records, record.Key)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// MetricsMiddleware records the latency and status of every request by echo route pattern, which keeps
+// the route label bounded even for paths matching no route.
+func MetricsMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// Render the error now so the status it maps to is recorded
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, route, strconv.Itoa(c.Response().Status)).
+				Observe(metrics.Since(start))
+			return nil
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/labstack/echo/v4"
+)
+
+// OperatorMiddleware restricts routes to operators presenting the configured X-Operator-Token.
+// An empty token disables the routes entirely.
+func OperatorMiddleware(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token == "" {
+				return apperrors.Forbidden("operator actions are disabled")
+			}
+
+			provided := c.Request().Header.Get("X-Operator-Token")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return apperrors.Unauthorized("invalid operator token")
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderTraceID returns the ID of the request's trace, to find it in the tracing backend
+const HeaderTraceID = "X-Trace-Id"
+
+// TracingMiddleware starts a server span per request, continuing the trace of an incoming traceparent
+// header. Handlers reach the span through the request context, so queries, RPC calls and published
+// messages join the trace.
+func TracingMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route := c.Path()
+			ctx := tracing.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracing.Start(ctx, req.Method+" "+route, trace.SpanKindServer,
+				attribute.String("http.request.method", req.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", req.URL.Path),
+			)
+			defer span.End()
+			if traceID := tracing.TraceID(ctx); traceID != "" {
+				c.Response().Header().Set(HeaderTraceID, traceID)
+			}
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				// Render the error now so the status it maps to is recorded
+				c.Error(err)
+				span.RecordError(err)
+			}
+			status := c.Response().Status
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package queues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrNotFound is returned for a queue that was never declared, by a publisher or a consumer
+var ErrNotFound = errors.New("queue not found")
+
+// Stats is the state of a queue
+type Stats struct {
+	Name string
+	// Messages are ready for delivery, those delivered but not yet acknowledged are not counted
+	Messages  int
+	Consumers int
+}
+
+// Message is a message read from a queue without consuming it
+type Message struct {
+	Body        []byte
+	Headers     map[string]interface{}
+	Timestamp   time.Time
+	Redelivered bool
+}
+
+// Stats returns the state of a queue, an error if it does not exist
+func (q *Queue) Stats(queueName string) (Stats, error) {
+	var stats Stats
+	err := q.withChannel(queueName, func(ch *amqp.Channel) error {
+		state, err := ch.QueueDeclarePassive(queueName, true, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+		stats = Stats{Name: state.Name, Messages: state.Messages, Consumers: state.Consumers}
+		return nil
+	})
+	return stats, err
+}
+
+// Purge deletes the ready messages of a queue and returns how many there were
+func (q *Queue) Purge(queueName string) (int, error) {
+	var purged int
+	err := q.withChannel(queueName, func(ch *amqp.Channel) error {
+		var err error
+		purged, err = ch.QueuePurge(queueName, false)
+		return err
+	})
+	return purged, err
+}
+
+// Peek returns up to limit messages from the head of a queue and leaves them in it. They are held
+// unacknowledged while read, then returned to the queue flagged as redelivered.
+func (q *Queue) Peek(queueName string, limit int) ([]Message, error) {
+	var messages []Message
+	err := q.withChannel(queueName, func(ch *amqp.Channel) error {
+		for len(messages) < limit {
+			d, ok, err := ch.Get(queueName, false)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			messages = append(messages, Message{
+				Body:        d.Body,
+				Headers:     d.Headers,
+				Timestamp:   d.Timestamp,
+				Redelivered: d.Redelivered,
+			})
+		}
+		// Closing the channel hands the unacknowledged messages back
+		return nil
+	})
+	return messages, err
+}
+
+// Requeue moves up to limit messages, every message ready when it starts if limit is 0, from one queue
+// to another, e.g. from FailedQueue(name) back to name. Each message is acknowledged once the broker
+// confirmed its copy, the headers added when it was parked are dropped.
+func (q *Queue) Requeue(ctx context.Context, from, to string, limit int) (int, error) {
+	var moved int
+	err := q.withChannel(from, func(ch *amqp.Channel) error {
+		state, err := ch.QueueDeclarePassive(from, true, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > state.Messages {
+			// Bounded by the messages present, so moving a queue onto itself terminates
+			limit = state.Messages
+		}
+		if _, err := ch.QueueDeclare(to, true, false, false, false, nil); err != nil {
+			return err
+		}
+		if err := ch.Confirm(false); err != nil {
+			return err
+		}
+
+		for moved < limit {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			d, ok, err := ch.Get(from, false)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+
+			headers := amqp.Table{}
+			for key, value := range d.Headers {
+				if key != HeaderError && key != HeaderFailedAt {
+					headers[key] = value
+				}
+			}
+			confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, "", to, false, false, amqp.Publishing{
+				ContentType:  d.ContentType,
+				Headers:      headers,
+				Timestamp:    d.Timestamp,
+				DeliveryMode: amqp.Persistent,
+				Body:         d.Body,
+			})
+			if err != nil {
+				return err
+			}
+			acked, err := confirmation.WaitContext(ctx)
+			if err != nil {
+				return err
+			}
+			if !acked {
+				return fmt.Errorf("broker refused the copy of a message of %s", from)
+			}
+			if err := d.Ack(false); err != nil {
+				return err
+			}
+			moved++
+		}
+		return nil
+	})
+	return moved, err
+}
+
+// withChannel runs f on a channel of its own, closed afterwards. The broker closes a channel on errors
+// such as a missing queue, the consumers' channel is kept out of it. A missing queueName is reported
+// as ErrNotFound.
+func (q *Queue) withChannel(queueName string, f func(ch *amqp.Channel) error) error {
+	ch, err := q.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+	err = f(ch)
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, queueName)
+	}
+	return err
+}
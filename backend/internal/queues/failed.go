@@ -0,0 +1,71 @@
+package queues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// failedSuffix names the queue the failed messages of a consumed queue are parked in
+const failedSuffix = ".failed"
+
+// Headers added to parked messages
+const (
+	HeaderError    = "x-aqua402-error"
+	HeaderFailedAt = "x-aqua402-failed-at"
+)
+
+// FailedQueue returns the queue the messages of queueName are parked in when their handler fails.
+// Nothing consumes it, its messages stay until moved back or purged.
+func FailedQueue(queueName string) string {
+	return queueName + failedSuffix
+}
+
+// Parked returns the copy of a failed delivery published to FailedQueue, its headers carry the
+// handler error and the time of the failure
+func Parked(d amqp.Delivery, handlerErr error, failedAt time.Time) amqp.Publishing {
+	headers := amqp.Table{}
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers[HeaderError] = handlerErr.Error()
+	headers[HeaderFailedAt] = failedAt.Unix()
+
+	return amqp.Publishing{
+		ContentType:  d.ContentType,
+		Headers:      headers,
+		Timestamp:    d.Timestamp,
+		DeliveryMode: amqp.Persistent,
+		Body:         d.Body,
+	}
+}
+
+// Settle acknowledges a delivery once handled. When handlerErr is set the delivery is first handed to
+// park as its Parked copy; if park fails it is rejected without requeueing, which drops it.
+func Settle(d amqp.Delivery, handlerErr error, park func(amqp.Publishing) error) error {
+	if handlerErr != nil {
+		if err := park(Parked(d, handlerErr, time.Now())); err != nil {
+			err = fmt.Errorf("failed to park message, dropping it: %w", err)
+			if nackErr := d.Nack(false, false); nackErr != nil {
+				return errors.Join(err, fmt.Errorf("failed to reject message: %w", nackErr))
+			}
+			return err
+		}
+	}
+	if err := d.Ack(false); err != nil {
+		return fmt.Errorf("failed to acknowledge message: %w", err)
+	}
+	return nil
+}
+
+// park publishes the copy of a failed message of queueName to its FailedQueue
+func (q *Queue) park(ctx context.Context, queueName string, parked amqp.Publishing) error {
+	failed := FailedQueue(queueName)
+	if _, err := q.ch.QueueDeclare(failed, true, false, false, false, nil); err != nil {
+		return err
+	}
+	return q.ch.PublishWithContext(ctx, "", failed, false, false, parked)
+}
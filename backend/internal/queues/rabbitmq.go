@@ -1,12 +1,26 @@
 package queues
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/Pagga-Wallet/aqua402/internal/metrics"
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// prefetch is how many messages a consumer holds before acknowledging them
+const prefetch = 10
+
+// depthInterval is how often a consumed queue's backlog is read for aqua402_queue_messages_ready
+const depthInterval = 15 * time.Second
+
 // Queue handles RabbitMQ operations
 type Queue struct {
 	conn *amqp.Connection
@@ -40,8 +54,27 @@ func (q *Queue) Close() error {
 	return nil
 }
 
-// Publish publishes a message to a queue
-func (q *Queue) Publish(queueName string, message interface{}) error {
+// Connected reports whether the connection and the channel to RabbitMQ are open. They are not
+// reopened once closed.
+func (q *Queue) Connected() bool {
+	return q.conn != nil && !q.conn.IsClosed() && q.ch != nil && !q.ch.IsClosed()
+}
+
+// Publish publishes a message to a queue. The trace context of ctx travels in the message headers, so
+// the consumer's span joins the publisher's trace.
+func (q *Queue) Publish(ctx context.Context, queueName string, message interface{}) error {
+	ctx, span := tracing.Start(ctx, "publish "+queueName, trace.SpanKindProducer, messagingAttributes(queueName)...)
+	err := q.publish(ctx, queueName, message)
+	tracing.End(span, err)
+	if err != nil {
+		metrics.QueuePublishErrors.WithLabelValues(queueName).Inc()
+		return err
+	}
+	metrics.QueuePublished.WithLabelValues(queueName).Inc()
+	return nil
+}
+
+func (q *Queue) publish(ctx context.Context, queueName string, message interface{}) error {
 	body, err := json.Marshal(message)
 	if err != nil {
 		return err
@@ -59,20 +92,31 @@ func (q *Queue) Publish(queueName string, message interface{}) error {
 		return err
 	}
 
-	return q.ch.Publish(
+	headers := amqp.Table{}
+	tracing.Inject(ctx, headerCarrier(headers))
+
+	return q.ch.PublishWithContext(
+		ctx,
 		"",        // exchange
 		queueName, // routing key
 		false,     // mandatory
 		false,     // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
-			Body:        body,
+			Headers:     headers,
+			// Consumers measure their lag from the publish time
+			Timestamp: time.Now(),
+			Body:      body,
 		},
 	)
 }
 
-// Consume consumes messages from a queue
-func (q *Queue) Consume(queueName string, handler func([]byte) error) error {
+// Consume delivers the messages of a queue to handler until ctx is done, one at a time. A message is
+// acknowledged once handled, or once parked in FailedQueue when handler fails. handler runs in a
+// consumer span continuing the trace of the publisher, carried by its ctx, which is not cancelled
+// with ctx: on shutdown the message in hand is finished, the prefetched ones go back to the queue and
+// Consume returns ctx.Err().
+func (q *Queue) Consume(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error) error {
 	_, err := q.ch.QueueDeclare(
 		queueName, // name
 		true,      // durable
@@ -85,10 +129,15 @@ func (q *Queue) Consume(queueName string, handler func([]byte) error) error {
 		return err
 	}
 
+	// Bound the messages delivered but not yet acknowledged, they are requeued on shutdown
+	if err := q.ch.Qos(prefetch, 0, false); err != nil {
+		return err
+	}
+
 	msgs, err := q.ch.Consume(
 		queueName, // queue
-		"",        // consumer
-		true,      // auto-ack
+		queueName, // consumer
+		false,     // auto-ack
 		false,     // exclusive
 		false,     // no-local
 		false,     // no-wait
@@ -98,14 +147,93 @@ func (q *Queue) Consume(queueName string, handler func([]byte) error) error {
 		return err
 	}
 
-	go func() {
-		for d := range msgs {
-			if err := handler(d.Body); err != nil {
-				log.Printf("Error processing message: %v", err)
+	depthCtx, stopDepth := context.WithCancel(ctx)
+	defer stopDepth()
+	go q.watchDepth(depthCtx, queueName)
+
+	handlerCtx := context.WithoutCancel(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			// Stop the deliveries, then hand back those already received
+			q.ch.Cancel(queueName, false)
+			for d := range msgs {
+				d.Nack(false, true)
+			}
+			return ctx.Err()
+		case d, ok := <-msgs:
+			if !ok {
+				return fmt.Errorf("deliveries of %s stopped, the channel was closed", queueName)
 			}
+			q.handle(handlerCtx, queueName, d, handler)
 		}
-	}()
+	}
+}
 
-	return nil
+// handle runs handler on a delivery and settles it
+func (q *Queue) handle(ctx context.Context, queueName string, d amqp.Delivery, handler func(ctx context.Context, body []byte) error) {
+	metrics.QueueConsumed.WithLabelValues(queueName).Inc()
+	if !d.Timestamp.IsZero() {
+		metrics.QueueConsumeDelay.WithLabelValues(queueName).Observe(metrics.Since(d.Timestamp))
+	}
+	ctx = tracing.Extract(ctx, headerCarrier(d.Headers))
+	ctx, span := tracing.Start(ctx, "process "+queueName, trace.SpanKindConsumer, messagingAttributes(queueName)...)
+	err := handler(ctx, d.Body)
+	tracing.End(span, err)
+	if err != nil {
+		metrics.QueueConsumeErrors.WithLabelValues(queueName).Inc()
+		log.Printf("Error processing message: %v", err)
+	}
+	// Failed messages are parked in FailedQueue to be requeued once the cause is fixed
+	if err := Settle(d, err, func(parked amqp.Publishing) error {
+		return q.park(ctx, queueName, parked)
+	}); err != nil {
+		log.Printf("Failed to settle message of %s: %v", queueName, err)
+	}
+}
+
+// messagingAttributes describe a queue in a span
+func messagingAttributes(queueName string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("messaging.system", "rabbitmq"),
+		attribute.String("messaging.destination.name", queueName),
+	}
+}
+
+// headerCarrier reads and writes the trace context in AMQP message headers
+type headerCarrier amqp.Table
+
+func (h headerCarrier) Get(key string) string {
+	value, _ := h[key].(string)
+	return value
 }
 
+func (h headerCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// watchDepth reports the messages waiting in queueName until ctx is done
+func (q *Queue) watchDepth(ctx context.Context, queueName string) {
+	ticker := time.NewTicker(depthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			state, err := q.ch.QueueDeclarePassive(queueName, true, false, false, false, nil)
+			if err != nil {
+				return
+			}
+			metrics.QueueMessagesReady.WithLabelValues(queueName).Set(float64(state.Messages))
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Markets of account requests and offers
+const (
+	MarketRFQ     = "rfq"
+	MarketAuction = "auction"
+)
+
+// AccountRepository reads what an address did across the RFQ, auction and Aqua tables
+type AccountRepository struct {
+	*Repository
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(repo *Repository) *AccountRepository {
+	return &AccountRepository{Repository: repo}
+}
+
+// fillsQuery lists the RFQs or auctions (the source parameter) that opened a credit line, with its lender
+const fillsQuery = `SELECT s.source_id AS source_id, s.credit_line_id AS credit_line_id, l.lender_address AS lender_address, toUInt8(1) AS filled
+	FROM pagga_data.credit_line_sources AS s FINAL
+	INNER JOIN (SELECT chain_id, id, lender_address FROM pagga_data.credit_lines FINAL WHERE chain_id = ?) AS l
+	ON l.chain_id = s.chain_id AND l.id = s.credit_line_id
+	WHERE s.chain_id = ? AND s.source = ?`
+
+// ListRequests returns the latest RFQs and auctions address asked for, newest first, with the number of
+// quotes or bids they got and the credit line they were filled with
+func (r *AccountRepository) ListRequests(ctx context.Context, chainID uint64, address string, limit int) ([]*AccountRequestModel, error) {
+	// RFQs submitted through the API are stored again once seen on-chain with their block time, FINAL only
+	// merges rows of the same creation time so LIMIT 1 BY keeps one of each
+	query := `SELECT * FROM (
+	            SELECT 'rfq' AS market, q.id, q.amount, q.duration, toInt64(0) AS end_time, q.created_at, o.offers, f.filled, f.credit_line_id
+	            FROM (SELECT id, amount, duration, toInt64(created_at) AS created_at FROM pagga_data.rfqs FINAL
+	                  WHERE chain_id = ? AND lower(borrower_address) = lower(?) ORDER BY created_at LIMIT 1 BY id) AS q
+	            LEFT JOIN (SELECT rfq_id, uniqExact(id) AS offers FROM pagga_data.quotes FINAL WHERE chain_id = ? GROUP BY rfq_id) AS o ON o.rfq_id = q.id
+	            LEFT JOIN (` + fillsQuery + `) AS f ON f.source_id = q.id
+	            UNION ALL
+	            SELECT 'auction' AS market, a.id, a.amount, a.duration, a.end_time, a.created_at, o.offers, f.filled, f.credit_line_id
+	            FROM (SELECT id, amount, duration, end_time, created_at FROM pagga_data.auctions FINAL
+	                  WHERE chain_id = ? AND lower(borrower_address) = lower(?) ORDER BY created_at LIMIT 1 BY id) AS a
+	            LEFT JOIN (SELECT auction_id, uniqExact(id) AS offers FROM pagga_data.bids FINAL WHERE chain_id = ? GROUP BY auction_id) AS o ON o.auction_id = a.id
+	            LEFT JOIN (` + fillsQuery + `) AS f ON f.source_id = a.id
+	          ) ORDER BY created_at DESC, market, id DESC LIMIT ?`
+	args := []interface{}{
+		chainID, address, chainID, chainID, chainID, MarketRFQ,
+		chainID, address, chainID, chainID, chainID, MarketAuction,
+		limit,
+	}
+	return scanRows(ctx, r.db, query, args, func(rows *sql.Rows) (*AccountRequestModel, error) {
+		req := new(AccountRequestModel)
+		return req, rows.Scan(&req.Market, &req.ID, &req.Amount, &req.Duration, &req.EndTime, &req.CreatedAt,
+			&req.Offers, &req.Filled, &req.CreditLineID)
+	})
+}
+
+// ListOffers returns the latest quotes and bids address made, newest first, with the outcome of the RFQ
+// or auction they were made on
+func (r *AccountRepository) ListOffers(ctx context.Context, chainID uint64, address string, limit int) ([]*AccountOfferModel, error) {
+	query := `SELECT * FROM (
+	            SELECT 'rfq' AS market, q.id, q.rfq_id AS request_id, q.rate_bps, q.limit, q.submitted_at AS created_at,
+	                   f.filled, f.credit_line_id, lower(f.lender_address) = lower(?) AS won
+	            FROM (SELECT id, rfq_id, rate_bps, "limit", submitted_at FROM pagga_data.quotes FINAL
+	                  WHERE chain_id = ? AND lower(lender_address) = lower(?)) AS q
+	            LEFT JOIN (` + fillsQuery + `) AS f ON f.source_id = q.rfq_id
+	            UNION ALL
+	            SELECT 'auction' AS market, b.id, b.auction_id AS request_id, b.rate_bps, b.limit, b.timestamp AS created_at,
+	                   f.filled, f.credit_line_id, lower(f.lender_address) = lower(?) AS won
+	            FROM (SELECT id, auction_id, rate_bps, "limit", timestamp FROM pagga_data.bids FINAL
+	                  WHERE chain_id = ? AND lower(lender_address) = lower(?)) AS b
+	            LEFT JOIN (` + fillsQuery + `) AS f ON f.source_id = b.auction_id
+	          ) ORDER BY created_at DESC, market, id DESC LIMIT ?`
+	args := []interface{}{
+		address, chainID, address, chainID, chainID, MarketRFQ,
+		address, chainID, address, chainID, chainID, MarketAuction,
+		limit,
+	}
+	return scanRows(ctx, r.db, query, args, func(rows *sql.Rows) (*AccountOfferModel, error) {
+		offer := new(AccountOfferModel)
+		return offer, rows.Scan(&offer.Market, &offer.ID, &offer.RequestID, &offer.RateBps, &offer.Limit, &offer.CreatedAt,
+			&offer.Filled, &offer.CreditLineID, &offer.Won)
+	})
+}
+
+// GetLiquidity returns the totals of the Aqua liquidity events of a lender
+func (r *AccountRepository) GetLiquidity(ctx context.Context, chainID uint64, lender string) (*AccountLiquidityModel, error) {
+	query := `SELECT toString(sumIf(toUInt256OrZero(amount), event_type = ?)), toString(sumIf(toUInt256OrZero(amount), event_type = ?)),
+	                 toString(sumIf(toUInt256OrZero(amount), event_type = ?)), toString(sumIf(toUInt256OrZero(amount), event_type = ?))
+	          FROM pagga_data.aqua_liquidity_events FINAL WHERE chain_id = ? AND lower(lender_address) = lower(?)`
+	liquidity := new(AccountLiquidityModel)
+	err := r.db.QueryRowContext(ctx, query,
+		LiquidityConnected, LiquidityWithdrawn, LiquidityReserved, LiquidityReleased, chainID, lender).Scan(
+		&liquidity.Connected, &liquidity.Withdrawn, &liquidity.Reserved, &liquidity.Released)
+	return liquidity, err
+}
+
+// AccountRequestModel is an RFQ or auction of a borrower. CreditLineID is set when Filled.
+type AccountRequestModel struct {
+	Market       string
+	ID           uint64
+	Amount       string
+	Duration     uint64
+	EndTime      int64
+	CreatedAt    int64
+	Offers       uint64
+	Filled       bool
+	CreditLineID uint64
+}
+
+// AccountOfferModel is a quote or bid of a lender. Won is set when the request was filled by the lender.
+type AccountOfferModel struct {
+	Market       string
+	ID           uint64
+	RequestID    uint64
+	RateBps      uint16
+	Limit        string
+	CreatedAt    int64
+	Filled       bool
+	CreditLineID uint64
+	Won          bool
+}
+
+// AccountLiquidityModel sums the Aqua liquidity events of a lender, amounts in wei
+type AccountLiquidityModel struct {
+	Connected string
+	Withdrawn string
+	Reserved  string
+	Released  string
+}
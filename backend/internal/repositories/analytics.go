@@ -0,0 +1,278 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// bucketExprs rolls a DateTime('UTC') expression up to a granularity.
+// %s is replaced by the expression being bucketed.
+var bucketExprs = map[string]string{
+	"hour":  "toInt64(toUnixTimestamp(toStartOfHour(%s)))",
+	"day":   "toInt64(toUnixTimestamp(toStartOfDay(%s)))",
+	"week":  "toInt64(toUnixTimestamp(toDateTime(toStartOfWeek(%s, 1), 'UTC')))",
+	"month": "toInt64(toUnixTimestamp(toDateTime(toStartOfMonth(%s), 'UTC')))",
+}
+
+// AnalyticsRepository aggregates the market tables when queried. They are read with FINAL, so rows stored
+// again by a redelivered or replayed event are counted once, grouped into hourly buckets before being
+// rolled up to the requested granularity.
+type AnalyticsRepository struct {
+	*Repository
+}
+
+// NewAnalyticsRepository creates a new analytics repository
+func NewAnalyticsRepository(repo *Repository) *AnalyticsRepository {
+	return &AnalyticsRepository{Repository: repo}
+}
+
+func bucketExpr(granularity, expr string) (string, error) {
+	format, ok := bucketExprs[granularity]
+	if !ok {
+		return "", fmt.Errorf("unsupported granularity %q", granularity)
+	}
+	return fmt.Sprintf(format, expr), nil
+}
+
+// Volume returns RFQ, auction and credit line counts and volumes per bucket in [from, to)
+func (r *AnalyticsRepository) Volume(ctx context.Context, chainID uint64, from, to int64, granularity string) ([]*VolumeRow, error) {
+	bucket, err := bucketExpr(granularity, "bucket")
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + bucket + ` AS b, market, count(), toString(sum(volume))
+	          FROM (
+	              SELECT ` + hourOf("created_at") + ` AS bucket, 'rfq' AS market, toUInt256OrZero(amount) AS volume
+	              FROM pagga_data.rfqs FINAL WHERE chain_id = ?
+	              UNION ALL
+	              SELECT ` + hourOf("created_at") + `, 'auction', toUInt256OrZero(amount)
+	              FROM pagga_data.auctions FINAL WHERE chain_id = ?
+	              UNION ALL
+	              SELECT ` + hourOf("created_at") + `, 'credit_line', toUInt256OrZero("limit")
+	              FROM pagga_data.credit_lines FINAL WHERE chain_id = ?
+	          )
+	          WHERE bucket >= toDateTime(?, 'UTC') AND bucket < toDateTime(?, 'UTC')
+	          GROUP BY b, market ORDER BY b, market`
+	args := []interface{}{chainID, chainID, chainID, from, to}
+	return scanRows(ctx, r.db, query, args, func(rows *sql.Rows) (*VolumeRow, error) {
+		row := new(VolumeRow)
+		return row, rows.Scan(&row.Bucket, &row.Market, &row.Count, &row.Volume)
+	})
+}
+
+// ClearingRates returns the average rate credit lines were opened at per bucket and duration bucket
+func (r *AnalyticsRepository) ClearingRates(ctx context.Context, chainID uint64, from, to int64, granularity string) ([]*ClearingRateRow, error) {
+	bucket, err := bucketExpr(granularity, "bucket")
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + bucket + ` AS b, duration_bucket, count(), avg(rate_bps)
+	          FROM (
+	              SELECT ` + hourOf("created_at") + ` AS bucket, rate_bps,
+	                     multiIf(expires_at - created_at < 7 * 86400, '0-7d',
+	                             expires_at - created_at < 30 * 86400, '7-30d',
+	                             expires_at - created_at < 90 * 86400, '30-90d',
+	                             '90d+') AS duration_bucket
+	              FROM pagga_data.credit_lines FINAL WHERE chain_id = ?
+	          )
+	          WHERE bucket >= toDateTime(?, 'UTC') AND bucket < toDateTime(?, 'UTC')
+	          GROUP BY b, duration_bucket ORDER BY b, duration_bucket`
+	return scanRows(ctx, r.db, query, []interface{}{chainID, from, to}, func(rows *sql.Rows) (*ClearingRateRow, error) {
+		row := new(ClearingRateRow)
+		return row, rows.Scan(&row.Bucket, &row.DurationBucket, &row.Lines, &row.AvgRateBps)
+	})
+}
+
+// LenderVolume returns the credit line volume opened by each lender per bucket
+func (r *AnalyticsRepository) LenderVolume(ctx context.Context, chainID uint64, from, to int64, granularity string) ([]*LenderVolumeRow, error) {
+	bucket, err := bucketExpr(granularity, "bucket")
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + bucket + ` AS b, lender_address, count(), toString(sum(volume))
+	          FROM (
+	              SELECT ` + hourOf("created_at") + ` AS bucket, lower(lender_address) AS lender_address,
+	                     toUInt256OrZero("limit") AS volume
+	              FROM pagga_data.credit_lines FINAL WHERE chain_id = ?
+	          )
+	          WHERE bucket >= toDateTime(?, 'UTC') AND bucket < toDateTime(?, 'UTC')
+	          GROUP BY b, lender_address ORDER BY b, sum(volume) DESC`
+	return scanRows(ctx, r.db, query, []interface{}{chainID, from, to}, func(rows *sql.Rows) (*LenderVolumeRow, error) {
+		row := new(LenderVolumeRow)
+		return row, rows.Scan(&row.Bucket, &row.LenderAddress, &row.Lines, &row.Volume)
+	})
+}
+
+// MarketActivity returns requests, quotes (bids) and fills per bucket and market
+func (r *AnalyticsRepository) MarketActivity(ctx context.Context, chainID uint64, from, to int64, granularity string) ([]*MarketActivityRow, error) {
+	bucket, err := bucketExpr(granularity, "bucket")
+	if err != nil {
+		return nil, err
+	}
+	// A fill counts in the hour its credit line was opened
+	query := `SELECT ` + bucket + ` AS b, market, countIf(kind = 'request'), countIf(kind = 'quote'), countIf(kind = 'fill')
+	          FROM (
+	              SELECT ` + hourOf("created_at") + ` AS bucket, 'rfq' AS market, 'request' AS kind
+	              FROM pagga_data.rfqs FINAL WHERE chain_id = ?
+	              UNION ALL
+	              SELECT ` + hourOf("submitted_at") + `, 'rfq', 'quote'
+	              FROM pagga_data.quotes FINAL WHERE chain_id = ?
+	              UNION ALL
+	              SELECT ` + hourOf("created_at") + `, 'auction', 'request'
+	              FROM pagga_data.auctions FINAL WHERE chain_id = ?
+	              UNION ALL
+	              SELECT ` + hourOf("timestamp") + `, 'auction', 'quote'
+	              FROM pagga_data.bids FINAL WHERE chain_id = ?
+	              UNION ALL
+	              SELECT ` + hourOf("l.created_at") + `, s.source, 'fill'
+	              FROM pagga_data.credit_line_sources AS s FINAL
+	              INNER JOIN (SELECT id, created_at FROM pagga_data.credit_lines FINAL WHERE chain_id = ?) AS l
+	                  ON l.id = s.credit_line_id
+	              WHERE s.chain_id = ?
+	          )
+	          WHERE bucket >= toDateTime(?, 'UTC') AND bucket < toDateTime(?, 'UTC')
+	          GROUP BY b, market ORDER BY b, market`
+	args := []interface{}{chainID, chainID, chainID, chainID, chainID, chainID, from, to}
+	return scanRows(ctx, r.db, query, args, func(rows *sql.Rows) (*MarketActivityRow, error) {
+		row := new(MarketActivityRow)
+		return row, rows.Scan(&row.Bucket, &row.Market, &row.Requests, &row.Quotes, &row.Fills)
+	})
+}
+
+// TimeToFirstQuote returns, per bucket of RFQ creation, how long RFQs waited for their first quote
+func (r *AnalyticsRepository) TimeToFirstQuote(ctx context.Context, chainID uint64, from, to int64, granularity string) ([]*FirstQuoteRow, error) {
+	bucket, err := bucketExpr(granularity, "toDateTime(created_at, 'UTC')")
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + bucket + ` AS b, count(), countIf(quoted),
+	                 ifNotFinite(avgIf(wait, quoted), 0),
+	                 ifNotFinite(quantileIf(0.5)(wait, quoted), 0),
+	                 ifNotFinite(quantileIf(0.9)(wait, quoted), 0)
+	          FROM (
+	              SELECT r.created_at AS created_at, q.first_quote_at > 0 AS quoted, q.first_quote_at - r.created_at AS wait
+	              FROM (
+	                  SELECT id, created_at FROM pagga_data.rfqs FINAL
+	                  WHERE chain_id = ? AND created_at >= ? AND created_at < ?
+	              ) AS r
+	              LEFT JOIN (
+	                  SELECT rfq_id, min(submitted_at) AS first_quote_at
+	                  FROM pagga_data.quotes FINAL WHERE chain_id = ? GROUP BY rfq_id
+	              ) AS q ON q.rfq_id = r.id
+	          )
+	          GROUP BY b ORDER BY b`
+	return scanRows(ctx, r.db, query, []interface{}{chainID, from, to, chainID}, func(rows *sql.Rows) (*FirstQuoteRow, error) {
+		row := new(FirstQuoteRow)
+		return row, rows.Scan(&row.Bucket, &row.RFQs, &row.Quoted, &row.AvgSeconds, &row.MedianSeconds, &row.P90Seconds)
+	})
+}
+
+// AquaLiquidity returns Aqua liquidity movements per bucket with running connected and reserved totals
+func (r *AnalyticsRepository) AquaLiquidity(ctx context.Context, chainID uint64, from, to int64, granularity string) ([]*AquaLiquidityRow, error) {
+	bucket, err := bucketExpr(granularity, "bucket")
+	if err != nil {
+		return nil, err
+	}
+	// Running totals need every movement since the first one, the range is applied afterwards
+	query := `SELECT b, toString(c), toString(w), toString(rs), toString(rl), toString(total), toString(in_use)
+	          FROM (
+	              SELECT ` + bucket + ` AS b, max(bucket) AS last,
+	                     sumIf(value, event_type = 'liquidity_connected') AS c,
+	                     sumIf(value, event_type = 'liquidity_withdrawn') AS w,
+	                     sumIf(value, event_type = 'liquidity_reserved') AS rs,
+	                     sumIf(value, event_type = 'liquidity_released') AS rl,
+	                     sum(c - w) OVER (ORDER BY b) AS total,
+	                     sum(rs - rl) OVER (ORDER BY b) AS in_use
+	              FROM (
+	                  SELECT ` + hourOf("timestamp") + ` AS bucket, event_type, toInt256OrZero(amount) AS value
+	                  FROM pagga_data.aqua_liquidity_events FINAL WHERE chain_id = ?
+	              )
+	              WHERE bucket < toDateTime(?, 'UTC')
+	              GROUP BY b
+	          )
+	          WHERE last >= toDateTime(?, 'UTC')
+	          ORDER BY b`
+	return scanRows(ctx, r.db, query, []interface{}{chainID, to, from}, func(rows *sql.Rows) (*AquaLiquidityRow, error) {
+		row := new(AquaLiquidityRow)
+		return row, rows.Scan(&row.Bucket, &row.Connected, &row.Withdrawn, &row.Reserved, &row.Released, &row.TotalConnected, &row.InUse)
+	})
+}
+
+// hourOf is the hour a Unix timestamp column falls in, as a DateTime('UTC')
+func hourOf(column string) string {
+	return "toStartOfHour(toDateTime(" + column + ", 'UTC'))"
+}
+
+// scanRows runs a query and scans every row with scan
+func scanRows[T any](ctx context.Context, db *instrumentedDB, query string, args []interface{}, scan func(*sql.Rows) (*T, error)) ([]*T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*T
+	for rows.Next() {
+		row, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// VolumeRow is the count and volume of a market in a bucket
+type VolumeRow struct {
+	Bucket int64
+	Market string
+	Count  uint64
+	Volume string
+}
+
+// ClearingRateRow is the average opening rate of credit lines in a bucket and duration bucket
+type ClearingRateRow struct {
+	Bucket         int64
+	DurationBucket string
+	Lines          uint64
+	AvgRateBps     float64
+}
+
+// LenderVolumeRow is the credit line volume opened by a lender in a bucket
+type LenderVolumeRow struct {
+	Bucket        int64
+	LenderAddress string
+	Lines         uint64
+	Volume        string
+}
+
+// MarketActivityRow counts requests, quotes and fills of a market in a bucket
+type MarketActivityRow struct {
+	Bucket   int64
+	Market   string
+	Requests uint64
+	Quotes   uint64
+	Fills    uint64
+}
+
+// FirstQuoteRow summarizes the wait for a first quote of RFQs created in a bucket
+type FirstQuoteRow struct {
+	Bucket        int64
+	RFQs          uint64
+	Quoted        uint64
+	AvgSeconds    float64
+	MedianSeconds float64
+	P90Seconds    float64
+}
+
+// AquaLiquidityRow holds Aqua liquidity movements in a bucket and the running totals at its end
+type AquaLiquidityRow struct {
+	Bucket         int64
+	Connected      string
+	Withdrawn      string
+	Reserved       string
+	Released       string
+	TotalConnected string
+	InUse          string
+}
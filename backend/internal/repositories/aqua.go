@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+)
+
+// Aqua liquidity event types
+const (
+	LiquidityConnected = "liquidity_connected"
+	LiquidityWithdrawn = "liquidity_withdrawn"
+	LiquidityReserved  = "liquidity_reserved"
+	LiquidityReleased  = "liquidity_released"
+)
+
+// AquaRepository handles AquaIntegration liquidity data operations
+type AquaRepository struct {
+	*Repository
+}
+
+// NewAquaRepository creates a new Aqua liquidity repository
+func NewAquaRepository(repo *Repository) *AquaRepository {
+	return &AquaRepository{Repository: repo}
+}
+
+// SaveLiquidityEvent saves a liquidity connection, withdrawal, reservation or release, replacing the event
+// stored for the same log
+func (r *AquaRepository) SaveLiquidityEvent(ctx context.Context, event *LiquidityEventModel) error {
+	query := `INSERT INTO pagga_data.aqua_liquidity_events (chain_id, lender_address, event_type, amount, tx_hash, block_number, log_index, timestamp)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		event.ChainID, event.LenderAddress, event.EventType, event.Amount, event.TxHash,
+		event.BlockNumber, event.LogIndex, event.Timestamp)
+	return err
+}
+
+// LiquidityEventModel represents an AquaIntegration liquidity event in ClickHouse
+type LiquidityEventModel struct {
+	ChainID       uint64
+	LenderAddress string
+	EventType     string
+	Amount        string
+	TxHash        string
+	BlockNumber   uint64
+	LogIndex      uint32
+	Timestamp     int64
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// CheckpointRepository stores how far the event monitor of each chain has got
+type CheckpointRepository struct {
+	*Repository
+}
+
+// NewCheckpointRepository creates a new monitor checkpoint repository
+func NewCheckpointRepository(repo *Repository) *CheckpointRepository {
+	return &CheckpointRepository{Repository: repo}
+}
+
+// SaveCheckpoint records lastBlock as the last block processed on a chain
+func (r *CheckpointRepository) SaveCheckpoint(ctx context.Context, chainID, lastBlock uint64) error {
+	query := `INSERT INTO pagga_data.monitor_checkpoints (chain_id, last_block, updated_at) VALUES (?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, chainID, lastBlock, time.Now().UnixNano())
+	return err
+}
+
+// GetCheckpoint returns the last block processed on a chain, sql.ErrNoRows when none was saved
+func (r *CheckpointRepository) GetCheckpoint(ctx context.Context, chainID uint64) (uint64, error) {
+	var lastBlock uint64
+	query := `SELECT last_block FROM pagga_data.monitor_checkpoints FINAL WHERE chain_id = ?`
+	err := r.db.QueryRowContext(ctx, query, chainID).Scan(&lastBlock)
+	return lastBlock, err
+}
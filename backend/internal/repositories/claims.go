@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// keeperException is the code of ClickHouse errors raised by ZooKeeper, such as a KeeperMap key that
+// already exists or a row changed since it was read
+const keeperException = 999
+
+// claimAttempts bounds how often Claim retries a key whose expired claim is being taken over
+const claimAttempts = 3
+
+// ClaimRepository holds exclusive, expiring claims on keys in the KeeperMap table claims. Its rows live in
+// ZooKeeper, shared by every ClickHouse node, and statements run in keeper_map_strict_mode: an insert
+// of a key already present fails and a delete fails if the row changed since it was read, so of the
+// replicas racing for a key exactly one gets it.
+type ClaimRepository struct {
+	*Repository
+}
+
+// NewClaimRepository creates a new claim repository
+func NewClaimRepository(repo *Repository) *ClaimRepository {
+	return &ClaimRepository{Repository: repo}
+}
+
+// Claim takes claim.Key for claim.Owner. When another owner holds an unexpired claim on the key, that
+// claim is returned and nothing is written. An expired claim is removed and the key taken over.
+func (r *ClaimRepository) Claim(ctx context.Context, claim *ClaimModel) (*ClaimModel, error) {
+	ctx = strictKeeperMap(ctx)
+	for attempt := 0; attempt < claimAttempts; attempt++ {
+		_, err := r.db.ExecContext(ctx, `INSERT INTO pagga_data.claims (key, owner, value, expires_at) VALUES (?, ?, ?, ?)`,
+			claim.Key, claim.Owner, claim.Value, claim.ExpiresAt)
+		if err == nil {
+			return nil, nil
+		}
+		if !isKeeperConflict(err) {
+			return nil, err
+		}
+
+		held, err := r.get(ctx, claim.Key)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Released since the insert, try again
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if held.ExpiresAt > time.Now().Unix() {
+			return held, nil
+		}
+		// Only the expired row is deleted: if another replica took the key over meanwhile, the row's
+		// version changed and the delete fails
+		_, err = r.db.ExecContext(ctx, `ALTER TABLE pagga_data.claims DELETE WHERE key = ? AND owner = ? AND expires_at = ?`,
+			held.Key, held.Owner, held.ExpiresAt)
+		if err != nil && !isKeeperConflict(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("claim on %s is contended", claim.Key)
+}
+
+// Release drops the claim of owner on key, if it still holds it
+func (r *ClaimRepository) Release(ctx context.Context, key, owner string) error {
+	_, err := r.db.ExecContext(strictKeeperMap(ctx), `ALTER TABLE pagga_data.claims DELETE WHERE key = ? AND owner = ?`, key, owner)
+	return err
+}
+
+func (r *ClaimRepository) get(ctx context.Context, key string) (*ClaimModel, error) {
+	claim := new(ClaimModel)
+	err := r.db.QueryRowContext(ctx, `SELECT key, owner, value, expires_at FROM pagga_data.claims WHERE key = ?`, key).Scan(
+		&claim.Key, &claim.Owner, &claim.Value, &claim.ExpiresAt)
+	return claim, err
+}
+
+// strictKeeperMap runs the statements of ctx in keeper_map_strict_mode
+func strictKeeperMap(ctx context.Context) context.Context {
+	return clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"keeper_map_strict_mode": 1}))
+}
+
+// isKeeperConflict reports a strict mode refusal: the key exists, or the row changed since it was read
+func isKeeperConflict(err error) bool {
+	var exception *clickhouse.Exception
+	if !errors.As(err, &exception) || exception.Code != keeperException {
+		return false
+	}
+	message := strings.ToLower(exception.Message)
+	return strings.Contains(message, "already exists") || strings.Contains(message, "node exists") ||
+		strings.Contains(message, "bad version")
+}
+
+// ClaimModel is a claim on Key held by Owner until ExpiresAt, Value is what the owner keeps with it
+type ClaimModel struct {
+	Key       string
+	Owner     string
+	Value     string
+	ExpiresAt int64
+}
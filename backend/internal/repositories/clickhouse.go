@@ -9,7 +9,7 @@ import (
 
 // Repository handles database operations using ClickHouse directly
 type Repository struct {
-	db *sql.DB
+	db *instrumentedDB
 }
 
 // NewRepository creates a new repository instance
@@ -19,7 +19,7 @@ func NewRepository(dsn string) (*Repository, error) {
 		return nil, err
 	}
 
-	return &Repository{db: sqldb}, nil
+	return &Repository{db: &instrumentedDB{DB: sqldb}}, nil
 }
 
 // Close closes the database connection
@@ -27,9 +27,14 @@ func (r *Repository) Close() error {
 	return r.db.Close()
 }
 
+// Ping checks that ClickHouse answers
+func (r *Repository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
 // DB returns the underlying database instance
 func (r *Repository) DB() *sql.DB {
-	return r.db
+	return r.db.DB
 }
 
 // RFQRepository handles RFQ data operations
@@ -44,51 +49,115 @@ func NewRFQRepository(repo *Repository) *RFQRepository {
 
 // SaveRFQ saves an RFQ to the database
 func (r *RFQRepository) SaveRFQ(ctx context.Context, rfq *RFQModel) error {
-	query := `INSERT INTO pagga_data.rfqs (borrower_address, amount, duration, collateral_type, flow_description, status, created_at) 
-	          VALUES (?, ?, ?, ?, ?, ?, toDateTime(?))`
+	query := `INSERT INTO pagga_data.rfqs (chain_id, id, borrower_address, amount, duration, collateral_type, flow_description, status, created_at) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, toDateTime(?))`
 	_, err := r.db.ExecContext(ctx, query,
-		rfq.BorrowerAddress, rfq.Amount, rfq.Duration, rfq.CollateralType,
+		rfq.ChainID, rfq.ID, rfq.BorrowerAddress, rfq.Amount, rfq.Duration, rfq.CollateralType,
 		rfq.FlowDescription, rfq.Status, rfq.CreatedAt)
 	return err
 }
 
-// GetRFQ retrieves an RFQ by ID
-func (r *RFQRepository) GetRFQ(ctx context.Context, id uint64) (*RFQModel, error) {
+// GetRFQ retrieves an RFQ by chain and ID
+func (r *RFQRepository) GetRFQ(ctx context.Context, chainID, id uint64) (*RFQModel, error) {
 	rfq := new(RFQModel)
-	query := `SELECT id, borrower_address, amount, duration, collateral_type, flow_description, status, toUnixTimestamp(created_at) as created_at 
-	          FROM pagga_data.rfqs WHERE id = ?`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&rfq.ID, &rfq.BorrowerAddress, &rfq.Amount, &rfq.Duration,
+	query := `SELECT chain_id, id, borrower_address, amount, duration, collateral_type, flow_description, status, toUnixTimestamp(created_at) as created_at 
+	          FROM pagga_data.rfqs FINAL WHERE chain_id = ? AND id = ?`
+	err := r.db.QueryRowContext(ctx, query, chainID, id).Scan(
+		&rfq.ChainID, &rfq.ID, &rfq.BorrowerAddress, &rfq.Amount, &rfq.Duration,
 		&rfq.CollateralType, &rfq.FlowDescription, &rfq.Status, &rfq.CreatedAt)
 	return rfq, err
 }
 
-// ListRFQs retrieves RFQs with pagination
-func (r *RFQRepository) ListRFQs(ctx context.Context, limit, offset int) ([]*RFQModel, error) {
-	query := `SELECT id, borrower_address, amount, duration, collateral_type, flow_description, status, toUnixTimestamp(created_at) as created_at 
-	          FROM pagga_data.rfqs ORDER BY created_at DESC LIMIT ? OFFSET ?`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// rfqList lists RFQs. The lender filter matches RFQs the lender quoted on.
+var rfqList = &listSchema{
+	table:       "pagga_data.rfqs FINAL",
+	columns:     "chain_id, id, borrower_address, amount, duration, collateral_type, flow_description, status, toUnixTimestamp(created_at) as created_at",
+	defaultSort: "created_at",
+	sorts: map[string]listSort{
+		"created_at": {expr: "toInt64(created_at)", param: "toInt64(?)"},
+		"amount":     amountSort,
+		"duration":   durationSort,
+		"id":         idSort,
+	},
+	filters: map[string]string{
+		FilterStatus:         "status = ?",
+		FilterBorrower:       "lower(borrower_address) = lower(?)",
+		FilterLender:         "(chain_id, id) IN (SELECT chain_id, rfq_id FROM pagga_data.quotes FINAL WHERE lower(lender_address) = lower(?))",
+		FilterAmountMin:      "toUInt256OrZero(amount) >= toUInt256(?)",
+		FilterAmountMax:      "toUInt256OrZero(amount) <= toUInt256(?)",
+		FilterDurationMin:    "duration >= ?",
+		FilterDurationMax:    "duration <= ?",
+		FilterCollateralType: "collateral_type = ?",
+		FilterCreatedFrom:    "toInt64(created_at) >= ?",
+		FilterCreatedTo:      "toInt64(created_at) < ?",
+	},
+}
 
-	var rfqs []*RFQModel
-	for rows.Next() {
+// ListRFQs retrieves a page of RFQs matching q
+func (r *RFQRepository) ListRFQs(ctx context.Context, q ListQuery) (*Page[RFQModel], error) {
+	return listPage(ctx, r.db, rfqList, q, "", nil, func(rows *sql.Rows, sortValue *string) (*RFQModel, error) {
 		rfq := new(RFQModel)
-		err := rows.Scan(
-			&rfq.ID, &rfq.BorrowerAddress, &rfq.Amount, &rfq.Duration,
-			&rfq.CollateralType, &rfq.FlowDescription, &rfq.Status, &rfq.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
-		rfqs = append(rfqs, rfq)
-	}
-	return rfqs, rows.Err()
+		return rfq, rows.Scan(
+			&rfq.ChainID, &rfq.ID, &rfq.BorrowerAddress, &rfq.Amount, &rfq.Duration,
+			&rfq.CollateralType, &rfq.FlowDescription, &rfq.Status, &rfq.CreatedAt, sortValue)
+	}, func(rfq *RFQModel) uint64 { return rfq.ID })
+}
+
+// SaveQuote saves a quote submitted on an RFQ, replacing the quote stored for the same log
+func (r *RFQRepository) SaveQuote(ctx context.Context, quote *QuoteModel) error {
+	query := `INSERT INTO pagga_data.quotes (chain_id, id, rfq_id, lender_address, rate_bps, limit, collateral_required, submitted_at, accepted,
+	              tx_hash, block_number, log_index)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		quote.ChainID, quote.ID, quote.RFQID, quote.LenderAddress, quote.RateBps, quote.Limit,
+		quote.CollateralRequired, quote.SubmittedAt, quote.Accepted, quote.TxHash, quote.BlockNumber, quote.LogIndex)
+	return err
+}
+
+// QuoteIndex returns the number of quotes of an RFQ emitted before the log at blockNumber and logIndex,
+// the index of the quote that log submitted. It is the same when the log is processed again.
+func (r *RFQRepository) QuoteIndex(ctx context.Context, chainID, rfqID, blockNumber uint64, logIndex uint32) (uint64, error) {
+	var count uint64
+	err := r.db.QueryRowContext(ctx, `SELECT count() FROM pagga_data.quotes FINAL
+	          WHERE chain_id = ? AND rfq_id = ? AND (block_number, log_index) < (?, ?)`,
+		chainID, rfqID, blockNumber, logIndex).Scan(&count)
+	return count, err
+}
+
+// quoteList lists the quotes of an RFQ. Amounts are quote limits and status is accepted or open.
+var quoteList = &listSchema{
+	table:       "pagga_data.quotes FINAL",
+	columns:     `chain_id, id, rfq_id, lender_address, rate_bps, "limit", collateral_required, submitted_at, accepted`,
+	defaultSort: "created_at",
+	sorts: map[string]listSort{
+		"created_at": {expr: "submitted_at", param: "toInt64(?)"},
+		"amount":     {expr: `toUInt256OrZero("limit")`, param: "toUInt256(?)"},
+		"rate_bps":   {expr: "rate_bps", param: "toUInt16(?)"},
+		"id":         idSort,
+	},
+	filters: map[string]string{
+		FilterStatus:      "if(accepted = 1, 'accepted', 'open') = ?",
+		FilterLender:      "lower(lender_address) = lower(?)",
+		FilterAmountMin:   `toUInt256OrZero("limit") >= toUInt256(?)`,
+		FilterAmountMax:   `toUInt256OrZero("limit") <= toUInt256(?)`,
+		FilterCreatedFrom: "submitted_at >= ?",
+		FilterCreatedTo:   "submitted_at < ?",
+	},
+}
+
+// ListQuotes retrieves a page of the quotes submitted on an RFQ
+func (r *RFQRepository) ListQuotes(ctx context.Context, rfqID uint64, q ListQuery) (*Page[QuoteModel], error) {
+	return listPage(ctx, r.db, quoteList, q, "rfq_id = ?", []interface{}{rfqID}, func(rows *sql.Rows, sortValue *string) (*QuoteModel, error) {
+		quote := new(QuoteModel)
+		return quote, rows.Scan(
+			&quote.ChainID, &quote.ID, &quote.RFQID, &quote.LenderAddress, &quote.RateBps, &quote.Limit,
+			&quote.CollateralRequired, &quote.SubmittedAt, &quote.Accepted, sortValue)
+	}, func(quote *QuoteModel) uint64 { return quote.ID })
 }
 
 // RFQModel represents RFQ data in ClickHouse
 type RFQModel struct {
+	ChainID         uint64
 	ID              uint64
 	BorrowerAddress string
 	Amount          string
@@ -111,51 +180,115 @@ func NewAuctionRepository(repo *Repository) *AuctionRepository {
 
 // SaveAuction saves an Auction to the database
 func (r *AuctionRepository) SaveAuction(ctx context.Context, auction *AuctionModel) error {
-	query := `INSERT INTO pagga_data.auctions (borrower_address, amount, duration, end_time, status, created_at) 
-	          VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO pagga_data.auctions (chain_id, id, borrower_address, amount, duration, end_time, status, created_at) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 	_, err := r.db.ExecContext(ctx, query,
-		auction.BorrowerAddress, auction.Amount, auction.Duration,
+		auction.ChainID, auction.ID, auction.BorrowerAddress, auction.Amount, auction.Duration,
 		auction.EndTime, auction.Status, auction.CreatedAt)
 	return err
 }
 
-// GetAuction retrieves an Auction by ID
-func (r *AuctionRepository) GetAuction(ctx context.Context, id uint64) (*AuctionModel, error) {
+// GetAuction retrieves an Auction by chain and ID
+func (r *AuctionRepository) GetAuction(ctx context.Context, chainID, id uint64) (*AuctionModel, error) {
 	auction := new(AuctionModel)
-	query := `SELECT id, borrower_address, amount, duration, end_time, status, created_at 
-	          FROM pagga_data.auctions WHERE id = ?`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&auction.ID, &auction.BorrowerAddress, &auction.Amount, &auction.Duration,
+	query := `SELECT chain_id, id, borrower_address, amount, duration, end_time, status, created_at 
+	          FROM pagga_data.auctions FINAL WHERE chain_id = ? AND id = ?`
+	err := r.db.QueryRowContext(ctx, query, chainID, id).Scan(
+		&auction.ChainID, &auction.ID, &auction.BorrowerAddress, &auction.Amount, &auction.Duration,
 		&auction.EndTime, &auction.Status, &auction.CreatedAt)
 	return auction, err
 }
 
-// ListAuctions retrieves Auctions with pagination
-func (r *AuctionRepository) ListAuctions(ctx context.Context, limit, offset int) ([]*AuctionModel, error) {
-	query := `SELECT id, borrower_address, amount, duration, end_time, status, created_at 
-	          FROM pagga_data.auctions ORDER BY created_at DESC LIMIT ? OFFSET ?`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// auctionList lists auctions. The lender filter matches auctions the lender bid on.
+var auctionList = &listSchema{
+	table:       "pagga_data.auctions FINAL",
+	columns:     "chain_id, id, borrower_address, amount, duration, end_time, status, created_at",
+	defaultSort: "created_at",
+	sorts: map[string]listSort{
+		"created_at": {expr: "toInt64(created_at)", param: "toInt64(?)"},
+		"end_time":   {expr: "end_time", param: "toInt64(?)"},
+		"amount":     amountSort,
+		"duration":   durationSort,
+		"id":         idSort,
+	},
+	filters: map[string]string{
+		FilterStatus:      "status = ?",
+		FilterBorrower:    "lower(borrower_address) = lower(?)",
+		FilterLender:      "(chain_id, id) IN (SELECT chain_id, auction_id FROM pagga_data.bids FINAL WHERE lower(lender_address) = lower(?))",
+		FilterAmountMin:   "toUInt256OrZero(amount) >= toUInt256(?)",
+		FilterAmountMax:   "toUInt256OrZero(amount) <= toUInt256(?)",
+		FilterDurationMin: "duration >= ?",
+		FilterDurationMax: "duration <= ?",
+		FilterCreatedFrom: "toInt64(created_at) >= ?",
+		FilterCreatedTo:   "toInt64(created_at) < ?",
+	},
+}
 
-	var auctions []*AuctionModel
-	for rows.Next() {
+// ListAuctions retrieves a page of auctions matching q
+func (r *AuctionRepository) ListAuctions(ctx context.Context, q ListQuery) (*Page[AuctionModel], error) {
+	return listPage(ctx, r.db, auctionList, q, "", nil, func(rows *sql.Rows, sortValue *string) (*AuctionModel, error) {
 		auction := new(AuctionModel)
-		err := rows.Scan(
-			&auction.ID, &auction.BorrowerAddress, &auction.Amount, &auction.Duration,
-			&auction.EndTime, &auction.Status, &auction.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
-		auctions = append(auctions, auction)
-	}
-	return auctions, rows.Err()
+		return auction, rows.Scan(
+			&auction.ChainID, &auction.ID, &auction.BorrowerAddress, &auction.Amount, &auction.Duration,
+			&auction.EndTime, &auction.Status, &auction.CreatedAt, sortValue)
+	}, func(auction *AuctionModel) uint64 { return auction.ID })
+}
+
+// SaveBid saves a bid placed on an auction, replacing the bid stored for the same log
+func (r *AuctionRepository) SaveBid(ctx context.Context, bid *BidModel) error {
+	query := `INSERT INTO pagga_data.bids (chain_id, id, auction_id, lender_address, rate_bps, limit, timestamp, is_winning,
+	              tx_hash, block_number, log_index)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		bid.ChainID, bid.ID, bid.AuctionID, bid.LenderAddress, bid.RateBps, bid.Limit,
+		bid.Timestamp, bid.IsWinning, bid.TxHash, bid.BlockNumber, bid.LogIndex)
+	return err
+}
+
+// BidIndex returns the number of bids on an auction emitted before the log at blockNumber and logIndex,
+// the index of the bid that log placed. It is the same when the log is processed again.
+func (r *AuctionRepository) BidIndex(ctx context.Context, chainID, auctionID, blockNumber uint64, logIndex uint32) (uint64, error) {
+	var count uint64
+	err := r.db.QueryRowContext(ctx, `SELECT count() FROM pagga_data.bids FINAL
+	          WHERE chain_id = ? AND auction_id = ? AND (block_number, log_index) < (?, ?)`,
+		chainID, auctionID, blockNumber, logIndex).Scan(&count)
+	return count, err
+}
+
+// bidList lists the bids of an auction. Amounts are bid limits and status is winning or open.
+var bidList = &listSchema{
+	table:       "pagga_data.bids FINAL",
+	columns:     `chain_id, id, auction_id, lender_address, rate_bps, "limit", timestamp, is_winning`,
+	defaultSort: "created_at",
+	sorts: map[string]listSort{
+		"created_at": {expr: "timestamp", param: "toInt64(?)"},
+		"amount":     {expr: `toUInt256OrZero("limit")`, param: "toUInt256(?)"},
+		"rate_bps":   {expr: "rate_bps", param: "toUInt16(?)"},
+		"id":         idSort,
+	},
+	filters: map[string]string{
+		FilterStatus:      "if(is_winning = 1, 'winning', 'open') = ?",
+		FilterLender:      "lower(lender_address) = lower(?)",
+		FilterAmountMin:   `toUInt256OrZero("limit") >= toUInt256(?)`,
+		FilterAmountMax:   `toUInt256OrZero("limit") <= toUInt256(?)`,
+		FilterCreatedFrom: "timestamp >= ?",
+		FilterCreatedTo:   "timestamp < ?",
+	},
+}
+
+// ListBids retrieves a page of the bids placed on an auction
+func (r *AuctionRepository) ListBids(ctx context.Context, auctionID uint64, q ListQuery) (*Page[BidModel], error) {
+	return listPage(ctx, r.db, bidList, q, "auction_id = ?", []interface{}{auctionID}, func(rows *sql.Rows, sortValue *string) (*BidModel, error) {
+		bid := new(BidModel)
+		return bid, rows.Scan(
+			&bid.ChainID, &bid.ID, &bid.AuctionID, &bid.LenderAddress, &bid.RateBps, &bid.Limit,
+			&bid.Timestamp, &bid.IsWinning, sortValue)
+	}, func(bid *BidModel) uint64 { return bid.ID })
 }
 
 // AuctionModel represents Auction data in ClickHouse
 type AuctionModel struct {
+	ChainID         uint64
 	ID              uint64
 	BorrowerAddress string
 	Amount          string
@@ -165,3 +298,36 @@ type AuctionModel struct {
 	Status          string
 	CreatedAt       int64
 }
+
+// QuoteModel represents quote data in ClickHouse
+type QuoteModel struct {
+	ChainID            uint64
+	ID                 uint64
+	RFQID              uint64
+	LenderAddress      string
+	RateBps            uint16
+	Limit              string
+	CollateralRequired string
+	SubmittedAt        int64
+	Accepted           uint8
+	// TxHash, BlockNumber and LogIndex identify the log that submitted the quote
+	TxHash      string
+	BlockNumber uint64
+	LogIndex    uint32
+}
+
+// BidModel represents bid data in ClickHouse
+type BidModel struct {
+	ChainID       uint64
+	ID            uint64
+	AuctionID     uint64
+	LenderAddress string
+	RateBps       uint16
+	Limit         string
+	Timestamp     int64
+	IsWinning     uint8
+	// TxHash, BlockNumber and LogIndex identify the log that placed the bid
+	TxHash      string
+	BlockNumber uint64
+	LogIndex    uint32
+}
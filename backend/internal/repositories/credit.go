@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+)
+
+// CreditRepository handles x402 credit line data operations
+type CreditRepository struct {
+	*Repository
+}
+
+// NewCreditRepository creates a new credit line repository
+func NewCreditRepository(repo *Repository) *CreditRepository {
+	return &CreditRepository{Repository: repo}
+}
+
+// SaveCreditLine saves a credit line to the database
+func (r *CreditRepository) SaveCreditLine(ctx context.Context, line *CreditLineModel) error {
+	query := `INSERT INTO pagga_data.credit_lines (chain_id, id, borrower_address, lender_address, limit, rate_bps, created_at, expires_at, tx_hash)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		line.ChainID, line.ID, line.BorrowerAddress, line.LenderAddress, line.Limit,
+		line.RateBps, line.CreatedAt, line.ExpiresAt, line.TxHash)
+	return err
+}
+
+// GetCreditLine retrieves a credit line by chain and ID
+func (r *CreditRepository) GetCreditLine(ctx context.Context, chainID, id uint64) (*CreditLineModel, error) {
+	line := new(CreditLineModel)
+	query := `SELECT chain_id, id, borrower_address, lender_address, limit, rate_bps, created_at, expires_at, tx_hash
+	          FROM pagga_data.credit_lines FINAL WHERE chain_id = ? AND id = ?`
+	err := r.db.QueryRowContext(ctx, query, chainID, id).Scan(
+		&line.ChainID, &line.ID, &line.BorrowerAddress, &line.LenderAddress, &line.Limit,
+		&line.RateBps, &line.CreatedAt, &line.ExpiresAt, &line.TxHash)
+	return line, err
+}
+
+// ListCreditLinesByBorrower retrieves all credit lines opened for a borrower on a chain
+func (r *CreditRepository) ListCreditLinesByBorrower(ctx context.Context, chainID uint64, borrower string) ([]*CreditLineModel, error) {
+	return r.listCreditLines(ctx, `chain_id = ? AND lower(borrower_address) = lower(?)`, chainID, borrower)
+}
+
+// ListCreditLinesByLender retrieves all credit lines a lender funds on a chain
+func (r *CreditRepository) ListCreditLinesByLender(ctx context.Context, chainID uint64, lender string) ([]*CreditLineModel, error) {
+	return r.listCreditLines(ctx, `chain_id = ? AND lower(lender_address) = lower(?)`, chainID, lender)
+}
+
+// listCreditLines retrieves the credit lines matching where, newest first
+func (r *CreditRepository) listCreditLines(ctx context.Context, where string, args ...interface{}) ([]*CreditLineModel, error) {
+	query := `SELECT chain_id, id, borrower_address, lender_address, limit, rate_bps, created_at, expires_at, tx_hash
+	          FROM pagga_data.credit_lines FINAL WHERE ` + where + ` ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []*CreditLineModel
+	for rows.Next() {
+		line := new(CreditLineModel)
+		err := rows.Scan(
+			&line.ChainID, &line.ID, &line.BorrowerAddress, &line.LenderAddress, &line.Limit,
+			&line.RateBps, &line.CreatedAt, &line.ExpiresAt, &line.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// ListExpiredCreditLines retrieves credit lines of every chain that expired before the given unix timestamp.
+// Settled lines, whose last risk status is settledStatus with nothing owed, are left out.
+func (r *CreditRepository) ListExpiredCreditLines(ctx context.Context, before int64, settledStatus string) ([]*CreditLineModel, error) {
+	query := `SELECT l.chain_id, l.id, l.borrower_address, l.lender_address, l.limit, l.rate_bps, l.created_at, l.expires_at, l.tx_hash
+	          FROM pagga_data.credit_lines AS l FINAL
+	          LEFT ANTI JOIN (
+	              SELECT chain_id, credit_line_id FROM pagga_data.credit_line_risk FINAL
+	              WHERE status = ? AND total_owed = '0'
+	          ) AS r ON r.chain_id = l.chain_id AND r.credit_line_id = l.id
+	          WHERE l.expires_at < ? ORDER BY l.expires_at`
+	rows, err := r.db.QueryContext(ctx, query, settledStatus, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []*CreditLineModel
+	for rows.Next() {
+		line := new(CreditLineModel)
+		err := rows.Scan(
+			&line.ChainID, &line.ID, &line.BorrowerAddress, &line.LenderAddress, &line.Limit,
+			&line.RateBps, &line.CreatedAt, &line.ExpiresAt, &line.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// SaveCreditLineSource records the RFQ or auction a credit line was opened from
+func (r *CreditRepository) SaveCreditLineSource(ctx context.Context, source *CreditLineSourceModel) error {
+	query := `INSERT INTO pagga_data.credit_line_sources (chain_id, credit_line_id, source, source_id, tx_hash)
+	          VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		source.ChainID, source.CreditLineID, source.Source, source.SourceID, source.TxHash)
+	return err
+}
+
+// GetCreditLineSource retrieves the RFQ or auction a credit line was opened from
+func (r *CreditRepository) GetCreditLineSource(ctx context.Context, chainID, creditLineID uint64) (*CreditLineSourceModel, error) {
+	source := new(CreditLineSourceModel)
+	query := `SELECT chain_id, credit_line_id, source, source_id, tx_hash
+	          FROM pagga_data.credit_line_sources FINAL WHERE chain_id = ? AND credit_line_id = ?`
+	err := r.db.QueryRowContext(ctx, query, chainID, creditLineID).Scan(
+		&source.ChainID, &source.CreditLineID, &source.Source, &source.SourceID, &source.TxHash)
+	return source, err
+}
+
+// SaveCreditEvent saves a draw or repay event to the database
+func (r *CreditRepository) SaveCreditEvent(ctx context.Context, event *CreditEventModel) error {
+	query := `INSERT INTO pagga_data.credit_events (chain_id, credit_line_id, event_type, amount, tx_hash, block_number, log_index, timestamp)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		event.ChainID, event.CreditLineID, event.EventType, event.Amount, event.TxHash,
+		event.BlockNumber, event.LogIndex, event.Timestamp)
+	return err
+}
+
+// ListCreditEvents retrieves all draw and repay events of a credit line in chain order
+func (r *CreditRepository) ListCreditEvents(ctx context.Context, chainID, creditLineID uint64) ([]*CreditEventModel, error) {
+	query := `SELECT chain_id, credit_line_id, event_type, amount, tx_hash, block_number, log_index, timestamp
+	          FROM pagga_data.credit_events FINAL WHERE chain_id = ? AND credit_line_id = ? ORDER BY block_number, log_index`
+	rows, err := r.db.QueryContext(ctx, query, chainID, creditLineID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*CreditEventModel
+	for rows.Next() {
+		event := new(CreditEventModel)
+		err := rows.Scan(
+			&event.ChainID, &event.CreditLineID, &event.EventType, &event.Amount, &event.TxHash,
+			&event.BlockNumber, &event.LogIndex, &event.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// SaveSnapshot saves a point-in-time view of a credit line balance for reporting
+func (r *CreditRepository) SaveSnapshot(ctx context.Context, snapshot *CreditSnapshotModel) error {
+	query := `INSERT INTO pagga_data.credit_line_snapshots (chain_id, credit_line_id, borrower_address, principal, accrued_interest, total_owed, interest_paid, interest_mode, snapshot_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		snapshot.ChainID, snapshot.CreditLineID, snapshot.BorrowerAddress, snapshot.Principal,
+		snapshot.AccruedInterest, snapshot.TotalOwed, snapshot.InterestPaid,
+		snapshot.InterestMode, snapshot.SnapshotAt)
+	return err
+}
+
+// CreditLineModel represents an x402 credit line in ClickHouse
+type CreditLineModel struct {
+	ChainID         uint64
+	ID              uint64
+	BorrowerAddress string
+	LenderAddress   string
+	Limit           string
+	RateBps         uint64
+	CreatedAt       int64
+	ExpiresAt       int64
+	TxHash          string
+}
+
+// Credit line sources
+const (
+	CreditSourceRFQ     = "rfq"
+	CreditSourceAuction = "auction"
+)
+
+// CreditLineSourceModel links a credit line to the RFQ or auction it was opened from
+type CreditLineSourceModel struct {
+	ChainID      uint64
+	CreditLineID uint64
+	Source       string
+	SourceID     uint64
+	TxHash       string
+}
+
+// Credit event types
+const (
+	CreditEventDraw  = "draw"
+	CreditEventRepay = "repay"
+)
+
+// CreditEventModel represents a draw or repay on a credit line in ClickHouse
+type CreditEventModel struct {
+	ChainID      uint64
+	CreditLineID uint64
+	EventType    string
+	Amount       string
+	TxHash       string
+	BlockNumber  uint64
+	LogIndex     uint32
+	Timestamp    int64
+}
+
+// CreditSnapshotModel represents a historical credit line balance in ClickHouse
+type CreditSnapshotModel struct {
+	ChainID         uint64
+	CreditLineID    uint64
+	BorrowerAddress string
+	Principal       string
+	AccruedInterest string
+	TotalOwed       string
+	InterestPaid    string
+	InterestMode    string
+	SnapshotAt      int64
+}
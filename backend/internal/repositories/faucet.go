@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Statuses of a faucet grant
+const (
+	// FaucetGrantReserved is held against the limits while its transaction is built
+	FaucetGrantReserved = "reserved"
+	// FaucetGrantSent has a transaction, see TxID
+	FaucetGrantSent = "sent"
+	// FaucetGrantReleased was given up before a transaction was made and no longer counts
+	FaucetGrantReleased = "released"
+)
+
+// FaucetRepository stores the faucet's grants. Requests for a chain are serialized by a claim on the
+// faucet, see ClaimRepository, so the grants read are all those made before.
+type FaucetRepository struct {
+	*ClaimRepository
+}
+
+// NewFaucetRepository creates a new faucet repository
+func NewFaucetRepository(repo *Repository) *FaucetRepository {
+	return &FaucetRepository{ClaimRepository: NewClaimRepository(repo)}
+}
+
+// SaveGrant inserts a new version of a grant
+func (r *FaucetRepository) SaveGrant(ctx context.Context, g *FaucetGrantModel) error {
+	query := `INSERT INTO pagga_data.faucet_grants (chain_id, id, token, address, ip, amount, tx_id, status, created_at, version)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		g.ChainID, g.ID, g.Token, g.Address, g.IP, g.Amount.String(), g.TxID, g.Status, g.CreatedAt,
+		uint64(time.Now().UnixNano()))
+	return err
+}
+
+// LastGrantTo returns when address was last granted token, 0 if never
+func (r *FaucetRepository) LastGrantTo(ctx context.Context, chainID uint64, token, address string) (int64, error) {
+	return r.lastGrant(ctx, chainID, token, "lower(address) = lower(?)", address)
+}
+
+// LastGrantFrom returns when a grant of token was last requested from ip, 0 if never
+func (r *FaucetRepository) LastGrantFrom(ctx context.Context, chainID uint64, token, ip string) (int64, error) {
+	return r.lastGrant(ctx, chainID, token, "ip = ?", ip)
+}
+
+func (r *FaucetRepository) lastGrant(ctx context.Context, chainID uint64, token, filter string, value string) (int64, error) {
+	query := `SELECT max(created_at) FROM pagga_data.faucet_grants FINAL
+	          WHERE chain_id = ? AND token = ? AND status != ? AND ` + filter
+	var last int64
+	err := r.db.QueryRowContext(ctx, query, chainID, token, FaucetGrantReleased, value).Scan(&last)
+	return last, err
+}
+
+// GrantedSince returns the total amount of token granted from since on
+func (r *FaucetRepository) GrantedSince(ctx context.Context, chainID uint64, token string, since int64) (*big.Int, error) {
+	query := `SELECT toString(sum(toUInt256(amount))) FROM pagga_data.faucet_grants FINAL
+	          WHERE chain_id = ? AND token = ? AND status != ? AND created_at >= ?`
+	var total string
+	if err := r.db.QueryRowContext(ctx, query, chainID, token, FaucetGrantReleased, since).Scan(&total); err != nil {
+		return nil, err
+	}
+	granted, ok := new(big.Int).SetString(total, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid granted total %q", total)
+	}
+	return granted, nil
+}
+
+// FaucetGrantModel is a grant of Amount base units of Token to Address, requested from IP
+type FaucetGrantModel struct {
+	ChainID uint64
+	ID      string
+	// Token is the symbol of the asset, ETH for the native one
+	Token   string
+	Address string
+	IP      string
+	Amount  *big.Int
+	TxID    string
+	Status  string
+	// CreatedAt is when the grant was reserved, the cooldowns and the budget's day count from it
+	CreatedAt int64
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// IdempotencyRepository stores the responses of Idempotency-Keys. A key being handled is claimed in
+// pagga_data.claims, see ClaimRepository, so one replica at a time runs its request.
+type IdempotencyRepository struct {
+	*Repository
+	claims *ClaimRepository
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(repo *Repository) *IdempotencyRepository {
+	return &IdempotencyRepository{Repository: repo, claims: NewClaimRepository(repo)}
+}
+
+// Reserve claims key for record. When the key has an unexpired response, or another request holds it,
+// that record is returned and nothing is written. A response is saved before its claim is released, so
+// it is looked up again once the key is claimed, in case the previous holder finished meanwhile.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *IdempotencyKeyModel) (*IdempotencyKeyModel, error) {
+	existing, err := r.get(ctx, record.Key)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	held, err := r.claims.Claim(ctx, &ClaimModel{
+		Key:       idempotencyClaim(record.Key),
+		Owner:     record.Owner,
+		Value:     record.RequestHash,
+		ExpiresAt: record.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	existing, err = r.get(ctx, record.Key)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		if held == nil {
+			if err := r.claims.Release(ctx, idempotencyClaim(record.Key), record.Owner); err != nil {
+				return nil, err
+			}
+		}
+		return existing, nil
+	}
+	if held != nil {
+		// Still being handled
+		return &IdempotencyKeyModel{
+			Key:         record.Key,
+			RequestHash: held.Value,
+			Owner:       held.Owner,
+			ExpiresAt:   held.ExpiresAt,
+		}, nil
+	}
+	return nil, nil
+}
+
+// Save stores the response of a reserved key and releases it
+func (r *IdempotencyRepository) Save(ctx context.Context, record *IdempotencyKeyModel) error {
+	if err := r.save(ctx, record); err != nil {
+		return err
+	}
+	return r.claims.Release(ctx, idempotencyClaim(record.Key), record.Owner)
+}
+
+// Delete releases a reserved key without a response, so the request can be retried
+func (r *IdempotencyRepository) Delete(ctx context.Context, record *IdempotencyKeyModel) error {
+	return r.claims.Release(ctx, idempotencyClaim(record.Key), record.Owner)
+}
+
+func idempotencyClaim(key string) string {
+	return "idempotency:" + key
+}
+
+func (r *IdempotencyRepository) get(ctx context.Context, key string) (*IdempotencyKeyModel, error) {
+	record := new(IdempotencyKeyModel)
+	query := `SELECT key, request_hash, owner, status_code, content_type, body, created_at, expires_at
+	          FROM pagga_data.idempotency_keys FINAL WHERE key = ? AND expires_at > ? AND status_code > 0`
+	err := r.db.QueryRowContext(ctx, query, key, time.Now().Unix()).Scan(
+		&record.Key, &record.RequestHash, &record.Owner, &record.StatusCode,
+		&record.ContentType, &record.Body, &record.CreatedAt, &record.ExpiresAt)
+	return record, err
+}
+
+func (r *IdempotencyRepository) save(ctx context.Context, record *IdempotencyKeyModel) error {
+	query := `INSERT INTO pagga_data.idempotency_keys (key, request_hash, owner, status_code, content_type, body, created_at, expires_at, version)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		record.Key, record.RequestHash, record.Owner, record.StatusCode,
+		record.ContentType, record.Body, record.CreatedAt, record.ExpiresAt, uint64(time.Now().UnixNano()))
+	return err
+}
+
+// IdempotencyKeyModel represents an Idempotency-Key and the response it replays in ClickHouse.
+// StatusCode is 0 while the first request with the key is still being handled, such records are only
+// claims and are not stored in idempotency_keys.
+type IdempotencyKeyModel struct {
+	Key         string
+	RequestHash string
+	Owner       string
+	StatusCode  uint16
+	ContentType string
+	Body        string
+	CreatedAt   int64
+	ExpiresAt   int64
+}
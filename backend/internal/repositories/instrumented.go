@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/metrics"
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentedDB times the statements run through it in aqua402_clickhouse_query_duration_seconds and
+// traces them as children of the span in their context
+type instrumentedDB struct {
+	*sql.DB
+}
+
+// statementTable finds the table a statement reads or writes, the first one named after FROM, INTO,
+// TABLE (ALTER TABLE mutations) or UPDATE
+var statementTable = regexp.MustCompile(`(?is)\b(?:FROM|INTO|UPDATE|TABLE)\s+([a-z_][a-z0-9_.]*)`)
+
+func (db *instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, q := startQuery(ctx, query)
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	q.end(err)
+	return result, err
+}
+
+func (db *instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, q := startQuery(ctx, query)
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	q.end(err)
+	return rows, err
+}
+
+func (db *instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, q := startQuery(ctx, query)
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	q.end(row.Err())
+	return row
+}
+
+// tracedQuery is a statement being run, described by its kind and table, e.g. SELECT pagga_data.rfqs
+type tracedQuery struct {
+	statement string
+	table     string
+	start     time.Time
+	span      trace.Span
+}
+
+// startQuery starts timing and tracing a statement
+func startQuery(ctx context.Context, statement string) (context.Context, *tracedQuery) {
+	q := &tracedQuery{statement: "OTHER", table: "unknown", start: time.Now()}
+	if fields := strings.Fields(statement); len(fields) > 0 {
+		q.statement = strings.ToUpper(fields[0])
+	}
+	if match := statementTable.FindStringSubmatch(statement); match != nil {
+		q.table = match[1]
+	}
+	ctx, q.span = tracing.Start(ctx, q.statement+" "+q.table, trace.SpanKindClient,
+		attribute.String("db.system", "clickhouse"),
+		attribute.String("db.operation", q.statement),
+		attribute.String("db.sql.table", q.table),
+		attribute.String("db.statement", statement),
+	)
+	return ctx, q
+}
+
+// end records the statement's latency and ends its span. Reads are timed until their first row.
+func (q *tracedQuery) end(err error) {
+	status := "ok"
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	if err != nil {
+		status = "error"
+	}
+	metrics.ClickHouseQueryDuration.WithLabelValues(q.statement, q.table, status).Observe(metrics.Since(q.start))
+	tracing.End(q.span, err)
+}
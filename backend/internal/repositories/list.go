@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+)
+
+// Page sizes of the list endpoints
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// Filter names accepted by ListQuery, not every list supports every filter
+const (
+	FilterStatus         = "status"
+	FilterBorrower       = "borrower"
+	FilterLender         = "lender"
+	FilterAmountMin      = "amount_min"
+	FilterAmountMax      = "amount_max"
+	FilterDurationMin    = "duration_min"
+	FilterDurationMax    = "duration_max"
+	FilterCollateralType = "collateral_type"
+	FilterCreatedFrom    = "created_from"
+	FilterCreatedTo      = "created_to"
+)
+
+// ErrInvalidListQuery is returned for unsupported filters or sorts and malformed cursors
+var ErrInvalidListQuery = apperrors.New(apperrors.KindInvalid, "invalid list query")
+
+// ListQuery filters, sorts and pages a list. Zero filters are not applied.
+type ListQuery struct {
+	// ChainID scopes the list to one chain, every listed table has a chain_id column
+	ChainID        uint64
+	Status         string
+	Borrower       string
+	Lender         string
+	MinAmount      string
+	MaxAmount      string
+	MinDuration    *uint64
+	MaxDuration    *uint64
+	CollateralType *uint8
+	CreatedFrom    *int64
+	CreatedTo      *int64
+
+	// Sort is the sort field, empty for the list's default. Ties are broken by id.
+	Sort  string
+	Desc  bool
+	Limit int
+	// After continues the list after the last row of a previous page
+	After *Cursor
+}
+
+type listFilter struct {
+	name string
+	arg  interface{}
+}
+
+func (q ListQuery) filters() []listFilter {
+	var filters []listFilter
+	add := func(name string, set bool, arg interface{}) {
+		if set {
+			filters = append(filters, listFilter{name: name, arg: arg})
+		}
+	}
+	add(FilterStatus, q.Status != "", q.Status)
+	add(FilterBorrower, q.Borrower != "", q.Borrower)
+	add(FilterLender, q.Lender != "", q.Lender)
+	add(FilterAmountMin, q.MinAmount != "", q.MinAmount)
+	add(FilterAmountMax, q.MaxAmount != "", q.MaxAmount)
+	if q.MinDuration != nil {
+		add(FilterDurationMin, true, *q.MinDuration)
+	}
+	if q.MaxDuration != nil {
+		add(FilterDurationMax, true, *q.MaxDuration)
+	}
+	if q.CollateralType != nil {
+		add(FilterCollateralType, true, *q.CollateralType)
+	}
+	if q.CreatedFrom != nil {
+		add(FilterCreatedFrom, true, *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		add(FilterCreatedTo, true, *q.CreatedTo)
+	}
+	return filters
+}
+
+// Cursor is the position after the last row of a page: its sort value and id.
+// It also records the sort so it cannot be replayed against a different order.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	ID    uint64 `json:"i"`
+}
+
+// Encode returns the cursor as an opaque URL-safe token
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token returned by Cursor.Encode
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+	}
+	c := new(Cursor)
+	if err := json.Unmarshal(data, c); err != nil || c.Sort == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+	}
+	return c, nil
+}
+
+// Page is one page of a list and the number of rows matching its filters
+type Page[T any] struct {
+	Items []*T
+	Total uint64
+	// NextCursor continues the list, empty on the last page
+	NextCursor string
+}
+
+// listSort is a sortable expression and the cast applied to cursor values compared with it
+type listSort struct {
+	expr  string
+	param string
+}
+
+// listSchema describes how a table is listed. Filter predicates take a single argument.
+type listSchema struct {
+	table       string
+	columns     string
+	defaultSort string
+	sorts       map[string]listSort
+	filters     map[string]string
+}
+
+var (
+	amountSort   = listSort{expr: "toUInt256OrZero(amount)", param: "toUInt256(?)"}
+	durationSort = listSort{expr: "duration", param: "toUInt64(?)"}
+	idSort       = listSort{expr: "id", param: "toUInt64(?)"}
+)
+
+// listPage runs q against schema within scope (a predicate on scopeArgs, may be empty).
+// scan reads the schema columns followed by the sort value of each row.
+func listPage[T any](ctx context.Context, db *instrumentedDB, schema *listSchema, q ListQuery, scope string, scopeArgs []interface{},
+	scan func(rows *sql.Rows, sortValue *string) (*T, error), idOf func(*T) uint64) (*Page[T], error) {
+	sortName := q.Sort
+	if sortName == "" {
+		sortName = schema.defaultSort
+	}
+	sort, ok := schema.sorts[sortName]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot sort by %s", ErrInvalidListQuery, sortName)
+	}
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+	if limit < 0 || limit > MaxListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListQuery, MaxListLimit)
+	}
+
+	var where []string
+	var args []interface{}
+	if q.ChainID != 0 {
+		where = append(where, "chain_id = ?")
+		args = append(args, q.ChainID)
+	}
+	if scope != "" {
+		where = append(where, scope)
+		args = append(args, scopeArgs...)
+	}
+	for _, f := range q.filters() {
+		predicate, ok := schema.filters[f.name]
+		if !ok {
+			return nil, fmt.Errorf("%w: filter %s is not supported here", ErrInvalidListQuery, f.name)
+		}
+		where = append(where, predicate)
+		args = append(args, f.arg)
+	}
+
+	var total uint64
+	countQuery := `SELECT count() FROM ` + schema.table + whereClause(where)
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	direction, op := "ASC", ">"
+	if q.Desc {
+		direction, op = "DESC", "<"
+	}
+	if q.After != nil {
+		if q.After.Sort != sortName || q.After.Desc != q.Desc {
+			return nil, fmt.Errorf("%w: cursor belongs to a different sort", ErrInvalidListQuery)
+		}
+		where = append(where, fmt.Sprintf("(%s, id) %s (%s, ?)", sort.expr, op, sort.param))
+		args = append(args, q.After.Value, q.After.ID)
+	}
+
+	query := `SELECT ` + schema.columns + `, toString(` + sort.expr + `)
+	          FROM ` + schema.table + whereClause(where) + `
+	          ORDER BY ` + sort.expr + ` ` + direction + `, id ` + direction + `
+	          LIMIT ?`
+	rows, err := db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &Page[T]{Items: make([]*T, 0, limit), Total: total}
+	var sortValue, lastValue string
+	for rows.Next() {
+		item, err := scan(rows, &sortValue)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Items) == limit {
+			// One row past the page means there is a next page
+			last := page.Items[limit-1]
+			page.NextCursor = Cursor{Sort: sortName, Desc: q.Desc, Value: lastValue, ID: idOf(last)}.Encode()
+			break
+		}
+		page.Items = append(page.Items, item)
+		lastValue = sortValue
+	}
+	return page, rows.Err()
+}
+
+func whereClause(predicates []string) string {
+	if len(predicates) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(predicates, " AND ")
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+)
+
+// RiskRepository handles credit line risk and liquidation data operations
+type RiskRepository struct {
+	*Repository
+}
+
+// NewRiskRepository creates a new risk repository
+func NewRiskRepository(repo *Repository) *RiskRepository {
+	return &RiskRepository{Repository: repo}
+}
+
+// SaveRiskStatus saves the current risk status of a credit line
+func (r *RiskRepository) SaveRiskStatus(ctx context.Context, status *RiskStatusModel) error {
+	query := `INSERT INTO pagga_data.credit_line_risk (chain_id, credit_line_id, borrower_address, lender_address, status, total_owed, expires_at, updated_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		status.ChainID, status.CreditLineID, status.BorrowerAddress, status.LenderAddress,
+		status.Status, status.TotalOwed, status.ExpiresAt, status.UpdatedAt)
+	return err
+}
+
+// GetRiskStatus retrieves the latest risk status of a credit line
+func (r *RiskRepository) GetRiskStatus(ctx context.Context, chainID, creditLineID uint64) (*RiskStatusModel, error) {
+	status := new(RiskStatusModel)
+	query := `SELECT chain_id, credit_line_id, borrower_address, lender_address, status, total_owed, expires_at, updated_at
+	          FROM pagga_data.credit_line_risk FINAL WHERE chain_id = ? AND credit_line_id = ?`
+	err := r.db.QueryRowContext(ctx, query, chainID, creditLineID).Scan(
+		&status.ChainID, &status.CreditLineID, &status.BorrowerAddress, &status.LenderAddress,
+		&status.Status, &status.TotalOwed, &status.ExpiresAt, &status.UpdatedAt)
+	return status, err
+}
+
+// SaveLiquidationCase saves a new version of a liquidation case
+func (r *RiskRepository) SaveLiquidationCase(ctx context.Context, c *LiquidationCaseModel) error {
+	query := `INSERT INTO pagga_data.liquidation_cases (chain_id, credit_line_id, rfq_id, borrower_address, lender_address, collateral_type, collateral_amount,
+	          outstanding, release_amount, action, status, operator, tx_hash, error, created_at, updated_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		c.ChainID, c.CreditLineID, c.RFQID, c.BorrowerAddress, c.LenderAddress, c.CollateralType,
+		c.CollateralAmount, c.Outstanding, c.ReleaseAmount, c.Action, c.Status,
+		c.Operator, c.TxHash, c.Error, c.CreatedAt, c.UpdatedAt)
+	return err
+}
+
+// GetLiquidationCase retrieves the liquidation case of a credit line
+func (r *RiskRepository) GetLiquidationCase(ctx context.Context, chainID, creditLineID uint64) (*LiquidationCaseModel, error) {
+	c := new(LiquidationCaseModel)
+	query := `SELECT chain_id, credit_line_id, rfq_id, borrower_address, lender_address, collateral_type, collateral_amount,
+	          outstanding, release_amount, action, status, operator, tx_hash, error, created_at, updated_at
+	          FROM pagga_data.liquidation_cases FINAL WHERE chain_id = ? AND credit_line_id = ?`
+	err := r.db.QueryRowContext(ctx, query, chainID, creditLineID).Scan(
+		&c.ChainID, &c.CreditLineID, &c.RFQID, &c.BorrowerAddress, &c.LenderAddress, &c.CollateralType,
+		&c.CollateralAmount, &c.Outstanding, &c.ReleaseAmount, &c.Action, &c.Status,
+		&c.Operator, &c.TxHash, &c.Error, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// ListLiquidationCases retrieves the liquidation cases of a chain, optionally filtered by status
+func (r *RiskRepository) ListLiquidationCases(ctx context.Context, chainID uint64, status string) ([]*LiquidationCaseModel, error) {
+	query := `SELECT chain_id, credit_line_id, rfq_id, borrower_address, lender_address, collateral_type, collateral_amount,
+	          outstanding, release_amount, action, status, operator, tx_hash, error, created_at, updated_at
+	          FROM pagga_data.liquidation_cases FINAL WHERE chain_id = ? AND (? = '' OR status = ?) ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, chainID, status, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []*LiquidationCaseModel
+	for rows.Next() {
+		c := new(LiquidationCaseModel)
+		err := rows.Scan(
+			&c.ChainID, &c.CreditLineID, &c.RFQID, &c.BorrowerAddress, &c.LenderAddress, &c.CollateralType,
+			&c.CollateralAmount, &c.Outstanding, &c.ReleaseAmount, &c.Action, &c.Status,
+			&c.Operator, &c.TxHash, &c.Error, &c.CreatedAt, &c.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, rows.Err()
+}
+
+// RiskStatusModel represents the risk classification of a credit line in ClickHouse
+type RiskStatusModel struct {
+	ChainID         uint64
+	CreditLineID    uint64
+	BorrowerAddress string
+	LenderAddress   string
+	Status          string
+	TotalOwed       string
+	ExpiresAt       int64
+	UpdatedAt       int64
+}
+
+// LiquidationCaseModel represents a collateral liquidation case in ClickHouse
+type LiquidationCaseModel struct {
+	ChainID          uint64
+	CreditLineID     uint64
+	RFQID            uint64
+	BorrowerAddress  string
+	LenderAddress    string
+	CollateralType   uint8
+	CollateralAmount string
+	Outstanding      string
+	ReleaseAmount    string
+	Action           string
+	Status           string
+	Operator         string
+	TxHash           string
+	Error            string
+	CreatedAt        int64
+	UpdatedAt        int64
+}
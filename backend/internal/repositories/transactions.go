@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TransactionRepository stores the transactions sent by evm.TxManager signers, it implements evm.TxStore
+type TransactionRepository struct {
+	*Repository
+}
+
+// NewTransactionRepository creates a new transaction repository
+func NewTransactionRepository(repo *Repository) *TransactionRepository {
+	return &TransactionRepository{Repository: repo}
+}
+
+const transactionColumns = `chain_id, id, from_address, to_address, value, data, nonce, gas_limit, gas_price,
+	gas_tip_cap, gas_fee_cap, urgency, hash, hashes, status, attempts, error, block_number, created_at, updated_at, last_sent_at`
+
+// SaveTx inserts a new version of a transaction
+func (r *TransactionRepository) SaveTx(ctx context.Context, tx *evm.TxRecord) error {
+	hashes := make([]string, len(tx.Hashes))
+	for i, hash := range tx.Hashes {
+		hashes[i] = hash.Hex()
+	}
+	hash := ""
+	if tx.Hash != (common.Hash{}) {
+		hash = tx.Hash.Hex()
+	}
+
+	query := `INSERT INTO pagga_data.evm_transactions (` + transactionColumns + `, version)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		tx.ChainID, tx.ID, tx.From.Hex(), tx.To.Hex(), bigString(tx.Value), hexutil.Encode(tx.Data), tx.Nonce, tx.GasLimit,
+		optionalBigString(tx.GasPrice), optionalBigString(tx.GasTipCap), optionalBigString(tx.GasFeeCap),
+		string(tx.Urgency), hash, hashes, string(tx.Status), tx.Attempts, tx.Error, tx.BlockNumber,
+		tx.CreatedAt, tx.UpdatedAt, tx.LastSentAt, uint64(time.Now().UnixNano()))
+	return err
+}
+
+// GetTx returns a transaction by ID
+func (r *TransactionRepository) GetTx(ctx context.Context, id string) (*evm.TxRecord, error) {
+	query := `SELECT ` + transactionColumns + ` FROM pagga_data.evm_transactions FINAL WHERE id = ?`
+	tx, err := scanTransaction(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, evm.ErrTxNotFound
+	}
+	return tx, err
+}
+
+// OutstandingTxs returns the requested, queued and pending transactions of an address on a chain
+func (r *TransactionRepository) OutstandingTxs(ctx context.Context, chainID uint64, from common.Address) ([]*evm.TxRecord, error) {
+	query := `SELECT ` + transactionColumns + ` FROM pagga_data.evm_transactions FINAL
+	          WHERE chain_id = ? AND lower(from_address) = lower(?) AND status IN (?, ?, ?)
+	          ORDER BY nonce`
+	rows, err := r.db.QueryContext(ctx, query, chainID, from.Hex(), string(evm.TxRequested), string(evm.TxQueued), string(evm.TxPending))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []*evm.TxRecord
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+func scanTransaction(row interface{ Scan(dest ...any) error }) (*evm.TxRecord, error) {
+	var (
+		tx                           evm.TxRecord
+		from, to, value, data, price string
+		tipCap, feeCap, urgency      string
+		hash, status                 string
+		hashes                       []string
+	)
+	err := row.Scan(&tx.ChainID, &tx.ID, &from, &to, &value, &data, &tx.Nonce, &tx.GasLimit, &price,
+		&tipCap, &feeCap, &urgency, &hash, &hashes, &status, &tx.Attempts, &tx.Error, &tx.BlockNumber, &tx.CreatedAt, &tx.UpdatedAt, &tx.LastSentAt)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.From = common.HexToAddress(from)
+	tx.To = common.HexToAddress(to)
+	tx.Value, _ = new(big.Int).SetString(value, 10)
+	tx.GasPrice = parseOptionalBig(price)
+	tx.GasTipCap = parseOptionalBig(tipCap)
+	tx.GasFeeCap = parseOptionalBig(feeCap)
+	tx.Urgency = evm.Urgency(urgency)
+	if decoded, err := hexutil.Decode(data); err == nil {
+		tx.Data = decoded
+	}
+	if hash != "" {
+		tx.Hash = common.HexToHash(hash)
+	}
+	for _, h := range hashes {
+		tx.Hashes = append(tx.Hashes, common.HexToHash(h))
+	}
+	tx.Status = evm.TxStatus(strings.ToLower(status))
+	return &tx, nil
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+// optionalBigString stores a nil value as an empty string, e.g. the gas price of a dynamic-fee transaction
+func optionalBigString(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func parseOptionalBig(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookRepository stores webhook subscriptions and their deliveries
+type WebhookRepository struct {
+	*Repository
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(repo *Repository) *WebhookRepository {
+	return &WebhookRepository{Repository: repo}
+}
+
+const webhookSubscriptionColumns = `id, chain_id, address, event_types, url, secret, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, subscription_id, chain_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_attempt_at, response_status, error, redelivery_of, created_at, updated_at`
+
+// SaveSubscription inserts a new version of a subscription
+func (r *WebhookRepository) SaveSubscription(ctx context.Context, s *WebhookSubscriptionModel) error {
+	query := `INSERT INTO pagga_data.webhook_subscriptions (` + webhookSubscriptionColumns + `, version)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		s.ID, s.ChainID, s.Address, s.EventTypes, s.URL, s.Secret, s.Active, s.CreatedAt, s.UpdatedAt,
+		uint64(time.Now().UnixNano()))
+	return err
+}
+
+// GetSubscription returns a subscription by ID, deleted ones included
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id string) (*WebhookSubscriptionModel, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM pagga_data.webhook_subscriptions FINAL WHERE id = ?`
+	return scanWebhookSubscription(r.db.QueryRowContext(ctx, query, id))
+}
+
+// ActiveSubscriptions returns the active subscriptions of a chain for any of addresses, which are lower case
+func (r *WebhookRepository) ActiveSubscriptions(ctx context.Context, chainID uint64, addresses []string) ([]*WebhookSubscriptionModel, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM pagga_data.webhook_subscriptions FINAL
+	          WHERE chain_id = ? AND has(?, address) AND active = 1`
+	rows, err := r.db.QueryContext(ctx, query, chainID, addresses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*WebhookSubscriptionModel
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+// SaveDelivery inserts a new version of a delivery
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, d *WebhookDeliveryModel) error {
+	query := `INSERT INTO pagga_data.webhook_deliveries (` + webhookDeliveryColumns + `, version)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		d.ID, d.SubscriptionID, d.ChainID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts,
+		d.NextAttemptAt, d.LastAttemptAt, d.ResponseStatus, d.Error, d.RedeliveryOf, d.CreatedAt, d.UpdatedAt,
+		uint64(time.Now().UnixNano()))
+	return err
+}
+
+// GetDelivery returns a delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*WebhookDeliveryModel, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM pagga_data.webhook_deliveries FINAL WHERE id = ?`
+	return scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id))
+}
+
+// ListDeliveries returns the latest deliveries of a subscription, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*WebhookDeliveryModel, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM pagga_data.webhook_deliveries FINAL
+	          WHERE subscription_id = ? ORDER BY created_at DESC, id LIMIT ?`
+	return r.queryDeliveries(ctx, query, subscriptionID, limit)
+}
+
+// DueDeliveries returns the pending deliveries whose next attempt is due at now, oldest first
+func (r *WebhookRepository) DueDeliveries(ctx context.Context, now int64, limit int) ([]*WebhookDeliveryModel, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM pagga_data.webhook_deliveries FINAL
+	          WHERE status = 'pending' AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`
+	return r.queryDeliveries(ctx, query, now, limit)
+}
+
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*WebhookDeliveryModel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*WebhookDeliveryModel
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanWebhookSubscription(row interface{ Scan(...interface{}) error }) (*WebhookSubscriptionModel, error) {
+	s := new(WebhookSubscriptionModel)
+	err := row.Scan(&s.ID, &s.ChainID, &s.Address, &s.EventTypes, &s.URL, &s.Secret, &s.Active, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }) (*WebhookDeliveryModel, error) {
+	d := new(WebhookDeliveryModel)
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.ChainID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastAttemptAt, &d.ResponseStatus, &d.Error, &d.RedeliveryOf, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// WebhookSubscriptionModel is a URL notified of the events of an address. EventTypes empty means every type.
+type WebhookSubscriptionModel struct {
+	ID         string
+	ChainID    uint64
+	Address    string
+	EventTypes []string
+	URL        string
+	// Secret signs the payloads, it is only shown when the subscription is created
+	Secret    string
+	Active    bool
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// WebhookDeliveryModel is one event sent, or to be sent, to a subscription
+type WebhookDeliveryModel struct {
+	ID             string
+	SubscriptionID string
+	ChainID        uint64
+	EventID        string
+	EventType      string
+	// Payload is the JSON body posted
+	Payload        string
+	Status         string
+	Attempts       uint32
+	NextAttemptAt  int64
+	LastAttemptAt  int64
+	ResponseStatus uint16
+	Error          string
+	// RedeliveryOf is the delivery a manual redelivery repeats
+	RedeliveryOf string
+	CreatedAt    int64
+	UpdatedAt    int64
+}
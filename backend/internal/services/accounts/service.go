@@ -0,0 +1,369 @@
+// Package accounts answers what an address is doing on the platform: its open RFQs, auctions, quotes,
+// bids, Aqua liquidity and credit lines, what it borrowed or lent, what it owes and what it should do next.
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/credit"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
+	"go.uber.org/zap"
+)
+
+// HistoryLimit bounds the requests and offers read per portfolio, the most recent are kept
+const HistoryLimit = 500
+
+// DueSoon is how far ahead an installment becomes a pending repayment
+const DueSoon = 7 * 24 * time.Hour
+
+// Roles of an address in a fill
+const (
+	RoleBorrower = "borrower"
+	RoleLender   = "lender"
+)
+
+// Pending action types
+const (
+	// ActionAcceptQuote is an open RFQ of the borrower with quotes to accept
+	ActionAcceptQuote = "accept_quote"
+	// ActionFinalizeAuction is an auction of the borrower whose bidding ended with bids
+	ActionFinalizeAuction = "finalize_auction"
+	// ActionRepayDue is a credit line installment overdue or due within DueSoon
+	ActionRepayDue = "repay_due"
+)
+
+type Service struct {
+	repo       *repositories.AccountRepository
+	creditRepo *repositories.CreditRepository
+	ledger     *credit.Service
+	logger     *zap.Logger
+}
+
+func NewService(repo *repositories.AccountRepository, creditRepo *repositories.CreditRepository, ledger *credit.Service, logger *zap.Logger) *Service {
+	return &Service{
+		repo:       repo,
+		creditRepo: creditRepo,
+		ledger:     ledger,
+		logger:     logger,
+	}
+}
+
+// Portfolio is everything an address does on a chain, as a borrower and as a lender
+type Portfolio struct {
+	ChainID uint64 `json:"chain_id"`
+	Address string `json:"address"`
+	// OpenPositions are the requests and offers still open, the credit lines not closed and the Aqua liquidity
+	OpenPositions OpenPositions `json:"open_positions"`
+	// Fills are the RFQs and auctions of the address that opened a credit line, and those it won as a lender
+	Fills []Fill `json:"fills"`
+	// Debt is what the address owes as a borrower
+	Debt Debt `json:"debt"`
+	// Interest is the interest repaid by the address as a borrower and to it as a lender
+	Interest       Interest `json:"interest"`
+	PendingActions []Action `json:"pending_actions"`
+	AsOf           int64    `json:"as_of"`
+}
+
+type OpenPositions struct {
+	// Requests are RFQs and auctions of the address not filled yet
+	Requests []Request `json:"requests"`
+	// Offers are quotes and bids of the address on requests not filled yet
+	Offers []Offer `json:"offers"`
+	// Borrowed are the credit lines of the address as a borrower still owing or not expired
+	Borrowed []credit.Obligation `json:"borrowed"`
+	// Lent are the credit lines funded by the address still owed or not expired
+	Lent      []credit.Position `json:"lent"`
+	Liquidity Liquidity         `json:"liquidity"`
+}
+
+// Request is an RFQ or an auction
+type Request struct {
+	Market   string `json:"market" enums:"rfq,auction"`
+	ID       uint64 `json:"id"`
+	Amount   string `json:"amount"`
+	Duration uint64 `json:"duration"`
+	// EndTime is when the bidding of an auction ends
+	EndTime int64 `json:"end_time,omitempty"`
+	// Offers is the number of quotes or bids received
+	Offers    uint64 `json:"offers"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// Offer is a quote on an RFQ or a bid on an auction
+type Offer struct {
+	Market    string `json:"market" enums:"rfq,auction"`
+	ID        uint64 `json:"id"`
+	RequestID uint64 `json:"request_id"`
+	RateBps   uint16 `json:"rate_bps"`
+	Limit     string `json:"limit"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// Fill is an RFQ or auction that opened a credit line
+type Fill struct {
+	Role         string `json:"role" enums:"borrower,lender"`
+	Market       string `json:"market" enums:"rfq,auction"`
+	RequestID    uint64 `json:"request_id"`
+	CreditLineID uint64 `json:"credit_line_id"`
+	// Counterparty is the lender of a borrower's fill and the borrower of a lender's
+	Counterparty string `json:"counterparty,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+	RateBps      uint64 `json:"rate_bps,omitempty"`
+	FilledAt     int64  `json:"filled_at,omitempty"`
+}
+
+// Debt is the outstanding principal and accrued interest of a borrower and its next installment
+type Debt struct {
+	Principal string              `json:"principal"`
+	Interest  string              `json:"interest"`
+	Total     string              `json:"total"`
+	NextDue   *credit.Installment `json:"next_due"`
+}
+
+// Interest is realized interest, the interest part of repayments
+type Interest struct {
+	Paid   string `json:"paid"`
+	Earned string `json:"earned"`
+}
+
+// Liquidity is the Aqua liquidity of a lender, in wei
+type Liquidity struct {
+	// Connected is connected and not withdrawn
+	Connected string `json:"connected"`
+	// Reserved is reserved for credit lines and not released
+	Reserved string `json:"reserved"`
+	// Available is connected and not reserved
+	Available string `json:"available"`
+}
+
+// Action is something the address should do next
+type Action struct {
+	Type   string `json:"type" enums:"accept_quote,finalize_auction,repay_due"`
+	Market string `json:"market,omitempty"`
+	// ID is the RFQ, auction or credit line the action is on
+	ID          uint64 `json:"id"`
+	Amount      string `json:"amount,omitempty"`
+	DueAt       int64  `json:"due_at,omitempty"`
+	Overdue     bool   `json:"overdue,omitempty"`
+	Description string `json:"description"`
+}
+
+// Holdings is what a portfolio is built from
+type Holdings struct {
+	ChainID   uint64
+	Address   string
+	Requests  []*repositories.AccountRequestModel
+	Offers    []*repositories.AccountOfferModel
+	Liquidity *repositories.AccountLiquidityModel
+	// Borrowed are the obligations of the address as a borrower
+	Borrowed *credit.Obligations
+	// Lent are the positions of the credit lines the address funds
+	Lent []credit.Position
+}
+
+// GetPortfolio returns the portfolio of address on a chain
+func (s *Service) GetPortfolio(ctx context.Context, chainID uint64, address string) (*Portfolio, error) {
+	var v validation.Validator
+	address = v.Address("address", address).Hex()
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	h := Holdings{ChainID: chainID, Address: address}
+	var err error
+	if h.Requests, err = s.repo.ListRequests(ctx, chainID, address, HistoryLimit); err != nil {
+		return nil, fmt.Errorf("failed to list account requests: %w", err)
+	}
+	if h.Offers, err = s.repo.ListOffers(ctx, chainID, address, HistoryLimit); err != nil {
+		return nil, fmt.Errorf("failed to list account offers: %w", err)
+	}
+	if h.Liquidity, err = s.repo.GetLiquidity(ctx, chainID, address); err != nil {
+		return nil, fmt.Errorf("failed to get account liquidity: %w", err)
+	}
+	if h.Borrowed, err = s.ledger.GetObligations(ctx, chainID, address); err != nil {
+		return nil, err
+	}
+	lines, err := s.creditRepo.ListCreditLinesByLender(ctx, chainID, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit lines: %w", err)
+	}
+	for _, line := range lines {
+		position, err := s.ledger.GetPosition(ctx, line)
+		if err != nil {
+			return nil, err
+		}
+		h.Lent = append(h.Lent, *position)
+	}
+
+	return NewPortfolio(h, time.Now().Unix()), nil
+}
+
+// NewPortfolio builds the portfolio of h as of now
+func NewPortfolio(h Holdings, now int64) *Portfolio {
+	p := &Portfolio{
+		ChainID: h.ChainID,
+		Address: h.Address,
+		OpenPositions: OpenPositions{
+			Requests:  []Request{},
+			Offers:    []Offer{},
+			Borrowed:  []credit.Obligation{},
+			Lent:      []credit.Position{},
+			Liquidity: liquidityOf(h.Liquidity),
+		},
+		Fills:          []Fill{},
+		Debt:           Debt{Principal: "0", Interest: "0", Total: "0"},
+		PendingActions: []Action{},
+		AsOf:           now,
+	}
+
+	borrowed := make(map[uint64]credit.Position)
+	paid := new(big.Int)
+	if h.Borrowed != nil {
+		p.Debt = Debt{
+			Principal: h.Borrowed.TotalPrincipal,
+			Interest:  h.Borrowed.TotalInterest,
+			Total:     h.Borrowed.TotalOwed,
+			NextDue:   h.Borrowed.NextDue,
+		}
+		for _, obligation := range h.Borrowed.CreditLines {
+			borrowed[obligation.CreditLineID] = obligation.Position
+			addAmount(paid, obligation.InterestPaid)
+			if isOpen(obligation.Position, now) {
+				p.OpenPositions.Borrowed = append(p.OpenPositions.Borrowed, obligation)
+			}
+			if due := obligation.NextDue; due != nil && (due.Overdue || due.DueAt <= now+int64(DueSoon.Seconds())) {
+				p.PendingActions = append(p.PendingActions, Action{
+					Type:        ActionRepayDue,
+					ID:          obligation.CreditLineID,
+					Amount:      due.Total,
+					DueAt:       due.DueAt,
+					Overdue:     due.Overdue,
+					Description: fmt.Sprintf("Repay %s wei due on credit line %d", due.Total, obligation.CreditLineID),
+				})
+			}
+		}
+	}
+	// Repayments come first, the most urgent at the top
+	sort.SliceStable(p.PendingActions, func(i, j int) bool { return p.PendingActions[i].DueAt < p.PendingActions[j].DueAt })
+
+	lent := make(map[uint64]credit.Position)
+	earned := new(big.Int)
+	for _, position := range h.Lent {
+		lent[position.CreditLineID] = position
+		addAmount(earned, position.InterestPaid)
+		if isOpen(position, now) {
+			p.OpenPositions.Lent = append(p.OpenPositions.Lent, position)
+		}
+	}
+	p.Interest = Interest{Paid: paid.String(), Earned: earned.String()}
+
+	for _, req := range h.Requests {
+		if req.Filled {
+			fill := Fill{Role: RoleBorrower, Market: req.Market, RequestID: req.ID, CreditLineID: req.CreditLineID}
+			if line, ok := borrowed[req.CreditLineID]; ok {
+				fill.Counterparty, fill.Limit, fill.RateBps, fill.FilledAt = line.LenderAddress, line.Limit, line.RateBps, line.CreatedAt
+			}
+			p.Fills = append(p.Fills, fill)
+			continue
+		}
+
+		p.OpenPositions.Requests = append(p.OpenPositions.Requests, Request{
+			Market:    req.Market,
+			ID:        req.ID,
+			Amount:    req.Amount,
+			Duration:  req.Duration,
+			EndTime:   req.EndTime,
+			Offers:    req.Offers,
+			CreatedAt: req.CreatedAt,
+		})
+		switch {
+		case req.Offers == 0:
+		case req.Market == repositories.MarketRFQ:
+			p.PendingActions = append(p.PendingActions, Action{
+				Type:        ActionAcceptQuote,
+				Market:      req.Market,
+				ID:          req.ID,
+				Description: fmt.Sprintf("Accept one of the %d quotes on RFQ %d", req.Offers, req.ID),
+			})
+		case req.Market == repositories.MarketAuction && req.EndTime > 0 && req.EndTime <= now:
+			p.PendingActions = append(p.PendingActions, Action{
+				Type:        ActionFinalizeAuction,
+				Market:      req.Market,
+				ID:          req.ID,
+				Description: fmt.Sprintf("Finalize auction %d, bidding ended with %d bids", req.ID, req.Offers),
+			})
+		}
+	}
+
+	for _, offer := range h.Offers {
+		switch {
+		case !offer.Filled:
+			p.OpenPositions.Offers = append(p.OpenPositions.Offers, Offer{
+				Market:    offer.Market,
+				ID:        offer.ID,
+				RequestID: offer.RequestID,
+				RateBps:   offer.RateBps,
+				Limit:     offer.Limit,
+				CreatedAt: offer.CreatedAt,
+			})
+		case offer.Won:
+			fill := Fill{Role: RoleLender, Market: offer.Market, RequestID: offer.RequestID, CreditLineID: offer.CreditLineID}
+			if line, ok := lent[offer.CreditLineID]; ok {
+				fill.Counterparty, fill.Limit, fill.RateBps, fill.FilledAt = line.BorrowerAddress, line.Limit, line.RateBps, line.CreatedAt
+			}
+			// A lender may have quoted several times on the request it won
+			if !containsFill(p.Fills, fill) {
+				p.Fills = append(p.Fills, fill)
+			}
+		}
+	}
+	sort.SliceStable(p.Fills, func(i, j int) bool { return p.Fills[i].FilledAt > p.Fills[j].FilledAt })
+
+	return p
+}
+
+// isOpen is a credit line still owing or not expired
+func isOpen(position credit.Position, now int64) bool {
+	return position.TotalOwed != "0" || position.ExpiresAt > now
+}
+
+func liquidityOf(m *repositories.AccountLiquidityModel) Liquidity {
+	if m == nil {
+		return Liquidity{Connected: "0", Reserved: "0", Available: "0"}
+	}
+	connected := floorSub(m.Connected, m.Withdrawn)
+	reserved := floorSub(m.Reserved, m.Released)
+	available := floorSub(connected.String(), reserved.String())
+	return Liquidity{Connected: connected.String(), Reserved: reserved.String(), Available: available.String()}
+}
+
+// floorSub returns a - b, or zero when b exceeds a. Unparseable amounts count as zero.
+func floorSub(a, b string) *big.Int {
+	result := addAmount(new(big.Int), a)
+	result.Sub(result, addAmount(new(big.Int), b))
+	if result.Sign() < 0 {
+		return result.SetInt64(0)
+	}
+	return result
+}
+
+func addAmount(total *big.Int, amount string) *big.Int {
+	if v, ok := new(big.Int).SetString(amount, 10); ok {
+		total.Add(total, v)
+	}
+	return total
+}
+
+func containsFill(fills []Fill, fill Fill) bool {
+	for _, f := range fills {
+		if f.Role == fill.Role && f.Market == fill.Market && f.RequestID == fill.RequestID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,339 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// Granularities supported by the analytics endpoints
+const (
+	GranularityHour  = "hour"
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// DefaultRange is the time range covered when no from is given
+const DefaultRange = 30 * 24 * time.Hour
+
+// maxRange bounds the time range per granularity so responses stay small
+var maxRange = map[string]time.Duration{
+	GranularityHour:  31 * 24 * time.Hour,
+	GranularityDay:   2 * 366 * 24 * time.Hour,
+	GranularityWeek:  10 * 366 * 24 * time.Hour,
+	GranularityMonth: 50 * 366 * 24 * time.Hour,
+}
+
+// ErrInvalidQuery is returned for malformed time ranges or granularities
+var ErrInvalidQuery = apperrors.New(apperrors.KindInvalid, "invalid analytics query")
+
+// Range is the chain, time range [from, to) and bucket granularity of a report
+type Range struct {
+	ChainID     uint64 `json:"chain_id"`
+	From        int64  `json:"from"`
+	To          int64  `json:"to"`
+	Granularity string `json:"granularity"`
+}
+
+// ParseRange parses unix second from/to and a granularity, defaulting to the last 30 days by day
+func ParseRange(from, to, granularity string, now time.Time) (Range, error) {
+	r := Range{To: now.Unix(), Granularity: granularity}
+	if r.Granularity == "" {
+		r.Granularity = GranularityDay
+	}
+	limit, ok := maxRange[r.Granularity]
+	if !ok {
+		return r, fmt.Errorf("%w: granularity must be hour, day, week or month", ErrInvalidQuery)
+	}
+
+	if to != "" {
+		v, err := strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			return r, fmt.Errorf("%w: to must be a unix timestamp", ErrInvalidQuery)
+		}
+		r.To = v
+	}
+	r.From = r.To - int64(DefaultRange.Seconds())
+	if from != "" {
+		v, err := strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return r, fmt.Errorf("%w: from must be a unix timestamp", ErrInvalidQuery)
+		}
+		r.From = v
+	}
+
+	if r.From >= r.To {
+		return r, fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	}
+	if time.Duration(r.To-r.From)*time.Second > limit {
+		return r, fmt.Errorf("%w: range too long for %s granularity (max %s)", ErrInvalidQuery, r.Granularity, limit)
+	}
+
+	return r, nil
+}
+
+type VolumePoint struct {
+	Bucket int64  `json:"bucket"`
+	Market string `json:"market"`
+	Count  uint64 `json:"count"`
+	Volume string `json:"volume"`
+}
+
+type VolumeReport struct {
+	Range
+	Points []VolumePoint `json:"points"`
+}
+
+type ClearingRatePoint struct {
+	Bucket         int64   `json:"bucket"`
+	DurationBucket string  `json:"duration_bucket"`
+	Lines          uint64  `json:"lines"`
+	AvgRateBps     float64 `json:"avg_rate_bps"`
+}
+
+type ClearingRateReport struct {
+	Range
+	Points []ClearingRatePoint `json:"points"`
+}
+
+type LenderSharePoint struct {
+	Bucket        int64   `json:"bucket"`
+	LenderAddress string  `json:"lender_address"`
+	Lines         uint64  `json:"lines"`
+	Volume        string  `json:"volume"`
+	Share         float64 `json:"share"`
+}
+
+type LenderShareReport struct {
+	Range
+	Points []LenderSharePoint `json:"points"`
+}
+
+type QuoteFillPoint struct {
+	Bucket           int64   `json:"bucket"`
+	Market           string  `json:"market"`
+	Requests         uint64  `json:"requests"`
+	Quotes           uint64  `json:"quotes"`
+	Fills            uint64  `json:"fills"`
+	QuotesPerRequest float64 `json:"quotes_per_request"`
+	QuoteToFill      float64 `json:"quote_to_fill"`
+}
+
+type QuoteFillReport struct {
+	Range
+	Points []QuoteFillPoint `json:"points"`
+}
+
+type FirstQuotePoint struct {
+	Bucket        int64   `json:"bucket"`
+	RFQs          uint64  `json:"rfqs"`
+	Quoted        uint64  `json:"quoted"`
+	AvgSeconds    float64 `json:"avg_seconds"`
+	MedianSeconds float64 `json:"median_seconds"`
+	P90Seconds    float64 `json:"p90_seconds"`
+}
+
+type FirstQuoteReport struct {
+	Range
+	Points []FirstQuotePoint `json:"points"`
+}
+
+type AquaUtilizationPoint struct {
+	Bucket         int64   `json:"bucket"`
+	Connected      string  `json:"connected"`
+	Withdrawn      string  `json:"withdrawn"`
+	Reserved       string  `json:"reserved"`
+	Released       string  `json:"released"`
+	TotalConnected string  `json:"total_connected"`
+	InUse          string  `json:"in_use"`
+	Utilization    float64 `json:"utilization"`
+}
+
+type AquaUtilizationReport struct {
+	Range
+	Points []AquaUtilizationPoint `json:"points"`
+}
+
+// Service serves the analytics read side, aggregated from the ClickHouse market tables when queried
+type Service struct {
+	repo   *repositories.AnalyticsRepository
+	logger *zap.Logger
+}
+
+func NewService(repo *repositories.AnalyticsRepository, logger *zap.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Volume returns RFQ, auction and credit line counts and volumes per bucket
+func (s *Service) Volume(ctx context.Context, r Range) (*VolumeReport, error) {
+	rows, err := s.repo.Volume(ctx, r.ChainID, r.From, r.To, r.Granularity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query volume: %w", err)
+	}
+
+	report := &VolumeReport{Range: r, Points: make([]VolumePoint, 0, len(rows))}
+	for _, row := range rows {
+		report.Points = append(report.Points, VolumePoint{
+			Bucket: row.Bucket,
+			Market: row.Market,
+			Count:  row.Count,
+			Volume: row.Volume,
+		})
+	}
+	return report, nil
+}
+
+// ClearingRates returns the average rate in bps credit lines cleared at, by duration bucket
+func (s *Service) ClearingRates(ctx context.Context, r Range) (*ClearingRateReport, error) {
+	rows, err := s.repo.ClearingRates(ctx, r.ChainID, r.From, r.To, r.Granularity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query clearing rates: %w", err)
+	}
+
+	report := &ClearingRateReport{Range: r, Points: make([]ClearingRatePoint, 0, len(rows))}
+	for _, row := range rows {
+		report.Points = append(report.Points, ClearingRatePoint{
+			Bucket:         row.Bucket,
+			DurationBucket: row.DurationBucket,
+			Lines:          row.Lines,
+			AvgRateBps:     row.AvgRateBps,
+		})
+	}
+	return report, nil
+}
+
+// LenderShare returns each lender's share of the credit line volume opened per bucket
+func (s *Service) LenderShare(ctx context.Context, r Range) (*LenderShareReport, error) {
+	rows, err := s.repo.LenderVolume(ctx, r.ChainID, r.From, r.To, r.Granularity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lender volume: %w", err)
+	}
+	return &LenderShareReport{Range: r, Points: LenderShares(rows)}, nil
+}
+
+// QuoteFill returns requests, quotes and fills per market with their ratios
+func (s *Service) QuoteFill(ctx context.Context, r Range) (*QuoteFillReport, error) {
+	rows, err := s.repo.MarketActivity(ctx, r.ChainID, r.From, r.To, r.Granularity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market activity: %w", err)
+	}
+
+	report := &QuoteFillReport{Range: r, Points: make([]QuoteFillPoint, 0, len(rows))}
+	for _, row := range rows {
+		report.Points = append(report.Points, QuoteFillPoint{
+			Bucket:           row.Bucket,
+			Market:           row.Market,
+			Requests:         row.Requests,
+			Quotes:           row.Quotes,
+			Fills:            row.Fills,
+			QuotesPerRequest: ratio(row.Quotes, row.Requests),
+			QuoteToFill:      ratio(row.Fills, row.Quotes),
+		})
+	}
+	return report, nil
+}
+
+// TimeToFirstQuote returns how long RFQs created in each bucket waited for a first quote
+func (s *Service) TimeToFirstQuote(ctx context.Context, r Range) (*FirstQuoteReport, error) {
+	rows, err := s.repo.TimeToFirstQuote(ctx, r.ChainID, r.From, r.To, r.Granularity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time to first quote: %w", err)
+	}
+
+	report := &FirstQuoteReport{Range: r, Points: make([]FirstQuotePoint, 0, len(rows))}
+	for _, row := range rows {
+		report.Points = append(report.Points, FirstQuotePoint{
+			Bucket:        row.Bucket,
+			RFQs:          row.RFQs,
+			Quoted:        row.Quoted,
+			AvgSeconds:    row.AvgSeconds,
+			MedianSeconds: row.MedianSeconds,
+			P90Seconds:    row.P90Seconds,
+		})
+	}
+	return report, nil
+}
+
+// AquaUtilization returns the share of connected Aqua liquidity reserved for credit at the end of each bucket
+func (s *Service) AquaUtilization(ctx context.Context, r Range) (*AquaUtilizationReport, error) {
+	rows, err := s.repo.AquaLiquidity(ctx, r.ChainID, r.From, r.To, r.Granularity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Aqua liquidity: %w", err)
+	}
+
+	report := &AquaUtilizationReport{Range: r, Points: make([]AquaUtilizationPoint, 0, len(rows))}
+	for _, row := range rows {
+		report.Points = append(report.Points, AquaUtilizationPoint{
+			Bucket:         row.Bucket,
+			Connected:      row.Connected,
+			Withdrawn:      row.Withdrawn,
+			Reserved:       row.Reserved,
+			Released:       row.Released,
+			TotalConnected: row.TotalConnected,
+			InUse:          row.InUse,
+			Utilization:    Utilization(row.InUse, row.TotalConnected),
+		})
+	}
+	return report, nil
+}
+
+// LenderShares converts per-lender volumes into shares of each bucket's total volume
+func LenderShares(rows []*repositories.LenderVolumeRow) []LenderSharePoint {
+	totals := make(map[int64]*big.Int)
+	for _, row := range rows {
+		if totals[row.Bucket] == nil {
+			totals[row.Bucket] = new(big.Int)
+		}
+		totals[row.Bucket].Add(totals[row.Bucket], parseAmount(row.Volume))
+	}
+
+	points := make([]LenderSharePoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, LenderSharePoint{
+			Bucket:        row.Bucket,
+			LenderAddress: row.LenderAddress,
+			Lines:         row.Lines,
+			Volume:        row.Volume,
+			Share:         fraction(parseAmount(row.Volume), totals[row.Bucket]),
+		})
+	}
+	return points
+}
+
+// Utilization returns inUse / total, 0 when nothing is connected
+func Utilization(inUse, total string) float64 {
+	return fraction(parseAmount(inUse), parseAmount(total))
+}
+
+func parseAmount(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return v
+}
+
+func fraction(num, den *big.Int) float64 {
+	if den.Sign() <= 0 || num.Sign() <= 0 {
+		return 0
+	}
+	f, _ := new(big.Rat).SetFrac(num, den).Float64()
+	return f
+}
+
+func ratio(num, den uint64) float64 {
+	if den == 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
@@ -2,9 +2,10 @@ package aqua
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
 	"github.com/Pagga-Wallet/aqua402/internal/queues"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
 	"go.uber.org/zap"
 )
 
@@ -21,50 +22,87 @@ func NewService(queue *queues.Queue, logger *zap.Logger) *Service {
 }
 
 type ConnectLiquidityRequest struct {
+	// ChainID is set from the chain query parameter
+	ChainID       uint64 `json:"-"`
 	LenderAddress string `json:"lender_address"`
 	Amount        string `json:"amount"`
 	TokenAddress  string `json:"token_address"`
 }
 
 type WithdrawLiquidityRequest struct {
+	ChainID       uint64 `json:"-"`
 	LenderAddress string `json:"lender_address"`
 	Amount        string `json:"amount"`
 }
 
+// Validate checks the request and normalizes addresses to their checksummed form
+func (req *ConnectLiquidityRequest) Validate() error {
+	var v validation.Validator
+	req.LenderAddress = v.Address("lender_address", req.LenderAddress).Hex()
+	v.Amount("amount", req.Amount, validation.WeiDecimals)
+	req.TokenAddress = v.Address("token_address", req.TokenAddress).Hex()
+	return v.Err()
+}
+
+// Validate checks the request and normalizes the lender address to its checksummed form
+func (req *WithdrawLiquidityRequest) Validate() error {
+	var v validation.Validator
+	req.LenderAddress = v.Address("lender_address", req.LenderAddress).Hex()
+	v.Amount("amount", req.Amount, validation.WeiDecimals)
+	return v.Err()
+}
+
 func (s *Service) ConnectLiquidity(ctx context.Context, req ConnectLiquidityRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
 	event := map[string]interface{}{
 		"type":          "liquidity_connected",
+		"chain_id":      req.ChainID,
 		"lender_address": req.LenderAddress,
 		"amount":        req.Amount,
 		"token_address": req.TokenAddress,
 	}
 
-	if err := s.queue.Publish("aqua.liquidity", event); err != nil {
+	if err := s.queue.Publish(ctx, "aqua.liquidity", event); err != nil {
 		s.logger.Error("Failed to publish liquidity event", zap.Error(err))
-		return fmt.Errorf("failed to connect liquidity: %w", err)
+		return apperrors.Unavailable(err, "failed to connect liquidity")
 	}
 
 	return nil
 }
 
 func (s *Service) WithdrawLiquidity(ctx context.Context, req WithdrawLiquidityRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
 	event := map[string]interface{}{
 		"type":          "liquidity_withdrawn",
+		"chain_id":      req.ChainID,
 		"lender_address": req.LenderAddress,
 		"amount":        req.Amount,
 	}
 
-	if err := s.queue.Publish("aqua.liquidity", event); err != nil {
+	if err := s.queue.Publish(ctx, "aqua.liquidity", event); err != nil {
 		s.logger.Error("Failed to publish withdrawal event", zap.Error(err))
-		return fmt.Errorf("failed to withdraw liquidity: %w", err)
+		return apperrors.Unavailable(err, "failed to withdraw liquidity")
 	}
 
 	return nil
 }
 
-func (s *Service) GetAvailableLiquidity(ctx context.Context, lenderAddress string) (map[string]interface{}, error) {
+func (s *Service) GetAvailableLiquidity(ctx context.Context, chainID uint64, lenderAddress string) (map[string]interface{}, error) {
+	var v validation.Validator
+	lenderAddress = v.Address("address", lenderAddress).Hex()
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
 	// TODO: Query from on-chain or cache
 	return map[string]interface{}{
+		"chain_id":       chainID,
 		"lender_address": lenderAddress,
 		"available":      "0",
 		"reserved":       "0",
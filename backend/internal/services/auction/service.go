@@ -2,10 +2,14 @@ package auction
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
 	"github.com/Pagga-Wallet/aqua402/internal/queues"
 	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +28,8 @@ func NewService(repo *repositories.AuctionRepository, queue *queues.Queue, logge
 }
 
 type CreateAuctionRequest struct {
+	// ChainID is set from the chain query parameter
+	ChainID         uint64 `json:"-"`
 	BorrowerAddress string `json:"borrower_address"`
 	Amount          string `json:"amount"`
 	Duration        uint64 `json:"duration"`
@@ -31,14 +37,39 @@ type CreateAuctionRequest struct {
 }
 
 type BidRequest struct {
+	ChainID       uint64 `json:"-"`
 	AuctionID     uint64 `json:"auction_id"`
 	LenderAddress string `json:"lender_address"`
 	RateBps       uint16 `json:"rate_bps"`
 	Limit         string `json:"limit"`
 }
 
+// Validate checks the request and normalizes the borrower address to its checksummed form
+func (req *CreateAuctionRequest) Validate() error {
+	var v validation.Validator
+	req.BorrowerAddress = v.Address("borrower_address", req.BorrowerAddress).Hex()
+	v.Amount("amount", req.Amount, validation.WeiDecimals)
+	v.Duration("duration", req.Duration, validation.MinDuration, validation.MaxDuration)
+	v.Duration("bidding_duration", req.BiddingDuration, validation.MinBiddingDuration, validation.MaxBiddingDuration)
+	return v.Err()
+}
+
+// Validate checks the request and normalizes the lender address to its checksummed form
+func (req *BidRequest) Validate() error {
+	var v validation.Validator
+	req.LenderAddress = v.Address("lender_address", req.LenderAddress).Hex()
+	v.RateBps("rate_bps", req.RateBps)
+	v.Amount("limit", req.Limit, validation.WeiDecimals)
+	return v.Err()
+}
+
 func (s *Service) CreateAuction(ctx context.Context, req CreateAuctionRequest) (map[string]interface{}, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	auction := map[string]interface{}{
+		"chain_id":         req.ChainID,
 		"borrower_address": req.BorrowerAddress,
 		"amount":           req.Amount,
 		"duration":         req.Duration,
@@ -47,52 +78,70 @@ func (s *Service) CreateAuction(ctx context.Context, req CreateAuctionRequest) (
 	}
 
 	event := map[string]interface{}{
-		"type":    "auction_created",
-		"payload": auction,
+		"type":     "auction_created",
+		"chain_id": req.ChainID,
+		"payload":  auction,
 	}
 
-	if err := s.queue.Publish("auction.events", event); err != nil {
+	if err := s.queue.Publish(ctx, "auction.events", event); err != nil {
 		s.logger.Error("Failed to publish auction event", zap.Error(err))
-		return nil, fmt.Errorf("failed to create auction: %w", err)
+		return nil, apperrors.Unavailable(err, "failed to create auction")
 	}
 
 	return auction, nil
 }
 
 func (s *Service) PlaceBid(ctx context.Context, req BidRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
 	event := map[string]interface{}{
 		"type":           "bid_placed",
+		"chain_id":       req.ChainID,
 		"auction_id":     req.AuctionID,
 		"lender_address": req.LenderAddress,
 		"rate_bps":       req.RateBps,
 		"limit":          req.Limit,
 	}
 
-	if err := s.queue.Publish("auction.bids", event); err != nil {
+	if err := s.queue.Publish(ctx, "auction.bids", event); err != nil {
 		s.logger.Error("Failed to publish bid event", zap.Error(err))
-		return fmt.Errorf("failed to place bid: %w", err)
+		return apperrors.Unavailable(err, "failed to place bid")
 	}
 
 	return nil
 }
 
-func (s *Service) GetAuction(ctx context.Context, id uint64) (*repositories.AuctionModel, error) {
-	return s.repo.GetAuction(ctx, id)
+func (s *Service) GetAuction(ctx context.Context, chainID, id uint64) (*repositories.AuctionModel, error) {
+	auction, err := s.repo.GetAuction(ctx, chainID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("auction %d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction %d: %w", id, err)
+	}
+	return auction, nil
+}
+
+func (s *Service) ListAuctions(ctx context.Context, q repositories.ListQuery) (*repositories.Page[repositories.AuctionModel], error) {
+	return s.repo.ListAuctions(ctx, q)
 }
 
-func (s *Service) ListAuctions(ctx context.Context, limit, offset int) ([]*repositories.AuctionModel, error) {
-	return s.repo.ListAuctions(ctx, limit, offset)
+func (s *Service) ListBids(ctx context.Context, auctionID uint64, q repositories.ListQuery) (*repositories.Page[repositories.BidModel], error) {
+	return s.repo.ListBids(ctx, auctionID, q)
 }
 
-func (s *Service) FinalizeAuction(ctx context.Context, auctionID uint64) error {
+func (s *Service) FinalizeAuction(ctx context.Context, chainID, auctionID uint64) error {
 	event := map[string]interface{}{
 		"type":       "auction_finalized",
+		"chain_id":   chainID,
 		"auction_id": auctionID,
 	}
 
-	if err := s.queue.Publish("auction.events", event); err != nil {
+	if err := s.queue.Publish(ctx, "auction.events", event); err != nil {
 		s.logger.Error("Failed to publish finalization event", zap.Error(err))
-		return fmt.Errorf("failed to finalize auction: %w", err)
+		return apperrors.Unavailable(err, "failed to finalize auction")
 	}
 
 	return nil
@@ -0,0 +1,216 @@
+package credit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+)
+
+// InterestMode selects how interest accrues on outstanding principal
+type InterestMode string
+
+const (
+	// InterestSimple accrues interest on outstanding principal only
+	InterestSimple InterestMode = "simple"
+	// InterestCompound also accrues interest on unpaid interest, compounded once per compounding period
+	InterestCompound InterestMode = "compound"
+)
+
+const (
+	secondsPerYear = 365 * 24 * 60 * 60
+	bpsDenominator = 10000
+)
+
+// Ledger replays the draw and repay history of a single credit line.
+// rateBps is an annual rate; repayments settle accrued interest before principal.
+type Ledger struct {
+	line              *repositories.CreditLineModel
+	mode              InterestMode
+	compoundingPeriod int64
+
+	principal    *big.Int
+	interest     *big.Int
+	interestBase *big.Int // unpaid interest carried into the current compounding period
+	drawn        *big.Int
+	repaid       *big.Int
+	interestPaid *big.Int
+	accruedTo    int64
+}
+
+// NewLedger creates an empty ledger for a credit line.
+// compoundingPeriod is in seconds and only used in compound mode.
+func NewLedger(line *repositories.CreditLineModel, mode InterestMode, compoundingPeriod int64) *Ledger {
+	if compoundingPeriod <= 0 {
+		compoundingPeriod = 24 * 60 * 60
+	}
+	return &Ledger{
+		line:              line,
+		mode:              mode,
+		compoundingPeriod: compoundingPeriod,
+		principal:         new(big.Int),
+		interest:          new(big.Int),
+		interestBase:      new(big.Int),
+		drawn:             new(big.Int),
+		repaid:            new(big.Int),
+		interestPaid:      new(big.Int),
+		accruedTo:         line.CreatedAt,
+	}
+}
+
+// Apply accrues interest up to the event time and books the draw or repayment
+func (l *Ledger) Apply(event *repositories.CreditEventModel) error {
+	amount, ok := new(big.Int).SetString(event.Amount, 10)
+	if !ok {
+		return fmt.Errorf("invalid amount %q in %s event %s", event.Amount, event.EventType, event.TxHash)
+	}
+
+	l.AccrueTo(event.Timestamp)
+
+	switch event.EventType {
+	case repositories.CreditEventDraw:
+		l.principal.Add(l.principal, amount)
+		l.drawn.Add(l.drawn, amount)
+	case repositories.CreditEventRepay:
+		l.repaid.Add(l.repaid, amount)
+
+		// Interest first, then principal
+		toInterest := minInt(amount, l.interest)
+		l.interest.Sub(l.interest, toInterest)
+		l.interestPaid.Add(l.interestPaid, toInterest)
+		if l.interestBase.Cmp(l.interest) > 0 {
+			l.interestBase.Set(l.interest)
+		}
+
+		toPrincipal := minInt(new(big.Int).Sub(amount, toInterest), l.principal)
+		l.principal.Sub(l.principal, toPrincipal)
+	default:
+		return fmt.Errorf("unknown credit event type %q", event.EventType)
+	}
+
+	return nil
+}
+
+// AccrueTo accrues interest up to the given unix timestamp
+func (l *Ledger) AccrueTo(ts int64) {
+	if ts <= l.accruedTo {
+		return
+	}
+
+	if l.mode != InterestCompound {
+		l.accrue(l.principal, ts-l.accruedTo)
+		l.accruedTo = ts
+		return
+	}
+
+	for l.accruedTo < ts {
+		// Compounding boundaries are anchored at the credit line creation time
+		elapsed := l.accruedTo - l.line.CreatedAt
+		boundary := l.line.CreatedAt + (elapsed/l.compoundingPeriod+1)*l.compoundingPeriod
+		end := ts
+		if boundary < end {
+			end = boundary
+		}
+
+		base := new(big.Int).Add(l.principal, l.interestBase)
+		l.accrue(base, end-l.accruedTo)
+		l.accruedTo = end
+
+		if end == boundary {
+			l.interestBase.Set(l.interest)
+		}
+	}
+}
+
+// accrue adds interest on base for the given number of seconds
+func (l *Ledger) accrue(base *big.Int, seconds int64) {
+	if base.Sign() == 0 || seconds <= 0 {
+		return
+	}
+	delta := new(big.Int).Mul(base, new(big.Int).SetUint64(l.line.RateBps))
+	delta.Mul(delta, big.NewInt(seconds))
+	delta.Quo(delta, big.NewInt(bpsDenominator*secondsPerYear))
+	l.interest.Add(l.interest, delta)
+}
+
+// Principal returns the outstanding principal
+func (l *Ledger) Principal() *big.Int {
+	return new(big.Int).Set(l.principal)
+}
+
+// AccruedInterest returns interest accrued and not yet repaid
+func (l *Ledger) AccruedInterest() *big.Int {
+	return new(big.Int).Set(l.interest)
+}
+
+// TotalOwed returns outstanding principal plus unpaid interest
+func (l *Ledger) TotalOwed() *big.Int {
+	return new(big.Int).Add(l.principal, l.interest)
+}
+
+// Project returns the installments due from the given time until the credit line expires,
+// assuming no further draws and every installment being paid on its due date.
+// Interest falls due every paymentInterval seconds counted from creation, principal at expiry.
+func (l *Ledger) Project(from, paymentInterval int64) []Installment {
+	p := l.clone()
+	p.AccrueTo(from)
+
+	if p.TotalOwed().Sign() == 0 {
+		return nil
+	}
+
+	expiresAt := p.line.ExpiresAt
+	if expiresAt <= from {
+		// Past expiry everything is due immediately
+		return []Installment{newInstallment(expiresAt, p.principal, p.interest, true)}
+	}
+
+	var installments []Installment
+	if paymentInterval > 0 {
+		elapsed := from - p.line.CreatedAt
+		for due := p.line.CreatedAt + (elapsed/paymentInterval+1)*paymentInterval; due < expiresAt; due += paymentInterval {
+			p.AccrueTo(due)
+			if p.interest.Sign() > 0 {
+				installments = append(installments, newInstallment(due, new(big.Int), p.interest, false))
+			}
+			p.interest.SetInt64(0)
+			p.interestBase.SetInt64(0)
+		}
+	}
+
+	p.AccrueTo(expiresAt)
+	return append(installments, newInstallment(expiresAt, p.principal, p.interest, false))
+}
+
+// clone returns an independent copy of the ledger state
+func (l *Ledger) clone() *Ledger {
+	return &Ledger{
+		line:              l.line,
+		mode:              l.mode,
+		compoundingPeriod: l.compoundingPeriod,
+		principal:         new(big.Int).Set(l.principal),
+		interest:          new(big.Int).Set(l.interest),
+		interestBase:      new(big.Int).Set(l.interestBase),
+		drawn:             new(big.Int).Set(l.drawn),
+		repaid:            new(big.Int).Set(l.repaid),
+		interestPaid:      new(big.Int).Set(l.interestPaid),
+		accruedTo:         l.accruedTo,
+	}
+}
+
+func newInstallment(dueAt int64, principal, interest *big.Int, overdue bool) Installment {
+	return Installment{
+		DueAt:     dueAt,
+		Principal: principal.String(),
+		Interest:  interest.String(),
+		Total:     new(big.Int).Add(principal, interest).String(),
+		Overdue:   overdue,
+	}
+}
+
+func minInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
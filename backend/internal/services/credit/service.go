@@ -0,0 +1,290 @@
+package credit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	repo              *repositories.CreditRepository
+	mode              InterestMode
+	paymentInterval   time.Duration
+	compoundingPeriod time.Duration
+	logger            *zap.Logger
+}
+
+// Config controls how credit line obligations are computed
+type Config struct {
+	InterestMode      InterestMode
+	PaymentInterval   time.Duration
+	CompoundingPeriod time.Duration
+}
+
+// ConfigFromEnv reads the credit ledger configuration from environment variables
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{InterestMode: InterestMode(os.Getenv("CREDIT_INTEREST_MODE"))}
+	if cfg.InterestMode != "" && cfg.InterestMode != InterestSimple && cfg.InterestMode != InterestCompound {
+		return cfg, fmt.Errorf("invalid CREDIT_INTEREST_MODE %q", cfg.InterestMode)
+	}
+
+	for key, target := range map[string]*time.Duration{
+		"CREDIT_PAYMENT_INTERVAL":   &cfg.PaymentInterval,
+		"CREDIT_COMPOUNDING_PERIOD": &cfg.CompoundingPeriod,
+	} {
+		if value := os.Getenv(key); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*target = d
+		}
+	}
+
+	return cfg, nil
+}
+
+func NewService(repo *repositories.CreditRepository, cfg Config, logger *zap.Logger) *Service {
+	if cfg.InterestMode == "" {
+		cfg.InterestMode = InterestSimple
+	}
+	if cfg.PaymentInterval <= 0 {
+		cfg.PaymentInterval = 30 * 24 * time.Hour
+	}
+	if cfg.CompoundingPeriod <= 0 {
+		cfg.CompoundingPeriod = 24 * time.Hour
+	}
+
+	return &Service{
+		repo:              repo,
+		mode:              cfg.InterestMode,
+		paymentInterval:   cfg.PaymentInterval,
+		compoundingPeriod: cfg.CompoundingPeriod,
+		logger:            logger,
+	}
+}
+
+// Installment is an amount falling due on a credit line
+type Installment struct {
+	DueAt     int64  `json:"due_at"`
+	Principal string `json:"principal"`
+	Interest  string `json:"interest"`
+	Total     string `json:"total"`
+	Overdue   bool   `json:"overdue"`
+}
+
+// Position is the balance of a credit line at a point in time
+type Position struct {
+	ChainID         uint64 `json:"chain_id"`
+	CreditLineID    uint64 `json:"credit_line_id"`
+	BorrowerAddress string `json:"borrower_address"`
+	LenderAddress   string `json:"lender_address"`
+	Limit           string `json:"limit"`
+	RateBps         uint64 `json:"rate_bps"`
+	CreatedAt       int64  `json:"created_at"`
+	ExpiresAt       int64  `json:"expires_at"`
+	Principal       string `json:"principal"`
+	AccruedInterest string `json:"accrued_interest"`
+	TotalOwed       string `json:"total_owed"`
+	TotalDrawn      string `json:"total_drawn"`
+	TotalRepaid     string `json:"total_repaid"`
+	InterestPaid    string `json:"interest_paid"`
+	AsOf            int64  `json:"as_of"`
+}
+
+// Schedule is the current position of a credit line and its projected installments
+type Schedule struct {
+	Position
+	InterestMode InterestMode  `json:"interest_mode"`
+	NextDue      *Installment  `json:"next_due"`
+	Installments []Installment `json:"installments"`
+}
+
+// Obligation is a credit line position with its next due amount
+type Obligation struct {
+	Position
+	NextDue *Installment `json:"next_due"`
+}
+
+// Obligations aggregates what a borrower owes across all credit lines
+type Obligations struct {
+	ChainID         uint64       `json:"chain_id"`
+	BorrowerAddress string       `json:"borrower_address"`
+	TotalPrincipal  string       `json:"total_principal"`
+	TotalInterest   string       `json:"total_interest"`
+	TotalOwed       string       `json:"total_owed"`
+	NextDue         *Installment `json:"next_due"`
+	CreditLines     []Obligation `json:"credit_lines"`
+	AsOf            int64        `json:"as_of"`
+}
+
+// GetSchedule returns the position and repayment schedule of a credit line
+func (s *Service) GetSchedule(ctx context.Context, chainID, creditLineID uint64) (*Schedule, error) {
+	line, err := s.repo.GetCreditLine(ctx, chainID, creditLineID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("credit line %d not found", creditLineID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit line %d: %w", creditLineID, err)
+	}
+
+	now := time.Now().Unix()
+	ledger, err := s.replay(ctx, line, now)
+	if err != nil {
+		return nil, err
+	}
+
+	installments := ledger.Project(now, int64(s.paymentInterval.Seconds()))
+	schedule := &Schedule{
+		Position:     positionOf(line, ledger, now),
+		InterestMode: s.mode,
+		Installments: installments,
+	}
+	if len(installments) > 0 {
+		schedule.NextDue = &installments[0]
+	}
+
+	return schedule, nil
+}
+
+// GetObligations returns what a borrower owes across all of their credit lines on a chain
+func (s *Service) GetObligations(ctx context.Context, chainID uint64, borrower string) (*Obligations, error) {
+	var v validation.Validator
+	borrower = v.Address("address", borrower).Hex()
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	lines, err := s.repo.ListCreditLinesByBorrower(ctx, chainID, borrower)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit lines: %w", err)
+	}
+
+	now := time.Now().Unix()
+	totalPrincipal := new(big.Int)
+	totalInterest := new(big.Int)
+	result := &Obligations{
+		ChainID:         chainID,
+		BorrowerAddress: borrower,
+		CreditLines:     []Obligation{},
+		AsOf:            now,
+	}
+
+	for _, line := range lines {
+		ledger, err := s.replay(ctx, line, now)
+		if err != nil {
+			return nil, err
+		}
+
+		obligation := Obligation{Position: positionOf(line, ledger, now)}
+		if installments := ledger.Project(now, int64(s.paymentInterval.Seconds())); len(installments) > 0 {
+			obligation.NextDue = &installments[0]
+			if result.NextDue == nil || installments[0].DueAt < result.NextDue.DueAt {
+				result.NextDue = &installments[0]
+			}
+		}
+
+		totalPrincipal.Add(totalPrincipal, ledger.Principal())
+		totalInterest.Add(totalInterest, ledger.AccruedInterest())
+		result.CreditLines = append(result.CreditLines, obligation)
+	}
+
+	result.TotalPrincipal = totalPrincipal.String()
+	result.TotalInterest = totalInterest.String()
+	result.TotalOwed = new(big.Int).Add(totalPrincipal, totalInterest).String()
+
+	return result, nil
+}
+
+// GetPosition returns the current position of a credit line
+func (s *Service) GetPosition(ctx context.Context, line *repositories.CreditLineModel) (*Position, error) {
+	now := time.Now().Unix()
+	ledger, err := s.replay(ctx, line, now)
+	if err != nil {
+		return nil, err
+	}
+
+	position := positionOf(line, ledger, now)
+	return &position, nil
+}
+
+// SnapshotCreditLine stores the current position of a credit line for historical reporting
+func (s *Service) SnapshotCreditLine(ctx context.Context, chainID, creditLineID uint64) error {
+	line, err := s.repo.GetCreditLine(ctx, chainID, creditLineID)
+	if err != nil {
+		return fmt.Errorf("failed to get credit line %d: %w", creditLineID, err)
+	}
+
+	now := time.Now().Unix()
+	ledger, err := s.replay(ctx, line, now)
+	if err != nil {
+		return err
+	}
+
+	snapshot := &repositories.CreditSnapshotModel{
+		ChainID:         line.ChainID,
+		CreditLineID:    line.ID,
+		BorrowerAddress: line.BorrowerAddress,
+		Principal:       ledger.Principal().String(),
+		AccruedInterest: ledger.AccruedInterest().String(),
+		TotalOwed:       ledger.TotalOwed().String(),
+		InterestPaid:    ledger.interestPaid.String(),
+		InterestMode:    string(s.mode),
+		SnapshotAt:      now,
+	}
+	if err := s.repo.SaveSnapshot(ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to save credit line snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// replay rebuilds the ledger of a credit line from its stored events up to the given time
+func (s *Service) replay(ctx context.Context, line *repositories.CreditLineModel, at int64) (*Ledger, error) {
+	events, err := s.repo.ListCreditEvents(ctx, line.ChainID, line.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit line events: %w", err)
+	}
+
+	ledger := NewLedger(line, s.mode, int64(s.compoundingPeriod.Seconds()))
+	for _, event := range events {
+		if event.Timestamp > at {
+			break
+		}
+		if err := ledger.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+	ledger.AccrueTo(at)
+
+	return ledger, nil
+}
+
+func positionOf(line *repositories.CreditLineModel, ledger *Ledger, at int64) Position {
+	return Position{
+		ChainID:         line.ChainID,
+		CreditLineID:    line.ID,
+		BorrowerAddress: line.BorrowerAddress,
+		LenderAddress:   line.LenderAddress,
+		Limit:           line.Limit,
+		RateBps:         line.RateBps,
+		CreatedAt:       line.CreatedAt,
+		ExpiresAt:       line.ExpiresAt,
+		Principal:       ledger.Principal().String(),
+		AccruedInterest: ledger.AccruedInterest().String(),
+		TotalOwed:       ledger.TotalOwed().String(),
+		TotalDrawn:      ledger.drawn.String(),
+		TotalRepaid:     ledger.repaid.String(),
+		InterestPaid:    ledger.interestPaid.String(),
+		AsOf:            at,
+	}
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Mode selects how the monitor learns about new blocks
+type Mode string
+
+const (
+	// ModeAuto subscribes when the client has a WebSocket endpoint and polls otherwise
+	ModeAuto Mode = "auto"
+	// ModePoll asks for the block number every PollInterval
+	ModePoll Mode = "poll"
+	// ModeSubscribe subscribes to new heads, and to the contract logs when events need no confirmations.
+	// The monitor polls while the subscription is down and fills the gap once it is restored.
+	ModeSubscribe Mode = "subscribe"
+)
+
+// Config controls how the event monitor follows the chain
+type Config struct {
+	Mode         Mode
+	PollInterval time.Duration
+	// BatchSize is the most blocks read by one eth_getLogs request when catching up
+	BatchSize uint64
+	// ResubscribeDelay is how long the monitor polls after a subscription drops before subscribing again
+	ResubscribeDelay time.Duration
+	// CheckpointInterval is how often the last processed block is saved, it is also saved on shutdown
+	CheckpointInterval time.Duration
+}
+
+// ConfigFromEnv reads EVENT_MONITOR_MODE (auto, poll or subscribe, default auto), EVENT_POLL_INTERVAL (5s),
+// EVENT_BATCH_SIZE (1000 blocks), EVENT_RESUBSCRIBE_DELAY (30s) and EVENT_CHECKPOINT_INTERVAL (30s)
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Mode:               ModeAuto,
+		PollInterval:       5 * time.Second,
+		BatchSize:          1000,
+		ResubscribeDelay:   30 * time.Second,
+		CheckpointInterval: 30 * time.Second,
+	}
+
+	if v := os.Getenv("EVENT_MONITOR_MODE"); v != "" {
+		switch mode := Mode(v); mode {
+		case ModeAuto, ModePoll, ModeSubscribe:
+			cfg.Mode = mode
+		default:
+			return cfg, fmt.Errorf("invalid EVENT_MONITOR_MODE %q, must be auto, poll or subscribe", v)
+		}
+	}
+	for key, target := range map[string]*time.Duration{
+		"EVENT_POLL_INTERVAL":       &cfg.PollInterval,
+		"EVENT_RESUBSCRIBE_DELAY":   &cfg.ResubscribeDelay,
+		"EVENT_CHECKPOINT_INTERVAL": &cfg.CheckpointInterval,
+	} {
+		if value := os.Getenv(key); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return cfg, fmt.Errorf("invalid %s %q", key, value)
+			}
+			*target = d
+		}
+	}
+	if v := os.Getenv("EVENT_BATCH_SIZE"); v != "" {
+		size, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || size == 0 {
+			return cfg, fmt.Errorf("invalid EVENT_BATCH_SIZE %q", v)
+		}
+		cfg.BatchSize = size
+	}
+	return cfg, nil
+}
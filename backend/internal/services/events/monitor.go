@@ -2,40 +2,108 @@ package events
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
+	"github.com/Pagga-Wallet/aqua402/internal/metrics"
 	"github.com/Pagga-Wallet/aqua402/internal/queues"
 	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
 	"github.com/Pagga-Wallet/aqua402/pkg/evm"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
-// Monitor monitors blockchain events and processes them
+// Monitor monitors the blockchain events of one chain and processes them
 type Monitor struct {
 	evmClient   *evm.Client
 	queue       *queues.Queue
 	rfqRepo     *repositories.RFQRepository
+	checkpoints    CheckpointStore
 	logger      *zap.Logger
+	config         Config
+	chainID        uint64
+	confirmations  uint64
 	rfqAddress  common.Address
 	auctionAddress common.Address
+	creditAddress  common.Address
+	financeAddress common.Address
+	aquaAddress    common.Address
 	lastBlock   uint64
+	// streamed holds the block numbers of logs processed from the log subscription in blocks after
+	// lastBlock, so the range read of those blocks skips them
+	streamed map[logKey]uint64
+
+	mu     sync.Mutex
+	status Status
+	// saved and savedAt are the last checkpoint written
+	saved   uint64
+	savedAt time.Time
 }
 
-// NewMonitor creates a new event monitor
+// CheckpointStore keeps the last block processed on each chain across restarts
+type CheckpointStore interface {
+	SaveCheckpoint(ctx context.Context, chainID, lastBlock uint64) error
+	// GetCheckpoint returns sql.ErrNoRows when no checkpoint was saved for the chain
+	GetCheckpoint(ctx context.Context, chainID uint64) (uint64, error)
+}
+
+// Status is how far a monitor has followed its chain
+type Status struct {
+	ChainID       uint64
+	Confirmations uint64
+	// Head is the latest chain head seen, 0 until the first one
+	Head uint64
+	// Processed is the last block whose logs were processed
+	Processed       uint64
+	LastProcessedAt time.Time
+	// Err is the error of the last attempt to process blocks, nil once one succeeds
+	Err error
+}
+
+// Lag returns how many blocks Processed is behind Head, confirmations included
+func (s Status) Lag() uint64 {
+	if s.Head <= s.Processed {
+		return 0
+	}
+	return s.Head - s.Processed
+}
+
+// logKey identifies a log within the chain
+type logKey struct {
+	txHash common.Hash
+	index  uint
+}
+
+// NewMonitor creates an event monitor for the contracts of chain, evmClient must be connected to it.
+// The monitor resumes after the block saved in checkpoints, or starts at the chain head when there is
+// none or checkpoints is nil.
 func NewMonitor(
 	evmClient *evm.Client,
 	queue *queues.Queue,
 	rfqRepo *repositories.RFQRepository,
-	rfqAddress, auctionAddress string,
+	checkpoints CheckpointStore,
+	chain *chains.Chain,
+	cfg Config,
 	logger *zap.Logger,
 ) (*Monitor, error) {
-	rfqAddr := common.HexToAddress(rfqAddress)
-	auctionAddr := common.HexToAddress(auctionAddress)
+	rfqAddr := common.HexToAddress(chain.Contracts.RFQ)
+	auctionAddr := common.HexToAddress(chain.Contracts.Auction)
+	// Credit line monitoring is optional, an empty address leaves it disabled
+	creditAddr := common.HexToAddress(chain.Contracts.Credit)
+	// AgentFinance links credit lines to the RFQ or auction they were opened from, also optional
+	financeAddr := common.HexToAddress(chain.Contracts.Finance)
+	// AquaIntegration liquidity movements feed utilization analytics, also optional
+	aquaAddr := common.HexToAddress(chain.Contracts.Aqua)
 
 	// Get current block number
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -46,33 +114,104 @@ func NewMonitor(
 		return nil, fmt.Errorf("failed to get block number: %w", err)
 	}
 
+	logger = logger.With(zap.Uint64("chain_id", chain.ID), zap.String("chain", chain.Name))
+	lastBlock := confirmed(blockNumber, chain.Confirmations)
+	var saved uint64
+	if checkpoints != nil {
+		checkpoint, err := checkpoints.GetCheckpoint(ctx, chain.ID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			logger.Warn("Failed to read the event monitor checkpoint, starting at the chain head", zap.Error(err))
+		case checkpoint > lastBlock:
+			// The chain was reset, as a local node is on restart
+			logger.Warn("Checkpoint is past the chain head, starting at the chain head",
+				zap.Uint64("checkpoint", checkpoint), zap.Uint64("head", blockNumber))
+		default:
+			lastBlock, saved = checkpoint, checkpoint
+		}
+	}
+
 	return &Monitor{
 		evmClient:      evmClient,
 		queue:          queue,
 		rfqRepo:        rfqRepo,
+		checkpoints:    checkpoints,
 		logger:         logger,
+		config:         cfg,
+		chainID:        chain.ID,
+		confirmations:  chain.Confirmations,
 		rfqAddress:     rfqAddr,
 		auctionAddress: auctionAddr,
-		lastBlock:      blockNumber,
+		creditAddress:  creditAddr,
+		financeAddress: financeAddr,
+		aquaAddress:    aquaAddr,
+		lastBlock:      lastBlock,
+		streamed:       make(map[logKey]uint64),
+		status: Status{
+			ChainID:         chain.ID,
+			Confirmations:   chain.Confirmations,
+			Head:            blockNumber,
+			Processed:       lastBlock,
+			LastProcessedAt: time.Now(),
+		},
+		saved:   saved,
+		savedAt: time.Now(),
 	}, nil
 }
 
-// Start starts monitoring blockchain events
+// Start starts monitoring blockchain events. In subscribe mode new heads, and contract logs when events
+// need no confirmations, are streamed from the node. When the subscription drops the monitor polls for
+// ResubscribeDelay, then subscribes again; the blocks missed meanwhile are read before streaming resumes.
 func (m *Monitor) Start(ctx context.Context) error {
+	subscribe := m.config.Mode == ModeSubscribe || (m.config.Mode == ModeAuto && m.evmClient.SupportsSubscriptions())
 	m.logger.Info("Starting event monitor", 
 		zap.String("rfq_address", m.rfqAddress.Hex()),
 		zap.String("auction_address", m.auctionAddress.Hex()),
-		zap.Uint64("starting_block", m.lastBlock))
+		zap.String("credit_address", m.creditAddress.Hex()),
+		zap.String("finance_address", m.financeAddress.Hex()),
+		zap.String("aqua_address", m.aquaAddress.Hex()),
+		zap.Uint64("confirmations", m.confirmations),
+		zap.Uint64("starting_block", m.lastBlock),
+		zap.Bool("subscribe", subscribe),
+		zap.Duration("poll_interval", m.config.PollInterval))
+
+	if !subscribe {
+		return m.poll(ctx, 0)
+	}
+	for {
+		err := m.stream(ctx)
+		if ctx.Err() != nil {
+			m.logger.Info("Event monitor stopped")
+			return ctx.Err()
+		}
+		m.logger.Warn("Event subscription failed, polling until it is restored",
+			zap.Error(err), zap.Duration("resubscribe_delay", m.config.ResubscribeDelay))
+		if err := m.poll(ctx, m.config.ResubscribeDelay); err != nil {
+			return err
+		}
+	}
+}
 
-	// Start polling for new blocks
-	ticker := time.NewTicker(5 * time.Second)
+// poll processes new blocks every PollInterval, for duration d or until ctx is done when d is 0
+func (m *Monitor) poll(ctx context.Context, d time.Duration) error {
+	ticker := time.NewTicker(m.config.PollInterval)
 	defer ticker.Stop()
 
+	var deadline <-chan time.Time
+	if d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			m.logger.Info("Event monitor stopped")
 			return ctx.Err()
+		case <-deadline:
+			return nil
 		case <-ticker.C:
 			if err := m.processNewBlocks(ctx); err != nil {
 				m.logger.Error("Error processing blocks", zap.Error(err))
@@ -81,64 +220,309 @@ func (m *Monitor) Start(ctx context.Context) error {
 	}
 }
 
-// processNewBlocks processes new blocks and extracts events
-func (m *Monitor) processNewBlocks(ctx context.Context) error {
-	currentBlock, err := m.evmClient.BlockNumber(ctx)
+// stream follows the chain through subscriptions until one of them fails. Every new head triggers a
+// read of the blocks it confirms, which also catches logs the log subscription missed.
+func (m *Monitor) stream(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	heads := make(chan *types.Header, 16)
+	headSub, err := m.evmClient.SubscribeNewHead(ctx, heads)
 	if err != nil {
-		return fmt.Errorf("failed to get current block: %w", err)
+		return fmt.Errorf("failed to subscribe to new heads: %w", err)
 	}
-
-	if currentBlock <= m.lastBlock {
-		return nil // No new blocks
+	defer headSub.Unsubscribe()
+
+	// Logs are only worth streaming when they can be processed on arrival, confirmed logs are read
+	// from the blocks once the head has moved far enough
+	var (
+		logs   chan types.Log
+		logErr <-chan error
+	)
+	if m.confirmations == 0 {
+		logs = make(chan types.Log, 256)
+		logSub, err := m.evmClient.SubscribeFilterLogs(ctx, ethereum.FilterQuery{Addresses: m.addresses()}, logs)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to logs: %w", err)
+		}
+		defer logSub.Unsubscribe()
+		logErr = logSub.Err()
 	}
+	m.logger.Info("Subscribed to chain events", zap.Bool("logs", logs != nil))
 
-	// Process blocks from lastBlock+1 to currentBlock
-	fromBlock := m.lastBlock + 1
-	toBlock := currentBlock
-
-	m.logger.Debug("Processing blocks",
-		zap.Uint64("from", fromBlock),
-		zap.Uint64("to", toBlock))
+	// Fill the gap since the last processed block, logs and heads arriving meanwhile wait in their channels
+	if err := m.processNewBlocks(ctx); err != nil {
+		return err
+	}
 
-	// Create filter query for RFQ events
-	rfqQuery := ethereum.FilterQuery{
-		FromBlock: new(big.Int).SetUint64(fromBlock),
-		ToBlock:   new(big.Int).SetUint64(toBlock),
-		Addresses: []common.Address{m.rfqAddress},
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-headSub.Err():
+			return fmt.Errorf("head subscription closed: %w", err)
+		case err := <-logErr:
+			return fmt.Errorf("log subscription closed: %w", err)
+		case log := <-logs:
+			m.processStreamedLog(ctx, log)
+		case head := <-heads:
+			if err := m.processBlocks(ctx, head.Number.Uint64()); err != nil {
+				m.logger.Error("Error processing blocks", zap.Error(err))
+			}
+		}
 	}
+}
 
-	// Create filter query for Auction events
-	auctionQuery := ethereum.FilterQuery{
-		FromBlock: new(big.Int).SetUint64(fromBlock),
-		ToBlock:   new(big.Int).SetUint64(toBlock),
-		Addresses: []common.Address{m.auctionAddress},
+// processStreamedLog processes a log from the log subscription unless the blocks read already did
+func (m *Monitor) processStreamedLog(ctx context.Context, log types.Log) {
+	if log.Removed {
+		m.logger.Warn("Processed log removed by a reorg",
+			zap.String("tx_hash", log.TxHash.Hex()), zap.Uint64("block", log.BlockNumber))
+		return
+	}
+	if log.BlockNumber <= m.lastBlock {
+		return
+	}
+	key := logKey{txHash: log.TxHash, index: log.Index}
+	if _, ok := m.streamed[key]; ok {
+		return
 	}
+	m.streamed[key] = log.BlockNumber
+	_ = m.processLog(ctx, log)
+}
 
-	// Get RFQ logs
-	rfqLogs, err := m.evmClient.FilterLogs(ctx, rfqQuery)
+// processNewBlocks processes the blocks confirmed since the last call
+func (m *Monitor) processNewBlocks(ctx context.Context) error {
+	head, err := m.evmClient.BlockNumber(ctx)
 	if err != nil {
-		m.logger.Warn("Failed to filter RFQ logs", zap.Error(err))
-	} else {
-		for _, log := range rfqLogs {
-			if err := m.processRFQEvent(ctx, log); err != nil {
-				m.logger.Error("Failed to process RFQ event", zap.Error(err), zap.String("tx_hash", log.TxHash.Hex()))
+		err = fmt.Errorf("failed to get current block: %w", err)
+		m.setErr(err)
+		return err
+	}
+	return m.processBlocks(ctx, head)
+}
+
+// processBlocks reads the logs of the blocks head confirms, BatchSize blocks per request. A failed read
+// is retried from the same block next time.
+func (m *Monitor) processBlocks(ctx context.Context, head uint64) (err error) {
+	defer func() { m.setErr(err) }()
+
+	// Blocks are processed once they have enough confirmations to be safe from reorgs
+	currentBlock := confirmed(head, m.confirmations)
+	m.observe(head, false)
+
+	for m.lastBlock < currentBlock {
+		fromBlock := m.lastBlock + 1
+		toBlock := min(currentBlock, m.lastBlock+m.config.BatchSize)
+
+		m.logger.Debug("Processing blocks",
+			zap.Uint64("from", fromBlock),
+			zap.Uint64("to", toBlock))
+
+		logs, err := m.evmClient.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: m.addresses(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to filter logs of blocks %d-%d: %w", fromBlock, toBlock, err)
+		}
+		for _, log := range logs {
+			if _, ok := m.streamed[logKey{txHash: log.TxHash, index: log.Index}]; ok {
+				continue
+			}
+			_ = m.processLog(ctx, log)
+		}
+		// Logs of a batch interrupted by shutdown may not have been published, it is read again on restart
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		m.lastBlock = toBlock
+		m.observe(head, true)
+		// Streamed logs of processed blocks are dropped on arrival, they need not be remembered
+		for key, block := range m.streamed {
+			if block <= m.lastBlock {
+				delete(m.streamed, key)
 			}
 		}
 	}
 
-	// Get Auction logs
-	auctionLogs, err := m.evmClient.FilterLogs(ctx, auctionQuery)
-	if err != nil {
-		m.logger.Warn("Failed to filter Auction logs", zap.Error(err))
-	} else {
-		for _, log := range auctionLogs {
-			if err := m.processAuctionEvent(ctx, log); err != nil {
-				m.logger.Error("Failed to process Auction event", zap.Error(err), zap.String("tx_hash", log.TxHash.Hex()))
+	if time.Since(m.lastSaved()) >= m.config.CheckpointInterval {
+		if err := m.Checkpoint(ctx); err != nil {
+			m.logger.Warn("Failed to save event monitor checkpoint", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// ReplayResult counts the logs a replay processed and those whose processing failed. To is the last
+// block replayed so far.
+type ReplayResult struct {
+	From      uint64
+	To        uint64
+	Processed int
+	Failed    int
+}
+
+// Replay processes the logs of blocks from to to again, BatchSize blocks per request, publishing their
+// events as when they were first seen. It neither moves the monitor's position nor saves a checkpoint,
+// so it can run beside the worker; consumers store events under the log that emitted them, replayed ones
+// replace the rows they wrote. progress, if not nil, is called after each batch.
+func (m *Monitor) Replay(ctx context.Context, from, to uint64, progress func(ReplayResult)) (ReplayResult, error) {
+	result := ReplayResult{From: from}
+	if to < from {
+		return result, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+
+	for fromBlock := from; fromBlock <= to; fromBlock = result.To + 1 {
+		toBlock := min(to, fromBlock+m.config.BatchSize-1)
+
+		logs, err := m.evmClient.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: m.addresses(),
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to filter logs of blocks %d-%d: %w", fromBlock, toBlock, err)
+		}
+		for _, log := range logs {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			if m.processLog(ctx, log) != nil {
+				result.Failed++
+				continue
 			}
+			result.Processed++
+		}
+
+		result.To = toBlock
+		if progress != nil {
+			progress(result)
 		}
 	}
+	return result, nil
+}
+
+// Checkpoint saves the last processed block when it moved since the last checkpoint. The worker calls
+// it once more after the monitor stopped.
+func (m *Monitor) Checkpoint(ctx context.Context) error {
+	if m.checkpoints == nil {
+		return nil
+	}
+	m.mu.Lock()
+	processed, saved := m.status.Processed, m.saved
+	m.mu.Unlock()
+	if processed == saved {
+		return nil
+	}
 
-	m.lastBlock = toBlock
+	if err := m.checkpoints.SaveCheckpoint(ctx, m.chainID, processed); err != nil {
+		return fmt.Errorf("failed to save checkpoint at block %d: %w", processed, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved, m.savedAt = processed, time.Now()
+	return nil
+}
+
+func (m *Monitor) lastSaved() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.savedAt
+}
+
+// observe reports head and lastBlock in the metrics and the status, advanced tells lastBlock moved
+func (m *Monitor) observe(head uint64, advanced bool) {
+	var lag uint64
+	if head > m.lastBlock {
+		lag = head - m.lastBlock
+	}
+	chainLabel := metrics.Chain(m.chainID)
+	metrics.MonitorHeadBlock.WithLabelValues(chainLabel).Set(float64(head))
+	metrics.MonitorHeadLag.WithLabelValues(chainLabel).Set(float64(lag))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.Head = head
+	m.status.Processed = m.lastBlock
+	if advanced {
+		metrics.MonitorProcessedBlock.WithLabelValues(chainLabel).Set(float64(m.lastBlock))
+		metrics.MonitorLastProcessed.WithLabelValues(chainLabel).SetToCurrentTime()
+		m.status.LastProcessedAt = time.Now()
+	}
+}
+
+// setErr records the outcome of the last attempt to process blocks
+func (m *Monitor) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.Err = err
+}
+
+// Status returns how far the monitor has got, it is safe to call while the monitor runs
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// addresses returns the monitored contract addresses
+func (m *Monitor) addresses() []common.Address {
+	addresses := []common.Address{m.rfqAddress, m.auctionAddress}
+	for _, address := range []common.Address{m.creditAddress, m.financeAddress, m.aquaAddress} {
+		if address != (common.Address{}) {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// processLog hands a log to the processor of the contract that emitted it. Its error is logged and
+// counted already, Replay reports it.
+func (m *Monitor) processLog(ctx context.Context, log types.Log) error {
+	event := "unknown"
+	if len(log.Topics) > 0 {
+		event = eventName(log.Topics[0].Hex())
+	}
+	// Each log starts a trace named after its transaction, continued by the messages it publishes
+	ctx, span := tracing.Start(ctx, "event "+event, trace.SpanKindInternal,
+		attribute.Int64("chain.id", int64(m.chainID)),
+		attribute.String("event.name", event),
+		attribute.String("tx_hash", log.TxHash.Hex()),
+		attribute.Int64("block_number", int64(log.BlockNumber)),
+		attribute.Int("log_index", int(log.Index)),
+		attribute.String("contract.address", log.Address.Hex()),
+	)
+
+	var (
+		contract string
+		err      error
+	)
+	switch log.Address {
+	case m.rfqAddress:
+		contract, err = "RFQ", m.processRFQEvent(ctx, log)
+	case m.auctionAddress:
+		contract, err = "Auction", m.processAuctionEvent(ctx, log)
+	case m.creditAddress:
+		contract, err = "credit line", m.processCreditEvent(ctx, log)
+	case m.financeAddress:
+		contract, err = "AgentFinance", m.processFinanceEvent(ctx, log)
+	case m.aquaAddress:
+		contract, err = "AquaIntegration", m.processAquaEvent(ctx, log)
+	default:
+		span.End()
+		return nil
+	}
+	tracing.End(span, err)
+
+	if err != nil {
+		metrics.MonitorLogErrors.WithLabelValues(metrics.Chain(m.chainID), event).Inc()
+		m.logger.Error("Failed to process "+contract+" event", zap.Error(err), zap.String("tx_hash", log.TxHash.Hex()))
+		return err
+	}
+	metrics.MonitorLogs.WithLabelValues(metrics.Chain(m.chainID), event).Inc()
 	return nil
 }
 
@@ -185,12 +569,18 @@ func (m *Monitor) processRFQCreated(ctx context.Context, log types.Log) error {
 	amount := new(big.Int).SetBytes(log.Data[0:32])
 	duration := new(big.Int).SetBytes(log.Data[32:64])
 
+	timestamp, err := m.blockTimestamp(ctx, log.BlockNumber)
+	if err != nil {
+		return err
+	}
+
 	eventData := map[string]interface{}{
 		"type":            "rfq_created",
 		"rfq_id":          rfqId.String(),
 		"borrower":        borrower.Hex(),
 		"amount":          amount.String(),
 		"duration":        duration.String(),
+		"timestamp":       timestamp,
 		"tx_hash":         log.TxHash.Hex(),
 		"block_number":    log.BlockNumber,
 		"block_hash":      log.BlockHash.Hex(),
@@ -199,7 +589,7 @@ func (m *Monitor) processRFQCreated(ctx context.Context, log types.Log) error {
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("rfq.events", eventData); err != nil {
+	if err := m.publish(ctx, "rfq.events", eventData); err != nil {
 		return fmt.Errorf("failed to publish RFQCreated event: %w", err)
 	}
 
@@ -228,12 +618,18 @@ func (m *Monitor) processQuoteSubmitted(ctx context.Context, log types.Log) erro
 	rateBps := new(big.Int).SetBytes(log.Data[30:32]) // Last 2 bytes of first 32 bytes
 	limit := new(big.Int).SetBytes(log.Data[32:64])
 
+	timestamp, err := m.blockTimestamp(ctx, log.BlockNumber)
+	if err != nil {
+		return err
+	}
+
 	eventData := map[string]interface{}{
 		"type":            "quote_submitted",
 		"rfq_id":          rfqId.String(),
 		"lender":          lender.Hex(),
 		"rate_bps":        rateBps.Uint64(),
 		"limit":           limit.String(),
+		"timestamp":       timestamp,
 		"tx_hash":         log.TxHash.Hex(),
 		"block_number":    log.BlockNumber,
 		"block_hash":      log.BlockHash.Hex(),
@@ -242,7 +638,7 @@ func (m *Monitor) processQuoteSubmitted(ctx context.Context, log types.Log) erro
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("rfq.quotes", eventData); err != nil {
+	if err := m.publish(ctx, "rfq.quotes", eventData); err != nil {
 		return fmt.Errorf("failed to publish QuoteSubmitted event: %w", err)
 	}
 
@@ -280,7 +676,7 @@ func (m *Monitor) processQuoteAccepted(ctx context.Context, log types.Log) error
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("rfq.events", eventData); err != nil {
+	if err := m.publish(ctx, "rfq.events", eventData); err != nil {
 		return fmt.Errorf("failed to publish QuoteAccepted event: %w", err)
 	}
 
@@ -314,7 +710,7 @@ func (m *Monitor) processRFQExecuted(ctx context.Context, log types.Log) error {
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("rfq.events", eventData); err != nil {
+	if err := m.publish(ctx, "rfq.events", eventData); err != nil {
 		return fmt.Errorf("failed to publish RFQExecuted event: %w", err)
 	}
 
@@ -369,12 +765,18 @@ func (m *Monitor) processAuctionCreated(ctx context.Context, log types.Log) erro
 	amount := new(big.Int).SetBytes(log.Data[0:32])
 	endTime := new(big.Int).SetBytes(log.Data[32:64])
 
+	timestamp, err := m.blockTimestamp(ctx, log.BlockNumber)
+	if err != nil {
+		return err
+	}
+
 	eventData := map[string]interface{}{
 		"type":            "auction_created",
 		"auction_id":      auctionId.String(),
 		"borrower":        borrower.Hex(),
 		"amount":          amount.String(),
 		"end_time":        endTime.String(),
+		"timestamp":       timestamp,
 		"tx_hash":         log.TxHash.Hex(),
 		"block_number":    log.BlockNumber,
 		"block_hash":      log.BlockHash.Hex(),
@@ -383,7 +785,7 @@ func (m *Monitor) processAuctionCreated(ctx context.Context, log types.Log) erro
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("auction.events", eventData); err != nil {
+	if err := m.publish(ctx, "auction.events", eventData); err != nil {
 		return fmt.Errorf("failed to publish AuctionCreated event: %w", err)
 	}
 
@@ -409,12 +811,18 @@ func (m *Monitor) processBidPlaced(ctx context.Context, log types.Log) error {
 	rateBps := new(big.Int).SetBytes(log.Data[30:32])
 	limit := new(big.Int).SetBytes(log.Data[32:64])
 
+	timestamp, err := m.blockTimestamp(ctx, log.BlockNumber)
+	if err != nil {
+		return err
+	}
+
 	eventData := map[string]interface{}{
 		"type":            "bid_placed",
 		"auction_id":      auctionId.String(),
 		"lender":          lender.Hex(),
 		"rate_bps":        rateBps.Uint64(),
 		"limit":           limit.String(),
+		"timestamp":       timestamp,
 		"tx_hash":         log.TxHash.Hex(),
 		"block_number":    log.BlockNumber,
 		"block_hash":      log.BlockHash.Hex(),
@@ -423,7 +831,7 @@ func (m *Monitor) processBidPlaced(ctx context.Context, log types.Log) error {
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("auction.bids", eventData); err != nil {
+	if err := m.publish(ctx, "auction.bids", eventData); err != nil {
 		return fmt.Errorf("failed to publish BidPlaced event: %w", err)
 	}
 
@@ -457,7 +865,7 @@ func (m *Monitor) processAuctionFinalized(ctx context.Context, log types.Log) er
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("auction.events", eventData); err != nil {
+	if err := m.publish(ctx, "auction.events", eventData); err != nil {
 		return fmt.Errorf("failed to publish AuctionFinalized event: %w", err)
 	}
 
@@ -490,7 +898,7 @@ func (m *Monitor) processAuctionSettled(ctx context.Context, log types.Log) erro
 	}
 
 	// Publish to RabbitMQ
-	if err := m.queue.Publish("auction.events", eventData); err != nil {
+	if err := m.publish(ctx, "auction.events", eventData); err != nil {
 		return fmt.Errorf("failed to publish AuctionSettled event: %w", err)
 	}
 
@@ -501,3 +909,259 @@ func (m *Monitor) processAuctionSettled(ctx context.Context, log types.Log) erro
 	return nil
 }
 
+// processCreditEvent processes x402 credit line contract events
+func (m *Monitor) processCreditEvent(ctx context.Context, log types.Log) error {
+	if log.Address != m.creditAddress {
+		return nil
+	}
+
+	if len(log.Topics) == 0 {
+		return nil
+	}
+
+	eventSigStr := log.Topics[0].Hex()
+
+	// Identify event by signature
+	switch eventSigStr {
+	case CreditLineOpenedSignature:
+		return m.processCreditLineOpened(ctx, log)
+	case CreditDrawnSignature:
+		return m.processCreditMovement(ctx, log, "credit_drawn")
+	case CreditRepaidSignature:
+		return m.processCreditMovement(ctx, log, "credit_repaid")
+	default:
+		m.logger.Debug("Unknown credit line event signature", zap.String("signature", eventSigStr))
+		return nil
+	}
+}
+
+// processCreditLineOpened processes CreditLineOpened event
+// CreditLineOpened(uint256 indexed creditLineId, address indexed borrower, address indexed lender, uint256 limit, uint256 rateBps, uint256 expiresAt)
+func (m *Monitor) processCreditLineOpened(ctx context.Context, log types.Log) error {
+	if len(log.Topics) < 4 || len(log.Data) < 96 {
+		return fmt.Errorf("invalid CreditLineOpened event data")
+	}
+
+	creditLineId := new(big.Int).SetBytes(log.Topics[1].Bytes())
+	borrower := common.BytesToAddress(log.Topics[2].Bytes())
+	lender := common.BytesToAddress(log.Topics[3].Bytes())
+
+	limit := new(big.Int).SetBytes(log.Data[0:32])
+	rateBps := new(big.Int).SetBytes(log.Data[32:64])
+	expiresAt := new(big.Int).SetBytes(log.Data[64:96])
+
+	timestamp, err := m.blockTimestamp(ctx, log.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	eventData := map[string]interface{}{
+		"type":             "credit_line_opened",
+		"credit_line_id":   creditLineId.String(),
+		"borrower":         borrower.Hex(),
+		"lender":           lender.Hex(),
+		"limit":            limit.String(),
+		"rate_bps":         rateBps.Uint64(),
+		"expires_at":       expiresAt.Uint64(),
+		"timestamp":        timestamp,
+		"tx_hash":          log.TxHash.Hex(),
+		"block_number":     log.BlockNumber,
+		"block_hash":       log.BlockHash.Hex(),
+		"log_index":        log.Index,
+		"contract_address": log.Address.Hex(),
+	}
+
+	// Publish to RabbitMQ
+	if err := m.publish(ctx, "credit.events", eventData); err != nil {
+		return fmt.Errorf("failed to publish CreditLineOpened event: %w", err)
+	}
+
+	m.logger.Info("Processed CreditLineOpened event",
+		zap.String("tx_hash", log.TxHash.Hex()),
+		zap.String("credit_line_id", creditLineId.String()),
+		zap.String("borrower", borrower.Hex()),
+		zap.String("lender", lender.Hex()))
+
+	return nil
+}
+
+// processCreditMovement processes CreditDrawn and CreditRepaid events
+// CreditDrawn(uint256 indexed creditLineId, uint256 amount)
+// CreditRepaid(uint256 indexed creditLineId, uint256 amount)
+func (m *Monitor) processCreditMovement(ctx context.Context, log types.Log, eventType string) error {
+	if len(log.Topics) < 2 || len(log.Data) < 32 {
+		return fmt.Errorf("invalid %s event data", eventType)
+	}
+
+	creditLineId := new(big.Int).SetBytes(log.Topics[1].Bytes())
+	amount := new(big.Int).SetBytes(log.Data[0:32])
+
+	// Interest accrual needs the on-chain time of the movement, not the time we saw it
+	timestamp, err := m.blockTimestamp(ctx, log.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	eventData := map[string]interface{}{
+		"type":             eventType,
+		"credit_line_id":   creditLineId.String(),
+		"amount":           amount.String(),
+		"timestamp":        timestamp,
+		"tx_hash":          log.TxHash.Hex(),
+		"block_number":     log.BlockNumber,
+		"block_hash":       log.BlockHash.Hex(),
+		"log_index":        log.Index,
+		"contract_address": log.Address.Hex(),
+	}
+
+	// Publish to RabbitMQ
+	if err := m.publish(ctx, "credit.events", eventData); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+
+	m.logger.Info("Processed credit line movement",
+		zap.String("type", eventType),
+		zap.String("tx_hash", log.TxHash.Hex()),
+		zap.String("credit_line_id", creditLineId.String()),
+		zap.String("amount", amount.String()))
+
+	return nil
+}
+
+// processFinanceEvent processes AgentFinance contract events
+// CreditLineCreatedFromRFQ(uint256 indexed rfqId, uint256 indexed creditLineId)
+// CreditLineCreatedFromAuction(uint256 indexed auctionId, uint256 indexed creditLineId)
+func (m *Monitor) processFinanceEvent(ctx context.Context, log types.Log) error {
+	if log.Address != m.financeAddress || len(log.Topics) == 0 {
+		return nil
+	}
+
+	var source string
+	switch log.Topics[0].Hex() {
+	case CreditLineCreatedFromRFQSignature:
+		source = "rfq"
+	case CreditLineCreatedFromAuctionSignature:
+		source = "auction"
+	default:
+		m.logger.Debug("Unknown AgentFinance event signature", zap.String("signature", log.Topics[0].Hex()))
+		return nil
+	}
+
+	if len(log.Topics) < 3 {
+		return fmt.Errorf("invalid AgentFinance %s event data", source)
+	}
+
+	sourceId := new(big.Int).SetBytes(log.Topics[1].Bytes())
+	creditLineId := new(big.Int).SetBytes(log.Topics[2].Bytes())
+
+	eventData := map[string]interface{}{
+		"type":             "credit_line_source",
+		"credit_line_id":   creditLineId.String(),
+		"source":           source,
+		"source_id":        sourceId.String(),
+		"tx_hash":          log.TxHash.Hex(),
+		"block_number":     log.BlockNumber,
+		"block_hash":       log.BlockHash.Hex(),
+		"log_index":        log.Index,
+		"contract_address": log.Address.Hex(),
+	}
+
+	// Publish to RabbitMQ
+	if err := m.publish(ctx, "credit.events", eventData); err != nil {
+		return fmt.Errorf("failed to publish credit line source event: %w", err)
+	}
+
+	m.logger.Info("Processed AgentFinance credit line event",
+		zap.String("tx_hash", log.TxHash.Hex()),
+		zap.String("source", source),
+		zap.String("source_id", sourceId.String()),
+		zap.String("credit_line_id", creditLineId.String()))
+
+	return nil
+}
+
+// processAquaEvent processes AquaIntegration liquidity events
+// LiquidityConnected(address indexed lender, uint256 amount)
+// LiquidityWithdrawn(address indexed lender, uint256 amount)
+// LiquidityReserved(address indexed lender, uint256 amount)
+// LiquidityReleased(address indexed lender, uint256 amount)
+func (m *Monitor) processAquaEvent(ctx context.Context, log types.Log) error {
+	if log.Address != m.aquaAddress || len(log.Topics) == 0 {
+		return nil
+	}
+
+	var eventType string
+	switch log.Topics[0].Hex() {
+	case LiquidityConnectedSignature:
+		eventType = "liquidity_connected"
+	case LiquidityWithdrawnSignature:
+		eventType = "liquidity_withdrawn"
+	case LiquidityReservedSignature:
+		eventType = "liquidity_reserved"
+	case LiquidityReleasedSignature:
+		eventType = "liquidity_released"
+	default:
+		m.logger.Debug("Unknown AquaIntegration event signature", zap.String("signature", log.Topics[0].Hex()))
+		return nil
+	}
+
+	if len(log.Topics) < 2 || len(log.Data) < 32 {
+		return fmt.Errorf("invalid %s event data", eventType)
+	}
+
+	lender := common.BytesToAddress(log.Topics[1].Bytes())
+	amount := new(big.Int).SetBytes(log.Data[0:32])
+
+	timestamp, err := m.blockTimestamp(ctx, log.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	eventData := map[string]interface{}{
+		"type":             eventType,
+		"lender":           lender.Hex(),
+		"amount":           amount.String(),
+		"timestamp":        timestamp,
+		"tx_hash":          log.TxHash.Hex(),
+		"block_number":     log.BlockNumber,
+		"block_hash":       log.BlockHash.Hex(),
+		"log_index":        log.Index,
+		"contract_address": log.Address.Hex(),
+	}
+
+	// Publish to RabbitMQ
+	if err := m.publish(ctx, "aqua.events", eventData); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+
+	m.logger.Info("Processed AquaIntegration event",
+		zap.String("type", eventType),
+		zap.String("tx_hash", log.TxHash.Hex()),
+		zap.String("lender", lender.Hex()),
+		zap.String("amount", amount.String()))
+
+	return nil
+}
+
+// blockTimestamp returns the timestamp of the given block
+func (m *Monitor) blockTimestamp(ctx context.Context, blockNumber uint64) (int64, error) {
+	header, err := m.evmClient.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block %d header: %w", blockNumber, err)
+	}
+	return int64(header.Time), nil
+}
+
+// publish sends an event to a queue, stamped with the chain it was read from
+func (m *Monitor) publish(ctx context.Context, queueName string, eventData map[string]interface{}) error {
+	eventData["chain_id"] = m.chainID
+	return m.queue.Publish(ctx, queueName, eventData)
+}
+
+// confirmed returns the highest block with the given number of confirmations at head
+func confirmed(head, confirmations uint64) uint64 {
+	if head < confirmations {
+		return 0
+	}
+	return head - confirmations
+}
@@ -41,6 +41,71 @@ var AuctionFinalizedSignature = calculateSignature("AuctionFinalized(uint256,add
 // Signature: keccak256("AuctionSettled(uint256,uint256)")
 var AuctionSettledSignature = calculateSignature("AuctionSettled(uint256,uint256)")
 
+// CreditLineOpened(uint256 indexed creditLineId, address indexed borrower, address indexed lender, uint256 limit, uint256 rateBps, uint256 expiresAt)
+// Signature: keccak256("CreditLineOpened(uint256,address,address,uint256,uint256,uint256)")
+var CreditLineOpenedSignature = calculateSignature("CreditLineOpened(uint256,address,address,uint256,uint256,uint256)")
+
+// CreditDrawn(uint256 indexed creditLineId, uint256 amount)
+// Signature: keccak256("CreditDrawn(uint256,uint256)")
+var CreditDrawnSignature = calculateSignature("CreditDrawn(uint256,uint256)")
+
+// CreditRepaid(uint256 indexed creditLineId, uint256 amount)
+// Signature: keccak256("CreditRepaid(uint256,uint256)")
+var CreditRepaidSignature = calculateSignature("CreditRepaid(uint256,uint256)")
+
+// CreditLineCreatedFromRFQ(uint256 indexed rfqId, uint256 indexed creditLineId)
+// Signature: keccak256("CreditLineCreatedFromRFQ(uint256,uint256)")
+var CreditLineCreatedFromRFQSignature = calculateSignature("CreditLineCreatedFromRFQ(uint256,uint256)")
+
+// CreditLineCreatedFromAuction(uint256 indexed auctionId, uint256 indexed creditLineId)
+// Signature: keccak256("CreditLineCreatedFromAuction(uint256,uint256)")
+var CreditLineCreatedFromAuctionSignature = calculateSignature("CreditLineCreatedFromAuction(uint256,uint256)")
+
+// LiquidityConnected(address indexed lender, uint256 amount)
+// Signature: keccak256("LiquidityConnected(address,uint256)")
+var LiquidityConnectedSignature = calculateSignature("LiquidityConnected(address,uint256)")
+
+// LiquidityWithdrawn(address indexed lender, uint256 amount)
+// Signature: keccak256("LiquidityWithdrawn(address,uint256)")
+var LiquidityWithdrawnSignature = calculateSignature("LiquidityWithdrawn(address,uint256)")
+
+// LiquidityReserved(address indexed lender, uint256 amount)
+// Signature: keccak256("LiquidityReserved(address,uint256)")
+var LiquidityReservedSignature = calculateSignature("LiquidityReserved(address,uint256)")
+
+// LiquidityReleased(address indexed lender, uint256 amount)
+// Signature: keccak256("LiquidityReleased(address,uint256)")
+var LiquidityReleasedSignature = calculateSignature("LiquidityReleased(address,uint256)")
+
+// eventNames maps the signatures to the event names used as metric labels
+var eventNames = map[string]string{
+	RFQCreatedSignature:                   "RFQCreated",
+	QuoteSubmittedSignature:               "QuoteSubmitted",
+	QuoteAcceptedSignature:                "QuoteAccepted",
+	RFQExecutedSignature:                  "RFQExecuted",
+	AuctionCreatedSignature:               "AuctionCreated",
+	BidPlacedSignature:                    "BidPlaced",
+	AuctionFinalizedSignature:             "AuctionFinalized",
+	AuctionSettledSignature:               "AuctionSettled",
+	CreditLineOpenedSignature:             "CreditLineOpened",
+	CreditDrawnSignature:                  "CreditDrawn",
+	CreditRepaidSignature:                 "CreditRepaid",
+	CreditLineCreatedFromRFQSignature:     "CreditLineCreatedFromRFQ",
+	CreditLineCreatedFromAuctionSignature: "CreditLineCreatedFromAuction",
+	LiquidityConnectedSignature:           "LiquidityConnected",
+	LiquidityWithdrawnSignature:           "LiquidityWithdrawn",
+	LiquidityReservedSignature:            "LiquidityReserved",
+	LiquidityReleasedSignature:            "LiquidityReleased",
+}
+
+// eventName returns the name of the event with the given signature, "unknown" for other events
+func eventName(signature string) string {
+	if name, ok := eventNames[signature]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 // calculateSignature calculates keccak256 hash of event signature
 func calculateSignature(signature string) string {
 	hash := sha3.NewLegacyKeccak256()
@@ -49,4 +114,3 @@ func calculateSignature(signature string) string {
 	// Take first 32 bytes (event signature is 32 bytes)
 	return "0x" + hex.EncodeToString(hashBytes[:32])
 }
-
@@ -0,0 +1,123 @@
+package faucet
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+)
+
+// Verifier checks the challenge token a client sends with a faucet request, proving it is not a script
+// draining the faucet. It returns a forbidden error for a missing or wrong token.
+type Verifier interface {
+	Verify(ctx context.Context, address, ip, token string) error
+}
+
+// NewVerifier returns the verifier selected by cfg.Challenge, nil if requests are not challenged
+func NewVerifier(cfg Config) Verifier {
+	switch cfg.Challenge {
+	case ChallengeProofOfWork:
+		return ProofOfWork{Difficulty: cfg.PoWDifficulty}
+	case ChallengeCaptcha:
+		return &Captcha{
+			VerifyURL: cfg.CaptchaVerifyURL,
+			Secret:    cfg.CaptchaSecret,
+			Client:    &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	return nil
+}
+
+// ProofOfWork accepts a token (a decimal nonce) when sha256("<lowercase address>:<token>") starts with
+// Difficulty zero bits. Each extra bit doubles the work, so 20 bits takes about a million hashes.
+type ProofOfWork struct {
+	Difficulty uint
+}
+
+func (p ProofOfWork) Verify(ctx context.Context, address, ip, token string) error {
+	if token == "" {
+		return apperrors.Forbidden("a proof-of-work challenge of %d bits is required", p.Difficulty)
+	}
+	if leadingZeroBits(powHash(address, token)) < p.Difficulty {
+		return apperrors.Forbidden("proof-of-work challenge does not meet %d bits", p.Difficulty)
+	}
+	return nil
+}
+
+// SolveProofOfWork finds a token for address that meets difficulty
+func SolveProofOfWork(address string, difficulty uint) string {
+	for nonce := uint64(0); ; nonce++ {
+		token := strconv.FormatUint(nonce, 10)
+		if leadingZeroBits(powHash(address, token)) >= difficulty {
+			return token
+		}
+	}
+}
+
+func powHash(address, token string) [32]byte {
+	return sha256.Sum256([]byte(strings.ToLower(address) + ":" + token))
+}
+
+func leadingZeroBits(h [32]byte) uint {
+	var n uint
+	for i := 0; i < len(h); i += 8 {
+		word := binary.BigEndian.Uint64(h[i : i+8])
+		n += uint(bits.LeadingZeros64(word))
+		if word != 0 {
+			break
+		}
+	}
+	return n
+}
+
+// Captcha checks tokens against an hCaptcha or reCAPTCHA style siteverify endpoint
+type Captcha struct {
+	VerifyURL string
+	Secret    string
+	Client    *http.Client
+}
+
+func (c *Captcha) Verify(ctx context.Context, address, ip, token string) error {
+	if token == "" {
+		return apperrors.Forbidden("a captcha token is required")
+	}
+
+	form := url.Values{"secret": {c.Secret}, "response": {token}}
+	if ip != "" {
+		form.Set("remoteip", ip)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.VerifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build captcha request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return apperrors.Unavailable(err, "failed to verify captcha")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.Unavailable(fmt.Errorf("status %d", resp.StatusCode), "failed to verify captcha")
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return apperrors.Unavailable(err, "failed to verify captcha")
+	}
+	if !result.Success {
+		return apperrors.Forbidden("captcha verification failed")
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package faucet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+)
+
+// Challenge kinds accepted by FAUCET_CHALLENGE
+const (
+	ChallengeNone        = "none"
+	ChallengeProofOfWork = "pow"
+	ChallengeCaptcha     = "captcha"
+)
+
+// Config limits what the faucet gives away. Amounts are in wei.
+type Config struct {
+	// MaxAmount is the most a single request may ask for
+	MaxAmount *big.Int
+	// AddressCooldown and IPCooldown are the waits between two grants to the same address or client IP
+	AddressCooldown time.Duration
+	IPCooldown      time.Duration
+	// DailyBudget caps the total given away per UTC day, zero for no cap
+	DailyBudget *big.Int
+	// MinBalance is the ETH balance the faucet keeps after paying a grant and its gas
+	MinBalance *big.Int
+	// Tokens are the ERC20 tokens dispensed besides ETH, each with its own amounts and the cooldowns above
+	Tokens []TokenConfig
+
+	Challenge        string
+	PoWDifficulty    uint
+	CaptchaVerifyURL string
+	CaptchaSecret    string
+
+	// Signer is FAUCET_SIGNER, see evm.SignerConfigFromEnv, and Tx how its transactions are tracked
+	Signer evm.SignerConfig
+	Tx     evm.TxManagerConfig
+	// Submit stores grants for the worker to send instead of sending them in process. It is set when the
+	// transactions are shared through ClickHouse, so API replicas never send from the faucet's key.
+	Submit bool
+}
+
+// ConfigFromEnv reads the faucet limits:
+// FAUCET_MAX_AMOUNT (ETH, default 1), FAUCET_ADDRESS_COOLDOWN (default 24h), FAUCET_IP_COOLDOWN (default 1h),
+// FAUCET_DAILY_BUDGET (ETH, default 100, 0 disables), FAUCET_MIN_BALANCE (ETH, default 10),
+// FAUCET_CHALLENGE (none, pow or captcha), FAUCET_POW_DIFFICULTY (leading zero bits, default 20),
+// FAUCET_CAPTCHA_VERIFY_URL and FAUCET_CAPTCHA_SECRET, and FAUCET_TOKENS (see ParseTokens), along with
+// the faucet signer and the TX_ transaction settings.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		AddressCooldown:  24 * time.Hour,
+		IPCooldown:       time.Hour,
+		Challenge:        ChallengeNone,
+		PoWDifficulty:    20,
+		CaptchaVerifyURL: "https://api.hcaptcha.com/siteverify",
+	}
+
+	amounts := []struct {
+		env string
+		def string
+		dst **big.Int
+	}{
+		{"FAUCET_MAX_AMOUNT", "1", &cfg.MaxAmount},
+		{"FAUCET_DAILY_BUDGET", "100", &cfg.DailyBudget},
+		{"FAUCET_MIN_BALANCE", "10", &cfg.MinBalance},
+	}
+	for _, a := range amounts {
+		s := os.Getenv(a.env)
+		if s == "" {
+			s = a.def
+		}
+		v, err := validation.ParseAmount(s, validation.EtherDecimals)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid %s %q: %w", a.env, s, err)
+		}
+		*a.dst = v
+	}
+	if cfg.MaxAmount.Sign() == 0 {
+		return cfg, fmt.Errorf("FAUCET_MAX_AMOUNT must be positive")
+	}
+
+	tokens, err := ParseTokens(os.Getenv("FAUCET_TOKENS"))
+	if err != nil {
+		return cfg, fmt.Errorf("invalid FAUCET_TOKENS: %w", err)
+	}
+	cfg.Tokens = tokens
+
+	durations := []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"FAUCET_ADDRESS_COOLDOWN", &cfg.AddressCooldown},
+		{"FAUCET_IP_COOLDOWN", &cfg.IPCooldown},
+	}
+	for _, d := range durations {
+		if s := os.Getenv(d.env); s != "" {
+			v, err := time.ParseDuration(s)
+			if err != nil || v < 0 {
+				return cfg, fmt.Errorf("invalid %s %q", d.env, s)
+			}
+			*d.dst = v
+		}
+	}
+
+	if v := os.Getenv("FAUCET_CHALLENGE"); v != "" {
+		cfg.Challenge = v
+	}
+	switch cfg.Challenge {
+	case ChallengeNone:
+	case ChallengeProofOfWork:
+		if s := os.Getenv("FAUCET_POW_DIFFICULTY"); s != "" {
+			v, err := strconv.ParseUint(s, 10, 8)
+			if err != nil || v == 0 || v > 64 {
+				return cfg, fmt.Errorf("invalid FAUCET_POW_DIFFICULTY %q, expected 1 to 64", s)
+			}
+			cfg.PoWDifficulty = uint(v)
+		}
+	case ChallengeCaptcha:
+		if v := os.Getenv("FAUCET_CAPTCHA_VERIFY_URL"); v != "" {
+			cfg.CaptchaVerifyURL = v
+		}
+		cfg.CaptchaSecret = os.Getenv("FAUCET_CAPTCHA_SECRET")
+		if cfg.CaptchaSecret == "" {
+			return cfg, fmt.Errorf("FAUCET_CAPTCHA_SECRET is required when FAUCET_CHALLENGE is captcha")
+		}
+	default:
+		return cfg, fmt.Errorf("invalid FAUCET_CHALLENGE %q, expected none, pow or captcha", cfg.Challenge)
+	}
+
+	if cfg.Signer, err = evm.SignerConfigFromEnv("FAUCET"); err != nil {
+		return cfg, err
+	}
+	if cfg.Tx, err = evm.TxManagerConfigFromEnv(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Limiter enforces the per-address and per-IP cooldowns and the daily budget of one asset, against the
+// grants recorded in the store. Reserve checks and records a grant in two steps, so the service holds the
+// faucet's claim around it, see Service.lock.
+type Limiter struct {
+	cfg     Config
+	store   Store
+	chainID uint64
+	token   string
+}
+
+// NewLimiter creates the limiter of token on a chain, with the grants of store
+func NewLimiter(cfg Config, store Store, chainID uint64, token string) *Limiter {
+	return &Limiter{cfg: cfg, store: store, chainID: chainID, token: token}
+}
+
+// Reserve records a grant of amount to address from ip at now, or returns a rate limited error saying
+// how long to wait. The grant counts against the limits until it is released, see Sent and Release.
+func (l *Limiter) Reserve(ctx context.Context, address, ip string, amount *big.Int, now time.Time) (*repositories.FaucetGrantModel, error) {
+	last, err := l.store.LastGrantTo(ctx, l.chainID, l.token, address)
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "failed to read faucet grants")
+	}
+	if wait := l.wait(last, l.cfg.AddressCooldown, now); wait > 0 {
+		return nil, apperrors.RateLimited(wait, "address %s already received tokens, retry in %s", address, wait.Round(time.Second))
+	}
+	if ip != "" {
+		last, err := l.store.LastGrantFrom(ctx, l.chainID, l.token, ip)
+		if err != nil {
+			return nil, apperrors.Unavailable(err, "failed to read faucet grants")
+		}
+		if wait := l.wait(last, l.cfg.IPCooldown, now); wait > 0 {
+			return nil, apperrors.RateLimited(wait, "too many faucet requests from this IP, retry in %s", wait.Round(time.Second))
+		}
+	}
+	if l.cfg.DailyBudget.Sign() > 0 {
+		midnight := now.UTC().Truncate(24 * time.Hour)
+		spent, err := l.store.GrantedSince(ctx, l.chainID, l.token, midnight.Unix())
+		if err != nil {
+			return nil, apperrors.Unavailable(err, "failed to read faucet grants")
+		}
+		if spent.Add(spent, amount).Cmp(l.cfg.DailyBudget) > 0 {
+			return nil, apperrors.RateLimited(midnight.Add(24*time.Hour).Sub(now), "faucet daily budget is exhausted")
+		}
+	}
+
+	grant := &repositories.FaucetGrantModel{
+		ChainID:   l.chainID,
+		ID:        newGrantID(),
+		Token:     l.token,
+		Address:   address,
+		IP:        ip,
+		Amount:    amount,
+		Status:    repositories.FaucetGrantReserved,
+		CreatedAt: now.Unix(),
+	}
+	if err := l.store.SaveGrant(ctx, grant); err != nil {
+		return nil, apperrors.Unavailable(err, "failed to save faucet grant")
+	}
+	return grant, nil
+}
+
+// Sent records the transaction paying a reserved grant
+func (l *Limiter) Sent(ctx context.Context, grant *repositories.FaucetGrantModel, txID string) error {
+	grant.Status, grant.TxID = repositories.FaucetGrantSent, txID
+	return l.store.SaveGrant(ctx, grant)
+}
+
+// Release gives up a reserved grant that was not paid out, it no longer counts against the limits
+func (l *Limiter) Release(ctx context.Context, grant *repositories.FaucetGrantModel) error {
+	grant.Status = repositories.FaucetGrantReleased
+	return l.store.SaveGrant(ctx, grant)
+}
+
+// wait is how long remains of a cooldown started by a grant at last, a Unix time or 0 for none
+func (l *Limiter) wait(last int64, cooldown time.Duration, now time.Time) time.Duration {
+	if last == 0 {
+		return 0
+	}
+	return time.Unix(last, 0).Add(cooldown).Sub(now)
+}
+
+func newGrantID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
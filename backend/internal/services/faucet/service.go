@@ -2,118 +2,505 @@ package faucet
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
-	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/metrics"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
 	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"go.uber.org/zap"
 )
 
 type Service struct {
-	evmClient  *evm.Client
-	privateKey *ecdsa.PrivateKey
-	chainID    *big.Int
-	logger     *zap.Logger
+	evmClient *evm.Client
+	chainID   uint64
+	erc20     *erc20
+	txm       *evm.TxManager
+	store     Store
+	config    Config
+	verifier  Verifier
+	logger    *zap.Logger
+
+	// assets are the native asset and the tokens whose decimals have been read, by upper case symbol
+	mu     sync.Mutex
+	assets map[string]*asset
 }
 
-func NewService(evmClient *evm.Client, logger *zap.Logger) (*Service, error) {
-	// Get private key from environment variable
-	privateKeyHex := os.Getenv("FAUCET_PRIVATE_KEY")
-	if privateKeyHex == "" {
-		// Default Hardhat account #0 private key for testing
-		// This is the first account from Hardhat's default accounts
-		privateKeyHex = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
-		logger.Warn("FAUCET_PRIVATE_KEY not set, using default Hardhat account #0")
+// NewService creates the faucet of a chain, evmClient must be connected to it. Its transactions are
+// kept in txStore, see Run and Config.Submit, and its grants in store.
+func NewService(evmClient *evm.Client, chainID uint64, txStore evm.TxStore, store Store, config Config, logger *zap.Logger) (*Service, error) {
+	signer, err := NewSigner(config, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	privateKey, err := crypto.HexToECDSA(privateKeyHex[2:]) // Remove 0x prefix
+	tokens, err := newERC20(evmClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
+		return nil, err
 	}
 
-	// Get chain ID from environment or use default (1337 for Hardhat)
-	chainIDStr := os.Getenv("FAUCET_CHAIN_ID")
-	chainID := big.NewInt(1337) // Default Hardhat chain ID
-	if chainIDStr != "" {
-		chainID, _ = chainID.SetString(chainIDStr, 10)
+	txConfig := config.Tx
+	txConfig.ChainID = new(big.Int).SetUint64(chainID)
+
+	native := &asset{
+		symbol:      NativeSymbol,
+		mode:        ModeNative,
+		decimals:    validation.EtherDecimals,
+		maxAmount:   config.MaxAmount,
+		dailyBudget: config.DailyBudget,
+		limiter:     NewLimiter(config, store, chainID, NativeSymbol),
 	}
 
 	return &Service{
-		evmClient:  evmClient,
-		privateKey: privateKey,
-		chainID:    chainID,
-		logger:     logger,
+		evmClient: evmClient,
+		chainID:   chainID,
+		erc20:     tokens,
+		txm:       evm.NewTxManager(evmClient, signer, txStore, txConfig, logger),
+		store:     store,
+		config:    config,
+		verifier:  NewVerifier(config),
+		logger:    logger,
+		assets:    map[string]*asset{NativeSymbol: native},
 	}, nil
 }
 
+// NewSigner creates the faucet's signer from FAUCET_SIGNER, a key, keystore or remote signer, see
+// evm.SignerConfigFromEnv. Outside production it falls back to Hardhat account #0.
+func NewSigner(config Config, logger *zap.Logger) (evm.Signer, error) {
+	signerConfig := config.Signer
+	if signerConfig.Kind == "" {
+		if signerConfig.Production {
+			return nil, errors.New("no faucet signer configured, set FAUCET_SIGNER")
+		}
+		// Hardhat account #0 is funded on the local node
+		signerConfig.Kind, signerConfig.PrivateKey = evm.SignerKey, evm.DevPrivateKey
+		logger.Warn("No faucet signer configured, using default Hardhat account #0")
+	}
+	signer, err := evm.NewSigner(context.Background(), signerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create faucet signer: %w", err)
+	}
+	return signer, nil
+}
+
+// ChainID returns the chain the faucet dispenses on
+func (s *Service) ChainID() uint64 {
+	return s.chainID
+}
+
+// Run sends and tracks the faucet's transactions, resending stuck ones, until ctx is done. Only one
+// process may run it for the faucet's key; with Config.Submit set the worker does and Run must not be called.
+func (s *Service) Run(ctx context.Context) error {
+	return s.txm.Run(ctx)
+}
+
 type RequestTokensRequest struct {
 	Address string `json:"address"`
-	Amount  string `json:"amount"` // Amount in ETH (will be converted to Wei)
+	// Token is the symbol or address of a token listed by GET /faucet/tokens, empty for ETH
+	Token string `json:"token,omitempty"`
+	// Amount is in token units, e.g. "1.5" ETH. It defaults to the token's max amount.
+	Amount string `json:"amount"`
+	// Challenge is the proof-of-work nonce or captcha token, required when the faucet is challenged
+	Challenge string `json:"challenge,omitempty"`
+	// IP is the client address the per-IP cooldown applies to, set by the handler
+	IP string `json:"-"`
+}
+
+// Grant is a faucet transaction that was sent or submitted. TxID follows it through GET /transactions/:id.
+type Grant struct {
+	ChainID uint64
+	TxID    string
+	// TxHash is empty for a submitted grant until the worker sends it
+	TxHash string
+	Token  string
+	Amount string
 }
 
-func (s *Service) RequestTokens(ctx context.Context, req RequestTokensRequest) (string, error) {
-	// Validate address
-	toAddress := common.HexToAddress(req.Address)
-	if toAddress == (common.Address{}) {
-		return "", fmt.Errorf("invalid address: %s", req.Address)
+// TokenInfo describes a dispensable asset and its limits. Amounts are in token units.
+type TokenInfo struct {
+	Symbol string `json:"symbol"`
+	// Address is the token contract, empty for ETH
+	Address     string `json:"address,omitempty"`
+	Mode        string `json:"mode"`
+	Decimals    uint8  `json:"decimals"`
+	MaxAmount   string `json:"max_amount"`
+	DailyBudget string `json:"daily_budget,omitempty"`
+	// Cooldowns are in seconds
+	AddressCooldown int64 `json:"address_cooldown"`
+	IPCooldown      int64 `json:"ip_cooldown"`
+}
+
+// ListTokens returns ETH and the configured tokens with their limits
+func (s *Service) ListTokens(ctx context.Context) ([]TokenInfo, error) {
+	symbols := []string{NativeSymbol}
+	for _, token := range s.config.Tokens {
+		symbols = append(symbols, token.Symbol)
 	}
 
-	// Parse amount (in ETH) and convert to Wei
-	amount, ok := new(big.Float).SetString(req.Amount)
-	if !ok {
-		return "", fmt.Errorf("invalid amount: %s", req.Amount)
+	tokens := make([]TokenInfo, 0, len(symbols))
+	for _, symbol := range symbols {
+		a, err := s.asset(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		info := TokenInfo{
+			Symbol:          a.symbol,
+			Mode:            a.mode,
+			Decimals:        a.decimals,
+			MaxAmount:       formatUnits(a.maxAmount, a.decimals),
+			AddressCooldown: int64(s.config.AddressCooldown.Seconds()),
+			IPCooldown:      int64(s.config.IPCooldown.Seconds()),
+		}
+		if a.token != nil {
+			info.Address = a.token.Hex()
+		}
+		if a.dailyBudget.Sign() > 0 {
+			info.DailyBudget = formatUnits(a.dailyBudget, a.decimals)
+		}
+		tokens = append(tokens, info)
 	}
-	// Convert ETH to Wei
-	weiPerEth := big.NewFloat(1e18)
-	amountWei, _ := new(big.Float).Mul(amount, weiPerEth).Int(nil)
+	return tokens, nil
+}
 
-	// Get sender address from private key
-	fromAddress := crypto.PubkeyToAddress(s.privateKey.PublicKey)
+// Status is the faucet's signer account, amounts in token units
+type Status struct {
+	ChainID uint64
+	Address string
+	// Nonce counts the mined transactions of the signer, PendingNonce those the node also has pending
+	Nonce        uint64
+	PendingNonce uint64
+	// MinBalance is the ETH balance the faucet keeps, Low is set when ETH is below it
+	MinBalance string
+	Low        bool
+	Balances   []Balance
+}
+
+// Balance is the faucet's balance of an asset
+type Balance struct {
+	Symbol string
+	// Address is the token contract, empty for ETH
+	Address string
+	Balance string
+}
 
-	// Get nonce
-	nonce, err := s.evmClient.PendingNonceAt(ctx, fromAddress)
+// Status reads the balances and nonces of the faucet's signer
+func (s *Service) Status(ctx context.Context) (*Status, error) {
+	from := s.txm.From()
+	nonce, err := s.evmClient.NonceAt(ctx, from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nonce: %w", err)
+	}
+	pending, err := s.evmClient.PendingNonceAt(ctx, from)
 	if err != nil {
-		return "", fmt.Errorf("failed to get nonce: %w", err)
+		return nil, fmt.Errorf("failed to read pending nonce: %w", err)
 	}
+	ethBalance, err := s.evmClient.GetBalance(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ETH balance: %w", err)
+	}
+
+	status := &Status{
+		ChainID:      s.chainID,
+		Address:      from.Hex(),
+		Nonce:        nonce,
+		PendingNonce: pending,
+		MinBalance:   formatUnits(s.config.MinBalance, validation.EtherDecimals),
+		Low:          ethBalance.Cmp(s.config.MinBalance) < 0,
+		Balances: []Balance{{
+			Symbol:  NativeSymbol,
+			Balance: formatUnits(ethBalance, validation.EtherDecimals),
+		}},
+	}
+	for _, token := range s.config.Tokens {
+		a, err := s.asset(ctx, token.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := s.erc20.balanceOf(ctx, token.Address, from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s balance: %w", token.Symbol, err)
+		}
+		status.Balances = append(status.Balances, Balance{
+			Symbol:  token.Symbol,
+			Address: token.Address.Hex(),
+			Balance: formatUnits(balance, a.decimals),
+		})
+	}
+	return status, nil
+}
 
-	// Get gas price
-	gasPrice, err := s.evmClient.SuggestGasPrice(ctx)
+// asset returns the asset named by a symbol or token address, reading a token's decimals on first use
+func (s *Service) asset(ctx context.Context, name string) (*asset, error) {
+	var token *TokenConfig
+	for i := range s.config.Tokens {
+		t := &s.config.Tokens[i]
+		if strings.EqualFold(t.Symbol, name) || strings.EqualFold(t.Address.Hex(), name) {
+			token = t
+			break
+		}
+	}
+	symbol := strings.ToUpper(name)
+	if token != nil {
+		symbol = token.Symbol
+	}
+
+	s.mu.Lock()
+	a, ok := s.assets[symbol]
+	s.mu.Unlock()
+	if ok {
+		return a, nil
+	}
+	if token == nil {
+		return nil, apperrors.Invalid("request validation failed", apperrors.FieldError{
+			Field:   "token",
+			Message: fmt.Sprintf("%s is not dispensed by this faucet, see GET /faucet/tokens", name),
+		})
+	}
+
+	decimals, err := s.erc20.decimals(ctx, token.Address)
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "failed to read %s decimals", token.Symbol)
+	}
+	maxAmount, err := validation.ParseAmount(token.MaxAmount, decimals)
 	if err != nil {
-		return "", fmt.Errorf("failed to get gas price: %w", err)
+		return nil, fmt.Errorf("invalid %s max amount %q: %w", token.Symbol, token.MaxAmount, err)
 	}
+	dailyBudget := new(big.Int)
+	if token.DailyBudget != "" {
+		if dailyBudget, err = validation.ParseAmount(token.DailyBudget, decimals); err != nil {
+			return nil, fmt.Errorf("invalid %s daily budget %q: %w", token.Symbol, token.DailyBudget, err)
+		}
+	}
+	limits := s.config
+	limits.DailyBudget = dailyBudget
 
-	// Create transaction
-	tx := types.NewTransaction(
-		nonce,
-		toAddress,
-		amountWei,
-		21000, // Standard ETH transfer gas limit
-		gasPrice,
-		nil,
-	)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Another request may have resolved the token meanwhile, keep its limiter
+	if a, ok := s.assets[symbol]; ok {
+		return a, nil
+	}
+	address := token.Address
+	a = &asset{
+		symbol:      token.Symbol,
+		token:       &address,
+		mode:        token.Mode,
+		decimals:    decimals,
+		maxAmount:   maxAmount,
+		dailyBudget: dailyBudget,
+		limiter:     NewLimiter(limits, s.store, s.chainID, token.Symbol),
+	}
+	s.assets[symbol] = a
+	return a, nil
+}
+
+func (s *Service) RequestTokens(ctx context.Context, req RequestTokensRequest) (*Grant, error) {
+	var v validation.Validator
+	toAddress := v.Address("address", req.Address)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
 
-	// Sign transaction
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(s.chainID), s.privateKey)
+	token := req.Token
+	if token == "" {
+		token = NativeSymbol
+	}
+	a, err := s.asset(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the amount in the asset's units, defaulting to the most a request may ask for
+	amount := a.maxAmount
+	if req.Amount != "" {
+		amount = v.Amount("amount", req.Amount, a.decimals)
+		if amount != nil && amount.Cmp(a.maxAmount) > 0 {
+			v.Fail("amount", "must be at most %s %s", formatUnits(a.maxAmount, a.decimals), a.symbol)
+		}
+		if err := v.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.verifier != nil {
+		if err := s.verifier.Verify(ctx, toAddress.Hex(), req.IP, req.Challenge); err != nil {
+			return nil, err
+		}
+	}
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Hold the grant against the cooldowns and daily budget, and give it back if nothing is sent
+	reserved, err := a.limiter.Reserve(ctx, toAddress.Hex(), req.IP, amount, time.Now())
 	if err != nil {
-		return "", fmt.Errorf("failed to sign transaction: %w", err)
+		return nil, err
 	}
+	sent := false
+	defer func() {
+		if sent {
+			return
+		}
+		if err := a.limiter.Release(context.WithoutCancel(ctx), reserved); err != nil {
+			s.logger.Error("Failed to release faucet grant", zap.String("grant_id", reserved.ID), zap.Error(err))
+		}
+	}()
 
-	// Send transaction
-	if err := s.evmClient.SendTransaction(ctx, signedTx); err != nil {
-		return "", fmt.Errorf("failed to send transaction: %w", err)
+	fromAddress := s.txm.From()
+
+	// Build the call: a plain transfer for ETH, mint or transfer for a token
+	to, value, data := toAddress, amount, []byte(nil)
+	if a.token != nil {
+		if a.mode == ModeTransfer {
+			balance, err := s.erc20.balanceOf(ctx, *a.token, fromAddress)
+			if err != nil {
+				return nil, apperrors.Unavailable(err, "failed to get faucet %s balance", a.symbol)
+			}
+			if balance.Cmp(amount) < 0 {
+				return nil, apperrors.Forbidden("faucet is out of %s, try a smaller amount or come back later", a.symbol)
+			}
+		}
+		if data, err = s.erc20.abi.Pack(a.mode, toAddress, amount); err != nil {
+			return nil, fmt.Errorf("failed to pack %s: %w", a.mode, err)
+		}
+		to, value = *a.token, new(big.Int)
+	}
+
+	// Price the transaction, the most it may pay per gas counts against the balance floor
+	fees, err := s.evmClient.SuggestFees(ctx, "")
+	if errors.Is(err, evm.ErrFeeAboveMax) {
+		return nil, apperrors.Unavailable(err, "network fees are too high, come back later")
+	}
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "failed to get fees")
 	}
 
-	txHash := signedTx.Hash().Hex()
+	// Estimate gas, a recipient contract or a token call costs more than a plain transfer
+	gasLimit, err := s.evmClient.EstimateGas(ctx, ethereum.CallMsg{From: fromAddress, To: &to, Value: value, Data: data})
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "failed to estimate gas")
+	}
+
+	// Keep the faucet above its ETH balance floor after paying the grant and the gas, and the transactions
+	// of earlier grants that are not mined yet, which the balance does not reflect
+	balance, err := s.evmClient.GetBalance(ctx, fromAddress)
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "failed to get faucet balance")
+	}
+	outstanding, err := s.txm.Outstanding(ctx)
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "failed to read faucet transactions")
+	}
+	cost := txCost(fees, value, gasLimit)
+	for _, tx := range outstanding {
+		cost.Add(cost, txCost(fees, tx.Value, tx.GasLimit))
+	}
+	if new(big.Int).Sub(balance, cost).Cmp(s.config.MinBalance) < 0 {
+		s.logger.Warn("Faucet balance floor reached",
+			zap.String("balance", balance.String()),
+			zap.Int("outstanding", len(outstanding)),
+			zap.String("min_balance", s.config.MinBalance.String()),
+		)
+		return nil, apperrors.Forbidden("faucet is running low on funds, try a smaller amount or come back later")
+	}
+
+	// The transaction manager allocates the nonce and resends the grant if it gets stuck. A submitted
+	// grant is sent by the worker running it.
+	var tx *evm.TxRecord
+	request := evm.TxRequest{To: to, Value: value, Data: data, GasLimit: gasLimit}
+	if s.config.Submit {
+		tx, err = s.txm.Submit(ctx, request)
+	} else {
+		tx, err = s.txm.Send(ctx, request)
+	}
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "failed to send transaction")
+	}
+	sent = true
+	if err := a.limiter.Sent(context.WithoutCancel(ctx), reserved, tx.ID); err != nil {
+		// The grant stays reserved and keeps counting against the limits
+		s.logger.Error("Failed to record faucet grant", zap.String("grant_id", reserved.ID), zap.Error(err))
+	}
+
+	grant := &Grant{
+		ChainID: s.chainID,
+		TxID:    tx.ID,
+		Token:   a.symbol,
+		Amount:  formatUnits(amount, a.decimals),
+	}
+	if tx.Hash != (common.Hash{}) {
+		grant.TxHash = tx.Hash.Hex()
+	}
 	s.logger.Info("Faucet transaction sent",
 		zap.String("to", req.Address),
-		zap.String("amount", req.Amount),
-		zap.String("tx_hash", txHash),
+		zap.String("ip", req.IP),
+		zap.String("token", grant.Token),
+		zap.String("amount", grant.Amount),
+		zap.String("tx_id", grant.TxID),
+		zap.String("tx_hash", grant.TxHash),
 	)
+	if units, err := strconv.ParseFloat(grant.Amount, 64); err == nil {
+		metrics.FaucetDispensed.WithLabelValues(metrics.Chain(s.chainID), grant.Token).Add(units)
+	}
+	metrics.FaucetGrants.WithLabelValues(metrics.Chain(s.chainID), grant.Token).Inc()
+
+	return grant, nil
+}
+
+// Requests for a chain are serialized across replicas by a claim on the faucet, so the limits read by
+// one request include every earlier grant and its transaction counts against the balance floor
+const (
+	lockTTL  = 30 * time.Second
+	lockWait = 5 * time.Second
+	lockPoll = 100 * time.Millisecond
+)
 
-	return txHash, nil
+// lock claims the faucet of the chain, waiting up to lockWait for another request to finish
+func (s *Service) lock(ctx context.Context) (unlock func(), err error) {
+	claim := &repositories.ClaimModel{Key: fmt.Sprintf("faucet:%d", s.chainID), Owner: newGrantID()}
+	deadline := time.Now().Add(lockWait)
+	for {
+		claim.ExpiresAt = time.Now().Add(lockTTL).Unix()
+		held, err := s.store.Claim(ctx, claim)
+		if err != nil {
+			return nil, apperrors.Unavailable(err, "failed to lock the faucet")
+		}
+		if held == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, apperrors.RateLimited(time.Second, "faucet is busy, retry in a second")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPoll):
+		}
+	}
+	return func() {
+		if err := s.store.Release(context.WithoutCancel(ctx), claim.Key, claim.Owner); err != nil {
+			s.logger.Warn("Failed to unlock the faucet, it frees up when the claim expires", zap.Error(err))
+		}
+	}, nil
+}
+
+// txCost is the most a transaction may take from the balance at the current fees
+func txCost(fees *evm.Fees, value *big.Int, gasLimit uint64) *big.Int {
+	cost := new(big.Int).Mul(fees.MaxPrice(), new(big.Int).SetUint64(gasLimit))
+	if value != nil {
+		cost.Add(cost, value)
+	}
+	return cost
 }
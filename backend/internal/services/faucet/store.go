@@ -0,0 +1,113 @@
+package faucet
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+)
+
+// Store keeps the faucet's grants and the claim serializing its requests. API replicas must share it,
+// or each applies the limits on its own.
+type Store interface {
+	// Claim takes claim.Key for claim.Owner, or returns the unexpired claim another owner holds on it
+	Claim(ctx context.Context, claim *repositories.ClaimModel) (*repositories.ClaimModel, error)
+	// Release drops the claim of owner on key, if it still holds it
+	Release(ctx context.Context, key, owner string) error
+
+	SaveGrant(ctx context.Context, g *repositories.FaucetGrantModel) error
+	// LastGrantTo and LastGrantFrom return when an address was last granted a token, or a grant was last
+	// requested from an IP, 0 if never. Released grants are left out.
+	LastGrantTo(ctx context.Context, chainID uint64, token, address string) (int64, error)
+	LastGrantFrom(ctx context.Context, chainID uint64, token, ip string) (int64, error)
+	// GrantedSince returns the total amount of a token granted from since on, released grants left out
+	GrantedSince(ctx context.Context, chainID uint64, token string, since int64) (*big.Int, error)
+}
+
+// MemoryStore keeps grants in process, for tests and single replica deployments
+type MemoryStore struct {
+	mu     sync.Mutex
+	claims map[string]*repositories.ClaimModel
+	grants map[string]*repositories.FaucetGrantModel
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		claims: make(map[string]*repositories.ClaimModel),
+		grants: make(map[string]*repositories.FaucetGrantModel),
+	}
+}
+
+func (s *MemoryStore) Claim(ctx context.Context, claim *repositories.ClaimModel) (*repositories.ClaimModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if held, ok := s.claims[claim.Key]; ok && held.ExpiresAt > time.Now().Unix() {
+		copied := *held
+		return &copied, nil
+	}
+	copied := *claim
+	s.claims[claim.Key] = &copied
+	return nil, nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if held, ok := s.claims[key]; ok && held.Owner == owner {
+		delete(s.claims, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) SaveGrant(ctx context.Context, g *repositories.FaucetGrantModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *g
+	copied.Amount = new(big.Int).Set(g.Amount)
+	s.grants[g.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) LastGrantTo(ctx context.Context, chainID uint64, token, address string) (int64, error) {
+	return s.lastGrant(chainID, token, func(g *repositories.FaucetGrantModel) bool {
+		return strings.EqualFold(g.Address, address)
+	}), nil
+}
+
+func (s *MemoryStore) LastGrantFrom(ctx context.Context, chainID uint64, token, ip string) (int64, error) {
+	return s.lastGrant(chainID, token, func(g *repositories.FaucetGrantModel) bool {
+		return g.IP == ip
+	}), nil
+}
+
+func (s *MemoryStore) lastGrant(chainID uint64, token string, match func(*repositories.FaucetGrantModel) bool) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var last int64
+	for _, g := range s.grants {
+		if s.counts(g, chainID, token) && match(g) && g.CreatedAt > last {
+			last = g.CreatedAt
+		}
+	}
+	return last
+}
+
+func (s *MemoryStore) GrantedSince(ctx context.Context, chainID uint64, token string, since int64) (*big.Int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := new(big.Int)
+	for _, g := range s.grants {
+		if s.counts(g, chainID, token) && g.CreatedAt >= since {
+			total.Add(total, g.Amount)
+		}
+	}
+	return total, nil
+}
+
+func (s *MemoryStore) counts(g *repositories.FaucetGrantModel, chainID uint64, token string) bool {
+	return g.ChainID == chainID && g.Token == token && g.Status != repositories.FaucetGrantReleased
+}
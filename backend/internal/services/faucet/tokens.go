@@ -0,0 +1,177 @@
+package faucet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// How an asset is dispensed
+const (
+	// ModeNative sends ETH
+	ModeNative = "native"
+	// ModeMint calls MockERC20.mint for the recipient
+	ModeMint = "mint"
+	// ModeTransfer transfers from the faucet's own token balance
+	ModeTransfer = "transfer"
+)
+
+// NativeSymbol is the symbol of the native asset, also used when a request names no token
+const NativeSymbol = "ETH"
+
+// erc20ABI covers the MockERC20 functions the faucet uses
+const erc20ABI = `[
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view",
+	 "inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"mint","stateMutability":"nonpayable",
+	 "inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable",
+	 "inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// TokenConfig is an ERC20 token the faucet dispenses. Amounts are in token units, they are converted
+// once the token's decimals have been read from the contract.
+type TokenConfig struct {
+	Symbol  string
+	Address common.Address
+	Mode    string
+	// MaxAmount is the most a single request may ask for
+	MaxAmount string
+	// DailyBudget caps the total given away per UTC day, empty or zero for no cap
+	DailyBudget string
+}
+
+// ParseTokens parses a token list such as
+// "USDC=0x5FbDB2315678afecb367f032d93F642f64180aa3:mint:1000:100000;DAI=0x...:transfer:500".
+// Each entry is SYMBOL=address:mode:max_amount[:daily_budget].
+func ParseTokens(spec string) ([]TokenConfig, error) {
+	var tokens []TokenConfig
+	seen := map[string]bool{NativeSymbol: true}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		symbol, rest, ok := strings.Cut(entry, "=")
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if !ok || symbol == "" {
+			return nil, fmt.Errorf("missing symbol in %q", entry)
+		}
+		if seen[symbol] {
+			return nil, fmt.Errorf("duplicate token %s", symbol)
+		}
+		seen[symbol] = true
+
+		fields := strings.Split(rest, ":")
+		if len(fields) < 3 || len(fields) > 4 {
+			return nil, fmt.Errorf("token %s must be SYMBOL=address:mode:max_amount[:daily_budget]", symbol)
+		}
+		address, err := validation.ParseAddress(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("token %s: %w", symbol, err)
+		}
+		token := TokenConfig{
+			Symbol:    symbol,
+			Address:   address,
+			Mode:      strings.TrimSpace(fields[1]),
+			MaxAmount: strings.TrimSpace(fields[2]),
+		}
+		if token.Mode != ModeMint && token.Mode != ModeTransfer {
+			return nil, fmt.Errorf("token %s: mode must be mint or transfer", symbol)
+		}
+		if token.MaxAmount == "" {
+			return nil, fmt.Errorf("token %s: missing max amount", symbol)
+		}
+		if len(fields) == 4 {
+			token.DailyBudget = strings.TrimSpace(fields[3])
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// asset is a dispensable asset with its amounts in base units
+type asset struct {
+	symbol string
+	// token is nil for the native asset
+	token       *common.Address
+	mode        string
+	decimals    uint8
+	maxAmount   *big.Int
+	dailyBudget *big.Int
+	limiter     *Limiter
+}
+
+// erc20 reads and calls MockERC20 contracts
+type erc20 struct {
+	evmClient *evm.Client
+	abi       abi.ABI
+}
+
+func newERC20(evmClient *evm.Client) (*erc20, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+	return &erc20{evmClient: evmClient, abi: parsed}, nil
+}
+
+func (t *erc20) decimals(ctx context.Context, token common.Address) (uint8, error) {
+	var decimals uint8
+	if err := t.call(ctx, token, "decimals", &decimals); err != nil {
+		return 0, err
+	}
+	return decimals, nil
+}
+
+func (t *erc20) balanceOf(ctx context.Context, token, account common.Address) (*big.Int, error) {
+	balance := new(big.Int)
+	if err := t.call(ctx, token, "balanceOf", &balance, account); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// call executes a view function and converts its single return value into out
+func (t *erc20) call(ctx context.Context, token common.Address, method string, out interface{}, args ...interface{}) error {
+	data, err := t.abi.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	result, err := t.evmClient.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	values, err := t.abi.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+	if len(values) != 1 {
+		return fmt.Errorf("unexpected %s result", method)
+	}
+
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// formatUnits renders an amount in base units as a decimal string in token units, without trailing zeros
+func formatUnits(amount *big.Int, decimals uint8) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	s := new(big.Rat).SetFrac(amount, unit).FloatString(int(decimals))
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
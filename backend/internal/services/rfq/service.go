@@ -2,11 +2,15 @@ package rfq
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
 	"github.com/Pagga-Wallet/aqua402/internal/queues"
 	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +29,8 @@ func NewService(repo *repositories.RFQRepository, queue *queues.Queue, logger *z
 }
 
 type CreateRFQRequest struct {
+	// ChainID is set from the chain query parameter
+	ChainID         uint64 `json:"-"`
 	BorrowerAddress string `json:"borrower_address"`
 	Amount          string `json:"amount"`
 	Duration        uint64 `json:"duration"`
@@ -33,6 +39,7 @@ type CreateRFQRequest struct {
 }
 
 type QuoteRequest struct {
+	ChainID            uint64 `json:"-"`
 	RFQID              uint64 `json:"rfq_id"`
 	LenderAddress      string `json:"lender_address"`
 	RateBps            uint16 `json:"rate_bps"`
@@ -40,8 +47,32 @@ type QuoteRequest struct {
 	CollateralRequired string `json:"collateral_required"`
 }
 
+// Validate checks the request and normalizes the borrower address to its checksummed form
+func (req *CreateRFQRequest) Validate() error {
+	var v validation.Validator
+	req.BorrowerAddress = v.Address("borrower_address", req.BorrowerAddress).Hex()
+	v.Amount("amount", req.Amount, validation.WeiDecimals)
+	v.Duration("duration", req.Duration, validation.MinDuration, validation.MaxDuration)
+	return v.Err()
+}
+
+// Validate checks the request and normalizes the lender address to its checksummed form
+func (req *QuoteRequest) Validate() error {
+	var v validation.Validator
+	req.LenderAddress = v.Address("lender_address", req.LenderAddress).Hex()
+	v.RateBps("rate_bps", req.RateBps)
+	v.Amount("limit", req.Limit, validation.WeiDecimals)
+	v.OptionalAmount("collateral_required", req.CollateralRequired, validation.WeiDecimals)
+	return v.Err()
+}
+
 func (s *Service) CreateRFQ(ctx context.Context, req CreateRFQRequest) (*repositories.RFQModel, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	rfq := &repositories.RFQModel{
+		ChainID:         req.ChainID,
 		BorrowerAddress: req.BorrowerAddress,
 		Amount:          req.Amount,
 		Duration:        req.Duration,
@@ -58,11 +89,12 @@ func (s *Service) CreateRFQ(ctx context.Context, req CreateRFQRequest) (*reposit
 
 	// Publish event to queue
 	event := map[string]interface{}{
-		"type":    "rfq_created",
-		"rfq_id":  rfq.ID,
-		"payload": rfq,
+		"type":     "rfq_created",
+		"chain_id": rfq.ChainID,
+		"rfq_id":   rfq.ID,
+		"payload":  rfq,
 	}
-	if err := s.queue.Publish("rfq.events", event); err != nil {
+	if err := s.queue.Publish(ctx, "rfq.events", event); err != nil {
 		s.logger.Warn("Failed to publish RFQ event", zap.Error(err))
 	}
 
@@ -70,9 +102,14 @@ func (s *Service) CreateRFQ(ctx context.Context, req CreateRFQRequest) (*reposit
 }
 
 func (s *Service) SubmitQuote(ctx context.Context, req QuoteRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
 	// Publish quote submission event
 	event := map[string]interface{}{
 		"type":           "quote_submitted",
+		"chain_id":       req.ChainID,
 		"rfq_id":         req.RFQID,
 		"lender_address": req.LenderAddress,
 		"rate_bps":       req.RateBps,
@@ -80,18 +117,29 @@ func (s *Service) SubmitQuote(ctx context.Context, req QuoteRequest) error {
 		"collateral":     req.CollateralRequired,
 	}
 
-	if err := s.queue.Publish("rfq.quotes", event); err != nil {
+	if err := s.queue.Publish(ctx, "rfq.quotes", event); err != nil {
 		s.logger.Warn("Failed to publish quote event", zap.Error(err))
-		return fmt.Errorf("failed to publish quote: %w", err)
+		return apperrors.Unavailable(err, "failed to publish quote")
 	}
 
 	return nil
 }
 
-func (s *Service) GetRFQ(ctx context.Context, id uint64) (*repositories.RFQModel, error) {
-	return s.repo.GetRFQ(ctx, id)
+func (s *Service) GetRFQ(ctx context.Context, chainID, id uint64) (*repositories.RFQModel, error) {
+	rfq, err := s.repo.GetRFQ(ctx, chainID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("RFQ %d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RFQ %d: %w", id, err)
+	}
+	return rfq, nil
+}
+
+func (s *Service) ListRFQs(ctx context.Context, q repositories.ListQuery) (*repositories.Page[repositories.RFQModel], error) {
+	return s.repo.ListRFQs(ctx, q)
 }
 
-func (s *Service) ListRFQs(ctx context.Context, limit, offset int) ([]*repositories.RFQModel, error) {
-	return s.repo.ListRFQs(ctx, limit, offset)
+func (s *Service) ListQuotes(ctx context.Context, rfqID uint64, q repositories.ListQuery) (*repositories.Page[repositories.QuoteModel], error) {
+	return s.repo.ListQuotes(ctx, rfqID, q)
 }
@@ -0,0 +1,39 @@
+package risk
+
+import (
+	"math/big"
+	"time"
+)
+
+// Credit line risk statuses
+const (
+	StatusCurrent    = "current"
+	StatusGrace      = "grace"
+	StatusDelinquent = "delinquent"
+	StatusDefaulted  = "defaulted"
+)
+
+// Thresholds define how long after expiry an unpaid credit line moves between statuses
+type Thresholds struct {
+	// GracePeriod is the time after expiry during which a balance is tolerated
+	GracePeriod time.Duration
+	// DefaultAfter is the time after expiry at which a balance is considered defaulted
+	DefaultAfter time.Duration
+}
+
+// Classify returns the risk status of a credit line expiring at expiresAt with owed outstanding at now
+func Classify(expiresAt, now int64, owed *big.Int, t Thresholds) string {
+	if owed == nil || owed.Sign() <= 0 || now < expiresAt {
+		return StatusCurrent
+	}
+
+	overdue := time.Duration(now-expiresAt) * time.Second
+	switch {
+	case overdue >= t.DefaultAfter:
+		return StatusDefaulted
+	case overdue >= t.GracePeriod:
+		return StatusDelinquent
+	default:
+		return StatusGrace
+	}
+}
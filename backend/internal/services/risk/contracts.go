@@ -0,0 +1,178 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// rfqABI covers the RFQ contract views needed to find the collateral of an executed RFQ
+const rfqABI = `[
+	{"type":"function","name":"getRFQ","stateMutability":"view",
+	 "inputs":[{"name":"rfqId","type":"uint256"}],
+	 "outputs":[{"name":"","type":"tuple","components":[
+		{"name":"borrower","type":"address"},
+		{"name":"amount","type":"uint256"},
+		{"name":"duration","type":"uint256"},
+		{"name":"collateralType","type":"uint8"},
+		{"name":"flowDescription","type":"string"},
+		{"name":"status","type":"uint8"},
+		{"name":"createdAt","type":"uint256"}]}]},
+	{"type":"function","name":"getQuotes","stateMutability":"view",
+	 "inputs":[{"name":"rfqId","type":"uint256"}],
+	 "outputs":[{"name":"","type":"tuple[]","components":[
+		{"name":"lender","type":"address"},
+		{"name":"rateBps","type":"uint16"},
+		{"name":"limit","type":"uint256"},
+		{"name":"collateralRequired","type":"uint256"},
+		{"name":"submittedAt","type":"uint256"},
+		{"name":"accepted","type":"bool"}]}]}
+]`
+
+// aquaABI covers the AquaIntegration function used to settle liquidation cases
+const aquaABI = `[
+	{"type":"function","name":"releaseLiquidity","stateMutability":"nonpayable",
+	 "inputs":[{"name":"lender","type":"address"},{"name":"amount","type":"uint256"}],
+	 "outputs":[]}
+]`
+
+// Contracts are the collateral reader and liquidity releaser of one chain, either may be nil
+type Contracts struct {
+	Collateral *CollateralReader
+	Releaser   Releaser
+}
+
+// Releaser settles approved liquidation cases; *LiquidityReleaser sends them to AquaIntegration
+type Releaser interface {
+	// ReleaseLiquidity frees amount of the lender's reserved liquidity and returns the transaction hash
+	ReleaseLiquidity(ctx context.Context, lender common.Address, amount *big.Int) (string, error)
+}
+
+type rfqData struct {
+	Borrower        common.Address
+	Amount          *big.Int
+	Duration        *big.Int
+	CollateralType  uint8
+	FlowDescription string
+	Status          uint8
+	CreatedAt       *big.Int
+}
+
+type quoteData struct {
+	Lender             common.Address
+	RateBps            uint16
+	Limit              *big.Int
+	CollateralRequired *big.Int
+	SubmittedAt        *big.Int
+	Accepted           bool
+}
+
+// CollateralReader reads the collateral terms of an RFQ from the RFQ contract
+type CollateralReader struct {
+	evmClient  *evm.Client
+	rfqAddress common.Address
+	abi        abi.ABI
+}
+
+// NewCollateralReader creates a collateral reader for the given RFQ contract
+func NewCollateralReader(evmClient *evm.Client, rfqAddress string) (*CollateralReader, error) {
+	parsed, err := abi.JSON(strings.NewReader(rfqABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RFQ ABI: %w", err)
+	}
+	return &CollateralReader{
+		evmClient:  evmClient,
+		rfqAddress: common.HexToAddress(rfqAddress),
+		abi:        parsed,
+	}, nil
+}
+
+// RFQCollateral returns the RFQ collateral type and the collateral required by the accepted quote
+func (r *CollateralReader) RFQCollateral(ctx context.Context, rfqID uint64) (uint8, *big.Int, error) {
+	id := new(big.Int).SetUint64(rfqID)
+
+	var rfq rfqData
+	if err := r.call(ctx, "getRFQ", &rfq, id); err != nil {
+		return 0, nil, err
+	}
+
+	var quotes []quoteData
+	if err := r.call(ctx, "getQuotes", &quotes, id); err != nil {
+		return 0, nil, err
+	}
+
+	for _, quote := range quotes {
+		if quote.Accepted {
+			return rfq.CollateralType, quote.CollateralRequired, nil
+		}
+	}
+
+	return rfq.CollateralType, new(big.Int), nil
+}
+
+// call executes a view function and converts its single return value into out
+func (r *CollateralReader) call(ctx context.Context, method string, out interface{}, args ...interface{}) error {
+	data, err := r.abi.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	result, err := r.evmClient.CallContract(ctx, ethereum.CallMsg{To: &r.rfqAddress, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	values, err := r.abi.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+	if len(values) != 1 {
+		return fmt.Errorf("unexpected %s result", method)
+	}
+
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// LiquidityReleaser sends AquaIntegration.releaseLiquidity transactions for approved liquidation cases
+type LiquidityReleaser struct {
+	txm         *evm.TxManager
+	aquaAddress common.Address
+	abi         abi.ABI
+}
+
+// NewLiquidityReleaser creates a releaser sending through txm, whose key must be the Aqua operator
+func NewLiquidityReleaser(txm *evm.TxManager, aquaAddress string) (*LiquidityReleaser, error) {
+	parsed, err := abi.JSON(strings.NewReader(aquaABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Aqua ABI: %w", err)
+	}
+
+	return &LiquidityReleaser{
+		txm:         txm,
+		aquaAddress: common.HexToAddress(aquaAddress),
+		abi:         parsed,
+	}, nil
+}
+
+// ReleaseLiquidity releases the lender's reserved Aqua liquidity and returns the transaction hash.
+// The transaction manager resends it with more gas if it gets stuck, see GET /transactions/:id.
+func (r *LiquidityReleaser) ReleaseLiquidity(ctx context.Context, lender common.Address, amount *big.Int) (string, error) {
+	data, err := r.abi.Pack("releaseLiquidity", lender, amount)
+	if err != nil {
+		return "", fmt.Errorf("failed to pack releaseLiquidity: %w", err)
+	}
+
+	tx, err := r.txm.Send(ctx, evm.TxRequest{To: r.aquaAddress, Data: data})
+	if err != nil {
+		return "", err
+	}
+
+	return tx.Hash.Hex(), nil
+}
@@ -0,0 +1,301 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/queues"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/credit"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// Monitor watches expired credit lines, flags delinquencies and drives liquidation cases
+type Monitor struct {
+	creditRepo    *repositories.CreditRepository
+	riskRepo      Store
+	creditService *credit.Service
+	queue         *queues.Queue
+	contracts     map[uint64]Contracts
+	cfg           Config
+	logger        *zap.Logger
+}
+
+// NewMonitor creates a new risk monitor. contracts holds an entry per chain ID, liquidation cases of
+// chains missing from it are not executed. Both contracts are optional: without a collateral reader no
+// liquidation cases are opened, without a releaser approved cases wait until one is configured.
+func NewMonitor(
+	creditRepo *repositories.CreditRepository,
+	riskRepo Store,
+	creditService *credit.Service,
+	queue *queues.Queue,
+	contracts map[uint64]Contracts,
+	cfg Config,
+	logger *zap.Logger,
+) *Monitor {
+	return &Monitor{
+		creditRepo:    creditRepo,
+		riskRepo:      riskRepo,
+		creditService: creditService,
+		queue:         queue,
+		contracts:     contracts,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// Start runs risk checks until the context is cancelled
+func (m *Monitor) Start(ctx context.Context) error {
+	m.logger.Info("Starting risk monitor",
+		zap.Duration("grace_period", m.cfg.GracePeriod),
+		zap.Duration("default_after", m.cfg.DefaultAfter),
+		zap.Duration("check_interval", m.cfg.CheckInterval))
+
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("Risk monitor stopped")
+			return ctx.Err()
+		case <-ticker.C:
+			if err := m.checkCreditLines(ctx); err != nil {
+				m.logger.Error("Error checking credit lines", zap.Error(err))
+			}
+			if err := m.ExecuteApprovedCases(ctx); err != nil {
+				m.logger.Error("Error executing liquidation cases", zap.Error(err))
+			}
+		}
+	}
+}
+
+// checkCreditLines classifies the expired credit lines not settled yet and records status changes. A line
+// found current with nothing owed is recorded as such and not listed again, so each check replays the
+// history of the lines still owing only.
+func (m *Monitor) checkCreditLines(ctx context.Context) error {
+	now := time.Now().Unix()
+	lines, err := m.creditRepo.ListExpiredCreditLines(ctx, now, StatusCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to list expired credit lines: %w", err)
+	}
+
+	for _, line := range lines {
+		if err := m.checkCreditLine(ctx, line, now); err != nil {
+			m.logger.Error("Failed to check credit line", zap.Uint64("credit_line_id", line.ID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func (m *Monitor) checkCreditLine(ctx context.Context, line *repositories.CreditLineModel, now int64) error {
+	position, err := m.creditService.GetPosition(ctx, line)
+	if err != nil {
+		return err
+	}
+	owed, _ := new(big.Int).SetString(position.TotalOwed, 10)
+	status := Classify(line.ExpiresAt, now, owed, m.cfg.Thresholds)
+
+	previous, previousOwed := StatusCurrent, ""
+	if prev, err := m.riskRepo.GetRiskStatus(ctx, line.ChainID, line.ID); err == nil {
+		previous, previousOwed = prev.Status, prev.TotalOwed
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get risk status: %w", err)
+	}
+
+	// A settled line is stored once even without a status change, which takes it off the expired list
+	settled := status == StatusCurrent && position.TotalOwed == "0"
+	if status != previous || (settled && previousOwed != "0") {
+		record := &repositories.RiskStatusModel{
+			ChainID:         line.ChainID,
+			CreditLineID:    line.ID,
+			BorrowerAddress: line.BorrowerAddress,
+			LenderAddress:   line.LenderAddress,
+			Status:          status,
+			TotalOwed:       position.TotalOwed,
+			ExpiresAt:       line.ExpiresAt,
+			UpdatedAt:       now,
+		}
+		if err := m.riskRepo.SaveRiskStatus(ctx, record); err != nil {
+			return fmt.Errorf("failed to save risk status: %w", err)
+		}
+	}
+
+	if status != previous {
+		event := map[string]interface{}{
+			"type":            "credit_line_status_changed",
+			"event_id":        fmt.Sprintf("risk-%d-%s-%d", line.ID, status, now),
+			"chain_id":        line.ChainID,
+			"credit_line_id":  line.ID,
+			"borrower":        line.BorrowerAddress,
+			"lender":          line.LenderAddress,
+			"previous_status": previous,
+			"status":          status,
+			"total_owed":      position.TotalOwed,
+			"expires_at":      line.ExpiresAt,
+		}
+		m.publish(ctx, event)
+
+		m.logger.Info("Credit line risk status changed",
+			zap.Uint64("chain_id", line.ChainID),
+			zap.Uint64("credit_line_id", line.ID),
+			zap.String("previous_status", previous),
+			zap.String("status", status),
+			zap.String("total_owed", position.TotalOwed))
+	}
+
+	if status == StatusDefaulted {
+		return m.openLiquidationCase(ctx, line, position.TotalOwed, now)
+	}
+
+	return nil
+}
+
+// openLiquidationCase opens a case awaiting operator approval when the defaulted line was collateralized
+func (m *Monitor) openLiquidationCase(ctx context.Context, line *repositories.CreditLineModel, outstanding string, now int64) error {
+	collateral := m.contracts[line.ChainID].Collateral
+	if collateral == nil {
+		return nil
+	}
+
+	if _, err := m.riskRepo.GetLiquidationCase(ctx, line.ChainID, line.ID); err == nil {
+		return nil // Case already opened
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get liquidation case: %w", err)
+	}
+
+	source, err := m.creditRepo.GetCreditLineSource(ctx, line.ChainID, line.ID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && source.Source != repositories.CreditSourceRFQ) {
+		return nil // Only RFQ quotes carry collateral
+	} else if err != nil {
+		return fmt.Errorf("failed to get credit line source: %w", err)
+	}
+
+	collateralType, collateralAmount, err := collateral.RFQCollateral(ctx, source.SourceID)
+	if err != nil {
+		return fmt.Errorf("failed to read RFQ collateral: %w", err)
+	}
+	if collateralType == 0 && collateralAmount.Sign() == 0 {
+		return nil
+	}
+
+	c := &repositories.LiquidationCaseModel{
+		ChainID:          line.ChainID,
+		CreditLineID:     line.ID,
+		RFQID:            source.SourceID,
+		BorrowerAddress:  line.BorrowerAddress,
+		LenderAddress:    line.LenderAddress,
+		CollateralType:   collateralType,
+		CollateralAmount: collateralAmount.String(),
+		Outstanding:      outstanding,
+		ReleaseAmount:    line.Limit,
+		Action:           ActionClaim,
+		Status:           CaseStatusPendingApproval,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := m.riskRepo.SaveLiquidationCase(ctx, c); err != nil {
+		return fmt.Errorf("failed to save liquidation case: %w", err)
+	}
+
+	m.publish(ctx, caseEvent("liquidation_case_opened", c))
+
+	m.logger.Info("Opened liquidation case",
+		zap.Uint64("chain_id", line.ChainID),
+		zap.Uint64("credit_line_id", line.ID),
+		zap.Uint64("rfq_id", source.SourceID),
+		zap.String("collateral_amount", c.CollateralAmount))
+
+	return nil
+}
+
+// ExecuteApprovedCases settles operator-approved cases of every chain through AquaIntegration.releaseLiquidity.
+// Each case is saved as executing before its transaction is sent and only approved cases are picked up,
+// so a case is sent at most once even when saving its outcome fails.
+func (m *Monitor) ExecuteApprovedCases(ctx context.Context) error {
+	for chainID, contracts := range m.contracts {
+		if err := m.executeChainCases(ctx, chainID, contracts.Releaser); err != nil {
+			return fmt.Errorf("chain %d: %w", chainID, err)
+		}
+	}
+	return nil
+}
+
+func (m *Monitor) executeChainCases(ctx context.Context, chainID uint64, releaser Releaser) error {
+	cases, err := m.riskRepo.ListLiquidationCases(ctx, chainID, CaseStatusApproved)
+	if err != nil {
+		return fmt.Errorf("failed to list approved liquidation cases: %w", err)
+	}
+	if len(cases) == 0 {
+		return nil
+	}
+	if releaser == nil {
+		m.logger.Warn("Approved liquidation cases waiting, but no liquidity releaser is configured",
+			zap.Uint64("chain_id", chainID),
+			zap.Int("cases", len(cases)))
+		return nil
+	}
+
+	for _, c := range cases {
+		if c.Status != CaseStatusApproved {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(c.ReleaseAmount, 10)
+		if !ok {
+			amount = new(big.Int)
+		}
+
+		c.Status = CaseStatusExecuting
+		c.UpdatedAt = time.Now().Unix()
+		if err := m.riskRepo.SaveLiquidationCase(ctx, c); err != nil {
+			m.logger.Error("Failed to mark liquidation case executing, not sending it", zap.Uint64("credit_line_id", c.CreditLineID), zap.Error(err))
+			continue
+		}
+
+		txHash, err := releaser.ReleaseLiquidity(ctx, common.HexToAddress(c.LenderAddress), amount)
+		c.UpdatedAt = time.Now().Unix()
+		if err != nil {
+			c.Status = CaseStatusFailed
+			c.Error = err.Error()
+			m.logger.Error("Failed to execute liquidation case", zap.Uint64("credit_line_id", c.CreditLineID), zap.Error(err))
+		} else {
+			c.Status = CaseStatusExecuted
+			c.TxHash = txHash
+			m.logger.Info("Executed liquidation case",
+				zap.Uint64("credit_line_id", c.CreditLineID),
+				zap.String("action", c.Action),
+				zap.String("tx_hash", txHash))
+		}
+
+		if err := m.riskRepo.SaveLiquidationCase(ctx, c); err != nil {
+			// The case stays executing and is not sent again, the transaction is tracked by the manager
+			m.logger.Error("Failed to save liquidation case, it stays executing",
+				zap.Uint64("credit_line_id", c.CreditLineID),
+				zap.String("status", c.Status),
+				zap.String("tx_hash", c.TxHash),
+				zap.Error(err))
+			continue
+		}
+
+		m.publish(ctx, caseEvent("liquidation_case_"+c.Status, c))
+	}
+
+	return nil
+}
+
+// publish sends a risk event to credit.risk, where the worker hands it to the webhook subscribers of the
+// borrower and lender
+func (m *Monitor) publish(ctx context.Context, event map[string]interface{}) {
+	if m.queue == nil {
+		return
+	}
+	if err := m.queue.Publish(ctx, "credit.risk", event); err != nil {
+		m.logger.Warn("Failed to publish risk event", zap.Any("type", event["type"]), zap.Error(err))
+	}
+}
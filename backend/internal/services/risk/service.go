@@ -0,0 +1,209 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/queues"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// Liquidation case statuses
+const (
+	CaseStatusPendingApproval = "pending_approval"
+	CaseStatusApproved        = "approved"
+	CaseStatusRejected        = "rejected"
+	// CaseStatusExecuting is saved before the release transaction is sent, so a case whose outcome could
+	// not be saved is never sent twice. A case left executing needs an operator to check its transaction.
+	CaseStatusExecuting = "executing"
+	CaseStatusExecuted  = "executed"
+	CaseStatusFailed    = "failed"
+)
+
+// ActionClaim is the only liquidation action: AquaIntegration.releaseLiquidity frees the credit line
+// limit reserved from the lender's liquidity, so the lender can use it again. No collateral is moved.
+const ActionClaim = "claim"
+
+// ErrCaseNotPending is returned when an operator acts on a case that is no longer awaiting approval
+var ErrCaseNotPending = apperrors.Conflict("liquidation case is not pending approval")
+
+// ErrInvalidAction is returned for an unknown liquidation action
+var ErrInvalidAction = apperrors.Invalid("invalid liquidation action",
+	apperrors.FieldError{Field: "action", Message: "must be " + ActionClaim})
+
+// Config controls the risk monitor
+type Config struct {
+	Thresholds
+	CheckInterval time.Duration
+}
+
+// ConfigFromEnv reads the risk monitor configuration from environment variables
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Thresholds: Thresholds{
+			GracePeriod:  72 * time.Hour,
+			DefaultAfter: 30 * 24 * time.Hour,
+		},
+		CheckInterval: time.Minute,
+	}
+
+	for key, target := range map[string]*time.Duration{
+		"RISK_GRACE_PERIOD":   &cfg.GracePeriod,
+		"RISK_DEFAULT_AFTER":  &cfg.DefaultAfter,
+		"RISK_CHECK_INTERVAL": &cfg.CheckInterval,
+	} {
+		if value := os.Getenv(key); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*target = d
+		}
+	}
+
+	if cfg.DefaultAfter < cfg.GracePeriod {
+		return cfg, fmt.Errorf("RISK_DEFAULT_AFTER (%s) must not be shorter than RISK_GRACE_PERIOD (%s)", cfg.DefaultAfter, cfg.GracePeriod)
+	}
+
+	return cfg, nil
+}
+
+// Service exposes credit line risk and the operator side of liquidation cases
+type Service struct {
+	repo   Store
+	queue  *queues.Queue
+	logger *zap.Logger
+}
+
+func NewService(repo Store, queue *queues.Queue, logger *zap.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		queue:  queue,
+		logger: logger,
+	}
+}
+
+type ApproveRequest struct {
+	Operator string `json:"operator"`
+	// Action defaults to claim, the only action
+	Action string `json:"action"`
+}
+
+type RejectRequest struct {
+	Operator string `json:"operator"`
+	Reason   string `json:"reason"`
+}
+
+// GetRiskStatus returns the latest risk status of a credit line
+func (s *Service) GetRiskStatus(ctx context.Context, chainID, creditLineID uint64) (*repositories.RiskStatusModel, error) {
+	status, err := s.repo.GetRiskStatus(ctx, chainID, creditLineID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("no risk status for credit line %d", creditLineID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk status: %w", err)
+	}
+	return status, nil
+}
+
+// ListCases returns the liquidation cases of a chain, optionally filtered by status
+func (s *Service) ListCases(ctx context.Context, chainID uint64, status string) ([]*repositories.LiquidationCaseModel, error) {
+	return s.repo.ListLiquidationCases(ctx, chainID, status)
+}
+
+// GetCase returns the liquidation case of a credit line
+func (s *Service) GetCase(ctx context.Context, chainID, creditLineID uint64) (*repositories.LiquidationCaseModel, error) {
+	c, err := s.repo.GetLiquidationCase(ctx, chainID, creditLineID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("no liquidation case for credit line %d", creditLineID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get liquidation case: %w", err)
+	}
+	return c, nil
+}
+
+// ApproveCase approves a pending liquidation case; the worker executes it on its next check
+func (s *Service) ApproveCase(ctx context.Context, chainID, creditLineID uint64, req ApproveRequest) (*repositories.LiquidationCaseModel, error) {
+	c, err := s.GetCase(ctx, chainID, creditLineID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != CaseStatusPendingApproval {
+		return nil, ErrCaseNotPending
+	}
+
+	if req.Action != "" {
+		if req.Action != ActionClaim {
+			return nil, ErrInvalidAction
+		}
+		c.Action = req.Action
+	}
+	c.Status = CaseStatusApproved
+	c.Operator = req.Operator
+	c.UpdatedAt = time.Now().Unix()
+
+	if err := s.repo.SaveLiquidationCase(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to save liquidation case: %w", err)
+	}
+
+	s.publish(ctx, "liquidation_case_approved", c)
+	return c, nil
+}
+
+// RejectCase closes a pending liquidation case without touching the collateral
+func (s *Service) RejectCase(ctx context.Context, chainID, creditLineID uint64, req RejectRequest) (*repositories.LiquidationCaseModel, error) {
+	c, err := s.GetCase(ctx, chainID, creditLineID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != CaseStatusPendingApproval {
+		return nil, ErrCaseNotPending
+	}
+
+	c.Status = CaseStatusRejected
+	c.Operator = req.Operator
+	c.Error = req.Reason
+	c.UpdatedAt = time.Now().Unix()
+
+	if err := s.repo.SaveLiquidationCase(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to save liquidation case: %w", err)
+	}
+
+	s.publish(ctx, "liquidation_case_rejected", c)
+	return c, nil
+}
+
+func (s *Service) publish(ctx context.Context, eventType string, c *repositories.LiquidationCaseModel) {
+	if s.queue == nil {
+		return
+	}
+	if err := s.queue.Publish(ctx, "credit.risk", caseEvent(eventType, c)); err != nil {
+		s.logger.Warn("Failed to publish liquidation case event", zap.String("type", eventType), zap.Error(err))
+	}
+}
+
+func caseEvent(eventType string, c *repositories.LiquidationCaseModel) map[string]interface{} {
+	return map[string]interface{}{
+		"type":              eventType,
+		"event_id":          fmt.Sprintf("liquidation-%d-%s", c.CreditLineID, c.Status),
+		"chain_id":          c.ChainID,
+		"credit_line_id":    c.CreditLineID,
+		"rfq_id":            c.RFQID,
+		"borrower":          c.BorrowerAddress,
+		"lender":            c.LenderAddress,
+		"collateral_type":   c.CollateralType,
+		"collateral_amount": c.CollateralAmount,
+		"outstanding":       c.Outstanding,
+		"action":            c.Action,
+		"status":            c.Status,
+		"operator":          c.Operator,
+		"tx_hash":           c.TxHash,
+	}
+}
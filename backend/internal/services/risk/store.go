@@ -0,0 +1,100 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+)
+
+// Store keeps credit line risk statuses and liquidation cases; *repositories.RiskRepository satisfies it
+type Store interface {
+	SaveRiskStatus(ctx context.Context, status *repositories.RiskStatusModel) error
+	// GetRiskStatus returns sql.ErrNoRows for a credit line that was never classified
+	GetRiskStatus(ctx context.Context, chainID, creditLineID uint64) (*repositories.RiskStatusModel, error)
+
+	SaveLiquidationCase(ctx context.Context, c *repositories.LiquidationCaseModel) error
+	// GetLiquidationCase returns sql.ErrNoRows for a credit line without a case
+	GetLiquidationCase(ctx context.Context, chainID, creditLineID uint64) (*repositories.LiquidationCaseModel, error)
+	// ListLiquidationCases returns the cases of a chain, newest first, all of them when status is empty
+	ListLiquidationCases(ctx context.Context, chainID uint64, status string) ([]*repositories.LiquidationCaseModel, error)
+}
+
+type caseKey struct {
+	chainID      uint64
+	creditLineID uint64
+}
+
+// MemoryStore keeps risk statuses and liquidation cases in process, for tests and single process deployments
+type MemoryStore struct {
+	mu       sync.Mutex
+	statuses map[caseKey]*repositories.RiskStatusModel
+	cases    map[caseKey]*repositories.LiquidationCaseModel
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		statuses: make(map[caseKey]*repositories.RiskStatusModel),
+		cases:    make(map[caseKey]*repositories.LiquidationCaseModel),
+	}
+}
+
+func (s *MemoryStore) SaveRiskStatus(ctx context.Context, status *repositories.RiskStatusModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *status
+	s.statuses[caseKey{status.ChainID, status.CreditLineID}] = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetRiskStatus(ctx context.Context, chainID, creditLineID uint64) (*repositories.RiskStatusModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.statuses[caseKey{chainID, creditLineID}]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *status
+	return &copied, nil
+}
+
+func (s *MemoryStore) SaveLiquidationCase(ctx context.Context, c *repositories.LiquidationCaseModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *c
+	s.cases[caseKey{c.ChainID, c.CreditLineID}] = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetLiquidationCase(ctx context.Context, chainID, creditLineID uint64) (*repositories.LiquidationCaseModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cases[caseKey{chainID, creditLineID}]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (s *MemoryStore) ListLiquidationCases(ctx context.Context, chainID uint64, status string) ([]*repositories.LiquidationCaseModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var cases []*repositories.LiquidationCaseModel
+	for _, c := range s.cases {
+		if c.ChainID == chainID && (status == "" || c.Status == status) {
+			copied := *c
+			cases = append(cases, &copied)
+		}
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		if cases[i].CreatedAt != cases[j].CreatedAt {
+			return cases[i].CreatedAt > cases[j].CreatedAt
+		}
+		return cases[i].CreditLineID < cases[j].CreditLineID
+	})
+	return cases, nil
+}
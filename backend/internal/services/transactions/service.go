@@ -0,0 +1,92 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// Service reports the status of transactions sent by the backend signers (faucet, liquidations)
+type Service struct {
+	store  evm.TxStore
+	logger *zap.Logger
+}
+
+func NewService(store evm.TxStore, logger *zap.Logger) *Service {
+	return &Service{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Transaction is the status of a backend transaction. Hash changes when a stuck transaction is resent
+// with higher fees, Hashes lists every broadcast. Dynamic-fee (EIP-1559) transactions have a tip and fee cap,
+// legacy ones a gas price.
+type Transaction struct {
+	ID          string   `json:"id"`
+	Status      string   `json:"status"`
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Value       string   `json:"value"`
+	Nonce       uint64   `json:"nonce"`
+	GasLimit    uint64   `json:"gas_limit"`
+	GasPrice    string   `json:"gas_price,omitempty"`
+	GasTipCap   string   `json:"gas_tip_cap,omitempty"`
+	GasFeeCap   string   `json:"gas_fee_cap,omitempty"`
+	Urgency     string   `json:"urgency,omitempty"`
+	Hash        string   `json:"tx_hash,omitempty"`
+	Hashes      []string `json:"tx_hashes"`
+	Attempts    uint32   `json:"attempts"`
+	BlockNumber uint64   `json:"block_number,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	CreatedAt   int64    `json:"created_at"`
+	UpdatedAt   int64    `json:"updated_at"`
+}
+
+// GetTransaction returns a transaction by the ID the sending endpoint returned
+func (s *Service) GetTransaction(ctx context.Context, id string) (*Transaction, error) {
+	tx, err := s.store.GetTx(ctx, id)
+	if errors.Is(err, evm.ErrTxNotFound) {
+		return nil, apperrors.NotFound("transaction %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Transaction{
+		ID:          tx.ID,
+		Status:      string(tx.Status),
+		From:        tx.From.Hex(),
+		To:          tx.To.Hex(),
+		Value:       tx.Value.String(),
+		Nonce:       tx.Nonce,
+		GasLimit:    tx.GasLimit,
+		Urgency:     string(tx.Urgency),
+		Hashes:      make([]string, 0, len(tx.Hashes)),
+		Attempts:    tx.Attempts,
+		BlockNumber: tx.BlockNumber,
+		Error:       tx.Error,
+		CreatedAt:   tx.CreatedAt,
+		UpdatedAt:   tx.UpdatedAt,
+	}
+	if tx.GasPrice != nil {
+		result.GasPrice = tx.GasPrice.String()
+	}
+	if tx.GasTipCap != nil {
+		result.GasTipCap = tx.GasTipCap.String()
+	}
+	if tx.GasFeeCap != nil {
+		result.GasFeeCap = tx.GasFeeCap.String()
+	}
+	if tx.Hash != (common.Hash{}) {
+		result.Hash = tx.Hash.Hex()
+	}
+	for _, hash := range tx.Hashes {
+		result.Hashes = append(result.Hashes, hash.Hex())
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// errPrivateAddress is returned when a delivery URL resolves to a loopback or private network address
+var errPrivateAddress = errors.New("webhook URL resolves to a private address")
+
+// reservedNets are the special-purpose ranges net.IP has no predicate for. NAT64 and 6to4 addresses
+// embed an IPv4 address, which may be a private one.
+var reservedNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // this network
+		"100.64.0.0/10", // carrier-grade NAT, where some clouds serve instance metadata
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // benchmarking
+		"240.0.0.0/4",   // reserved, with the limited broadcast address
+		"64:ff9b::/96",  // NAT64
+		"2002::/16",     // 6to4
+	} {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}()
+
+// privateIP reports an address deliveries must not reach: loopback, private, link-local (which holds the
+// cloud metadata endpoints), unspecified, multicast or another special-purpose range
+func privateIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, ipNet := range reservedNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// privateHost reports a URL host that names a private address without resolving it: an IP literal in a
+// private range or localhost. Other names are checked when the dispatcher connects.
+func privateHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && privateIP(ip)
+}
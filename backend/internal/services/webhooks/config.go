@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config controls how deliveries are sent and retried
+type Config struct {
+	// MaxAttempts is how many times a delivery is tried before it is marked failed
+	MaxAttempts uint32
+	// RetryBase is the wait after the first failed attempt, doubled after each further one up to RetryMax
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// Timeout bounds each attempt, the subscriber must answer 2xx within it
+	Timeout time.Duration
+	// PollInterval is how often the dispatcher looks for due deliveries
+	PollInterval time.Duration
+	// Concurrency is how many deliveries are sent at once
+	Concurrency int
+	// AllowPrivateURLs lets subscriptions name, and deliveries reach, loopback and private network addresses,
+	// for local development
+	AllowPrivateURLs bool
+}
+
+// ConfigFromEnv reads WEBHOOK_MAX_ATTEMPTS (default 8), WEBHOOK_RETRY_BASE (30s), WEBHOOK_RETRY_MAX (1h),
+// WEBHOOK_TIMEOUT (10s), WEBHOOK_POLL_INTERVAL (2s), WEBHOOK_CONCURRENCY (8) and WEBHOOK_ALLOW_PRIVATE_URLS
+// (false)
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		MaxAttempts:  8,
+		RetryBase:    30 * time.Second,
+		RetryMax:     time.Hour,
+		Timeout:      10 * time.Second,
+		PollInterval: 2 * time.Second,
+		Concurrency:  8,
+	}
+
+	for key, target := range map[string]*time.Duration{
+		"WEBHOOK_RETRY_BASE":    &cfg.RetryBase,
+		"WEBHOOK_RETRY_MAX":     &cfg.RetryMax,
+		"WEBHOOK_TIMEOUT":       &cfg.Timeout,
+		"WEBHOOK_POLL_INTERVAL": &cfg.PollInterval,
+	} {
+		if value := os.Getenv(key); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return cfg, fmt.Errorf("invalid %s %q", key, value)
+			}
+			*target = d
+		}
+	}
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || n == 0 {
+			return cfg, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS %q", v)
+		}
+		cfg.MaxAttempts = uint32(n)
+	}
+	if v := os.Getenv("WEBHOOK_CONCURRENCY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return cfg, fmt.Errorf("invalid WEBHOOK_CONCURRENCY %q", v)
+		}
+		cfg.Concurrency = n
+	}
+	if v := os.Getenv("WEBHOOK_ALLOW_PRIVATE_URLS"); v != "" {
+		allow, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid WEBHOOK_ALLOW_PRIVATE_URLS %q", v)
+		}
+		cfg.AllowPrivateURLs = allow
+	}
+	if cfg.RetryMax < cfg.RetryBase {
+		return cfg, fmt.Errorf("WEBHOOK_RETRY_MAX must not be below WEBHOOK_RETRY_BASE")
+	}
+	return cfg, nil
+}
+
+// Backoff returns the wait before the next attempt of a delivery that failed attempts times
+func (c Config) Backoff(attempts uint32) time.Duration {
+	wait := c.RetryBase
+	for i := uint32(1); i < attempts && wait < c.RetryMax; i++ {
+		wait *= 2
+	}
+	return min(wait, c.RetryMax)
+}
@@ -0,0 +1,201 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// Dispatcher sends due deliveries and schedules the retries of those that fail
+type Dispatcher struct {
+	store  Store
+	cfg    Config
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewDispatcher creates a dispatcher. Redirects are not followed and, unless AllowPrivateURLs is set,
+// subscribers can't point deliveries at the worker's own network: the address is checked when connecting,
+// after DNS resolution, so a name resolving to a private address is refused too.
+func NewDispatcher(store Store, cfg Config, logger *zap.Logger) *Dispatcher {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateURLs {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || privateIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Dispatcher{
+		store: store,
+		cfg:   cfg,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// Run sends due deliveries every PollInterval until ctx is cancelled. Attempts in flight are finished.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchDue(ctx); err != nil {
+			d.logger.Error("Failed to dispatch webhook deliveries", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends the deliveries due now, Concurrency at a time. It takes batch after batch while
+// every attempt is recorded; a delivery whose outcome could not be saved is still due, so the rest
+// waits for the next poll instead of picking it up again at once.
+func (d *Dispatcher) DispatchDue(ctx context.Context) error {
+	limit := d.cfg.Concurrency * 4
+	for ctx.Err() == nil {
+		deliveries, err := d.store.DueDeliveries(ctx, time.Now().Unix(), limit)
+		if err != nil {
+			return fmt.Errorf("failed to find due webhook deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		sem := make(chan struct{}, d.cfg.Concurrency)
+		var wg sync.WaitGroup
+		var unrecorded atomic.Int32
+		for _, delivery := range deliveries {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				if !d.attempt(context.WithoutCancel(ctx), delivery) {
+					unrecorded.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := unrecorded.Load(); n > 0 {
+			return fmt.Errorf("failed to record %d webhook deliveries", n)
+		}
+		if len(deliveries) < limit {
+			return nil
+		}
+	}
+	return nil
+}
+
+// attempt sends a delivery once and records the outcome, it returns false if it could not be recorded
+func (d *Dispatcher) attempt(ctx context.Context, delivery *repositories.WebhookDeliveryModel) bool {
+	logger := d.logger.With(zap.String("delivery_id", delivery.ID), zap.String("subscription_id", delivery.SubscriptionID),
+		zap.String("event_id", delivery.EventID))
+
+	now := time.Now()
+	subscription, err := d.store.GetSubscription(ctx, delivery.SubscriptionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		delivery.UpdatedAt = now.Unix()
+		delivery.Status = DeliveryFailed
+		delivery.Error = "subscription not found"
+		return d.save(ctx, logger, delivery)
+	}
+	if err != nil {
+		logger.Error("Failed to get webhook subscription", zap.Error(err))
+		return false
+	}
+
+	delivery.UpdatedAt = now.Unix()
+	if !subscription.Active {
+		delivery.Status = DeliveryFailed
+		delivery.Error = "subscription deleted"
+		return d.save(ctx, logger, delivery)
+	}
+
+	status, err := d.post(ctx, subscription, delivery, now)
+	delivery.Attempts++
+	delivery.LastAttemptAt = now.Unix()
+	delivery.ResponseStatus = uint16(status)
+	delivery.Error = ""
+	switch {
+	case err == nil:
+		delivery.Status = DeliveryDelivered
+		logger.Debug("Webhook delivered", zap.Int("status", status))
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = DeliveryFailed
+		delivery.Error = err.Error()
+		logger.Warn("Webhook delivery failed, giving up", zap.Error(err), zap.Uint32("attempts", delivery.Attempts))
+	default:
+		delivery.Error = err.Error()
+		delivery.NextAttemptAt = now.Add(d.cfg.Backoff(delivery.Attempts)).Unix()
+		logger.Info("Webhook delivery failed, retrying", zap.Error(err), zap.Uint32("attempts", delivery.Attempts),
+			zap.Int64("next_attempt_at", delivery.NextAttemptAt))
+	}
+	return d.save(ctx, logger, delivery)
+}
+
+// post sends the payload and returns the response status, an error for anything but 2xx
+func (d *Dispatcher) post(ctx context.Context, subscription *repositories.WebhookSubscriptionModel,
+	delivery *repositories.WebhookDeliveryModel, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "aqua402-webhooks/1")
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, now, body))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) save(ctx context.Context, logger *zap.Logger, delivery *repositories.WebhookDeliveryModel) bool {
+	if err := d.store.SaveDelivery(ctx, delivery); err != nil {
+		logger.Error("Failed to save webhook delivery", zap.Error(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+)
+
+// Lookup finds the parties of the RFQs, auctions and credit lines events refer to by ID, since events
+// such as quote_submitted or auction_finalized don't carry the borrower. Unknown IDs return sql.ErrNoRows.
+type Lookup interface {
+	RFQParties(ctx context.Context, chainID, id uint64) ([]string, error)
+	AuctionParties(ctx context.Context, chainID, id uint64) ([]string, error)
+	CreditLineParties(ctx context.Context, chainID, id uint64) ([]string, error)
+}
+
+// RepositoryLookup reads the parties from ClickHouse, a nil repository finds nobody
+type RepositoryLookup struct {
+	RFQs     *repositories.RFQRepository
+	Auctions *repositories.AuctionRepository
+	Credit   *repositories.CreditRepository
+}
+
+func (l *RepositoryLookup) RFQParties(ctx context.Context, chainID, id uint64) ([]string, error) {
+	if l.RFQs == nil {
+		return nil, nil
+	}
+	rfq, err := l.RFQs.GetRFQ(ctx, chainID, id)
+	if err != nil {
+		return nil, err
+	}
+	return []string{rfq.BorrowerAddress}, nil
+}
+
+func (l *RepositoryLookup) AuctionParties(ctx context.Context, chainID, id uint64) ([]string, error) {
+	if l.Auctions == nil {
+		return nil, nil
+	}
+	auction, err := l.Auctions.GetAuction(ctx, chainID, id)
+	if err != nil {
+		return nil, err
+	}
+	return []string{auction.BorrowerAddress}, nil
+}
+
+func (l *RepositoryLookup) CreditLineParties(ctx context.Context, chainID, id uint64) ([]string, error) {
+	if l.Credit == nil {
+		return nil, nil
+	}
+	line, err := l.Credit.GetCreditLine(ctx, chainID, id)
+	if err != nil {
+		return nil, err
+	}
+	return []string{line.BorrowerAddress, line.LenderAddress}, nil
+}
@@ -0,0 +1,452 @@
+// Package webhooks notifies integrators of the on-chain events of their addresses. Subscriptions name an
+// address, a URL and the event types wanted; the worker records a delivery for each matching event it
+// consumes and the dispatcher posts it, signed with the subscription's secret, retrying with exponential
+// backoff until the subscriber answers 2xx.
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
+	"go.uber.org/zap"
+)
+
+// EventTypes are the event types subscriptions can ask for, as published on the rfq.events, rfq.quotes,
+// auction.events, auction.bids and credit.events queues, and by the risk monitor on credit.risk
+var EventTypes = []string{
+	"rfq_created", "quote_submitted", "quote_accepted", "rfq_executed",
+	"auction_created", "bid_placed", "auction_finalized", "auction_settled",
+	"credit_line_opened", "credit_drawn", "credit_repaid",
+	"credit_line_status_changed", "liquidation_case_opened", "liquidation_case_approved",
+	"liquidation_case_rejected", "liquidation_case_executed", "liquidation_case_failed",
+}
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	// DeliveryFailed is a delivery that failed MaxAttempts times or whose subscription was deleted
+	DeliveryFailed = "failed"
+)
+
+const (
+	// DefaultDeliveryLimit and MaxDeliveryLimit bound the delivery log returned at once
+	DefaultDeliveryLimit = 50
+	MaxDeliveryLimit     = 200
+)
+
+// Service manages subscriptions and records the deliveries of events
+type Service struct {
+	store  Store
+	lookup Lookup
+	config Config
+	logger *zap.Logger
+}
+
+// NewService creates a webhook service. lookup finds the parties of the RFQs, auctions and credit lines
+// events refer to by ID; without it only the addresses an event carries are notified. Unless
+// config.AllowPrivateURLs is set, URLs naming a loopback or private address are refused.
+func NewService(store Store, lookup Lookup, config Config, logger *zap.Logger) *Service {
+	return &Service{
+		store:  store,
+		lookup: lookup,
+		config: config,
+		logger: logger,
+	}
+}
+
+type SubscribeRequest struct {
+	ChainID uint64 `json:"-"`
+	// Address is the borrower or lender whose events are delivered
+	Address string `json:"address"`
+	// EventTypes lists the event types delivered, every type when empty
+	EventTypes []string `json:"event_types"`
+	// URL receives the events as POST requests, it must be http or https and not name a private address
+	URL string `json:"url"`
+}
+
+// Validate checks the request and normalizes the address to lower case
+func (req *SubscribeRequest) Validate() error {
+	var v validation.Validator
+	req.Address = strings.ToLower(v.Address("address", req.Address).Hex())
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(EventTypes, eventType) {
+			v.Fail("event_types", "has unknown event type %q, expected one of %s", eventType, strings.Join(EventTypes, ", "))
+		}
+	}
+	v.Required("url", req.URL)
+	if req.URL != "" {
+		u, err := url.Parse(req.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+			v.Fail("url", "must be an absolute http or https URL without credentials")
+		}
+	}
+	return v.Err()
+}
+
+// Subscription is a webhook subscription. Secret is only returned when it is created.
+type Subscription struct {
+	ID         string   `json:"id"`
+	ChainID    uint64   `json:"chain_id"`
+	Address    string   `json:"address"`
+	EventTypes []string `json:"event_types"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	Active     bool     `json:"active"`
+	CreatedAt  int64    `json:"created_at"`
+	UpdatedAt  int64    `json:"updated_at"`
+}
+
+// Delivery is an event sent, or to be sent, to a subscription
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       uint32          `json:"attempts"`
+	NextAttemptAt  int64           `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  int64           `json:"last_attempt_at,omitempty"`
+	ResponseStatus uint16          `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	RedeliveryOf   string          `json:"redelivery_of,omitempty"`
+	CreatedAt      int64           `json:"created_at"`
+	UpdatedAt      int64           `json:"updated_at"`
+}
+
+// Event is the JSON body posted to subscribers. ID is the same for every delivery of an event, subscribers
+// use it to drop the ones they already handled.
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	ChainID   uint64                 `json:"chain_id"`
+	CreatedAt int64                  `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Subscribe creates a subscription with a new secret. The subscribed address owns it: the other methods
+// only find subscriptions of the owner they are given.
+func (s *Service) Subscribe(ctx context.Context, req SubscribeRequest) (*Subscription, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	// Names resolving to private addresses are refused by the dispatcher when it connects
+	if u, _ := url.Parse(req.URL); !s.config.AllowPrivateURLs && privateHost(u.Hostname()) {
+		var v validation.Validator
+		v.Fail("url", "must not point at a loopback, private or link-local address")
+		return nil, v.Err()
+	}
+
+	now := time.Now().Unix()
+	subscription := &repositories.WebhookSubscriptionModel{
+		ID:         randomID(),
+		ChainID:    req.ChainID,
+		Address:    req.Address,
+		EventTypes: append([]string{}, req.EventTypes...),
+		URL:        req.URL,
+		Secret:     "whsec_" + randomID() + randomID(),
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.store.SaveSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	s.logger.Info("Webhook subscription created",
+		zap.String("subscription_id", subscription.ID),
+		zap.String("address", subscription.Address),
+		zap.Strings("event_types", subscription.EventTypes))
+
+	result := toSubscription(subscription)
+	result.Secret = subscription.Secret
+	return result, nil
+}
+
+// GetSubscription returns a subscription of owner, without its secret
+func (s *Service) GetSubscription(ctx context.Context, id, owner string) (*Subscription, error) {
+	subscription, err := s.getSubscription(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+	return toSubscription(subscription), nil
+}
+
+// Unsubscribe deactivates a subscription of owner, its pending deliveries are dropped when due
+func (s *Service) Unsubscribe(ctx context.Context, id, owner string) error {
+	subscription, err := s.getSubscription(ctx, id, owner)
+	if err != nil {
+		return err
+	}
+	if !subscription.Active {
+		return nil
+	}
+	subscription.Active = false
+	subscription.UpdatedAt = time.Now().Unix()
+	if err := s.store.SaveSubscription(ctx, subscription); err != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the delivery log of a subscription of owner, newest first
+func (s *Service) ListDeliveries(ctx context.Context, id, owner string, limit int) ([]*Delivery, error) {
+	if _, err := s.getSubscription(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultDeliveryLimit
+	}
+	deliveries, err := s.store.ListDeliveries(ctx, id, min(limit, MaxDeliveryLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	result := make([]*Delivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = toDelivery(d)
+	}
+	return result, nil
+}
+
+// Redeliver sends the event of a delivery of a subscription of owner again as a new delivery, whatever
+// the status of the first
+func (s *Service) Redeliver(ctx context.Context, id, owner, deliveryID string) (*Delivery, error) {
+	subscription, err := s.getSubscription(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Active {
+		return nil, apperrors.Conflict("webhook subscription %s is deleted", id)
+	}
+	original, err := s.store.GetDelivery(ctx, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && original.SubscriptionID != id) {
+		return nil, apperrors.NotFound("webhook delivery %s not found", deliveryID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	now := time.Now().Unix()
+	redelivery := &repositories.WebhookDeliveryModel{
+		ID:             randomID(),
+		SubscriptionID: id,
+		ChainID:        original.ChainID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		RedeliveryOf:   original.ID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.store.SaveDelivery(ctx, redelivery); err != nil {
+		return nil, fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	return toDelivery(redelivery), nil
+}
+
+// Enqueue records a delivery of an event consumed from a queue for every subscription of its parties.
+// Only on-chain events of EventTypes are delivered, API submissions are not final; events the backend
+// raises itself, such as risk changes, carry an event_id instead of a transaction. An event consumed
+// again is not delivered twice. It returns the number of deliveries recorded.
+func (s *Service) Enqueue(ctx context.Context, body []byte, defaultChainID uint64) (int, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return 0, fmt.Errorf("invalid event: %w", err)
+	}
+	eventType, _ := data["type"].(string)
+	txHash, _ := data["tx_hash"].(string)
+	eventID, _ := data["event_id"].(string)
+	if (txHash == "" && eventID == "") || !slices.Contains(EventTypes, eventType) {
+		return 0, nil
+	}
+	chainID := defaultChainID
+	if id, ok := data["chain_id"].(float64); ok && id > 0 {
+		chainID = uint64(id)
+	}
+	logIndex, _ := data["log_index"].(float64)
+
+	parties, err := s.parties(ctx, chainID, data)
+	if err != nil {
+		return 0, err
+	}
+	if len(parties) == 0 {
+		return 0, nil
+	}
+	subscriptions, err := s.store.ActiveSubscriptions(ctx, chainID, parties)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find webhook subscriptions: %w", err)
+	}
+
+	if eventID == "" {
+		eventID = fmt.Sprintf("%s-%d", strings.ToLower(txHash), int64(logIndex))
+	}
+
+	now := time.Now().Unix()
+	event := Event{
+		ID:        fmt.Sprintf("%d-%s", chainID, eventID),
+		Type:      eventType,
+		ChainID:   chainID,
+		CreatedAt: now,
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for _, subscription := range subscriptions {
+		if len(subscription.EventTypes) > 0 && !slices.Contains(subscription.EventTypes, eventType) {
+			continue
+		}
+		// The first delivery of an event has a fixed ID, so an event consumed again is recognized
+		id := deliveryID(subscription.ID, event.ID)
+		if _, err := s.store.GetDelivery(ctx, id); err == nil {
+			continue
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return enqueued, fmt.Errorf("failed to get webhook delivery: %w", err)
+		}
+
+		delivery := &repositories.WebhookDeliveryModel{
+			ID:             id,
+			SubscriptionID: subscription.ID,
+			ChainID:        chainID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := s.store.SaveDelivery(ctx, delivery); err != nil {
+			return enqueued, fmt.Errorf("failed to save webhook delivery: %w", err)
+		}
+		enqueued++
+	}
+	return enqueued, nil
+}
+
+// parties returns the lower case addresses an event concerns: those it carries, and the borrower and
+// lender of the RFQ, auction or credit line it refers to
+func (s *Service) parties(ctx context.Context, chainID uint64, data map[string]interface{}) ([]string, error) {
+	var parties []string
+	add := func(address string) {
+		address = strings.ToLower(address)
+		if address != "" && !slices.Contains(parties, address) {
+			parties = append(parties, address)
+		}
+	}
+	for _, field := range []string{"borrower", "lender", "winning_lender"} {
+		address, _ := data[field].(string)
+		add(address)
+	}
+	if s.lookup == nil {
+		return parties, nil
+	}
+
+	refs := []struct {
+		field string
+		find  func(ctx context.Context, chainID, id uint64) ([]string, error)
+	}{
+		{"rfq_id", s.lookup.RFQParties},
+		{"auction_id", s.lookup.AuctionParties},
+		{"credit_line_id", s.lookup.CreditLineParties},
+	}
+	for _, ref := range refs {
+		value, _ := data[ref.field].(string)
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		addresses, err := ref.find(ctx, chainID, id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to find the parties of %s %d: %w", ref.field, id, err)
+		}
+		for _, address := range addresses {
+			add(address)
+		}
+	}
+	return parties, nil
+}
+
+// getSubscription returns a subscription of owner. Those of other addresses are not found, so their IDs
+// can't be probed.
+func (s *Service) getSubscription(ctx context.Context, id, owner string) (*repositories.WebhookSubscriptionModel, error) {
+	subscription, err := s.store.GetSubscription(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !strings.EqualFold(subscription.Address, owner)) {
+		return nil, apperrors.NotFound("webhook subscription %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func toSubscription(s *repositories.WebhookSubscriptionModel) *Subscription {
+	eventTypes := s.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return &Subscription{
+		ID:         s.ID,
+		ChainID:    s.ChainID,
+		Address:    s.Address,
+		EventTypes: eventTypes,
+		URL:        s.URL,
+		Active:     s.Active,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+func toDelivery(d *repositories.WebhookDeliveryModel) *Delivery {
+	delivery := &Delivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+	if d.Status == DeliveryPending {
+		delivery.NextAttemptAt = d.NextAttemptAt
+	}
+	return delivery
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func deliveryID(subscriptionID, eventID string) string {
+	sum := sha256.Sum256([]byte(subscriptionID + "/" + eventID))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,69 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a delivery
+const (
+	HeaderSignature = "X-Aqua402-Signature"
+	HeaderEvent     = "X-Aqua402-Event"
+	HeaderEventID   = "X-Aqua402-Event-Id"
+	HeaderDelivery  = "X-Aqua402-Delivery"
+)
+
+// ErrInvalidSignature is returned by Verify for a payload not signed with the secret, or signed too long ago
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the X-Aqua402-Signature header of body sent at t: t=<unix seconds>,v1=<hex HMAC-SHA256 of
+// "<unix seconds>.<body>" keyed with the subscription secret>
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + mac(secret, timestamp, body)
+}
+
+// Verify checks the X-Aqua402-Signature header of body and that it was signed within tolerance of now,
+// which stops a captured delivery from being replayed later. Subscribers in Go can call it as is.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: signed %s ago", ErrInvalidSignature, age.Round(time.Second))
+	}
+
+	expected := mac(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
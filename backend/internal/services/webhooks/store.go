@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+)
+
+// Store keeps subscriptions and deliveries. The API and the worker must share it: the API records
+// subscriptions and redeliveries, the worker's dispatcher sends them.
+type Store interface {
+	SaveSubscription(ctx context.Context, s *repositories.WebhookSubscriptionModel) error
+	// GetSubscription returns sql.ErrNoRows for an unknown ID
+	GetSubscription(ctx context.Context, id string) (*repositories.WebhookSubscriptionModel, error)
+	// ActiveSubscriptions returns the active subscriptions of a chain for any of addresses, in lower case
+	ActiveSubscriptions(ctx context.Context, chainID uint64, addresses []string) ([]*repositories.WebhookSubscriptionModel, error)
+
+	SaveDelivery(ctx context.Context, d *repositories.WebhookDeliveryModel) error
+	// GetDelivery returns sql.ErrNoRows for an unknown ID
+	GetDelivery(ctx context.Context, id string) (*repositories.WebhookDeliveryModel, error)
+	// ListDeliveries returns the latest deliveries of a subscription, newest first
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*repositories.WebhookDeliveryModel, error)
+	// DueDeliveries returns the pending deliveries whose next attempt is due at now, oldest first
+	DueDeliveries(ctx context.Context, now int64, limit int) ([]*repositories.WebhookDeliveryModel, error)
+}
+
+// MemoryStore keeps subscriptions and deliveries in process, for tests and single process deployments
+type MemoryStore struct {
+	mu            sync.Mutex
+	subscriptions map[string]*repositories.WebhookSubscriptionModel
+	deliveries    map[string]*repositories.WebhookDeliveryModel
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		subscriptions: make(map[string]*repositories.WebhookSubscriptionModel),
+		deliveries:    make(map[string]*repositories.WebhookDeliveryModel),
+	}
+}
+
+func (s *MemoryStore) SaveSubscription(ctx context.Context, subscription *repositories.WebhookSubscriptionModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions[subscription.ID] = copySubscription(subscription)
+	return nil
+}
+
+func (s *MemoryStore) GetSubscription(ctx context.Context, id string) (*repositories.WebhookSubscriptionModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subscription, ok := s.subscriptions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return copySubscription(subscription), nil
+}
+
+func (s *MemoryStore) ActiveSubscriptions(ctx context.Context, chainID uint64, addresses []string) ([]*repositories.WebhookSubscriptionModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var subscriptions []*repositories.WebhookSubscriptionModel
+	for _, subscription := range s.subscriptions {
+		if subscription.Active && subscription.ChainID == chainID && slices.Contains(addresses, subscription.Address) {
+			subscriptions = append(subscriptions, copySubscription(subscription))
+		}
+	}
+	return subscriptions, nil
+}
+
+func (s *MemoryStore) SaveDelivery(ctx context.Context, d *repositories.WebhookDeliveryModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *d
+	s.deliveries[d.ID] = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetDelivery(ctx context.Context, id string) (*repositories.WebhookDeliveryModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (s *MemoryStore) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*repositories.WebhookDeliveryModel, error) {
+	deliveries := s.filter(func(d *repositories.WebhookDeliveryModel) bool { return d.SubscriptionID == subscriptionID })
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].CreatedAt != deliveries[j].CreatedAt {
+			return deliveries[i].CreatedAt > deliveries[j].CreatedAt
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries[:min(limit, len(deliveries))], nil
+}
+
+func (s *MemoryStore) DueDeliveries(ctx context.Context, now int64, limit int) ([]*repositories.WebhookDeliveryModel, error) {
+	deliveries := s.filter(func(d *repositories.WebhookDeliveryModel) bool {
+		return d.Status == DeliveryPending && d.NextAttemptAt <= now
+	})
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].NextAttemptAt != deliveries[j].NextAttemptAt {
+			return deliveries[i].NextAttemptAt < deliveries[j].NextAttemptAt
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries[:min(limit, len(deliveries))], nil
+}
+
+func (s *MemoryStore) filter(keep func(d *repositories.WebhookDeliveryModel) bool) []*repositories.WebhookDeliveryModel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deliveries []*repositories.WebhookDeliveryModel
+	for _, d := range s.deliveries {
+		if keep(d) {
+			copied := *d
+			deliveries = append(deliveries, &copied)
+		}
+	}
+	return deliveries
+}
+
+func copySubscription(s *repositories.WebhookSubscriptionModel) *repositories.WebhookSubscriptionModel {
+	copied := *s
+	copied.EventTypes = append([]string(nil), s.EventTypes...)
+	return &copied
+}
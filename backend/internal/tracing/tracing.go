@@ -0,0 +1,122 @@
+// Package tracing sets up OpenTelemetry tracing for the API and the worker. Trace context travels in
+// W3C traceparent headers, over HTTP and in the headers of RabbitMQ messages.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporter selects where spans are sent
+type Exporter string
+
+const (
+	// ExporterNone records no spans, trace context is still propagated
+	ExporterNone Exporter = "none"
+	// ExporterOTLP sends spans over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT (default localhost:4318)
+	ExporterOTLP Exporter = "otlp"
+	// ExporterStdout prints spans as JSON, for local development
+	ExporterStdout Exporter = "stdout"
+)
+
+// Config controls tracing. The OTLP exporter, the sampler and the resource are further configured by
+// the standard OTEL_* variables, read by the SDK.
+type Config struct {
+	Exporter Exporter
+}
+
+// ConfigFromEnv reads OTEL_TRACES_EXPORTER (otlp, stdout or console, or none, the default)
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Exporter: ExporterNone}
+	switch v := os.Getenv("OTEL_TRACES_EXPORTER"); v {
+	case "", "none":
+	case "otlp":
+		cfg.Exporter = ExporterOTLP
+	case "stdout", "console":
+		cfg.Exporter = ExporterStdout
+	default:
+		return cfg, fmt.Errorf("invalid OTEL_TRACES_EXPORTER %q, must be otlp, stdout or none", v)
+	}
+	return cfg, nil
+}
+
+// tracer creates the spans of the backend. It follows the provider installed by Setup.
+var tracer = otel.Tracer("github.com/Pagga-Wallet/aqua402")
+
+// Setup installs the tracer provider and the trace context propagator. service names the process
+// unless OTEL_SERVICE_NAME is set. The returned function flushes the spans left and stops exporting.
+func Setup(ctx context.Context, cfg Config, service string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(service)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the %s resource: %w", service, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name, a child of the span in ctx if there is one
+func Start(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End ends span, marking it failed when err is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject writes the trace context of ctx to carrier
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+// Extract returns ctx with the trace context read from carrier
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// TraceID returns the ID of the trace ctx belongs to, empty when there is none
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
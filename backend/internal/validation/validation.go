@@ -0,0 +1,160 @@
+// Package validation checks request fields and collects every problem as an apperrors validation error
+package validation
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Bounds enforced on market requests
+const (
+	MaxRateBps = 10_000
+
+	MinDuration        = time.Hour
+	MaxDuration        = 5 * 365 * 24 * time.Hour
+	MinBiddingDuration = time.Minute
+	MaxBiddingDuration = 30 * 24 * time.Hour
+
+	// WeiDecimals parses amounts given in base units (wei) that cannot have a fractional part
+	WeiDecimals = 0
+	// EtherDecimals parses amounts given in ether
+	EtherDecimals = 18
+)
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ParseAddress parses a 0x-prefixed hex address. Mixed-case addresses must carry a valid EIP-55
+// checksum, all lower or upper case ones are accepted as is. The zero address is rejected.
+func ParseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) || !strings.HasPrefix(s, "0x") {
+		return common.Address{}, fmt.Errorf("must be a 0x-prefixed 20 byte hex address")
+	}
+	address := common.HexToAddress(s)
+	hex := s[2:]
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && s != address.Hex() {
+		return common.Address{}, fmt.Errorf("has an invalid EIP-55 checksum")
+	}
+	if address == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("must not be the zero address")
+	}
+	return address, nil
+}
+
+// ParseAmount parses a decimal amount of a token with decimals into base units,
+// e.g. "1.5" with 6 decimals is 1500000. The result fits a uint256.
+func ParseAmount(s string, decimals uint8) (*big.Int, error) {
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || !digits(whole) || (hasFrac && (frac == "" || !digits(frac))) {
+		if decimals == 0 {
+			return nil, fmt.Errorf("must be a non-negative integer")
+		}
+		return nil, fmt.Errorf("must be a non-negative decimal number")
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > int(decimals) {
+		return nil, fmt.Errorf("has more than %d decimals", decimals)
+	}
+
+	v, _ := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(decimals)-len(frac)), 10)
+	if v.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("is too large")
+	}
+	return v, nil
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Validator collects field errors. Its methods return the parsed value, or the zero value when invalid.
+type Validator struct {
+	fields []apperrors.FieldError
+}
+
+// Fail rejects field with a message
+func (v *Validator) Fail(field, format string, args ...interface{}) {
+	v.fields = append(v.fields, apperrors.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Required rejects an empty field
+func (v *Validator) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Fail(field, "is required")
+	}
+}
+
+// Address parses a required address, see ParseAddress
+func (v *Validator) Address(field, value string) common.Address {
+	if value == "" {
+		v.Fail(field, "is required")
+		return common.Address{}
+	}
+	address, err := ParseAddress(value)
+	if err != nil {
+		v.Fail(field, "%s", err)
+	}
+	return address
+}
+
+// Amount parses a required, strictly positive amount, see ParseAmount
+func (v *Validator) Amount(field, value string, decimals uint8) *big.Int {
+	if value == "" {
+		v.Fail(field, "is required")
+		return nil
+	}
+	amount, err := ParseAmount(value, decimals)
+	if err != nil {
+		v.Fail(field, "%s", err)
+		return nil
+	}
+	if amount.Sign() == 0 {
+		v.Fail(field, "must be greater than 0")
+		return nil
+	}
+	return amount
+}
+
+// OptionalAmount parses an amount that may be empty or zero
+func (v *Validator) OptionalAmount(field, value string, decimals uint8) *big.Int {
+	if value == "" {
+		return new(big.Int)
+	}
+	amount, err := ParseAmount(value, decimals)
+	if err != nil {
+		v.Fail(field, "%s", err)
+		return nil
+	}
+	return amount
+}
+
+// RateBps checks an annual rate in basis points is between 1 and MaxRateBps
+func (v *Validator) RateBps(field string, value uint16) {
+	if value == 0 || value > MaxRateBps {
+		v.Fail(field, "must be between 1 and %d", MaxRateBps)
+	}
+}
+
+// Duration checks a duration in seconds is within [min, max]
+func (v *Validator) Duration(field string, seconds uint64, min, max time.Duration) {
+	if seconds < uint64(min.Seconds()) || seconds > uint64(max.Seconds()) {
+		v.Fail(field, "must be between %d and %d seconds", uint64(min.Seconds()), uint64(max.Seconds()))
+	}
+}
+
+// Err returns the collected field errors as a validation error, nil if there are none
+func (v *Validator) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return apperrors.Invalid("request validation failed", v.fields...)
+}
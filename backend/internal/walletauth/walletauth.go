@@ -0,0 +1,74 @@
+// Package walletauth defines the wallet signatures authenticating API requests, made by the Go client's
+// WalletAuth and checked by the API's AuthMiddleware
+package walletauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/x402"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Wallet signature headers
+const (
+	HeaderAddress   = "X-Aqua402-Address"
+	HeaderTimestamp = "X-Aqua402-Timestamp"
+	HeaderSignature = "X-Aqua402-Signature"
+)
+
+// MaxSignatureAge is how far the timestamp of a signature may be from the server clock
+const MaxSignatureAge = 5 * time.Minute
+
+var (
+	// ErrMissingSignature is returned when a request carries no wallet signature headers
+	ErrMissingSignature = errors.New("missing wallet signature")
+	// ErrInvalidSignature is returned when a wallet signature does not match its address
+	ErrInvalidSignature = errors.New("invalid wallet signature")
+	// ErrStaleSignature is returned when a wallet signature is older or newer than MaxSignatureAge
+	ErrStaleSignature = errors.New("wallet signature timestamp out of range")
+)
+
+// Message is what a wallet signs for a request: the method, the request URI with its query, the unix
+// timestamp and the hex SHA-256 of the body on separate lines
+func Message(method, requestURI string, timestamp int64, body []byte) []byte {
+	digest := sha256.Sum256(body)
+	return []byte(fmt.Sprintf("aqua402 request\n%s %s\n%d\n%s", method, requestURI, timestamp, hex.EncodeToString(digest[:])))
+}
+
+// Verify checks the EIP-191 personal signature of Message sent in the headers of a request whose body
+// was read into body, and returns the signing address
+func Verify(req *http.Request, body []byte, now time.Time) (common.Address, error) {
+	address := req.Header.Get(HeaderAddress)
+	signature := req.Header.Get(HeaderSignature)
+	if address == "" || signature == "" || req.Header.Get(HeaderTimestamp) == "" {
+		return common.Address{}, ErrMissingSignature
+	}
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("%w: malformed address", ErrInvalidSignature)
+	}
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > MaxSignatureAge || age < -MaxSignatureAge {
+		return common.Address{}, ErrStaleSignature
+	}
+
+	hash := accounts.TextHash(Message(req.Method, req.URL.RequestURI(), timestamp, body))
+	signer, err := x402.RecoverSigner(hash, signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if signer != common.HexToAddress(address) {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return signer, nil
+}
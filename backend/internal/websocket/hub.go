@@ -62,6 +62,21 @@ func (h *Hub) Register(client *Client) {
 	h.register <- client
 }
 
+// Stats returns the connected clients and the distinct topics they are subscribed to
+func (h *Hub) Stats() (clients, topics int) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	subscribed := make(map[string]struct{})
+	for client := range h.clients {
+		client.mu.RLock()
+		for topic := range client.topics {
+			subscribed[topic] = struct{}{}
+		}
+		client.mu.RUnlock()
+	}
+	return len(h.clients), len(subscribed)
+}
+
 func (h *Hub) Broadcast(message interface{}) {
 	data, err := json.Marshal(message)
 	if err != nil {
@@ -0,0 +1,16 @@
+package chains
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying chain
+func NewContext(ctx context.Context, chain *Chain) context.Context {
+	return context.WithValue(ctx, contextKey{}, chain)
+}
+
+// FromContext returns the chain stored by NewContext
+func FromContext(ctx context.Context) (*Chain, bool) {
+	chain, ok := ctx.Value(contextKey{}).(*Chain)
+	return chain, ok
+}
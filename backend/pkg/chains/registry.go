@@ -0,0 +1,227 @@
+// Package chains describes the EVM chains aqua402 is deployed on: their RPC endpoints, contracts,
+// confirmation depth and tokens. Every stored row and published event carries the ID of its chain.
+package chains
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// HardhatChainID is the chain ID of the local Hardhat node, the default chain when none is configured
+const HardhatChainID = 1337
+
+// ErrUnknownChain is returned for a chain ID or name missing from the registry
+var ErrUnknownChain = errors.New("unknown chain")
+
+// Token is an asset on a chain. Address is empty for the native asset.
+type Token struct {
+	Symbol   string `json:"symbol"`
+	Address  string `json:"address,omitempty"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// Contracts are the aqua402 contract addresses on a chain. Only RFQ and Auction are required for
+// event monitoring, the others are tracked when set.
+type Contracts struct {
+	RFQ     string `json:"rfq,omitempty"`
+	Auction string `json:"auction,omitempty"`
+	Credit  string `json:"credit,omitempty"`
+	Finance string `json:"finance,omitempty"`
+	Aqua    string `json:"aqua,omitempty"`
+}
+
+// Chain is one deployment of the aqua402 contracts
+type Chain struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+	// RPCURLs are tried in turn by the chain's client, empty for EVM_RPC_URL
+	RPCURLs   []string  `json:"rpc_urls,omitempty"`
+	Contracts Contracts `json:"contracts"`
+	// Confirmations is how many blocks an event waits for before it is processed
+	Confirmations uint64 `json:"confirmations"`
+	NativeToken   Token  `json:"native_token"`
+	StableToken   *Token `json:"stable_token,omitempty"`
+}
+
+// ClientConfig returns base with the chain's RPC endpoints, base's own endpoints are kept when the
+// chain lists none
+func (c *Chain) ClientConfig(base evm.ClientConfig) evm.ClientConfig {
+	if len(c.RPCURLs) > 0 {
+		base.URLs = c.RPCURLs
+	}
+	return base
+}
+
+// Public returns the chain without its RPC endpoints, which may embed provider API keys
+func (c *Chain) Public() Chain {
+	public := *c
+	public.RPCURLs = nil
+	return public
+}
+
+// Registry holds the configured chains, one of them the default used when a request names none
+type Registry struct {
+	chains       []*Chain
+	byID         map[uint64]*Chain
+	byName       map[string]*Chain
+	defaultChain *Chain
+}
+
+// NewRegistry validates chains and returns their registry. defaultRef is the ID or name of the
+// default chain, empty for the first one.
+func NewRegistry(chains []Chain, defaultRef string) (*Registry, error) {
+	if len(chains) == 0 {
+		return nil, errors.New("no chains configured")
+	}
+	r := &Registry{byID: make(map[uint64]*Chain), byName: make(map[string]*Chain)}
+	for i := range chains {
+		chain := chains[i]
+		chain.Name = strings.ToLower(strings.TrimSpace(chain.Name))
+		if err := chain.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := r.byID[chain.ID]; ok {
+			return nil, fmt.Errorf("chain %d is configured twice", chain.ID)
+		}
+		if _, ok := r.byName[chain.Name]; ok {
+			return nil, fmt.Errorf("chain name %q is used twice", chain.Name)
+		}
+		r.chains = append(r.chains, &chain)
+		r.byID[chain.ID] = &chain
+		r.byName[chain.Name] = &chain
+	}
+
+	r.defaultChain = r.chains[0]
+	if defaultRef != "" {
+		chain, err := r.Get(defaultRef)
+		if err != nil {
+			return nil, fmt.Errorf("default chain: %w", err)
+		}
+		r.defaultChain = chain
+	}
+	return r, nil
+}
+
+func (c *Chain) validate() error {
+	if c.ID == 0 {
+		return errors.New("chain ID is required")
+	}
+	if c.Name == "" {
+		c.Name = strconv.FormatUint(c.ID, 10)
+	}
+	if _, err := strconv.ParseUint(c.Name, 10, 64); err == nil && c.Name != strconv.FormatUint(c.ID, 10) {
+		return fmt.Errorf("chain %d: a numeric name must be the chain ID", c.ID)
+	}
+	for name, address := range map[string]string{
+		"rfq": c.Contracts.RFQ, "auction": c.Contracts.Auction, "credit": c.Contracts.Credit,
+		"finance": c.Contracts.Finance, "aqua": c.Contracts.Aqua,
+	} {
+		if address != "" && !common.IsHexAddress(address) {
+			return fmt.Errorf("chain %s: invalid %s contract address %q", c.Name, name, address)
+		}
+	}
+	if c.NativeToken.Symbol == "" {
+		c.NativeToken = Token{Symbol: "ETH", Decimals: 18}
+	}
+	if c.StableToken != nil && !common.IsHexAddress(c.StableToken.Address) {
+		return fmt.Errorf("chain %s: invalid stable token address %q", c.Name, c.StableToken.Address)
+	}
+	return nil
+}
+
+// Default returns the chain used when a request names none
+func (r *Registry) Default() *Chain {
+	return r.defaultChain
+}
+
+// Get returns a chain by ID or name, the default chain for an empty ref
+func (r *Registry) Get(ref string) (*Chain, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if ref == "" {
+		return r.defaultChain, nil
+	}
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		return r.ByID(id)
+	}
+	chain, ok := r.byName[ref]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownChain, ref)
+	}
+	return chain, nil
+}
+
+// ByID returns a chain by ID
+func (r *Registry) ByID(id uint64) (*Chain, error) {
+	chain, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownChain, id)
+	}
+	return chain, nil
+}
+
+// All returns the chains in configuration order
+func (r *Registry) All() []*Chain {
+	return append([]*Chain(nil), r.chains...)
+}
+
+// registryFile is the CHAINS_FILE format
+type registryFile struct {
+	Default string  `json:"default"`
+	Chains  []Chain `json:"chains"`
+}
+
+// LoadFromEnv reads the registry from the JSON file at CHAINS_FILE, in which ${VAR} references are
+// expanded so RPC API keys can stay in the environment. Without CHAINS_FILE the registry holds a single
+// chain described by CHAIN_ID (default 1337), CHAIN_NAME (hardhat), CHAIN_CONFIRMATIONS (0) and the
+// RFQ_CONTRACT_ADDRESS, AUCTION_CONTRACT_ADDRESS, X402_CREDIT_CONTRACT_ADDRESS,
+// AGENT_FINANCE_CONTRACT_ADDRESS and AQUA_CONTRACT_ADDRESS variables, using the EVM_RPC_URL endpoints. DEFAULT_CHAIN overrides the file's default chain.
+func LoadFromEnv() (*Registry, error) {
+	if path := os.Getenv("CHAINS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CHAINS_FILE: %w", err)
+		}
+		var file registryFile
+		if err := json.Unmarshal([]byte(os.ExpandEnv(string(data))), &file); err != nil {
+			return nil, fmt.Errorf("invalid CHAINS_FILE %s: %w", path, err)
+		}
+		if ref := os.Getenv("DEFAULT_CHAIN"); ref != "" {
+			file.Default = ref
+		}
+		return NewRegistry(file.Chains, file.Default)
+	}
+
+	chain := Chain{
+		ID:   HardhatChainID,
+		Name: os.Getenv("CHAIN_NAME"),
+		Contracts: Contracts{
+			RFQ:     os.Getenv("RFQ_CONTRACT_ADDRESS"),
+			Auction: os.Getenv("AUCTION_CONTRACT_ADDRESS"),
+			Credit:  os.Getenv("X402_CREDIT_CONTRACT_ADDRESS"),
+			Finance: os.Getenv("AGENT_FINANCE_CONTRACT_ADDRESS"),
+			Aqua:    os.Getenv("AQUA_CONTRACT_ADDRESS"),
+		},
+	}
+	var err error
+	if v := os.Getenv("CHAIN_ID"); v != "" {
+		if chain.ID, err = strconv.ParseUint(v, 10, 64); err != nil || chain.ID == 0 {
+			return nil, fmt.Errorf("invalid CHAIN_ID %q", v)
+		}
+	}
+	if chain.Name == "" && chain.ID == HardhatChainID {
+		chain.Name = "hardhat"
+	}
+	if v := os.Getenv("CHAIN_CONFIRMATIONS"); v != "" {
+		if chain.Confirmations, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid CHAIN_CONFIRMATIONS %q", v)
+		}
+	}
+	return NewRegistry([]Chain{chain}, os.Getenv("DEFAULT_CHAIN"))
+}
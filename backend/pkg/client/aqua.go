@@ -0,0 +1,24 @@
+package client
+
+import "context"
+
+// ConnectLiquidity connects a lender's tokens to Aqua. It is not retried.
+func (c *Client) ConnectLiquidity(ctx context.Context, req ConnectLiquidityRequest) error {
+	_, err := c.do(ctx, call{route: routeConnectLiquidity, body: req}, nil)
+	return err
+}
+
+// WithdrawLiquidity withdraws a lender's unreserved liquidity from Aqua. It is not retried.
+func (c *Client) WithdrawLiquidity(ctx context.Context, req WithdrawLiquidityRequest) error {
+	_, err := c.do(ctx, call{route: routeWithdrawLiquidity, body: req}, nil)
+	return err
+}
+
+// GetLiquidity returns the Aqua liquidity of a lender
+func (c *Client) GetLiquidity(ctx context.Context, lender string) (*Liquidity, error) {
+	liquidity := new(Liquidity)
+	if _, err := c.do(ctx, call{route: routeGetLiquidity, params: []string{lender}}, liquidity); err != nil {
+		return nil, err
+	}
+	return liquidity, nil
+}
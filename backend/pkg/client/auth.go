@@ -0,0 +1,80 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/walletauth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Wallet signature headers
+const (
+	HeaderAddress   = walletauth.HeaderAddress
+	HeaderTimestamp = walletauth.HeaderTimestamp
+	HeaderSignature = walletauth.HeaderSignature
+)
+
+// HeaderOperatorToken authenticates operator routes
+const HeaderOperatorToken = "X-Operator-Token"
+
+// MaxSignatureAge is how far the timestamp of a wallet signature may be from the server clock
+const MaxSignatureAge = walletauth.MaxSignatureAge
+
+// Auth authenticates a request before each attempt. body is the encoded request body, nil if it has none.
+type Auth interface {
+	Authenticate(req *http.Request, body []byte, now time.Time) error
+}
+
+// BearerToken sends a static Authorization: Bearer token
+type BearerToken string
+
+func (t BearerToken) Authenticate(req *http.Request, body []byte, now time.Time) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// OperatorToken sends the X-Operator-Token of operator routes
+type OperatorToken string
+
+func (t OperatorToken) Authenticate(req *http.Request, body []byte, now time.Time) error {
+	req.Header.Set(HeaderOperatorToken, string(t))
+	return nil
+}
+
+// WalletAuth signs every request with the key of a wallet. The signature is an EIP-191 personal
+// signature of AuthMessage, sent with the address and timestamp in the X-Aqua402-* headers.
+type WalletAuth struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewWalletAuth signs requests as the owner of key
+func NewWalletAuth(key *ecdsa.PrivateKey) *WalletAuth {
+	return &WalletAuth{key: key}
+}
+
+// Address returns the address requests are signed as
+func (w *WalletAuth) Address() common.Address {
+	return crypto.PubkeyToAddress(w.key.PublicKey)
+}
+
+func (w *WalletAuth) Authenticate(req *http.Request, body []byte, now time.Time) error {
+	timestamp := now.Unix()
+	signature, err := SignMessage(w.key, AuthMessage(req.Method, req.URL.RequestURI(), timestamp, body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderAddress, w.Address().Hex())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, signature)
+	return nil
+}
+
+// AuthMessage is the message a wallet signs for a request: the method, the request URI with its
+// query, the unix timestamp and the hex SHA-256 of the body on separate lines
+func AuthMessage(method, requestURI string, timestamp int64, body []byte) []byte {
+	return walletauth.Message(method, requestURI, timestamp, body)
+}
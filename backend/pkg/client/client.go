@@ -0,0 +1,346 @@
+// Package client is a Go SDK for the aqua402 API: typed methods for RFQs,
+// quotes, auctions, bids, Aqua liquidity, the faucet and credit lines, wallet
+// signature auth, retries that reuse one Idempotency-Key across attempts and
+// a WebSocket subscription helper. Paths and models follow the OpenAPI spec
+// in backend/docs, which the tests check them against.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderIdempotencyKey makes retried POST requests replay the first response
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderTotalCount and HeaderNextCursor carry the pagination of list endpoints
+	HeaderTotalCount = "X-Total-Count"
+	HeaderNextCursor = "X-Next-Cursor"
+)
+
+// Client calls the aqua402 API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	auth       Auth
+	chain      string
+	retry      RetryPolicy
+	now        func() time.Time
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests with httpClient instead of http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithAuth authenticates every request with auth
+func WithAuth(auth Auth) Option {
+	return func(c *Client) { c.auth = auth }
+}
+
+// WithChain sets the chain query parameter, the ID or name of a chain listed by GET /chains.
+// Requests go to the API's default chain without it.
+func WithChain(chain string) Option {
+	return func(c *Client) { c.chain = chain }
+}
+
+// WithRetry replaces DefaultRetryPolicy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// New creates a client of the API at baseURL, e.g. "http://localhost:8080/api/v1"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	return c
+}
+
+// OnChain returns a copy of the client sending requests to another chain
+func (c *Client) OnChain(chain string) *Client {
+	copied := *c
+	copied.chain = chain
+	return &copied
+}
+
+// Error is an error response of the API, decoded from its RFC 7807 problem details
+type Error struct {
+	Type       string        `json:"type"`
+	Title      string        `json:"title"`
+	Status     int           `json:"status"`
+	Detail     string        `json:"detail,omitempty"`
+	Instance   string        `json:"instance,omitempty"`
+	Errors     []FieldError  `json:"errors,omitempty"`
+	RetryAfter time.Duration `json:"-"`
+}
+
+// FieldError is a validation error of a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("aqua402: %d %s", e.Status, e.Title)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	for _, field := range e.Errors {
+		msg += fmt.Sprintf("; %s: %s", field.Field, field.Message)
+	}
+	return msg
+}
+
+// IsStatus reports whether err is an API error with the given HTTP status
+func IsStatus(err error, status int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Status == status
+}
+
+// Page is a page of a list endpoint. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	Total      uint64
+	NextCursor string
+}
+
+// ListOptions filter, sort and paginate list endpoints. Zero values are left out.
+type ListOptions struct {
+	Status         string
+	Borrower       string
+	Lender         string
+	AmountMin      string
+	AmountMax      string
+	DurationMin    uint64
+	DurationMax    uint64
+	CollateralType *uint8
+	// CreatedFrom and CreatedTo are unix seconds
+	CreatedFrom int64
+	CreatedTo   int64
+	// Sort is a field name, prefixed with "-" for descending order
+	Sort   string
+	Limit  int
+	Cursor string
+}
+
+func (o ListOptions) values() url.Values {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	setUint := func(key string, value uint64) {
+		if value != 0 {
+			q.Set(key, strconv.FormatUint(value, 10))
+		}
+	}
+	set("status", o.Status)
+	set("borrower", o.Borrower)
+	set("lender", o.Lender)
+	set("amount_min", o.AmountMin)
+	set("amount_max", o.AmountMax)
+	setUint("duration_min", o.DurationMin)
+	setUint("duration_max", o.DurationMax)
+	if o.CollateralType != nil {
+		q.Set("collateral_type", strconv.FormatUint(uint64(*o.CollateralType), 10))
+	}
+	if o.CreatedFrom != 0 {
+		q.Set("created_from", strconv.FormatInt(o.CreatedFrom, 10))
+	}
+	if o.CreatedTo != 0 {
+		q.Set("created_to", strconv.FormatInt(o.CreatedTo, 10))
+	}
+	set("sort", o.Sort)
+	if o.Limit != 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	set("cursor", o.Cursor)
+	return q
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey sets the Idempotency-Key of the requests made with ctx. Without it the client
+// generates a key per call, so only its own retries are deduplicated.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// call describes one API call
+type call struct {
+	route Route
+	// params fill in the placeholders of the route path
+	params []string
+	query  url.Values
+	body   interface{}
+}
+
+func (c *Client) url(cl call) (string, error) {
+	path := cl.route.Path
+	for _, param := range cl.params {
+		start := strings.IndexByte(path, '{')
+		end := strings.IndexByte(path, '}')
+		if start < 0 || end < start {
+			return "", fmt.Errorf("too many parameters for %s", cl.route.Path)
+		}
+		path = path[:start] + url.PathEscape(param) + path[end+1:]
+	}
+	if strings.ContainsRune(path, '{') {
+		return "", fmt.Errorf("missing parameters for %s", cl.route.Path)
+	}
+
+	query := url.Values{}
+	for key, values := range cl.query {
+		query[key] = values
+	}
+	if c.chain != "" && query.Get("chain") == "" {
+		query.Set("chain", c.chain)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.baseURL + path, nil
+}
+
+// do sends the call, retrying it as allowed by the retry policy, and decodes a 2xx response into out
+func (c *Client) do(ctx context.Context, cl call, out interface{}) (http.Header, error) {
+	target, err := c.url(cl)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if cl.body != nil {
+		if body, err = json.Marshal(cl.body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	// One key covers every attempt of the call, so the API applies it once
+	key := ""
+	if cl.route.Idempotent {
+		key, _ = ctx.Value(idempotencyKey{}).(string)
+		if key == "" {
+			key = newIdempotencyKey()
+		}
+	}
+	retryable := cl.route.Method == http.MethodGet || key != ""
+
+	for attempt := 1; ; attempt++ {
+		header, err := c.send(ctx, cl.route.Method, target, body, key, out)
+		if err == nil || !retryable || attempt >= c.retry.MaxAttempts || !c.retry.retries(err) {
+			return header, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.retry.delay(attempt, err)):
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, body []byte, key string, out interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	if c.auth != nil {
+		// Signatures are made per attempt, over a fresh timestamp
+		if err := c.auth.Authenticate(req, body, c.now()); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.Header, decodeError(resp)
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.Header, fmt.Errorf("failed to decode %s %s response: %w", method, req.URL.Path, err)
+		}
+	}
+	return resp.Header, nil
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if len(data) > 0 && json.Unmarshal(data, apiErr) != nil {
+		apiErr.Detail = strings.TrimSpace(string(data))
+	}
+	// The body describes the status, the status line is the one to trust
+	apiErr.Status = resp.StatusCode
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
+
+// page decodes a list response with its pagination headers
+func page[T any](ctx context.Context, c *Client, cl call) (*Page[T], error) {
+	items := make([]T, 0)
+	header, err := c.do(ctx, cl, &items)
+	if err != nil {
+		return nil, err
+	}
+	result := &Page[T]{Items: items, NextCursor: header.Get(HeaderNextCursor)}
+	if total := header.Get(HeaderTotalCount); total != "" {
+		if result.Total, err = strconv.ParseUint(total, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid %s header: %w", HeaderTotalCount, err)
+		}
+	}
+	return result, nil
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func formatID(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
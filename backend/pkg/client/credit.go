@@ -0,0 +1,21 @@
+package client
+
+import "context"
+
+// GetSchedule returns the position and repayment schedule of a credit line
+func (c *Client) GetSchedule(ctx context.Context, creditLineID uint64) (*Schedule, error) {
+	schedule := new(Schedule)
+	if _, err := c.do(ctx, call{route: routeSchedule, params: []string{formatID(creditLineID)}}, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetObligations returns what a borrower owes across all credit lines
+func (c *Client) GetObligations(ctx context.Context, borrower string) (*Obligations, error) {
+	obligations := new(Obligations)
+	if _, err := c.do(ctx, call{route: routeObligations, params: []string{borrower}}, obligations); err != nil {
+		return nil, err
+	}
+	return obligations, nil
+}
@@ -0,0 +1,21 @@
+package client
+
+import "context"
+
+// RequestTokens asks the faucet for test tokens. The faucet only dispenses on its own chain.
+func (c *Client) RequestTokens(ctx context.Context, req RequestTokensRequest) (*Grant, error) {
+	grant := new(Grant)
+	if _, err := c.do(ctx, call{route: routeRequestTokens, body: req}, grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// FaucetTokens lists the assets the faucet dispenses
+func (c *Client) FaucetTokens(ctx context.Context) ([]TokenInfo, error) {
+	var tokens []TokenInfo
+	if _, err := c.do(ctx, call{route: routeFaucetTokens}, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
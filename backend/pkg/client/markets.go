@@ -0,0 +1,79 @@
+package client
+
+import "context"
+
+// CreateRFQ submits an RFQ
+func (c *Client) CreateRFQ(ctx context.Context, req CreateRFQRequest) (*RFQ, error) {
+	rfq := new(RFQ)
+	if _, err := c.do(ctx, call{route: routeCreateRFQ, body: req}, rfq); err != nil {
+		return nil, err
+	}
+	return rfq, nil
+}
+
+// ListRFQs lists RFQs matching opts
+func (c *Client) ListRFQs(ctx context.Context, opts ListOptions) (*Page[RFQ], error) {
+	return page[RFQ](ctx, c, call{route: routeListRFQs, query: opts.values()})
+}
+
+// GetRFQ returns an RFQ
+func (c *Client) GetRFQ(ctx context.Context, id uint64) (*RFQ, error) {
+	rfq := new(RFQ)
+	if _, err := c.do(ctx, call{route: routeGetRFQ, params: []string{formatID(id)}}, rfq); err != nil {
+		return nil, err
+	}
+	return rfq, nil
+}
+
+// SubmitQuote quotes on an RFQ
+func (c *Client) SubmitQuote(ctx context.Context, rfqID uint64, req QuoteRequest) error {
+	req.RFQID = rfqID
+	_, err := c.do(ctx, call{route: routeQuote, params: []string{formatID(rfqID)}, body: req}, nil)
+	return err
+}
+
+// ListQuotes lists the quotes of an RFQ matching opts
+func (c *Client) ListQuotes(ctx context.Context, rfqID uint64, opts ListOptions) (*Page[Quote], error) {
+	return page[Quote](ctx, c, call{route: routeListQuote, params: []string{formatID(rfqID)}, query: opts.values()})
+}
+
+// CreateAuction submits an auction
+func (c *Client) CreateAuction(ctx context.Context, req CreateAuctionRequest) (*PendingAuction, error) {
+	auction := new(PendingAuction)
+	if _, err := c.do(ctx, call{route: routeCreateAuction, body: req}, auction); err != nil {
+		return nil, err
+	}
+	return auction, nil
+}
+
+// ListAuctions lists auctions matching opts
+func (c *Client) ListAuctions(ctx context.Context, opts ListOptions) (*Page[Auction], error) {
+	return page[Auction](ctx, c, call{route: routeListAuctions, query: opts.values()})
+}
+
+// GetAuction returns an auction
+func (c *Client) GetAuction(ctx context.Context, id uint64) (*Auction, error) {
+	auction := new(Auction)
+	if _, err := c.do(ctx, call{route: routeGetAuction, params: []string{formatID(id)}}, auction); err != nil {
+		return nil, err
+	}
+	return auction, nil
+}
+
+// PlaceBid bids on an auction
+func (c *Client) PlaceBid(ctx context.Context, auctionID uint64, req BidRequest) error {
+	req.AuctionID = auctionID
+	_, err := c.do(ctx, call{route: routeBid, params: []string{formatID(auctionID)}, body: req}, nil)
+	return err
+}
+
+// ListBids lists the bids of an auction matching opts
+func (c *Client) ListBids(ctx context.Context, auctionID uint64, opts ListOptions) (*Page[Bid], error) {
+	return page[Bid](ctx, c, call{route: routeListBids, params: []string{formatID(auctionID)}, query: opts.values()})
+}
+
+// FinalizeAuction asks for an auction whose bidding ended to be finalized. It is not retried.
+func (c *Client) FinalizeAuction(ctx context.Context, auctionID uint64) error {
+	_, err := c.do(ctx, call{route: routeFinalizeAuction, params: []string{formatID(auctionID)}}, nil)
+	return err
+}
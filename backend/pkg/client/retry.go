@@ -0,0 +1,71 @@
+package client
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy retries GET requests and POST requests carrying an Idempotency-Key when the API is
+// unreachable, rate limited or temporarily failing. Other requests are sent once.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt, 1 disables retries
+	MaxAttempts int
+	// BaseDelay doubles after every attempt up to MaxDelay, with up to 50% jitter.
+	// A Retry-After header of the response takes precedence; one longer than MaxDelay, e.g. a
+	// faucet cooldown, is not waited for and the error is returned with its RetryAfter.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy makes up to 4 attempts over about 2 seconds
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// transportError is a request that got no response
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+func (p RetryPolicy) retries(err error) bool {
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.RetryAfter > p.MaxDelay {
+		return false
+	}
+	switch apiErr.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, p.MaxDelay)
+	}
+
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,48 @@
+package client
+
+import "net/http"
+
+// Route is an operation of the API, with its path as written in the OpenAPI spec, relative to /api/v1
+type Route struct {
+	Method string
+	Path   string
+	// Idempotent routes accept an Idempotency-Key, which makes POSTs to them safe to retry
+	Idempotent bool
+	// Signed routes take a wallet signature of the address they act for, see WalletAuth. The market routes
+	// accept unsigned requests until the next release, the others require it.
+	Signed bool
+}
+
+var (
+	routeCreateRFQ = Route{Method: http.MethodPost, Path: "/rfq", Idempotent: true, Signed: true}
+	routeListRFQs  = Route{Method: http.MethodGet, Path: "/rfq"}
+	routeGetRFQ    = Route{Method: http.MethodGet, Path: "/rfq/{id}"}
+	routeQuote     = Route{Method: http.MethodPost, Path: "/rfq/{id}/quote", Idempotent: true, Signed: true}
+	routeListQuote = Route{Method: http.MethodGet, Path: "/rfq/{id}/quotes"}
+
+	routeCreateAuction   = Route{Method: http.MethodPost, Path: "/auction", Idempotent: true, Signed: true}
+	routeListAuctions    = Route{Method: http.MethodGet, Path: "/auction"}
+	routeGetAuction      = Route{Method: http.MethodGet, Path: "/auction/{id}"}
+	routeBid             = Route{Method: http.MethodPost, Path: "/auction/{id}/bid", Idempotent: true, Signed: true}
+	routeListBids        = Route{Method: http.MethodGet, Path: "/auction/{id}/bids"}
+	routeFinalizeAuction = Route{Method: http.MethodPost, Path: "/auction/{id}/finalize"}
+
+	routeConnectLiquidity  = Route{Method: http.MethodPost, Path: "/aqua/liquidity", Signed: true}
+	routeGetLiquidity      = Route{Method: http.MethodGet, Path: "/aqua/liquidity/{address}"}
+	routeWithdrawLiquidity = Route{Method: http.MethodPost, Path: "/aqua/withdraw", Signed: true}
+
+	routeRequestTokens = Route{Method: http.MethodPost, Path: "/faucet", Idempotent: true}
+	routeFaucetTokens  = Route{Method: http.MethodGet, Path: "/faucet/tokens"}
+
+	routeSchedule    = Route{Method: http.MethodGet, Path: "/credit-lines/{id}/schedule"}
+	routeObligations = Route{Method: http.MethodGet, Path: "/borrowers/{address}/obligations"}
+)
+
+// Routes lists the operations the client calls
+var Routes = []Route{
+	routeCreateRFQ, routeListRFQs, routeGetRFQ, routeQuote, routeListQuote,
+	routeCreateAuction, routeListAuctions, routeGetAuction, routeBid, routeListBids, routeFinalizeAuction,
+	routeConnectLiquidity, routeGetLiquidity, routeWithdrawLiquidity,
+	routeRequestTokens, routeFaucetTokens,
+	routeSchedule, routeObligations,
+}
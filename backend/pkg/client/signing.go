@@ -0,0 +1,52 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/x402"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// SignMessage makes an EIP-191 personal signature of msg, as eth_sign and personal_sign do,
+// and returns the 65-byte hex signature
+func SignMessage(key *ecdsa.PrivateKey, msg []byte) (string, error) {
+	return sign(key, accounts.TextHash(msg))
+}
+
+// RecoverMessage returns the address that made an EIP-191 personal signature of msg
+func RecoverMessage(msg []byte, signature string) (common.Address, error) {
+	return x402.RecoverSigner(accounts.TextHash(msg), signature)
+}
+
+// SignTypedData makes an EIP-712 signature of typed data, as eth_signTypedData_v4 does, and returns
+// the 65-byte hex signature. EIP-3009 authorizations and EIP-2612 permits have helpers in pkg/x402.
+func SignTypedData(key *ecdsa.PrivateKey, data apitypes.TypedData) (string, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", data.PrimaryType, err)
+	}
+	return sign(key, hash)
+}
+
+// RecoverTypedData returns the address that made an EIP-712 signature of typed data
+func RecoverTypedData(data apitypes.TypedData, signature string) (common.Address, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to hash %s: %w", data.PrimaryType, err)
+	}
+	return x402.RecoverSigner(hash, signature)
+}
+
+func sign(key *ecdsa.PrivateKey, hash []byte) (string, error) {
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // Ethereum-style V
+	return hexutil.Encode(sig), nil
+}
@@ -0,0 +1,212 @@
+package client
+
+// Models of the API. JSON names follow the definitions of the OpenAPI spec, amounts are decimal strings in wei
+// and times are unix seconds.
+
+// RFQ is a borrower's request for quotes
+type RFQ struct {
+	ChainID         uint64 `json:"chainID"`
+	ID              uint64 `json:"id"`
+	BorrowerAddress string `json:"borrowerAddress"`
+	Amount          string `json:"amount"`
+	Duration        uint64 `json:"duration"`
+	CollateralType  uint8  `json:"collateralType"`
+	FlowDescription string `json:"flowDescription"`
+	Status          string `json:"status"`
+	CreatedAt       int64  `json:"createdAt"`
+}
+
+// CreateRFQRequest asks lenders to quote for a credit line
+type CreateRFQRequest struct {
+	BorrowerAddress string `json:"borrower_address"`
+	Amount          string `json:"amount"`
+	// Duration of the credit line in seconds
+	Duration        uint64 `json:"duration"`
+	CollateralType  uint8  `json:"collateral_type"`
+	FlowDescription string `json:"flow_description"`
+}
+
+// Quote is a lender's offer on an RFQ. Accepted is 1 once the borrower accepted it.
+type Quote struct {
+	ChainID            uint64 `json:"chainID"`
+	ID                 uint64 `json:"id"`
+	RFQID              uint64 `json:"rfqid"`
+	LenderAddress      string `json:"lenderAddress"`
+	RateBps            uint16 `json:"rateBps"`
+	Limit              string `json:"limit"`
+	CollateralRequired string `json:"collateralRequired"`
+	SubmittedAt        int64  `json:"submittedAt"`
+	Accepted           uint8  `json:"accepted"`
+}
+
+// QuoteRequest quotes a rate and limit on an RFQ. RFQID is set from the path.
+type QuoteRequest struct {
+	RFQID              uint64 `json:"rfq_id"`
+	LenderAddress      string `json:"lender_address"`
+	RateBps            uint16 `json:"rate_bps"`
+	Limit              string `json:"limit"`
+	CollateralRequired string `json:"collateral_required"`
+}
+
+// Auction is a borrower's auction of a credit line to the lowest rate
+type Auction struct {
+	ChainID         uint64 `json:"chainID"`
+	ID              uint64 `json:"id"`
+	BorrowerAddress string `json:"borrowerAddress"`
+	Amount          string `json:"amount"`
+	Duration        uint64 `json:"duration"`
+	BiddingDuration uint64 `json:"biddingDuration"`
+	EndTime         int64  `json:"endTime"`
+	Status          string `json:"status"`
+	CreatedAt       int64  `json:"createdAt"`
+}
+
+// CreateAuctionRequest opens bidding on a credit line for BiddingDuration seconds
+type CreateAuctionRequest struct {
+	BorrowerAddress string `json:"borrower_address"`
+	Amount          string `json:"amount"`
+	Duration        uint64 `json:"duration"`
+	BiddingDuration uint64 `json:"bidding_duration"`
+}
+
+// PendingAuction is an auction accepted by the API. It gets its ID once created on-chain.
+type PendingAuction struct {
+	ChainID         uint64 `json:"chain_id"`
+	BorrowerAddress string `json:"borrower_address"`
+	Amount          string `json:"amount"`
+	Duration        uint64 `json:"duration"`
+	BiddingDuration uint64 `json:"bidding_duration"`
+	Status          string `json:"status"`
+}
+
+// Bid is a lender's bid on an auction. IsWinning is 1 for the bid that won.
+type Bid struct {
+	ChainID       uint64 `json:"chainID"`
+	ID            uint64 `json:"id"`
+	AuctionID     uint64 `json:"auctionID"`
+	LenderAddress string `json:"lenderAddress"`
+	RateBps       uint16 `json:"rateBps"`
+	Limit         string `json:"limit"`
+	Timestamp     int64  `json:"timestamp"`
+	IsWinning     uint8  `json:"isWinning"`
+}
+
+// BidRequest bids a rate and limit on an auction. AuctionID is set from the path.
+type BidRequest struct {
+	AuctionID     uint64 `json:"auction_id"`
+	LenderAddress string `json:"lender_address"`
+	RateBps       uint16 `json:"rate_bps"`
+	Limit         string `json:"limit"`
+}
+
+// ConnectLiquidityRequest makes a lender's tokens available to Aqua credit lines
+type ConnectLiquidityRequest struct {
+	LenderAddress string `json:"lender_address"`
+	Amount        string `json:"amount"`
+	TokenAddress  string `json:"token_address"`
+}
+
+// WithdrawLiquidityRequest takes unreserved liquidity back out of Aqua
+type WithdrawLiquidityRequest struct {
+	LenderAddress string `json:"lender_address"`
+	Amount        string `json:"amount"`
+}
+
+// Liquidity is the Aqua liquidity of a lender
+type Liquidity struct {
+	ChainID       uint64 `json:"chain_id"`
+	LenderAddress string `json:"lender_address"`
+	Available     string `json:"available"`
+	Reserved      string `json:"reserved"`
+	Total         string `json:"total"`
+}
+
+// RequestTokensRequest asks the faucet for test tokens
+type RequestTokensRequest struct {
+	Address string `json:"address"`
+	// Token is the symbol or address of a token listed by FaucetTokens, empty for ETH
+	Token string `json:"token,omitempty"`
+	// Amount is in token units, e.g. "1.5". It defaults to the token's max amount.
+	Amount string `json:"amount"`
+	// Challenge is the proof-of-work nonce or captcha token of challenged faucets
+	Challenge string `json:"challenge,omitempty"`
+}
+
+// Grant is a faucet transaction. TxID follows it through GET /transactions/{id}.
+type Grant struct {
+	ChainID uint64 `json:"chain_id"`
+	TxID    string `json:"tx_id"`
+	TxHash  string `json:"tx_hash"`
+	Address string `json:"address"`
+	Token   string `json:"token"`
+	Amount  string `json:"amount"`
+}
+
+// TokenInfo is an asset the faucet dispenses and its limits, amounts in token units
+type TokenInfo struct {
+	Symbol string `json:"symbol"`
+	// Address is the token contract, empty for ETH
+	Address     string `json:"address,omitempty"`
+	Mode        string `json:"mode"`
+	Decimals    uint8  `json:"decimals"`
+	MaxAmount   string `json:"max_amount"`
+	DailyBudget string `json:"daily_budget,omitempty"`
+	// Cooldowns are in seconds
+	AddressCooldown int64 `json:"address_cooldown"`
+	IPCooldown      int64 `json:"ip_cooldown"`
+}
+
+// Installment is an amount due on a credit line
+type Installment struct {
+	DueAt     int64  `json:"due_at"`
+	Principal string `json:"principal"`
+	Interest  string `json:"interest"`
+	Total     string `json:"total"`
+	Overdue   bool   `json:"overdue"`
+}
+
+// Position is the balance of a credit line at a point in time
+type Position struct {
+	ChainID         uint64 `json:"chain_id"`
+	CreditLineID    uint64 `json:"credit_line_id"`
+	BorrowerAddress string `json:"borrower_address"`
+	LenderAddress   string `json:"lender_address"`
+	Limit           string `json:"limit"`
+	RateBps         uint64 `json:"rate_bps"`
+	CreatedAt       int64  `json:"created_at"`
+	ExpiresAt       int64  `json:"expires_at"`
+	Principal       string `json:"principal"`
+	AccruedInterest string `json:"accrued_interest"`
+	TotalOwed       string `json:"total_owed"`
+	TotalDrawn      string `json:"total_drawn"`
+	TotalRepaid     string `json:"total_repaid"`
+	InterestPaid    string `json:"interest_paid"`
+	AsOf            int64  `json:"as_of"`
+}
+
+// Schedule is the position of a credit line and its projected installments
+type Schedule struct {
+	Position
+	// InterestMode is "simple" or "compound"
+	InterestMode string        `json:"interest_mode"`
+	NextDue      *Installment  `json:"next_due"`
+	Installments []Installment `json:"installments"`
+}
+
+// Obligation is a credit line position with its next due amount
+type Obligation struct {
+	Position
+	NextDue *Installment `json:"next_due"`
+}
+
+// Obligations is what a borrower owes across all credit lines
+type Obligations struct {
+	ChainID         uint64       `json:"chain_id"`
+	BorrowerAddress string       `json:"borrower_address"`
+	TotalPrincipal  string       `json:"total_principal"`
+	TotalInterest   string       `json:"total_interest"`
+	TotalOwed       string       `json:"total_owed"`
+	NextDue         *Installment `json:"next_due"`
+	CreditLines     []Obligation `json:"credit_lines"`
+	AsOf            int64        `json:"as_of"`
+}
@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Event types, as published by the API and the event monitor
+const (
+	EventRFQCreated       = "rfq_created"
+	EventQuoteSubmitted   = "quote_submitted"
+	EventQuoteAccepted    = "quote_accepted"
+	EventRFQExecuted      = "rfq_executed"
+	EventAuctionCreated   = "auction_created"
+	EventBidPlaced        = "bid_placed"
+	EventAuctionFinalized = "auction_finalized"
+	EventAuctionSettled   = "auction_settled"
+	EventCreditLineOpened = "credit_line_opened"
+	EventCreditDrawn      = "credit_drawn"
+	EventCreditRepaid     = "credit_repaid"
+)
+
+// RFQTopic and AuctionTopic name the topics of the events of one RFQ or auction
+func RFQTopic(id uint64) string     { return "rfq:" + formatID(id) }
+func AuctionTopic(id uint64) string { return "auction:" + formatID(id) }
+
+// Event is a message pushed over the WebSocket. The fields common to event types are decoded,
+// Decode reads the rest into a struct of the caller's.
+type Event struct {
+	Type         string
+	ChainID      uint64
+	RFQID        uint64
+	AuctionID    uint64
+	CreditLineID uint64
+	// Borrower and Lender are the borrower and lender addresses named by the event, if any
+	Borrower    string
+	Lender      string
+	Amount      string
+	Limit       string
+	RateBps     uint64
+	TxHash      string
+	BlockNumber uint64
+	LogIndex    uint64
+	Timestamp   int64
+	Raw         json.RawMessage
+}
+
+// UnmarshalJSON accepts IDs and amounts as numbers or strings, on-chain events carry them as strings
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	text := func(keys ...string) string {
+		for _, key := range keys {
+			raw, ok := fields[key]
+			if !ok {
+				continue
+			}
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				return s
+			}
+			var n json.Number
+			if json.Unmarshal(raw, &n) == nil {
+				return n.String()
+			}
+		}
+		return ""
+	}
+	number := func(keys ...string) uint64 {
+		n, _ := strconv.ParseUint(text(keys...), 10, 64)
+		return n
+	}
+
+	*e = Event{
+		Type:         text("type"),
+		ChainID:      number("chain_id"),
+		RFQID:        number("rfq_id"),
+		AuctionID:    number("auction_id"),
+		CreditLineID: number("credit_line_id"),
+		Borrower:     text("borrower", "borrower_address"),
+		Lender:       text("lender", "lender_address", "winning_lender"),
+		Amount:       text("amount"),
+		Limit:        text("limit"),
+		RateBps:      number("rate_bps"),
+		TxHash:       text("tx_hash"),
+		BlockNumber:  number("block_number"),
+		LogIndex:     number("log_index"),
+		Timestamp:    int64(number("timestamp")),
+		Raw:          append(json.RawMessage(nil), data...),
+	}
+	return nil
+}
+
+// Decode decodes the whole event into v
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Raw, v)
+}
+
+// Topics returns the topics the event belongs to
+func (e Event) Topics() []string {
+	var topics []string
+	if e.RFQID != 0 {
+		topics = append(topics, RFQTopic(e.RFQID))
+	}
+	if e.AuctionID != 0 {
+		topics = append(topics, AuctionTopic(e.AuctionID))
+	}
+	return topics
+}
+
+// Subscription streams the events of its topics until it is closed, its context is done or the
+// connection drops. Err then tells why Events was closed.
+type Subscription struct {
+	conn   *websocket.Conn
+	events chan Event
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	topics  map[string]bool
+	err     error
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Subscribe connects to the WebSocket and subscribes to topics, e.g. RFQTopic(1). Without topics
+// every event is received.
+func (c *Client) Subscribe(ctx context.Context, topics ...string) (*Subscription, error) {
+	return c.subscribe(ctx, "/ws", topics)
+}
+
+// SubscribeRFQ streams the events of an RFQ
+func (c *Client) SubscribeRFQ(ctx context.Context, id uint64) (*Subscription, error) {
+	return c.subscribe(ctx, "/ws/rfq/"+formatID(id), []string{RFQTopic(id)})
+}
+
+// SubscribeAuction streams the events of an auction
+func (c *Client) SubscribeAuction(ctx context.Context, id uint64) (*Subscription, error) {
+	return c.subscribe(ctx, "/ws/auction/"+formatID(id), []string{AuctionTopic(id)})
+}
+
+func (c *Client) subscribe(ctx context.Context, path string, topics []string) (*Subscription, error) {
+	target := c.baseURL + path
+	switch {
+	case strings.HasPrefix(target, "https://"):
+		target = "wss://" + strings.TrimPrefix(target, "https://")
+	case strings.HasPrefix(target, "http://"):
+		target = "ws://" + strings.TrimPrefix(target, "http://")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.auth != nil {
+		if err := c.auth.Authenticate(req, nil, c.now()); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, target, req.Header)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			return nil, decodeError(resp)
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %w", path, err)
+	}
+
+	sub := &Subscription{
+		conn:   conn,
+		events: make(chan Event, 64),
+		topics: make(map[string]bool),
+		done:   make(chan struct{}),
+	}
+	if err := sub.Subscribe(topics...); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go sub.read()
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.fail(ctx.Err())
+		case <-sub.done:
+		}
+	}()
+	return sub, nil
+}
+
+// Events returns the channel of events, closed when the subscription ends
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Subscribe adds topics to the subscription
+func (s *Subscription) Subscribe(topics ...string) error {
+	for _, topic := range topics {
+		if err := s.send("subscribe", topic); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.topics[topic] = true
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Unsubscribe removes topics from the subscription
+func (s *Subscription) Unsubscribe(topics ...string) error {
+	for _, topic := range topics {
+		s.mu.Lock()
+		delete(s.topics, topic)
+		s.mu.Unlock()
+		if err := s.send("unsubscribe", topic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Err returns why the subscription ended, nil while it runs or after Close
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() error {
+	s.fail(nil)
+	return nil
+}
+
+func (s *Subscription) send(action, topic string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.conn.WriteJSON(map[string]string{"action": action, "topic": topic}); err != nil {
+		return fmt.Errorf("failed to %s to %s: %w", action, topic, err)
+	}
+	return nil
+}
+
+func (s *Subscription) read() {
+	defer close(s.events)
+	for {
+		_, message, err := s.conn.ReadMessage()
+		if err != nil {
+			s.fail(err)
+			return
+		}
+
+		var event Event
+		if err := json.Unmarshal(message, &event); err != nil {
+			continue
+		}
+		// The hub pushes every event to every connection, so topics are filtered here
+		if !s.wants(event) {
+			continue
+		}
+
+		select {
+		case s.events <- event:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Subscription) wants(event Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.topics) == 0 {
+		return true
+	}
+	for _, topic := range event.Topics() {
+		if s.topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+// fail ends the subscription with err, the first call wins
+func (s *Subscription) fail(err error) {
+	s.once.Do(func() {
+		if err != nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+		}
+		close(s.done)
+
+		s.writeMu.Lock()
+		_ = s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		s.writeMu.Unlock()
+		s.conn.Close()
+	})
+}
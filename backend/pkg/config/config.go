@@ -0,0 +1,276 @@
+// Package config resolves the settings of the API, the worker and aqua402ctl. Settings are environment
+// variable names; their values come from, in increasing precedence, the profile defaults, the profile
+// file, the config files, the environment and --set flags. Load exports the resolved values to the
+// environment, where the components' ConfigFromEnv functions read them.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config is the configuration shared by the API and the worker
+type Config struct {
+	Profile       Profile
+	Port          int
+	ClickHouseDSN string
+	RabbitMQURL   string
+	// PrintConfig is set by --print-config: the binary prints the configuration and exits
+	PrintConfig bool
+	// Warnings are problems that did not stop loading, such as unknown keys in a config file
+	Warnings []string
+
+	settings map[string]setting
+}
+
+// setting is a resolved value and where it came from: default, a file path, env or flag
+type setting struct {
+	value  string
+	source string
+}
+
+// stringList is a repeatable flag
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(s string) error { *l = append(*l, s); return nil }
+
+// Load resolves the configuration of the binary name from its command line arguments:
+//
+//	--env PROFILE      development, staging or production, overriding APP_ENV
+//	--config FILE      a .env file, repeatable, later files win; defaults to the comma separated CONFIG_FILE
+//	--set KEY=VALUE    a setting, repeatable, overriding every other source
+//	--print-config     print the configuration with secrets redacted and exit
+//
+// The profile file CONFIG_DIR/<profile>.env is read first when CONFIG_DIR is set. Empty environment
+// variables count as unset. Invalid settings are returned with the configuration, so it can still be
+// printed; nothing is exported then.
+func Load(name string, args []string) (*Config, error) {
+	var (
+		files, sets stringList
+		profileFlag string
+		cfg         = &Config{settings: make(map[string]setting)}
+	)
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&profileFlag, "env", "", "profile: development, staging or production (default APP_ENV)")
+	fs.Var(&files, "config", "`file` of KEY=VALUE settings, repeatable (default CONFIG_FILE)")
+	fs.Var(&sets, "set", "`KEY=VALUE` setting overriding every other source, repeatable")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", false, "print the configuration with secrets redacted and exit")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+
+	flags := make(map[string]string)
+	for _, s := range sets {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || !known(key) {
+			return nil, fmt.Errorf("invalid --set %q, expected a known KEY=VALUE", s)
+		}
+		flags[key] = value
+	}
+	if profileFlag != "" {
+		flags["APP_ENV"] = profileFlag
+	}
+
+	// Files are named by flags or the environment, settings inside them cannot name other files
+	if len(files) == 0 {
+		files = splitList(lookup(flags, "CONFIG_FILE"))
+	}
+	var layers []map[string]string
+	var sources []string
+	for _, path := range files {
+		values, err := ParseEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		layers, sources = append(layers, values), append(sources, path)
+	}
+
+	// The profile decides the defaults and the profile file, so it is resolved first
+	profileName := lookup(flags, "APP_ENV")
+	for i := len(layers) - 1; i >= 0 && profileName == ""; i-- {
+		profileName = layers[i]["APP_ENV"]
+	}
+	profile, ok := parseProfile(strings.ToLower(profileName))
+	if !ok {
+		return nil, fmt.Errorf("invalid APP_ENV %q, expected development, staging or production", profileName)
+	}
+	cfg.Profile = profile
+
+	for key, value := range profiles[profile] {
+		cfg.settings[key] = setting{value: value, source: "default"}
+	}
+	if dir := lookup(flags, "CONFIG_DIR"); dir != "" {
+		path := filepath.Join(dir, string(profile)+".env")
+		values, err := ParseEnvFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			layers, sources = append([]map[string]string{values}, layers...), append([]string{path}, sources...)
+		}
+	}
+	for i, values := range layers {
+		var unknown []string
+		for key, value := range values {
+			if !known(key) {
+				unknown = append(unknown, key)
+				continue
+			}
+			if value != "" {
+				cfg.settings[key] = setting{value: value, source: sources[i]}
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("ignored unknown settings in %s: %s", sources[i], strings.Join(unknown, ", ")))
+		}
+	}
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if value != "" && known(key) {
+			cfg.settings[key] = setting{value: value, source: "env"}
+		}
+	}
+	for key, value := range flags {
+		cfg.settings[key] = setting{value: value, source: "flag"}
+	}
+	cfg.settings["APP_ENV"] = setting{value: string(profile), source: cfg.source("APP_ENV")}
+
+	if err := cfg.readSecretFiles(); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, err
+	}
+
+	// Components read their settings from the environment
+	for key, s := range cfg.settings {
+		if err := os.Setenv(key, s.value); err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", key, err)
+		}
+	}
+	return cfg, nil
+}
+
+// readSecretFiles resolves KEY_FILE settings into KEY, unless KEY is set itself. CHAINS_FILE and
+// CONFIG_FILE are not secret files, CHAINS and CONFIG are no settings.
+func (c *Config) readSecretFiles() error {
+	for key, s := range c.settings {
+		base, ok := strings.CutSuffix(key, "_FILE")
+		if _, isSetting := Known[base]; !ok || !isSetting {
+			continue
+		}
+		if existing, set := c.settings[base]; set && existing.source != "default" {
+			continue
+		}
+		content, err := os.ReadFile(s.value)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		c.settings[base] = setting{value: strings.TrimSpace(string(content)), source: s.value}
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	var errs []error
+	for _, key := range required[c.Profile] {
+		if c.Get(key) == "" {
+			errs = append(errs, fmt.Errorf("%s is required in %s", key, c.Profile))
+		}
+	}
+	if v := c.Get("PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil || port <= 0 || port > 65535 {
+			errs = append(errs, fmt.Errorf("invalid PORT %q", v))
+		}
+		c.Port = port
+	}
+	c.ClickHouseDSN = c.Get("CLICKHOUSE_DSN")
+	c.RabbitMQURL = c.Get("RABBITMQ_URL")
+	return errors.Join(errs...)
+}
+
+// Get returns the resolved value of a setting, empty when unset
+func (c *Config) Get(key string) string {
+	return c.settings[key].value
+}
+
+func (c *Config) source(key string) string {
+	if s, ok := c.settings[key]; ok {
+		return s.source
+	}
+	return "default"
+}
+
+// Print writes the resolved settings in .env format, each with its source, secrets redacted
+func (c *Config) Print(w io.Writer) {
+	keys := make([]string, 0, len(c.settings))
+	for key := range c.settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "# profile %s\n", c.Profile)
+	for _, key := range keys {
+		s := c.settings[key]
+		fmt.Fprintf(w, "%s=%s  # %s\n", key, redact(key, s.value), s.source)
+	}
+}
+
+// redact hides secrets and the passwords of credential URLs. KEY_FILE settings are paths, shown as is.
+func redact(key, value string) string {
+	switch Known[key] {
+	case Secret:
+		return "<redacted>"
+	case Credentials:
+		u, err := url.Parse(value)
+		if err != nil {
+			return "<redacted>"
+		}
+		return u.Redacted()
+	}
+	return value
+}
+
+// known reports whether key is a setting, or names the file holding one
+func known(key string) bool {
+	if _, ok := Known[key]; ok {
+		return true
+	}
+	base, ok := strings.CutSuffix(key, "_FILE")
+	if !ok {
+		return false
+	}
+	_, ok = Known[base]
+	return ok
+}
+
+// lookup returns a setting from the flags, then the environment
+func lookup(flags map[string]string, key string) string {
+	if v := flags[key]; v != "" {
+		return v
+	}
+	return os.Getenv(key)
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,13 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// IsProduction reports whether APP_ENV is production. Development defaults such as the Hardhat
+// accounts are refused in production.
+func IsProduction() bool {
+	env := strings.ToLower(os.Getenv("APP_ENV"))
+	return env == "production" || env == "prod"
+}
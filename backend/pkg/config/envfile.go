@@ -7,15 +7,17 @@ import (
 	"strings"
 )
 
-// LoadEnvFile loads environment variables from a .env file
-// File format: KEY=VALUE (one per line, comments start with #)
-func LoadEnvFile(path string) error {
+// ParseEnvFile reads a .env file: KEY=VALUE lines, optionally prefixed with export, blank lines and
+// comments starting with #. Values may be double quoted, with \n, \t, \" and \\ escapes, or single quoted
+// and taken literally. An unquoted value ends at " #", which starts a comment. Malformed lines are errors.
+func ParseEnvFile(path string) (map[string]string, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return fmt.Errorf("failed to open env file %s: %w", path, err)
+		return nil, fmt.Errorf("failed to open env file %s: %w", path, err)
 	}
 	defer file.Close()
 
+	values := make(map[string]string)
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
 
@@ -27,49 +29,82 @@ func LoadEnvFile(path string) error {
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
 
-		// Parse KEY=VALUE
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue // Skip malformed lines
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !validKey(key) {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNum)
 		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		// Remove quotes if present
-		if len(value) >= 2 {
-			if (value[0] == '"' && value[len(value)-1] == '"') ||
-				(value[0] == '\'' && value[len(value)-1] == '\'') {
-				value = value[1 : len(value)-1]
-			}
-		}
-
-		// Set environment variable if not already set
-		if os.Getenv(key) == "" {
-			os.Setenv(key, value)
+		value, err := parseValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s: %w", path, lineNum, key, err)
 		}
+		values[key] = value
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading env file: %w", err)
+		return nil, fmt.Errorf("error reading env file %s: %w", path, err)
 	}
-
-	return nil
+	return values, nil
 }
 
-// LoadEnvFileIfExists loads environment variables from a .env file if it exists
-// Returns true if file was loaded, false if file doesn't exist
-func LoadEnvFileIfExists(path string) (bool, error) {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return false, nil
+func validKey(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
 	}
-
-	err := LoadEnvFile(path)
-	if err != nil {
-		return false, err
+	for _, r := range key {
+		if !(r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
+			return false
+		}
 	}
-
-	return true, nil
+	return true
 }
 
+func parseValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	switch quote := raw[0]; quote {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		if rest := strings.TrimSpace(raw[end+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after quoted value", rest)
+		}
+		return raw[1 : end+1], nil
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			switch c := raw[i]; c {
+			case '\\':
+				if i+1 == len(raw) {
+					return "", fmt.Errorf("unterminated quote")
+				}
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(raw[i])
+				}
+			case '"':
+				if rest := strings.TrimSpace(raw[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+					return "", fmt.Errorf("unexpected %q after quoted value", rest)
+				}
+				return b.String(), nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated quote")
+	}
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw), nil
+}
@@ -2,93 +2,270 @@ package evm
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/time/rate"
 )
 
-// Client wraps Ethereum client for EVM interactions
+// Client wraps Ethereum clients for EVM interactions. Requests go to the healthiest of its RPC endpoints
+// and fail over to the others, see ClientConfig.
 type Client struct {
-	client *ethclient.Client
+	endpoints []*endpoint
+	config    ClientConfig
+	fees      FeeConfig
+	stop      context.CancelFunc
+
+	mu sync.Mutex
+	// id caches the chain ID once read
+	id *big.Int
 }
 
-// NewClient creates a new EVM client
+// NewClient creates a new EVM client for a comma separated list of RPC URLs with the default pool
+// settings, pricing transactions at normal urgency, see SetFeeConfig
 func NewClient(rpcURL string) (*Client, error) {
-	client, err := ethclient.Dial(rpcURL)
-	if err != nil {
-		return nil, err
+	return Dial(ClientConfig{URLs: splitURLs(rpcURL)})
+}
+
+// Dial creates a new EVM client for cfg's endpoints. With several endpoints their health is checked
+// in the background until Close.
+func Dial(cfg ClientConfig) (*Client, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, errors.New("no RPC URL")
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = 15 * time.Second
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 3
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = 30 * time.Second
+	}
+
+	c := &Client{config: cfg, fees: FeeConfig{Urgency: UrgencyNormal}}
+	for _, url := range cfg.URLs {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to dial %s: %w", url, err)
+		}
+		e := &endpoint{url: url, client: client}
+		if cfg.RateLimit > 0 {
+			e.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), max(1, int(cfg.RateLimit)))
+		}
+		c.endpoints = append(c.endpoints, e)
+	}
+
+	if len(c.endpoints) > 1 {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.stop = cancel
+		go c.healthCheck(ctx)
 	}
-	return &Client{client: client}, nil
+	return c, nil
+}
+
+// Close stops health checks and closes the connections
+func (c *Client) Close() {
+	if c.stop != nil {
+		c.stop()
+	}
+	for _, e := range c.endpoints {
+		e.client.Close()
+	}
+}
+
+// SetFeeConfig sets the default urgency and the per-chain maximum fees used by SuggestFees
+func (c *Client) SetFeeConfig(cfg FeeConfig) {
+	if cfg.Urgency == "" {
+		cfg.Urgency = UrgencyNormal
+	}
+	c.fees = cfg
 }
 
 // GetBalance returns the balance of an address
 func (c *Client) GetBalance(ctx context.Context, address common.Address) (*big.Int, error) {
-	return c.client.BalanceAt(ctx, address, nil)
+	return call(ctx, c, "eth_getBalance", func(client *ethclient.Client) (*big.Int, error) {
+		return client.BalanceAt(ctx, address, nil)
+	})
 }
 
 // SendTransaction sends a transaction
 func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	return c.client.SendTransaction(ctx, tx)
+	// Sending the same transaction to another endpoint is harmless, nodes deduplicate by hash
+	_, err := call(ctx, c, "eth_sendRawTransaction", func(client *ethclient.Client) (struct{}, error) {
+		return struct{}{}, client.SendTransaction(ctx, tx)
+	})
+	return err
 }
 
 // TransactionReceipt returns the receipt of a transaction
 func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
-	return c.client.TransactionReceipt(ctx, txHash)
+	return call(ctx, c, "eth_getTransactionReceipt", func(client *ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, txHash)
+	})
 }
 
 // CallContract executes a message call
 func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	return c.client.CallContract(ctx, msg, blockNumber)
+	return call(ctx, c, "eth_call", func(client *ethclient.Client) ([]byte, error) {
+		return client.CallContract(ctx, msg, blockNumber)
+	})
 }
 
 // PendingNonceAt returns the account nonce of the given account in the pending state
 func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
-	return c.client.PendingNonceAt(ctx, account)
+	return call(ctx, c, "eth_getTransactionCount", func(client *ethclient.Client) (uint64, error) {
+		return client.PendingNonceAt(ctx, account)
+	})
+}
+
+// NonceAt returns the account nonce at the given block, the latest block when blockNumber is nil
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return call(ctx, c, "eth_getTransactionCount", func(client *ethclient.Client) (uint64, error) {
+		return client.NonceAt(ctx, account, blockNumber)
+	})
 }
 
 // SuggestGasPrice retrieves the currently suggested gas price
 func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
-	return c.client.SuggestGasPrice(ctx)
+	return call(ctx, c, "eth_gasPrice", func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
 }
 
 // EstimateGas tries to estimate the gas needed to execute a specific transaction
 func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
-	return c.client.EstimateGas(ctx, msg)
+	return call(ctx, c, "eth_estimateGas", func(client *ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, msg)
+	})
 }
 
-// GetAuth returns a transaction auth for contract interactions
+// FeeHistory returns the base fees and priority fee percentiles of the blockCount blocks up to lastBlock
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return call(ctx, c, "eth_feeHistory", func(client *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+// GetAuth returns transact options signing with a hex private key, for abigen bindings. chainID is read
+// from the node when nil. Fees are priced by SuggestFees at the default urgency.
 func (c *Client) GetAuth(ctx context.Context, privateKey string, chainID *big.Int) (*bind.TransactOpts, error) {
-	// This would be implemented with actual private key handling
-	// For now, return a placeholder
-	return nil, nil
+	signer, err := ParseKeySigner(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return c.TransactOpts(ctx, signer, chainID)
+}
+
+// TransactOpts returns transact options signing with signer, for abigen bindings. chainID is read from
+// the node when nil. Fees are priced by SuggestFees at the default urgency.
+func (c *Client) TransactOpts(ctx context.Context, signer Signer, chainID *big.Int) (*bind.TransactOpts, error) {
+	if chainID == nil {
+		var err error
+		if chainID, err = c.chainID(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	auth := &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(ctx, tx, chainID)
+		},
+		Context: ctx,
+	}
+
+	fees, err := c.SuggestFees(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if fees.Dynamic() {
+		auth.GasTipCap, auth.GasFeeCap = fees.GasTipCap, fees.GasFeeCap
+	} else {
+		auth.GasPrice = fees.GasPrice
+	}
+	return auth, nil
 }
 
-// FilterLogs executes a filter query
+// FilterLogs executes a filter query. A block range the provider rejects as too large is split up.
 func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
-	return c.client.FilterLogs(ctx, query)
+	return c.filterLogs(ctx, query)
 }
 
 // SubscribeFilterLogs creates a subscription that will receive logs matching the given query
 func (c *Client) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
-	return c.client.SubscribeFilterLogs(ctx, query, ch)
+	return call(ctx, c, "eth_subscribe", func(client *ethclient.Client) (ethereum.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
+
+// SubscribeNewHead creates a subscription that will receive the header of every new block
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return call(ctx, c, "eth_subscribe", func(client *ethclient.Client) (ethereum.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	})
+}
+
+// SupportsSubscriptions reports whether one of the endpoints is a WebSocket or IPC endpoint able to serve
+// subscriptions, HTTP endpoints cannot
+func (c *Client) SupportsSubscriptions() bool {
+	for _, e := range c.endpoints {
+		if !strings.HasPrefix(e.url, "http://") && !strings.HasPrefix(e.url, "https://") {
+			return true
+		}
+	}
+	return false
 }
 
 // HeaderByNumber returns a block header from the current canonical chain
 func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
-	return c.client.HeaderByNumber(ctx, number)
+	return call(ctx, c, "eth_getBlockByNumber", func(client *ethclient.Client) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+}
+
+// ChainID retrieves the chain ID of the connected network
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return call(ctx, c, "eth_chainId", func(client *ethclient.Client) (*big.Int, error) {
+		return client.ChainID(ctx)
+	})
+}
+
+// chainID returns the chain ID, read from the node once
+func (c *Client) chainID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.id == nil {
+		id, err := c.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		c.id = id
+	}
+	return new(big.Int).Set(c.id), nil
 }
 
 // BlockNumber returns the most recent block number
 func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
-	return c.client.BlockNumber(ctx)
+	return call(ctx, c, "eth_blockNumber", func(client *ethclient.Client) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
 }
 
-// Client returns the underlying ethclient.Client
+// Client returns the ethclient.Client of the preferred endpoint, requests made with it do not fail over
 func (c *Client) Client() *ethclient.Client {
-	return c.client
+	return c.candidates()[0].client
 }
-
@@ -0,0 +1,231 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Urgency trades inclusion speed for cost
+type Urgency string
+
+const (
+	UrgencySlow   Urgency = "slow"
+	UrgencyNormal Urgency = "normal"
+	UrgencyFast   Urgency = "fast"
+)
+
+// feeProfile is the priority fee percentile paid by recent blocks' transactions and how many times the next
+// base fee the fee cap covers, in percent, so the transaction stays includable while the base fee rises
+type feeProfile struct {
+	rewardPercentile  float64
+	baseFeeMultiplier int64
+}
+
+var feeProfiles = map[Urgency]feeProfile{
+	UrgencySlow:   {rewardPercentile: 10, baseFeeMultiplier: 125},
+	UrgencyNormal: {rewardPercentile: 50, baseFeeMultiplier: 200},
+	UrgencyFast:   {rewardPercentile: 90, baseFeeMultiplier: 300},
+}
+
+// feeHistoryBlocks is how many recent blocks priority fees are sampled from
+const feeHistoryBlocks = 20
+
+// ErrFeeAboveMax is returned when the base fee alone is above the chain's maximum fee, so no
+// transaction under the guard could be included
+var ErrFeeAboveMax = errors.New("network fee is above the configured maximum")
+
+// Fees prices a transaction. GasTipCap and GasFeeCap are set on chains with a base fee (EIP-1559),
+// GasPrice on chains without one.
+type Fees struct {
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	GasPrice  *big.Int
+}
+
+// Dynamic reports whether the fees are for an EIP-1559 transaction
+func (f *Fees) Dynamic() bool {
+	return f.GasFeeCap != nil
+}
+
+// MaxPrice is the most a unit of gas may cost
+func (f *Fees) MaxPrice() *big.Int {
+	if f.Dynamic() {
+		return f.GasFeeCap
+	}
+	return f.GasPrice
+}
+
+// FeeConfig selects the default urgency and guards the fees paid on each chain
+type FeeConfig struct {
+	Urgency Urgency
+	// MaxFeePerGas caps the fee cap (or gas price) per chain ID, in wei
+	MaxFeePerGas map[uint64]*big.Int
+}
+
+// FeeConfigFromEnv reads EVM_FEE_URGENCY (slow, normal or fast, default normal) and EVM_MAX_FEE_PER_GAS,
+// a list of chain ID and wei pairs such as "1=200000000000;31337=5000000000"
+func FeeConfigFromEnv() (FeeConfig, error) {
+	cfg := FeeConfig{Urgency: UrgencyNormal, MaxFeePerGas: make(map[uint64]*big.Int)}
+	if v := os.Getenv("EVM_FEE_URGENCY"); v != "" {
+		if _, ok := feeProfiles[Urgency(v)]; !ok {
+			return cfg, fmt.Errorf("invalid EVM_FEE_URGENCY %q, expected slow, normal or fast", v)
+		}
+		cfg.Urgency = Urgency(v)
+	}
+	for _, entry := range strings.Split(os.Getenv("EVM_MAX_FEE_PER_GAS"), ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		chain, fee, ok := strings.Cut(entry, "=")
+		chainID, err := strconv.ParseUint(strings.TrimSpace(chain), 10, 64)
+		if !ok || err != nil {
+			return cfg, fmt.Errorf("invalid EVM_MAX_FEE_PER_GAS entry %q, expected chain_id=wei", entry)
+		}
+		max, ok := new(big.Int).SetString(strings.TrimSpace(fee), 10)
+		if !ok || max.Sign() <= 0 {
+			return cfg, fmt.Errorf("invalid EVM_MAX_FEE_PER_GAS fee for chain %d", chainID)
+		}
+		cfg.MaxFeePerGas[chainID] = max
+	}
+	return cfg, nil
+}
+
+// FeesFromHistory prices a transaction from an eth_feeHistory result sampled at the urgency's reward
+// percentile: the tip is the median of the sampled rewards, the fee cap the next base fee times the
+// urgency's multiplier plus the tip. minTip is used when recent blocks paid no tips.
+func FeesFromHistory(history *ethereum.FeeHistory, urgency Urgency, minTip *big.Int) (*Fees, error) {
+	profile, ok := feeProfiles[urgency]
+	if !ok {
+		return nil, fmt.Errorf("unknown urgency %q", urgency)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("fee history has no base fee")
+	}
+
+	var rewards []*big.Int
+	for _, block := range history.Reward {
+		if len(block) > 0 && block[0] != nil && block[0].Sign() > 0 {
+			rewards = append(rewards, block[0])
+		}
+	}
+	tip := new(big.Int)
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		tip.Set(rewards[len(rewards)/2])
+	}
+	if minTip != nil && tip.Cmp(minTip) < 0 {
+		tip.Set(minTip)
+	}
+
+	// The last base fee is the one of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(profile.baseFeeMultiplier))
+	feeCap.Div(feeCap, big.NewInt(100))
+	feeCap.Add(feeCap, tip)
+	return &Fees{GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
+
+// Guard caps fees at the chain's maximum. It fails with ErrFeeAboveMax when the next base fee is above it.
+func (c FeeConfig) Guard(chainID *big.Int, fees *Fees, baseFee *big.Int) (*Fees, error) {
+	if chainID == nil || !chainID.IsUint64() {
+		return fees, nil
+	}
+	max, ok := c.MaxFeePerGas[chainID.Uint64()]
+	if !ok {
+		return fees, nil
+	}
+	if !fees.Dynamic() {
+		if fees.GasPrice.Cmp(max) > 0 {
+			return nil, fmt.Errorf("%w: gas price %s > %s", ErrFeeAboveMax, fees.GasPrice, max)
+		}
+		return fees, nil
+	}
+	if baseFee != nil && baseFee.Cmp(max) > 0 {
+		return nil, fmt.Errorf("%w: base fee %s > %s", ErrFeeAboveMax, baseFee, max)
+	}
+	guarded := &Fees{GasTipCap: fees.GasTipCap, GasFeeCap: fees.GasFeeCap}
+	if guarded.GasFeeCap.Cmp(max) > 0 {
+		guarded.GasFeeCap = new(big.Int).Set(max)
+	}
+	if guarded.GasTipCap.Cmp(guarded.GasFeeCap) > 0 {
+		guarded.GasTipCap = new(big.Int).Set(guarded.GasFeeCap)
+	}
+	return guarded, nil
+}
+
+// SuggestFees prices a transaction at urgency, the client's default urgency when empty. Chains without
+// a base fee get a legacy gas price. Fees are capped by the chain's maximum, see FeeConfig.
+func (c *Client) SuggestFees(ctx context.Context, urgency Urgency) (*Fees, error) {
+	if urgency == "" {
+		urgency = c.fees.Urgency
+	}
+	profile, ok := feeProfiles[urgency]
+	if !ok {
+		return nil, fmt.Errorf("unknown urgency %q", urgency)
+	}
+	chainID, err := c.chainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := c.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if header.BaseFee == nil {
+		gasPrice, err := c.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get gas price: %w", err)
+		}
+		return c.fees.Guard(chainID, &Fees{GasPrice: gasPrice}, nil)
+	}
+
+	history, err := c.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{profile.rewardPercentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	minTip, err := call(ctx, c, "eth_maxPriorityFeePerGas", func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasTipCap(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+	}
+	if urgency == UrgencySlow {
+		// The node's suggestion is a normal tip, slow transactions settle for half of it
+		minTip.Div(minTip, big.NewInt(2))
+	}
+	fees, err := FeesFromHistory(history, urgency, minTip)
+	if err != nil {
+		return nil, err
+	}
+	return c.fees.Guard(chainID, fees, history.BaseFee[len(history.BaseFee)-1])
+}
+
+// NewTx builds a dynamic-fee transaction, or a legacy one when fees have no fee cap
+func NewTx(chainID *big.Int, nonce uint64, to common.Address, value *big.Int, gasLimit uint64, fees *Fees, data []byte) *types.Transaction {
+	if !fees.Dynamic() {
+		return types.NewTransaction(nonce, to, value, gasLimit, fees.GasPrice, data)
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: fees.GasTipCap,
+		GasFeeCap: fees.GasFeeCap,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+}
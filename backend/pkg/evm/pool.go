@@ -0,0 +1,364 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// tracer traces RPC requests, under the span of the caller's context
+var tracer = otel.Tracer("github.com/Pagga-Wallet/aqua402/pkg/evm")
+
+// ClientConfig lists the RPC endpoints of one chain and how a Client spreads requests over them
+type ClientConfig struct {
+	URLs []string
+	// HealthCheckInterval is how often every endpoint's block number and latency are checked
+	HealthCheckInterval time.Duration
+	// RateLimit is the requests per second sent to each endpoint, 0 for no limit
+	RateLimit float64
+	// FailureThreshold consecutive failures open an endpoint's circuit breaker for BreakerCooldown
+	FailureThreshold int
+	BreakerCooldown  time.Duration
+	// MaxBlockLag is how many blocks an endpoint may trail the highest head before it is used last
+	MaxBlockLag uint64
+}
+
+// ClientConfigFromEnv reads EVM_RPC_URL, a comma separated list of endpoints (default
+// http://hardhat-node:8545), EVM_RPC_HEALTH_INTERVAL (15s), EVM_RPC_RATE_LIMIT (requests per second per
+// endpoint, 0 for none), EVM_RPC_FAILURE_THRESHOLD (3), EVM_RPC_BREAKER_COOLDOWN (30s) and
+// EVM_RPC_MAX_BLOCK_LAG (5)
+func ClientConfigFromEnv() (ClientConfig, error) {
+	cfg := ClientConfig{
+		URLs:                splitURLs(os.Getenv("EVM_RPC_URL")),
+		HealthCheckInterval: 15 * time.Second,
+		FailureThreshold:    3,
+		BreakerCooldown:     30 * time.Second,
+		MaxBlockLag:         5,
+	}
+	if len(cfg.URLs) == 0 {
+		cfg.URLs = []string{"http://hardhat-node:8545"}
+	}
+
+	var err error
+	if v := os.Getenv("EVM_RPC_HEALTH_INTERVAL"); v != "" {
+		if cfg.HealthCheckInterval, err = time.ParseDuration(v); err != nil || cfg.HealthCheckInterval <= 0 {
+			return cfg, fmt.Errorf("invalid EVM_RPC_HEALTH_INTERVAL %q", v)
+		}
+	}
+	if v := os.Getenv("EVM_RPC_RATE_LIMIT"); v != "" {
+		if cfg.RateLimit, err = strconv.ParseFloat(v, 64); err != nil || cfg.RateLimit < 0 {
+			return cfg, fmt.Errorf("invalid EVM_RPC_RATE_LIMIT %q", v)
+		}
+	}
+	if v := os.Getenv("EVM_RPC_FAILURE_THRESHOLD"); v != "" {
+		if cfg.FailureThreshold, err = strconv.Atoi(v); err != nil || cfg.FailureThreshold <= 0 {
+			return cfg, fmt.Errorf("invalid EVM_RPC_FAILURE_THRESHOLD %q", v)
+		}
+	}
+	if v := os.Getenv("EVM_RPC_BREAKER_COOLDOWN"); v != "" {
+		if cfg.BreakerCooldown, err = time.ParseDuration(v); err != nil || cfg.BreakerCooldown <= 0 {
+			return cfg, fmt.Errorf("invalid EVM_RPC_BREAKER_COOLDOWN %q", v)
+		}
+	}
+	if v := os.Getenv("EVM_RPC_MAX_BLOCK_LAG"); v != "" {
+		if cfg.MaxBlockLag, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return cfg, fmt.Errorf("invalid EVM_RPC_MAX_BLOCK_LAG %q", v)
+		}
+	}
+	return cfg, nil
+}
+
+func splitURLs(s string) []string {
+	var urls []string
+	for _, url := range strings.Split(s, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// endpoint is one RPC URL with its health
+type endpoint struct {
+	url     string
+	client  *ethclient.Client
+	limiter *rate.Limiter
+
+	mu sync.Mutex
+	// latency is a moving average of successful request durations
+	latency  time.Duration
+	failures int
+	// openUntil is when a tripped circuit breaker lets a trial request through again
+	openUntil time.Time
+	head      uint64
+	lagging   bool
+}
+
+func (e *endpoint) succeeded(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = (4*e.latency + latency) / 5
+	}
+	e.failures = 0
+	e.openUntil = time.Time{}
+}
+
+func (e *endpoint) failed(threshold int, cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures++
+	// A failed trial request reopens the breaker right away
+	if e.failures >= threshold {
+		e.openUntil = time.Now().Add(cooldown)
+	}
+}
+
+// rank orders endpoints: closed breakers first, then those in sync with the chain, then those whose
+// last request succeeded, then the fastest. Endpoints not measured yet count as fastest, so they get tried.
+func (e *endpoint) rank(now time.Time) (open, lagging, failing bool, latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return now.Before(e.openUntil), e.lagging, e.failures > 0, e.latency
+}
+
+// candidates returns the endpoints in the order requests should try them. Endpoints with an open
+// breaker come last rather than not at all, a request still has a chance when every endpoint is down.
+func (c *Client) candidates() []*endpoint {
+	if len(c.endpoints) == 1 {
+		return c.endpoints
+	}
+	type ranked struct {
+		e       *endpoint
+		open    bool
+		lagging bool
+		failing bool
+		latency time.Duration
+	}
+	now := time.Now()
+	list := make([]ranked, len(c.endpoints))
+	for i, e := range c.endpoints {
+		open, lagging, failing, latency := e.rank(now)
+		list[i] = ranked{e, open, lagging, failing, latency}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.open != b.open {
+			return !a.open
+		}
+		if a.lagging != b.lagging {
+			return !a.lagging
+		}
+		if a.failing != b.failing {
+			return !a.failing
+		}
+		return a.latency < b.latency
+	})
+	endpoints := make([]*endpoint, len(list))
+	for i, r := range list {
+		endpoints[i] = r.e
+	}
+	return endpoints
+}
+
+// call runs f against the best endpoint, failing over to the next one on transport errors, server
+// errors and rate limiting. Errors from the node about the request itself, such as a revert or a
+// missing receipt, are returned as is. Endpoints out of rate limit tokens are tried last, after waiting.
+// The request is traced as a client span named after the JSON-RPC method.
+func call[T any](ctx context.Context, c *Client, method string, f func(*ethclient.Client) (T, error)) (result T, err error) {
+	ctx, span := tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", method),
+	))
+	attempts := 0
+	defer func() {
+		span.SetAttributes(attribute.Int("rpc.attempts", attempts))
+		// A missing receipt or block is an answer, not a failure
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	var (
+		zero    T
+		lastErr error
+		limited []*endpoint
+	)
+	// try reports whether the request is settled, successfully or with an error no endpoint can fix
+	try := func(e *endpoint) (T, bool, error) {
+		attempts++
+		start := time.Now()
+		result, err := f(e.client)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return zero, true, err
+		case errors.Is(err, rpc.ErrNotificationsUnsupported):
+			// An HTTP endpoint was asked for a subscription, a WebSocket one may follow
+			lastErr = err
+			return zero, false, err
+		case err != nil && failover(err):
+			e.failed(c.config.FailureThreshold, c.config.BreakerCooldown)
+			lastErr = fmt.Errorf("%s: %w", e.url, err)
+			return zero, false, err
+		}
+		e.succeeded(time.Since(start))
+		return result, true, err
+	}
+
+	for _, e := range c.candidates() {
+		if e.limiter != nil && !e.limiter.Allow() {
+			limited = append(limited, e)
+			continue
+		}
+		if result, done, err := try(e); done {
+			return result, err
+		}
+	}
+	for _, e := range limited {
+		if err := e.limiter.Wait(ctx); err != nil {
+			return zero, err
+		}
+		if result, done, err := try(e); done {
+			return result, err
+		}
+	}
+	return zero, lastErr
+}
+
+// failover reports whether err is the endpoint's fault, so another endpoint may answer
+func failover(err error) bool {
+	if errors.Is(err, ethereum.NotFound) || isRangeTooLarge(err) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// The node answered: only rate limiting is worth another endpoint
+		message := strings.ToLower(rpcErr.Error())
+		return rpcErr.ErrorCode() == -32005 || strings.Contains(message, "rate limit") ||
+			strings.Contains(message, "too many requests")
+	}
+	// Anything else failed before a JSON-RPC answer, such as a refused connection or a malformed response
+	return true
+}
+
+// isRangeTooLarge reports whether an eth_getLogs error asks for a smaller block range or fewer results.
+// Providers word it differently.
+func isRangeTooLarge(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, hint := range []string{
+		"block range", "range too", "range is too", "query returned more than", "response size",
+		"too many blocks", "is limited to", "max results",
+	} {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterLogs splits the query's block range in halves while the provider rejects it as too large
+func (c *Client) filterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	logs, err := call(ctx, c, "eth_getLogs", func(client *ethclient.Client) ([]types.Log, error) {
+		return client.FilterLogs(ctx, query)
+	})
+	if err == nil || !isRangeTooLarge(err) || query.BlockHash != nil ||
+		query.FromBlock == nil || query.ToBlock == nil || query.FromBlock.Sign() < 0 || query.ToBlock.Sign() < 0 {
+		return logs, err
+	}
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	if to <= from {
+		return nil, err
+	}
+
+	mid := from + (to-from)/2
+	left, right := query, query
+	left.ToBlock = new(big.Int).SetUint64(mid)
+	right.FromBlock = new(big.Int).SetUint64(mid + 1)
+	leftLogs, err := c.filterLogs(ctx, left)
+	if err != nil {
+		return nil, err
+	}
+	rightLogs, err := c.filterLogs(ctx, right)
+	if err != nil {
+		return nil, err
+	}
+	return append(leftLogs, rightLogs...), nil
+}
+
+// healthCheck polls every endpoint's block number until the client is closed. Endpoints that answer
+// have their breaker closed and latency updated, those trailing the highest head are marked lagging.
+func (c *Client) healthCheck(ctx context.Context) {
+	ticker := time.NewTicker(c.config.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		c.checkEndpoints(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) checkEndpoints(ctx context.Context) {
+	var (
+		wg      sync.WaitGroup
+		highest uint64
+		mu      sync.Mutex
+	)
+	for _, e := range c.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.config.HealthCheckInterval)
+			defer cancel()
+			start := time.Now()
+			head, err := e.client.BlockNumber(checkCtx)
+			if err != nil {
+				if ctx.Err() == nil {
+					e.failed(c.config.FailureThreshold, c.config.BreakerCooldown)
+				}
+				return
+			}
+			e.succeeded(time.Since(start))
+			e.mu.Lock()
+			e.head = head
+			e.mu.Unlock()
+			mu.Lock()
+			highest = max(highest, head)
+			mu.Unlock()
+		}(e)
+	}
+	wg.Wait()
+
+	for _, e := range c.endpoints {
+		e.mu.Lock()
+		e.lagging = e.head+c.config.MaxBlockLag < highest
+		e.mu.Unlock()
+	}
+}
@@ -0,0 +1,182 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RemoteSigner signs through a JSON-RPC signer holding the key, such as web3signer (eth_signTransaction,
+// the default method) or Clef (account_signTransaction). The signed transaction is checked against the
+// request, so a signer cannot change what is sent.
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+	method  string
+}
+
+// NewRemoteSigner connects to the signer at url. When address is zero the signer's first eth_accounts
+// entry is used. httpClient may be nil.
+func NewRemoteSigner(ctx context.Context, url string, address common.Address, method string, httpClient *http.Client) (*RemoteSigner, error) {
+	if url == "" {
+		return nil, errors.New("remote signer URL not set")
+	}
+	if method == "" {
+		method = "eth_signTransaction"
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+
+	if address == (common.Address{}) {
+		var accounts []common.Address
+		if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+			return nil, fmt.Errorf("failed to list remote signer accounts: %w", err)
+		}
+		if len(accounts) == 0 {
+			return nil, errors.New("remote signer has no accounts")
+		}
+		address = accounts[0]
+	}
+	return &RemoteSigner{client: client, address: address, method: method}, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, s.method, newSignTxArgs(s.address, tx, chainID)); err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+
+	// web3signer answers the raw transaction, Clef an object holding it
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err != nil {
+		var clef struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := json.Unmarshal(result, &clef); err != nil || len(clef.Raw) == 0 {
+			return nil, fmt.Errorf("remote signer returned an unexpected result: %s", result)
+		}
+		raw = clef.Raw
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid transaction: %w", err)
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	if signed.Type() != tx.Type() || signer.Hash(signed) != signer.Hash(tx) {
+		return nil, errors.New("remote signer signed a different transaction")
+	}
+	if from, err := types.Sender(signer, signed); err != nil || from != s.address {
+		return nil, fmt.Errorf("remote signer signed as %s, expected %s", from.Hex(), s.address.Hex())
+	}
+	return signed, nil
+}
+
+// signTxArgs are the eth_signTransaction parameters
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+func newSignTxArgs(from common.Address, tx *types.Transaction, chainID *big.Int) signTxArgs {
+	args := signTxArgs{
+		From:    from,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+	return args
+}
+
+// transaction rebuilds the transaction the arguments describe
+func (a signTxArgs) transaction() (*types.Transaction, error) {
+	if a.To == nil {
+		return nil, errors.New("contract creation is not supported")
+	}
+	if a.ChainID == nil {
+		return nil, errors.New("chainId is required")
+	}
+	fees := &Fees{GasPrice: (*big.Int)(a.GasPrice)}
+	if a.MaxFeePerGas != nil {
+		fees = &Fees{GasFeeCap: (*big.Int)(a.MaxFeePerGas), GasTipCap: new(big.Int)}
+		if a.MaxPriorityFeePerGas != nil {
+			fees.GasTipCap = (*big.Int)(a.MaxPriorityFeePerGas)
+		}
+	}
+	if fees.MaxPrice() == nil {
+		return nil, errors.New("gasPrice or maxFeePerGas is required")
+	}
+	value := new(big.Int)
+	if a.Value != nil {
+		value = (*big.Int)(a.Value)
+	}
+	return NewTx((*big.Int)(a.ChainID), uint64(a.Nonce), *a.To, value, uint64(a.Gas), fees, a.Data), nil
+}
+
+// NewSignerServer serves eth_accounts and eth_signTransaction for signer over JSON-RPC. It stands in for
+// a remote signer in tests and local setups, the key stays in the serving process.
+func NewSignerServer(signer Signer) http.Handler {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &signerService{signer: signer}); err != nil {
+		// Only fails when the service has no suitable methods
+		panic(err)
+	}
+	return server
+}
+
+type signerService struct {
+	signer Signer
+}
+
+func (s *signerService) Accounts() []common.Address {
+	return []common.Address{s.signer.Address()}
+}
+
+func (s *signerService) SignTransaction(ctx context.Context, args signTxArgs) (hexutil.Bytes, error) {
+	if args.From != s.signer.Address() {
+		return nil, fmt.Errorf("unknown account %s", args.From.Hex())
+	}
+	tx, err := args.transaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := s.signer.SignTx(ctx, tx, (*big.Int)(args.ChainID))
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
@@ -0,0 +1,201 @@
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/config"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions for one account. The key may live in process, in an encrypted keystore or
+// behind a remote signer, callers only see the address.
+type Signer interface {
+	Address() common.Address
+	// SignTx returns tx signed for chainID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Signer kinds, selected by the <PREFIX>_SIGNER environment variable
+const (
+	SignerKey      = "key"
+	SignerKeystore = "keystore"
+	SignerRemote   = "remote"
+)
+
+// DevPrivateKey is Hardhat's account #0, funded on local nodes and known to everyone
+const DevPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// ErrSignerNotConfigured is returned by NewSigner when no signer environment variable is set
+var ErrSignerNotConfigured = errors.New("signer not configured")
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner creates a signer for key
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// ParseKeySigner creates a signer from a hex private key, with or without the 0x prefix
+func ParseKeySigner(privateKeyHex string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return NewKeySigner(key), nil
+}
+
+// NewKeystoreSigner decrypts a geth keystore file (web3 secret storage) with passphrase
+func NewKeystoreSigner(path, passphrase string) (*KeySigner, error) {
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	key, err := keystore.DecryptKey(encrypted, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// SignerConfig selects a signer. Production refuses the well-known development accounts.
+type SignerConfig struct {
+	Kind       string
+	PrivateKey string
+	// Keystore is the path of an encrypted key file and Password its passphrase
+	Keystore string
+	Password string
+	// URL, Address and Method address a remote signer, see RemoteSigner
+	URL     string
+	Address common.Address
+	Method  string
+
+	Production bool
+}
+
+// SignerConfigFromEnv reads the signer of one role from variables named after prefix, e.g. FAUCET:
+//
+//   - FAUCET_SIGNER: key, keystore or remote. It defaults to the kind whose variables are set.
+//   - FAUCET_PRIVATE_KEY or FAUCET_PRIVATE_KEY_FILE: a hex private key
+//   - FAUCET_KEYSTORE with FAUCET_KEYSTORE_PASSWORD or FAUCET_KEYSTORE_PASSWORD_FILE: a geth keystore file
+//   - FAUCET_SIGNER_URL, FAUCET_SIGNER_ADDRESS and FAUCET_SIGNER_METHOD: a remote JSON-RPC signer
+//
+// Kind is empty when none is set. Production is set when APP_ENV is production.
+func SignerConfigFromEnv(prefix string) (SignerConfig, error) {
+	cfg := SignerConfig{
+		Kind:       os.Getenv(prefix + "_SIGNER"),
+		Keystore:   os.Getenv(prefix + "_KEYSTORE"),
+		URL:        os.Getenv(prefix + "_SIGNER_URL"),
+		Method:     os.Getenv(prefix + "_SIGNER_METHOD"),
+		Production: config.IsProduction(),
+	}
+
+	var err error
+	if cfg.PrivateKey, err = envOrFile(prefix + "_PRIVATE_KEY"); err != nil {
+		return cfg, err
+	}
+	if cfg.Password, err = envOrFile(prefix + "_KEYSTORE_PASSWORD"); err != nil {
+		return cfg, err
+	}
+	if v := os.Getenv(prefix + "_SIGNER_ADDRESS"); v != "" {
+		if !common.IsHexAddress(v) {
+			return cfg, fmt.Errorf("invalid %s_SIGNER_ADDRESS %q", prefix, v)
+		}
+		cfg.Address = common.HexToAddress(v)
+	}
+
+	if cfg.Kind == "" {
+		switch {
+		case cfg.URL != "":
+			cfg.Kind = SignerRemote
+		case cfg.Keystore != "":
+			cfg.Kind = SignerKeystore
+		case cfg.PrivateKey != "":
+			cfg.Kind = SignerKey
+		}
+	}
+	switch cfg.Kind {
+	case "", SignerKey, SignerKeystore, SignerRemote:
+		return cfg, nil
+	default:
+		return cfg, fmt.Errorf("invalid %s_SIGNER %q, expected key, keystore or remote", prefix, cfg.Kind)
+	}
+}
+
+// envOrFile returns the variable name, or the trimmed content of the file named by name_FILE
+func envOrFile(name string) (string, error) {
+	if v := os.Getenv(name); v != "" {
+		return v, nil
+	}
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", name, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// NewSigner creates the signer cfg selects. It fails with ErrSignerNotConfigured when Kind is empty,
+// and in production when the signer is a development account.
+func NewSigner(ctx context.Context, cfg SignerConfig) (Signer, error) {
+	var (
+		signer Signer
+		err    error
+	)
+	switch cfg.Kind {
+	case "":
+		return nil, ErrSignerNotConfigured
+	case SignerKey:
+		signer, err = ParseKeySigner(cfg.PrivateKey)
+	case SignerKeystore:
+		signer, err = NewKeystoreSigner(cfg.Keystore, cfg.Password)
+	case SignerRemote:
+		signer, err = NewRemoteSigner(ctx, cfg.URL, cfg.Address, cfg.Method, nil)
+	default:
+		return nil, fmt.Errorf("unknown signer kind %q", cfg.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Production && IsDevAccount(signer.Address()) {
+		return nil, fmt.Errorf("refusing to sign with development account %s in production", signer.Address().Hex())
+	}
+	return signer, nil
+}
+
+// devAccounts are the first Hardhat and Anvil accounts, whose keys are public
+var devAccounts = map[common.Address]bool{
+	common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"): true,
+	common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"): true,
+	common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"): true,
+	common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906"): true,
+	common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65"): true,
+}
+
+// IsDevAccount reports whether address is one of the default Hardhat accounts
+func IsDevAccount(address common.Address) bool {
+	return devAccounts[address]
+}
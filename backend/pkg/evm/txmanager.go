@@ -0,0 +1,748 @@
+package evm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// TxStatus is the lifecycle state of a managed transaction
+type TxStatus string
+
+const (
+	// TxRequested was submitted without a nonce, the manager running for the key sends it on its next poll
+	TxRequested TxStatus = "requested"
+	// TxQueued has a nonce but has not been broadcast yet
+	TxQueued TxStatus = "queued"
+	// TxPending is broadcast and waiting to be mined
+	TxPending TxStatus = "pending"
+	// TxConfirmed was mined and succeeded
+	TxConfirmed TxStatus = "confirmed"
+	// TxReverted was mined and failed
+	TxReverted TxStatus = "reverted"
+	// TxDropped lost its nonce to a transaction the manager did not send
+	TxDropped TxStatus = "dropped"
+	// TxFailed was rejected by the node or could not be signed, its nonce was reused
+	TxFailed TxStatus = "failed"
+)
+
+// Final reports whether the status can no longer change
+func (s TxStatus) Final() bool {
+	return s == TxConfirmed || s == TxReverted || s == TxDropped || s == TxFailed
+}
+
+// transferGas is the gas limit of a plain ETH transfer, used by gap fillers
+const transferGas = 21000
+
+// ErrTxNotFound is returned by a TxStore for an unknown transaction ID
+var ErrTxNotFound = errors.New("transaction not found")
+
+// errSign wraps signing errors, a transaction that failed to sign never left the process
+var errSign = errors.New("failed to sign transaction")
+
+// rejections are the errors of nodes refusing a transaction, which they then do not relay
+var rejections = []string{
+	"nonce too low",
+	"nonce too high",
+	"insufficient funds",
+	"underpriced",
+	"intrinsic gas too low",
+	"exceeds block gas limit",
+	"gas limit reached",
+	"fee cap less than block base fee",
+	"max fee per gas less than block base fee",
+	"max priority fee per gas higher than max fee per gas",
+	"tip above fee cap",
+	"exceeds the configured cap",
+	"invalid sender",
+	"invalid transaction",
+	"oversized data",
+	"negative value",
+	"transaction type not supported",
+}
+
+// TxRecord is a transaction sent by a TxManager. It keeps its ID when it is replaced with a higher gas
+// price, Hash is the latest broadcast and Hashes every one of them.
+type TxRecord struct {
+	ChainID  uint64
+	ID       string
+	From     common.Address
+	To       common.Address
+	Value    *big.Int
+	Data     []byte
+	Nonce    uint64
+	GasLimit uint64
+	// GasTipCap and GasFeeCap price a dynamic-fee transaction, GasPrice a legacy one
+	GasTipCap   *big.Int
+	GasFeeCap   *big.Int
+	GasPrice    *big.Int
+	Urgency     Urgency
+	Hash        common.Hash
+	Hashes      []common.Hash
+	Status      TxStatus
+	Attempts    uint32
+	Error       string
+	BlockNumber uint64
+	CreatedAt   int64
+	UpdatedAt   int64
+	LastSentAt  int64
+}
+
+// TxStore persists the transactions of a TxManager so they are tracked across restarts
+type TxStore interface {
+	// SaveTx inserts or replaces a transaction by ID
+	SaveTx(ctx context.Context, tx *TxRecord) error
+	// GetTx returns a transaction, ErrTxNotFound if there is none
+	GetTx(ctx context.Context, id string) (*TxRecord, error)
+	// OutstandingTxs returns the requested, queued and pending transactions of an address on a chain
+	OutstandingTxs(ctx context.Context, chainID uint64, from common.Address) ([]*TxRecord, error)
+}
+
+// TxBackend is the subset of an EVM client a TxManager needs; *Client satisfies it
+type TxBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SuggestFees(ctx context.Context, urgency Urgency) (*Fees, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// TxManagerConfig tunes receipt polling and gas bumping
+type TxManagerConfig struct {
+	ChainID *big.Int
+	// PollInterval is how often receipts are checked, default 2s
+	PollInterval time.Duration
+	// StuckAfter is how long a transaction may wait unmined before it is resent with more gas, default 1m
+	StuckAfter time.Duration
+	// GasBumpPercent is the gas price increase of a replacement, at least the 10% nodes require
+	GasBumpPercent int64
+	// MaxGasPrice caps the fee cap (or gas price) of replacements, nil for no cap
+	MaxGasPrice *big.Int
+}
+
+// TxManagerConfigFromEnv reads TX_POLL_INTERVAL, TX_STUCK_AFTER, TX_GAS_BUMP_PERCENT and TX_MAX_GAS_PRICE (wei).
+// ChainID is left for the caller.
+func TxManagerConfigFromEnv() (TxManagerConfig, error) {
+	var cfg TxManagerConfig
+	durations := []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"TX_POLL_INTERVAL", &cfg.PollInterval},
+		{"TX_STUCK_AFTER", &cfg.StuckAfter},
+	}
+	for _, d := range durations {
+		if s := os.Getenv(d.env); s != "" {
+			v, err := time.ParseDuration(s)
+			if err != nil || v <= 0 {
+				return cfg, fmt.Errorf("invalid %s %q", d.env, s)
+			}
+			*d.dst = v
+		}
+	}
+	if s := os.Getenv("TX_GAS_BUMP_PERCENT"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v < 10 {
+			return cfg, fmt.Errorf("invalid TX_GAS_BUMP_PERCENT %q, expected at least 10", s)
+		}
+		cfg.GasBumpPercent = v
+	}
+	if s := os.Getenv("TX_MAX_GAS_PRICE"); s != "" {
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok || v.Sign() <= 0 {
+			return cfg, fmt.Errorf("invalid TX_MAX_GAS_PRICE %q", s)
+		}
+		cfg.MaxGasPrice = v
+	}
+	return cfg, nil
+}
+
+// TxRequest is a transaction to send. GasLimit is estimated when zero, Urgency defaults to the backend's.
+type TxRequest struct {
+	To       common.Address
+	Value    *big.Int
+	Data     []byte
+	GasLimit uint64
+	Urgency  Urgency
+}
+
+// TxManager sends transactions from one key. It allocates nonces locally so concurrent senders never
+// share one, persists every transaction before broadcasting it, polls receipts, resends stuck
+// transactions with a higher gas price and fills nonce gaps that would block later transactions.
+// Only one process may Send and Run for a key: two processes sending from the same key race on nonces.
+// Other processes sharing the store Submit, and the running manager sends their transactions.
+type TxManager struct {
+	backend TxBackend
+	signer  Signer
+	from    common.Address
+	store   TxStore
+	config  TxManagerConfig
+	logger  *zap.Logger
+
+	// mu serializes nonce allocation and broadcasts of new transactions
+	mu sync.Mutex
+	// pollMu serializes polls, which hold mu only to read the outstanding transactions
+	pollMu sync.Mutex
+	// next is the next nonce to allocate, nil until synced with the node and the store
+	next *uint64
+}
+
+// NewTxManager creates a manager sending from the signer's address
+func NewTxManager(backend TxBackend, signer Signer, store TxStore, config TxManagerConfig, logger *zap.Logger) *TxManager {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	if config.StuckAfter <= 0 {
+		config.StuckAfter = time.Minute
+	}
+	if config.GasBumpPercent < 10 {
+		config.GasBumpPercent = 10
+	}
+	return &TxManager{
+		backend: backend,
+		signer:  signer,
+		from:    signer.Address(),
+		store:   store,
+		config:  config,
+		logger:  logger,
+	}
+}
+
+// From returns the address transactions are sent from
+func (m *TxManager) From() common.Address {
+	return m.from
+}
+
+// Outstanding returns the requested, queued and pending transactions of the key, whoever submitted them
+func (m *TxManager) Outstanding(ctx context.Context) ([]*TxRecord, error) {
+	return m.store.OutstandingTxs(ctx, m.config.ChainID.Uint64(), m.from)
+}
+
+// Send allocates a nonce, persists and broadcasts a transaction. The returned record is pending;
+// follow it with Status. A transaction the node rejects is marked failed and its nonce is reused. One whose
+// broadcast failed otherwise, e.g. timed out, may have reached the node: it is returned pending too, and
+// Poll finds its receipt or sends it again.
+func (m *TxManager) Send(ctx context.Context, req TxRequest) (*TxRecord, error) {
+	record := m.newRecord(req, TxQueued)
+	if err := m.send(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Submit stores a transaction for the manager running for the key to send on its next poll. It allocates
+// no nonce and makes no RPC call, so any process sharing the store can submit, such as API replicas while
+// the worker runs the manager. The returned record is requested; follow it with Status.
+func (m *TxManager) Submit(ctx context.Context, req TxRequest) (*TxRecord, error) {
+	record := m.newRecord(req, TxRequested)
+	if err := m.store.SaveTx(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to save transaction: %w", err)
+	}
+	return record, nil
+}
+
+func (m *TxManager) newRecord(req TxRequest, status TxStatus) *TxRecord {
+	value := req.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	now := time.Now().Unix()
+	return &TxRecord{
+		ChainID:   m.config.ChainID.Uint64(),
+		ID:        newTxID(),
+		From:      m.from,
+		To:        req.To,
+		Value:     value,
+		Data:      req.Data,
+		GasLimit:  req.GasLimit,
+		Urgency:   req.Urgency,
+		Status:    status,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// send prices record, then allocates its nonce, persists and broadcasts it
+func (m *TxManager) send(ctx context.Context, record *TxRecord) error {
+	if record.GasLimit == 0 {
+		estimated, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{From: m.from, To: &record.To, Value: record.Value, Data: record.Data})
+		if err != nil {
+			return fmt.Errorf("failed to estimate gas: %w", err)
+		}
+		record.GasLimit = estimated
+	}
+	fees, err := m.backend.SuggestFees(ctx, record.Urgency)
+	if err != nil {
+		return fmt.Errorf("failed to get fees: %w", err)
+	}
+	record.setFees(fees)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record.Status = TxQueued
+	record.UpdatedAt = time.Now().Unix()
+
+	// A nonce too low means another sender used the key, resync once and retry
+	for attempt := 0; ; attempt++ {
+		nonce, err := m.nextNonce(ctx)
+		if err != nil {
+			return err
+		}
+		record.Nonce = nonce
+		if err := m.store.SaveTx(ctx, record); err != nil {
+			return fmt.Errorf("failed to save transaction: %w", err)
+		}
+
+		err = m.broadcast(ctx, record)
+		if err == nil {
+			*m.next = nonce + 1
+			if err := m.store.SaveTx(ctx, record); err != nil {
+				// The queued record is still stored, polling finds it pending by its hash
+				m.logger.Error("Failed to save sent transaction", zap.String("id", record.ID), zap.Error(err))
+			}
+			return nil
+		}
+		if attempt == 0 && isNonceTooLow(err) {
+			// Nothing was sent, the record is saved again with the resynced nonce
+			m.next = nil
+			record.Status, record.Hashes = TxQueued, nil
+			continue
+		}
+
+		if !isRejected(err) {
+			// The node may have taken the transaction before failing, so its nonce stays used. Left
+			// pending without a send time, the next poll looks up its receipt or resends it.
+			*m.next = nonce + 1
+			record.Status = TxPending
+			record.Error = err.Error()
+			record.UpdatedAt = time.Now().Unix()
+			m.logger.Warn("Transaction broadcast failed, it is resent unless mined",
+				zap.String("id", record.ID), zap.Uint64("nonce", nonce), zap.Error(err))
+			if err := m.store.SaveTx(ctx, record); err != nil {
+				m.logger.Error("Failed to save sent transaction", zap.String("id", record.ID), zap.Error(err))
+			}
+			return nil
+		}
+
+		// Nothing was sent, fail the record so the nonce can be reused
+		record.Status = TxFailed
+		record.Error = err.Error()
+		record.UpdatedAt = time.Now().Unix()
+		if saveErr := m.store.SaveTx(ctx, record); saveErr != nil {
+			m.logger.Error("Failed to save failed transaction", zap.String("id", record.ID), zap.Error(saveErr))
+		}
+		return fmt.Errorf("failed to send transaction: %w", err)
+	}
+}
+
+// Status returns a transaction sent by this manager or another one sharing the store
+func (m *TxManager) Status(ctx context.Context, id string) (*TxRecord, error) {
+	return m.store.GetTx(ctx, id)
+}
+
+// Run polls receipts every PollInterval until ctx is done
+func (m *TxManager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Poll(ctx); err != nil && ctx.Err() == nil {
+			m.logger.Warn("Failed to poll transactions", zap.String("from", m.from.Hex()), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks the outstanding transactions once: queued ones are broadcast, mined ones are settled,
+// stuck ones are resent with more gas, missing nonces below them are filled with empty transfers and
+// submitted ones are sent. Only reading the outstanding transactions holds up Send, the RPC calls don't.
+func (m *TxManager) Poll(ctx context.Context) error {
+	m.pollMu.Lock()
+	defer m.pollMu.Unlock()
+
+	// Read the mined nonce first: a transaction below it has a receipt by the time receipts are read
+	confirmed, err := m.backend.NonceAt(ctx, m.from, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	// Send saves and broadcasts under mu, so no transaction is read half sent. Transactions it sends
+	// afterwards take nonces above every one read here, which leaves the gaps below to this poll.
+	m.mu.Lock()
+	records, err := m.store.OutstandingTxs(ctx, m.config.ChainID.Uint64(), m.from)
+	if err == nil && m.next != nil && *m.next < confirmed {
+		*m.next = confirmed
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to load transactions: %w", err)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Nonce < records[j].Nonce })
+
+	outstanding := make(map[uint64]bool)
+	var highest uint64
+	var requested []*TxRecord
+	for _, record := range records {
+		if record.Status == TxRequested {
+			requested = append(requested, record)
+			continue
+		}
+		if err := m.track(ctx, record, confirmed); err != nil {
+			m.logger.Warn("Failed to track transaction", zap.String("id", record.ID), zap.Error(err))
+		}
+		if !record.Status.Final() {
+			outstanding[record.Nonce] = true
+			if record.Nonce >= highest {
+				highest = record.Nonce + 1
+			}
+		}
+	}
+
+	// A nonce nobody is sending blocks every transaction above it
+	for nonce := confirmed; nonce < highest; nonce++ {
+		if outstanding[nonce] {
+			continue
+		}
+		if err := m.fillGap(ctx, nonce); err != nil {
+			m.logger.Warn("Failed to fill nonce gap", zap.Uint64("nonce", nonce), zap.Error(err))
+		}
+	}
+
+	sort.Slice(requested, func(i, j int) bool { return requested[i].CreatedAt < requested[j].CreatedAt })
+	for _, record := range requested {
+		m.sendRequested(ctx, record)
+	}
+	return nil
+}
+
+// sendRequested sends a submitted transaction. One whose gas cannot be estimated is failed, one that
+// could not be priced stays requested for the next poll.
+func (m *TxManager) sendRequested(ctx context.Context, record *TxRecord) {
+	err := m.send(ctx, record)
+	if err == nil {
+		m.logger.Info("Sent submitted transaction", zap.String("id", record.ID), zap.String("tx_hash", record.Hash.Hex()))
+		return
+	}
+	m.logger.Warn("Failed to send submitted transaction", zap.String("id", record.ID), zap.Error(err))
+	if record.Status != TxRequested || record.GasLimit != 0 {
+		return
+	}
+	record.Status = TxFailed
+	record.Error = err.Error()
+	record.UpdatedAt = time.Now().Unix()
+	if err := m.store.SaveTx(ctx, record); err != nil {
+		m.logger.Error("Failed to save failed transaction", zap.String("id", record.ID), zap.Error(err))
+	}
+}
+
+// track advances one outstanding transaction
+func (m *TxManager) track(ctx context.Context, record *TxRecord, confirmed uint64) error {
+	if record.Status == TxQueued {
+		// Saved but maybe not broadcast, e.g. the process stopped in between. Signers sign deterministically (RFC 6979),
+		// so re-signing finds the hash it would have had.
+		if _, err := m.sign(ctx, record); err != nil {
+			return err
+		}
+	}
+
+	// Any of the broadcasts may have been mined, try the latest first
+	for i := len(record.Hashes) - 1; i >= 0; i-- {
+		receipt, err := m.backend.TransactionReceipt(ctx, record.Hashes[i])
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get receipt: %w", err)
+		}
+		record.Hash = record.Hashes[i]
+		record.BlockNumber = receipt.BlockNumber.Uint64()
+		record.Status = TxConfirmed
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			record.Status = TxReverted
+		}
+		record.UpdatedAt = time.Now().Unix()
+		m.logger.Info("Transaction mined",
+			zap.String("id", record.ID),
+			zap.String("tx_hash", record.Hash.Hex()),
+			zap.String("status", string(record.Status)))
+		return m.store.SaveTx(ctx, record)
+	}
+
+	if record.Nonce < confirmed {
+		record.Status = TxDropped
+		record.Error = fmt.Sprintf("nonce %d was used by another transaction", record.Nonce)
+		record.UpdatedAt = time.Now().Unix()
+		m.logger.Warn("Transaction dropped", zap.String("id", record.ID), zap.Uint64("nonce", record.Nonce))
+		return m.store.SaveTx(ctx, record)
+	}
+
+	if record.Status == TxQueued {
+		if err := m.broadcast(ctx, record); err != nil {
+			return err
+		}
+		return m.store.SaveTx(ctx, record)
+	}
+
+	if time.Since(time.Unix(record.LastSentAt, 0)) < m.config.StuckAfter {
+		return nil
+	}
+	return m.bump(ctx, record)
+}
+
+// bump resends a stuck transaction with the same nonce and higher fees
+func (m *TxManager) bump(ctx context.Context, record *TxRecord) error {
+	suggested, err := m.backend.SuggestFees(ctx, record.Urgency)
+	if err != nil {
+		return fmt.Errorf("failed to get fees: %w", err)
+	}
+
+	// At the cap the same transaction is rebroadcast, in case the node forgot it
+	previous, previousHash := record.fees(), record.Hash
+	if bumped := m.bumpFees(previous, suggested); bumped.MaxPrice().Cmp(previous.MaxPrice()) > 0 {
+		record.setFees(bumped)
+	}
+	if err := m.broadcast(ctx, record); err != nil {
+		record.setFees(previous)
+		record.Hash = previousHash
+		return err
+	}
+	m.logger.Info("Resent stuck transaction",
+		zap.String("id", record.ID),
+		zap.Uint64("nonce", record.Nonce),
+		zap.String("max_gas_price", record.fees().MaxPrice().String()),
+		zap.String("tx_hash", record.Hash.Hex()))
+	return m.store.SaveTx(ctx, record)
+}
+
+// bumpFees raises fees by GasBumpPercent, or to the suggested fees if they are higher, up to MaxGasPrice.
+// Nodes only replace a transaction whose tip and fee cap both rise by 10%.
+func (m *TxManager) bumpFees(previous, suggested *Fees) *Fees {
+	if !previous.Dynamic() {
+		return &Fees{GasPrice: m.bumpPrice(previous.GasPrice, suggested.MaxPrice())}
+	}
+	suggestedTip := suggested.GasTipCap
+	if suggestedTip == nil {
+		suggestedTip = suggested.GasPrice
+	}
+	bumped := &Fees{
+		GasTipCap: m.bumpPrice(previous.GasTipCap, suggestedTip),
+		GasFeeCap: m.bumpPrice(previous.GasFeeCap, suggested.MaxPrice()),
+	}
+	if bumped.GasTipCap.Cmp(bumped.GasFeeCap) > 0 {
+		bumped.GasTipCap = new(big.Int).Set(bumped.GasFeeCap)
+	}
+	return bumped
+}
+
+func (m *TxManager) bumpPrice(previous, suggested *big.Int) *big.Int {
+	price := new(big.Int).Mul(previous, big.NewInt(100+m.config.GasBumpPercent))
+	price.Div(price, big.NewInt(100))
+	if price.Cmp(previous) <= 0 {
+		price.Add(previous, big.NewInt(1))
+	}
+	if suggested != nil && suggested.Cmp(price) > 0 {
+		price.Set(suggested)
+	}
+	if m.config.MaxGasPrice != nil && price.Cmp(m.config.MaxGasPrice) > 0 {
+		price.Set(m.config.MaxGasPrice)
+	}
+	return price
+}
+
+// fillGap sends an empty transfer to self at nonce
+func (m *TxManager) fillGap(ctx context.Context, nonce uint64) error {
+	// Everything above the gap waits for it
+	fees, err := m.backend.SuggestFees(ctx, UrgencyFast)
+	if err != nil {
+		return fmt.Errorf("failed to get fees: %w", err)
+	}
+	now := time.Now().Unix()
+	record := &TxRecord{
+		ChainID:   m.config.ChainID.Uint64(),
+		ID:        newTxID(),
+		From:      m.from,
+		To:        m.from,
+		Value:     new(big.Int),
+		Nonce:     nonce,
+		GasLimit:  transferGas,
+		Urgency:   UrgencyFast,
+		Status:    TxQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	record.setFees(fees)
+	if err := m.store.SaveTx(ctx, record); err != nil {
+		return fmt.Errorf("failed to save transaction: %w", err)
+	}
+	if err := m.broadcast(ctx, record); err != nil {
+		// Left queued, the next poll retries it
+		return err
+	}
+	m.logger.Warn("Filled nonce gap", zap.Uint64("nonce", nonce), zap.String("tx_hash", record.Hash.Hex()))
+	return m.store.SaveTx(ctx, record)
+}
+
+// sign signs record with its current fees and adds the hash to the ones receipts are looked up for
+func (m *TxManager) sign(ctx context.Context, record *TxRecord) (*types.Transaction, error) {
+	tx := NewTx(m.config.ChainID, record.Nonce, record.To, record.Value, record.GasLimit, record.fees(), record.Data)
+	signed, err := m.signer.SignTx(ctx, tx, m.config.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errSign, err)
+	}
+	record.Hash = signed.Hash()
+	for _, hash := range record.Hashes {
+		if hash == record.Hash {
+			return signed, nil
+		}
+	}
+	record.Hashes = append(record.Hashes, record.Hash)
+	return signed, nil
+}
+
+// broadcast signs and sends record. A node that already has the transaction counts as sent.
+func (m *TxManager) broadcast(ctx context.Context, record *TxRecord) error {
+	signed, err := m.sign(ctx, record)
+	if err != nil {
+		return err
+	}
+	if err := m.backend.SendTransaction(ctx, signed); err != nil && !isKnown(err) {
+		return err
+	}
+
+	now := time.Now().Unix()
+	record.Status = TxPending
+	record.Attempts++
+	record.LastSentAt = now
+	record.UpdatedAt = now
+	return nil
+}
+
+// nextNonce returns the next nonce, syncing it from the node and the store on first use
+func (m *TxManager) nextNonce(ctx context.Context) (uint64, error) {
+	if m.next != nil {
+		return *m.next, nil
+	}
+	next, err := m.backend.PendingNonceAt(ctx, m.from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	records, err := m.store.OutstandingTxs(ctx, m.config.ChainID.Uint64(), m.from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load transactions: %w", err)
+	}
+	for _, record := range records {
+		if record.Status != TxRequested && record.Nonce >= next {
+			next = record.Nonce + 1
+		}
+	}
+	m.next = &next
+	return next, nil
+}
+
+func isNonceTooLow(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isRejected reports a transaction that was not sent: it failed to sign or the node refused it
+func isRejected(err error) bool {
+	if errors.Is(err, errSign) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, rejection := range rejections {
+		if strings.Contains(msg, rejection) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnown reports a broadcast of a transaction the node already has
+func isKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
+
+// fees returns the fees the record is signed with
+func (r *TxRecord) fees() *Fees {
+	if r.GasFeeCap != nil && r.GasFeeCap.Sign() > 0 {
+		return &Fees{GasTipCap: r.GasTipCap, GasFeeCap: r.GasFeeCap}
+	}
+	return &Fees{GasPrice: r.GasPrice}
+}
+
+func (r *TxRecord) setFees(fees *Fees) {
+	r.GasTipCap, r.GasFeeCap, r.GasPrice = fees.GasTipCap, fees.GasFeeCap, fees.GasPrice
+}
+
+func newTxID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// MemoryTxStore keeps transactions in process, for tests and deployments without ClickHouse
+type MemoryTxStore struct {
+	mu  sync.Mutex
+	txs map[string]*TxRecord
+}
+
+// NewMemoryTxStore creates an empty in-memory store
+func NewMemoryTxStore() *MemoryTxStore {
+	return &MemoryTxStore{txs: make(map[string]*TxRecord)}
+}
+
+func (s *MemoryTxStore) SaveTx(ctx context.Context, tx *TxRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs[tx.ID] = copyTx(tx)
+	return nil
+}
+
+func (s *MemoryTxStore) GetTx(ctx context.Context, id string) (*TxRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, ok := s.txs[id]
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+	return copyTx(tx), nil
+}
+
+func (s *MemoryTxStore) OutstandingTxs(ctx context.Context, chainID uint64, from common.Address) ([]*TxRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var txs []*TxRecord
+	for _, tx := range s.txs {
+		if tx.ChainID == chainID && tx.From == from && !tx.Status.Final() {
+			txs = append(txs, copyTx(tx))
+		}
+	}
+	return txs, nil
+}
+
+func copyTx(tx *TxRecord) *TxRecord {
+	copied := *tx
+	copied.Hashes = append([]common.Hash(nil), tx.Hashes...)
+	return &copied
+}
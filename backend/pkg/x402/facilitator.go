@@ -0,0 +1,154 @@
+package x402
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Facilitator verifies and settles payments on behalf of a resource server
+type Facilitator interface {
+	Verify(ctx context.Context, payment *PaymentPayload, requirements *PaymentRequirements) (*VerifyResponse, error)
+	Settle(ctx context.Context, payment *PaymentPayload, requirements *PaymentRequirements) (*SettleResponse, error)
+}
+
+// facilitatorRequest is the body of the facilitator /verify and /settle endpoints
+type facilitatorRequest struct {
+	X402Version         int                  `json:"x402Version"`
+	PaymentPayload      *PaymentPayload      `json:"paymentPayload"`
+	PaymentRequirements *PaymentRequirements `json:"paymentRequirements"`
+}
+
+// HTTPFacilitator talks to a remote x402 facilitator over HTTP
+type HTTPFacilitator struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPFacilitator creates a facilitator client for the given base URL
+func NewHTTPFacilitator(baseURL string) *HTTPFacilitator {
+	return &HTTPFacilitator{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Verify asks the facilitator whether the payment satisfies the requirements
+func (f *HTTPFacilitator) Verify(ctx context.Context, payment *PaymentPayload, requirements *PaymentRequirements) (*VerifyResponse, error) {
+	var result VerifyResponse
+	if err := f.post(ctx, "/verify", payment, requirements, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Settle asks the facilitator to execute the payment on-chain
+func (f *HTTPFacilitator) Settle(ctx context.Context, payment *PaymentPayload, requirements *PaymentRequirements) (*SettleResponse, error) {
+	var result SettleResponse
+	if err := f.post(ctx, "/settle", payment, requirements, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (f *HTTPFacilitator) post(ctx context.Context, path string, payment *PaymentPayload, requirements *PaymentRequirements, out interface{}) error {
+	body, err := json.Marshal(facilitatorRequest{
+		X402Version:         Version,
+		PaymentPayload:      payment,
+		PaymentRequirements: requirements,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("facilitator %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("facilitator %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode facilitator %s response: %w", path, err)
+	}
+	return nil
+}
+
+// LocalFacilitator verifies payments in-process and settles them without touching a chain.
+// It rejects replayed nonces, which makes it suitable for development and offline tests.
+type LocalFacilitator struct {
+	mu   sync.Mutex
+	used map[string]bool
+	now  func() time.Time
+}
+
+// NewLocalFacilitator creates an in-process facilitator
+func NewLocalFacilitator() *LocalFacilitator {
+	return &LocalFacilitator{
+		used: make(map[string]bool),
+		now:  time.Now,
+	}
+}
+
+// Verify checks the payment signature and terms
+func (f *LocalFacilitator) Verify(ctx context.Context, payment *PaymentPayload, requirements *PaymentRequirements) (*VerifyResponse, error) {
+	result := Verify(payment, requirements, f.now())
+	if !result.IsValid {
+		return result, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.used[nonceKey(payment)] {
+		return invalid(ReasonNonceUsed, result.Payer), nil
+	}
+	return result, nil
+}
+
+// Settle verifies the payment, marks its nonce as used and returns a receipt
+// whose transaction hash is derived from the signature
+func (f *LocalFacilitator) Settle(ctx context.Context, payment *PaymentPayload, requirements *PaymentRequirements) (*SettleResponse, error) {
+	result := Verify(payment, requirements, f.now())
+	if !result.IsValid {
+		return &SettleResponse{ErrorReason: result.InvalidReason, Network: requirements.Network, Payer: result.Payer}, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := nonceKey(payment)
+	if f.used[key] {
+		return &SettleResponse{ErrorReason: ReasonNonceUsed, Network: requirements.Network, Payer: result.Payer}, nil
+	}
+	f.used[key] = true
+
+	return &SettleResponse{
+		Success:     true,
+		Transaction: hexutil.Encode(crypto.Keccak256([]byte(payment.Payload.Signature))),
+		Network:     requirements.Network,
+		Payer:       result.Payer,
+	}, nil
+}
+
+func nonceKey(payment *PaymentPayload) string {
+	if a := payment.Payload.Authorization; a != nil {
+		return strings.ToLower(a.From) + ":" + strings.ToLower(a.Nonce)
+	}
+	p := payment.Payload.Permit
+	return strings.ToLower(p.Owner) + ":permit:" + p.Nonce
+}
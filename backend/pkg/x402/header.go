@@ -0,0 +1,58 @@
+package x402
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// EncodePayment encodes a payment payload for the X-PAYMENT header
+func EncodePayment(payment *PaymentPayload) (string, error) {
+	return encodeHeader(payment)
+}
+
+// DecodePayment decodes an X-PAYMENT header
+func DecodePayment(header string) (*PaymentPayload, error) {
+	var payment PaymentPayload
+	if err := decodeHeader(header, &payment); err != nil {
+		return nil, err
+	}
+	if payment.Payload.Authorization == nil && payment.Payload.Permit == nil {
+		return nil, fmt.Errorf("payment carries neither an authorization nor a permit")
+	}
+	return &payment, nil
+}
+
+// EncodeSettleResponse encodes a settlement receipt for the X-PAYMENT-RESPONSE header
+func EncodeSettleResponse(receipt *SettleResponse) (string, error) {
+	return encodeHeader(receipt)
+}
+
+// DecodeSettleResponse decodes an X-PAYMENT-RESPONSE header
+func DecodeSettleResponse(header string) (*SettleResponse, error) {
+	var receipt SettleResponse
+	if err := decodeHeader(header, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// Headers are base64-encoded JSON
+func encodeHeader(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func decodeHeader(header string, v interface{}) error {
+	data, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return fmt.Errorf("invalid base64: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
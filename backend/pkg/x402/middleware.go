@@ -0,0 +1,279 @@
+package x402
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/pkg/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+// ContextKeyPayer is the echo context key holding the address paying for the request, charged once the handler succeeded
+const ContextKeyPayer = "x402_payer"
+
+// Price is what a protected route costs, in the smallest unit of the asset
+type Price struct {
+	Amount      string
+	Description string
+}
+
+// Config configures the x402 middleware
+type Config struct {
+	Facilitator Facilitator
+	Network     string
+	// Asset is the EIP-3009/EIP-2612 token contract, AssetName and AssetVersion its EIP-712 domain
+	Asset             string
+	AssetName         string
+	AssetVersion      string
+	PayTo             string
+	MaxTimeoutSeconds int
+	// Routes maps "METHOD /route/:param" (the echo route path) to its price
+	Routes map[string]Price
+}
+
+// ConfigFromEnv reads the middleware configuration from environment variables.
+// Payments go through the facilitator at X402_FACILITATOR_URL. Without it, the in-process LocalFacilitator,
+// which settles without moving funds, must be asked for with X402_FACILITATOR=local and is refused in
+// production. No route is protected unless X402_ROUTES is set, and routes need one of the two.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Network:           os.Getenv("X402_NETWORK"),
+		Asset:             os.Getenv("X402_ASSET"),
+		AssetName:         os.Getenv("X402_ASSET_NAME"),
+		AssetVersion:      os.Getenv("X402_ASSET_VERSION"),
+		PayTo:             os.Getenv("X402_PAY_TO"),
+		MaxTimeoutSeconds: 60,
+	}
+	if cfg.Network == "" {
+		cfg.Network = "hardhat"
+	}
+	if cfg.AssetName == "" {
+		cfg.AssetName = "USD Coin"
+	}
+	if cfg.AssetVersion == "" {
+		cfg.AssetVersion = "2"
+	}
+	if value := os.Getenv("X402_MAX_TIMEOUT_SECONDS"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid X402_MAX_TIMEOUT_SECONDS: %w", err)
+		}
+		cfg.MaxTimeoutSeconds = seconds
+	}
+
+	url, facilitator := os.Getenv("X402_FACILITATOR_URL"), os.Getenv("X402_FACILITATOR")
+	switch {
+	case facilitator != "" && facilitator != "local":
+		return cfg, fmt.Errorf("invalid X402_FACILITATOR %q, expected local", facilitator)
+	case url != "":
+		cfg.Facilitator = NewHTTPFacilitator(url)
+	case facilitator == "local":
+		if config.IsProduction() {
+			return cfg, fmt.Errorf("X402_FACILITATOR=local settles payments without moving funds and is refused in production")
+		}
+		cfg.Facilitator = NewLocalFacilitator()
+	}
+
+	routes, err := ParseRoutes(os.Getenv("X402_ROUTES"))
+	if err != nil {
+		return cfg, fmt.Errorf("invalid X402_ROUTES: %w", err)
+	}
+	cfg.Routes = routes
+
+	if len(cfg.Routes) > 0 {
+		if cfg.Facilitator == nil {
+			return cfg, fmt.Errorf("X402_ROUTES requires X402_FACILITATOR_URL, or X402_FACILITATOR=local outside production")
+		}
+		if _, err := ChainID(cfg.Network); err != nil {
+			return cfg, err
+		}
+		if !common.IsHexAddress(cfg.Asset) || !common.IsHexAddress(cfg.PayTo) {
+			return cfg, fmt.Errorf("X402_ASSET and X402_PAY_TO must be addresses when X402_ROUTES is set")
+		}
+	}
+
+	return cfg, nil
+}
+
+// ParseRoutes parses a route price list such as
+// "GET /api/v1/borrowers/:address/obligations=10000;GET /api/v1/credit-lines/:id/schedule=5000"
+func ParseRoutes(spec string) (map[string]Price, error) {
+	routes := make(map[string]Price)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		idx := strings.LastIndex(entry, "=")
+		if idx == -1 {
+			return nil, fmt.Errorf("missing price in %q", entry)
+		}
+		route, amount := strings.TrimSpace(entry[:idx]), strings.TrimSpace(entry[idx+1:])
+		if len(strings.Fields(route)) != 2 {
+			return nil, fmt.Errorf("route %q must be \"METHOD /path\"", route)
+		}
+		if _, err := strconv.ParseUint(amount, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid price %q for %s", amount, route)
+		}
+
+		fields := strings.Fields(route)
+		routes[strings.ToUpper(fields[0])+" "+fields[1]] = Price{Amount: amount}
+	}
+	return routes, nil
+}
+
+// Middleware answers priced routes with 402 Payment Required until the request carries
+// a valid X-PAYMENT header. The payment is verified through the facilitator before the handler
+// runs, and settled only once the handler succeeded: its response is held until then, and sent
+// with the settlement receipt in X-PAYMENT-RESPONSE. A handler error or a non-2xx response is
+// passed on without charging the payer. Routes without a price pass through untouched.
+func Middleware(cfg Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			price, ok := cfg.Routes[c.Request().Method+" "+c.Path()]
+			if !ok {
+				return next(c)
+			}
+
+			requirements := cfg.requirements(c, price)
+
+			header := c.Request().Header.Get(HeaderPayment)
+			if header == "" {
+				return paymentRequired(c, requirements, HeaderPayment+" header is required")
+			}
+
+			payment, err := DecodePayment(header)
+			if err != nil {
+				return paymentRequired(c, requirements, "Invalid "+HeaderPayment+" header: "+err.Error())
+			}
+
+			ctx := c.Request().Context()
+			verification, err := cfg.Facilitator.Verify(ctx, payment, requirements)
+			if err != nil {
+				return apperrors.Unavailable(err, "payment facilitator unavailable")
+			}
+			if !verification.IsValid {
+				return paymentRequired(c, requirements, verification.InvalidReason)
+			}
+			c.Set(ContextKeyPayer, verification.Payer)
+
+			res := c.Response()
+			buffer := newBufferedResponse(res.Writer)
+			res.Writer = buffer
+			err = next(c)
+			res.Writer = buffer.writer
+			if err != nil || res.Status < 200 || res.Status >= 300 {
+				buffer.flush()
+				return err
+			}
+
+			receipt, err := cfg.Facilitator.Settle(ctx, payment, requirements)
+			if err == nil && !receipt.Success {
+				buffer.discard(res)
+				return paymentRequired(c, requirements, receipt.ErrorReason)
+			}
+			if err != nil {
+				buffer.discard(res)
+				return apperrors.Unavailable(err, "payment facilitator unavailable")
+			}
+
+			encoded, err := EncodeSettleResponse(receipt)
+			if err != nil {
+				buffer.discard(res)
+				return err
+			}
+			// A handler that wrote nothing answers with its status and the receipt
+			buffer.WriteHeader(res.Status)
+			buffer.header.Set(HeaderPaymentResponse, encoded)
+			buffer.header.Add("Access-Control-Expose-Headers", HeaderPaymentResponse)
+			buffer.flush()
+			return nil
+		}
+	}
+}
+
+// bufferedResponse holds the response of a paid handler until its payment is settled
+type bufferedResponse struct {
+	writer http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse(w http.ResponseWriter) *bufferedResponse {
+	return &bufferedResponse{writer: w, header: w.Header().Clone()}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// flush sends the held response, if the handler wrote one
+func (b *bufferedResponse) flush() {
+	if b.status == 0 {
+		return
+	}
+	header := b.writer.Header()
+	for key, values := range b.header {
+		header[key] = values
+	}
+	b.writer.WriteHeader(b.status)
+	_, _ = b.writer.Write(b.body.Bytes())
+}
+
+// discard drops the held response, so another one can be written in its place
+func (b *bufferedResponse) discard(res *echo.Response) {
+	res.Committed = false
+	res.Size = 0
+}
+
+func (cfg Config) requirements(c echo.Context, price Price) *PaymentRequirements {
+	description := price.Description
+	if description == "" {
+		description = "Access to " + c.Request().Method + " " + c.Request().URL.Path
+	}
+
+	return &PaymentRequirements{
+		Scheme:            SchemeExact,
+		Network:           cfg.Network,
+		MaxAmountRequired: price.Amount,
+		Resource:          c.Scheme() + "://" + c.Request().Host + c.Request().URL.Path,
+		Description:       description,
+		MimeType:          "application/json",
+		PayTo:             cfg.PayTo,
+		MaxTimeoutSeconds: cfg.MaxTimeoutSeconds,
+		Asset:             cfg.Asset,
+		Extra: map[string]string{
+			"name":    cfg.AssetName,
+			"version": cfg.AssetVersion,
+		},
+	}
+}
+
+func paymentRequired(c echo.Context, requirements *PaymentRequirements, reason string) error {
+	return c.JSON(http.StatusPaymentRequired, PaymentRequiredResponse{
+		X402Version: Version,
+		Error:       reason,
+		Accepts:     []PaymentRequirements{*requirements},
+	})
+}
@@ -0,0 +1,152 @@
+package x402
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Domain is the EIP-712 domain of the token being paid
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract common.Address
+}
+
+// DomainFor builds the EIP-712 domain of the asset in the payment requirements.
+// The token name and version come from requirements.Extra.
+func DomainFor(requirements *PaymentRequirements) (Domain, error) {
+	chainID, err := ChainID(requirements.Network)
+	if err != nil {
+		return Domain{}, err
+	}
+	if !common.IsHexAddress(requirements.Asset) {
+		return Domain{}, fmt.Errorf("invalid asset address %q", requirements.Asset)
+	}
+	return Domain{
+		Name:              requirements.Extra["name"],
+		Version:           requirements.Extra["version"],
+		ChainID:           chainID,
+		VerifyingContract: common.HexToAddress(requirements.Asset),
+	}, nil
+}
+
+var domainType = []apitypes.Type{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+}
+
+// AuthorizationHash returns the EIP-712 hash of an EIP-3009 TransferWithAuthorization message
+func AuthorizationHash(domain Domain, auth *Authorization) ([]byte, error) {
+	return typedDataHash(domain, "TransferWithAuthorization", []apitypes.Type{
+		{Name: "from", Type: "address"},
+		{Name: "to", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "validAfter", Type: "uint256"},
+		{Name: "validBefore", Type: "uint256"},
+		{Name: "nonce", Type: "bytes32"},
+	}, apitypes.TypedDataMessage{
+		"from":        auth.From,
+		"to":          auth.To,
+		"value":       auth.Value,
+		"validAfter":  auth.ValidAfter,
+		"validBefore": auth.ValidBefore,
+		"nonce":       auth.Nonce,
+	})
+}
+
+// PermitHash returns the EIP-712 hash of an EIP-2612 Permit message
+func PermitHash(domain Domain, permit *Permit) ([]byte, error) {
+	return typedDataHash(domain, "Permit", []apitypes.Type{
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	}, apitypes.TypedDataMessage{
+		"owner":    permit.Owner,
+		"spender":  permit.Spender,
+		"value":    permit.Value,
+		"nonce":    permit.Nonce,
+		"deadline": permit.Deadline,
+	})
+}
+
+func typedDataHash(domain Domain, primaryType string, fields []apitypes.Type, message apitypes.TypedDataMessage) ([]byte, error) {
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domainType,
+			primaryType:    fields,
+		},
+		PrimaryType: primaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:              domain.Name,
+			Version:           domain.Version,
+			ChainId:           (*math.HexOrDecimal256)(domain.ChainID),
+			VerifyingContract: domain.VerifyingContract.Hex(),
+		},
+		Message: message,
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash %s: %w", primaryType, err)
+	}
+	return hash, nil
+}
+
+// SignAuthorization signs an EIP-3009 authorization and returns the 65-byte hex signature
+func SignAuthorization(key *ecdsa.PrivateKey, domain Domain, auth *Authorization) (string, error) {
+	hash, err := AuthorizationHash(domain, auth)
+	if err != nil {
+		return "", err
+	}
+	return sign(key, hash)
+}
+
+// SignPermit signs an EIP-2612 permit and returns the 65-byte hex signature
+func SignPermit(key *ecdsa.PrivateKey, domain Domain, permit *Permit) (string, error) {
+	hash, err := PermitHash(domain, permit)
+	if err != nil {
+		return "", err
+	}
+	return sign(key, hash)
+}
+
+func sign(key *ecdsa.PrivateKey, hash []byte) (string, error) {
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // Ethereum-style V
+	return hexutil.Encode(sig), nil
+}
+
+// RecoverSigner returns the address that produced signature over hash
+func RecoverSigner(hash []byte, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
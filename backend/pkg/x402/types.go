@@ -0,0 +1,117 @@
+// Package x402 implements the x402 HTTP payment protocol: resources answer
+// 402 Payment Required with their payment requirements, clients retry with a
+// signed payment in the X-PAYMENT header, and a facilitator verifies and
+// settles the payment before the resource is served.
+package x402
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Version is the x402 protocol version implemented by this package
+const Version = 1
+
+// SchemeExact is the x402 scheme paying an exact amount of an ERC-20 token
+const SchemeExact = "exact"
+
+// Protocol headers
+const (
+	HeaderPayment         = "X-PAYMENT"
+	HeaderPaymentResponse = "X-PAYMENT-RESPONSE"
+)
+
+// Networks maps x402 network names to EVM chain IDs
+var Networks = map[string]int64{
+	"ethereum":       1,
+	"sepolia":        11155111,
+	"base":           8453,
+	"base-sepolia":   84532,
+	"polygon":        137,
+	"polygon-amoy":   80002,
+	"polygon-mumbai": 80001,
+	"hardhat":        1337,
+	"localhost":      1337,
+}
+
+// ChainID returns the chain ID of an x402 network name
+func ChainID(network string) (*big.Int, error) {
+	id, ok := Networks[network]
+	if !ok {
+		return nil, fmt.Errorf("unsupported network %q", network)
+	}
+	return big.NewInt(id), nil
+}
+
+// PaymentRequirements describes how a resource must be paid for
+type PaymentRequirements struct {
+	Scheme            string `json:"scheme"`
+	Network           string `json:"network"`
+	MaxAmountRequired string `json:"maxAmountRequired"`
+	Resource          string `json:"resource"`
+	Description       string `json:"description"`
+	MimeType          string `json:"mimeType"`
+	PayTo             string `json:"payTo"`
+	MaxTimeoutSeconds int    `json:"maxTimeoutSeconds"`
+	Asset             string `json:"asset"`
+	// Extra carries the EIP-712 domain name and version of the asset
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// PaymentRequiredResponse is the body of a 402 Payment Required response
+type PaymentRequiredResponse struct {
+	X402Version int                   `json:"x402Version"`
+	Error       string                `json:"error"`
+	Accepts     []PaymentRequirements `json:"accepts"`
+}
+
+// PaymentPayload is the decoded X-PAYMENT header
+type PaymentPayload struct {
+	X402Version int          `json:"x402Version"`
+	Scheme      string       `json:"scheme"`
+	Network     string       `json:"network"`
+	Payload     ExactPayload `json:"payload"`
+}
+
+// ExactPayload is the signed payment of the exact scheme.
+// Exactly one of Authorization (EIP-3009) or Permit (EIP-2612) is set.
+type ExactPayload struct {
+	Signature     string         `json:"signature"`
+	Authorization *Authorization `json:"authorization,omitempty"`
+	Permit        *Permit        `json:"permit,omitempty"`
+}
+
+// Authorization is an EIP-3009 transferWithAuthorization message
+type Authorization struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+	ValidAfter  string `json:"validAfter"`
+	ValidBefore string `json:"validBefore"`
+	Nonce       string `json:"nonce"`
+}
+
+// Permit is an EIP-2612 permit message granting the payee an allowance
+type Permit struct {
+	Owner    string `json:"owner"`
+	Spender  string `json:"spender"`
+	Value    string `json:"value"`
+	Nonce    string `json:"nonce"`
+	Deadline string `json:"deadline"`
+}
+
+// VerifyResponse is the facilitator's verdict on a payment
+type VerifyResponse struct {
+	IsValid       bool   `json:"isValid"`
+	InvalidReason string `json:"invalidReason,omitempty"`
+	Payer         string `json:"payer,omitempty"`
+}
+
+// SettleResponse is the facilitator's settlement receipt, returned to the client in X-PAYMENT-RESPONSE
+type SettleResponse struct {
+	Success     bool   `json:"success"`
+	ErrorReason string `json:"errorReason,omitempty"`
+	Transaction string `json:"transaction"`
+	Network     string `json:"network"`
+	Payer       string `json:"payer,omitempty"`
+}
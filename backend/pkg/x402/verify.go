@@ -0,0 +1,100 @@
+package x402
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Reasons reported in VerifyResponse.InvalidReason
+const (
+	ReasonUnsupportedVersion = "unsupported_x402_version"
+	ReasonInvalidScheme      = "invalid_scheme"
+	ReasonInvalidNetwork     = "invalid_network"
+	ReasonInvalidPayload     = "invalid_payload"
+	ReasonInvalidSignature   = "invalid_signature"
+	ReasonRecipientMismatch  = "recipient_mismatch"
+	ReasonInsufficientAmount = "insufficient_amount"
+	ReasonNotYetValid        = "authorization_not_yet_valid"
+	ReasonExpired            = "authorization_expired"
+	ReasonNonceUsed          = "nonce_already_used"
+)
+
+// Verify checks a payment against the requirements it claims to satisfy: scheme,
+// network, payee, amount, validity window and the EIP-712 signature of the payer.
+// It does not check on-chain state such as balances or nonce usage.
+func Verify(payment *PaymentPayload, requirements *PaymentRequirements, now time.Time) *VerifyResponse {
+	if payment.X402Version != Version {
+		return invalid(ReasonUnsupportedVersion, "")
+	}
+	if payment.Scheme != requirements.Scheme {
+		return invalid(ReasonInvalidScheme, "")
+	}
+	if payment.Network != requirements.Network {
+		return invalid(ReasonInvalidNetwork, "")
+	}
+
+	domain, err := DomainFor(requirements)
+	if err != nil {
+		return invalid(ReasonInvalidNetwork, "")
+	}
+	required, ok := new(big.Int).SetString(requirements.MaxAmountRequired, 10)
+	if !ok {
+		return invalid(ReasonInvalidPayload, "")
+	}
+
+	var (
+		payer, payee     string
+		value            string
+		notBefore, until string
+		hash             []byte
+	)
+	switch p := payment.Payload; {
+	case p.Authorization != nil:
+		a := p.Authorization
+		payer, payee, value = a.From, a.To, a.Value
+		notBefore, until = a.ValidAfter, a.ValidBefore
+		hash, err = AuthorizationHash(domain, a)
+	case p.Permit != nil:
+		pm := p.Permit
+		payer, payee, value = pm.Owner, pm.Spender, pm.Value
+		notBefore, until = "0", pm.Deadline
+		hash, err = PermitHash(domain, pm)
+	default:
+		return invalid(ReasonInvalidPayload, "")
+	}
+	if err != nil || !common.IsHexAddress(payer) || !common.IsHexAddress(payee) {
+		return invalid(ReasonInvalidPayload, payer)
+	}
+
+	signer, err := RecoverSigner(hash, payment.Payload.Signature)
+	if err != nil || signer != common.HexToAddress(payer) {
+		return invalid(ReasonInvalidSignature, payer)
+	}
+	if !strings.EqualFold(payee, requirements.PayTo) {
+		return invalid(ReasonRecipientMismatch, payer)
+	}
+
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Cmp(required) < 0 {
+		return invalid(ReasonInsufficientAmount, payer)
+	}
+
+	ts := big.NewInt(now.Unix())
+	after, ok := new(big.Int).SetString(notBefore, 10)
+	if !ok || ts.Cmp(after) < 0 {
+		return invalid(ReasonNotYetValid, payer)
+	}
+	before, ok := new(big.Int).SetString(until, 10)
+	if !ok || ts.Cmp(before) >= 0 {
+		return invalid(ReasonExpired, payer)
+	}
+
+	return &VerifyResponse{IsValid: true, Payer: signer.Hex()}
+}
+
+func invalid(reason, payer string) *VerifyResponse {
+	return &VerifyResponse{IsValid: false, InvalidReason: reason, Payer: payer}
+}
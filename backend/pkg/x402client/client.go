@@ -0,0 +1,199 @@
+// Package x402client is an HTTP client that pays x402 paywalls out of the
+// caller's x402 credit line: on 402 Payment Required it draws the requested
+// amount on a credit line, signs an EIP-3009 authorization for the payee and
+// retries the request with the X-PAYMENT header.
+package x402client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/x402"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNoAcceptablePayment is returned when none of the payment requirements of a 402 response can be met
+var ErrNoAcceptablePayment = errors.New("no acceptable x402 payment requirements")
+
+// Client wraps an http.Client and pays 402 responses with credit
+type Client struct {
+	httpClient *http.Client
+	key        *ecdsa.PrivateKey
+	credit     Credit
+	spending   *spendTracker
+	now        func() time.Time
+}
+
+// New creates a client paying as the borrower owning key. A nil httpClient uses http.DefaultClient.
+func New(httpClient *http.Client, key *ecdsa.PrivateKey, credit Credit, limits Limits) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		httpClient: httpClient,
+		key:        key,
+		credit:     credit,
+		spending:   newSpendTracker(limits),
+		now:        time.Now,
+	}
+}
+
+// Address returns the borrower address the client pays from
+func (c *Client) Address() common.Address {
+	return crypto.PubkeyToAddress(c.key.PublicKey)
+}
+
+// Spent returns the amount spent today on domain and across all domains
+func (c *Client) Spent(domain string) (*big.Int, *big.Int) {
+	return c.spending.snapshot(domain, c.now())
+}
+
+// Get issues a GET request, paying for it if required
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends the request; on 402 Payment Required it pays with credit and retries once.
+// The retried response carries the settlement receipt in X-PAYMENT-RESPONSE.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	// The body is sent twice, so it must be replayable
+	if req.Body != nil && req.GetBody == nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusPaymentRequired {
+		return resp, err
+	}
+
+	var challenge x402.PaymentRequiredResponse
+	err = json.NewDecoder(resp.Body).Decode(&challenge)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode 402 response: %w", err)
+	}
+
+	requirements, err := c.selectRequirements(challenge.Accepts)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := c.pay(req.Context(), req.URL.Hostname(), requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set(x402.HeaderPayment, header)
+
+	return c.httpClient.Do(retry)
+}
+
+// selectRequirements picks the first exact-scheme requirement on a known network
+func (c *Client) selectRequirements(accepts []x402.PaymentRequirements) (*x402.PaymentRequirements, error) {
+	for i := range accepts {
+		r := &accepts[i]
+		if r.Scheme != x402.SchemeExact {
+			continue
+		}
+		if _, err := x402.DomainFor(r); err != nil {
+			continue
+		}
+		if _, ok := new(big.Int).SetString(r.MaxAmountRequired, 10); !ok {
+			continue
+		}
+		return r, nil
+	}
+	return nil, ErrNoAcceptablePayment
+}
+
+// pay draws the required amount on a credit line and returns the X-PAYMENT header authorizing its transfer
+func (c *Client) pay(ctx context.Context, domain string, requirements *x402.PaymentRequirements) (string, error) {
+	amount, _ := new(big.Int).SetString(requirements.MaxAmountRequired, 10)
+
+	now := c.now()
+	if err := c.spending.reserve(domain, amount, now); err != nil {
+		return "", err
+	}
+
+	header, err := c.drawAndSign(ctx, requirements, amount, now)
+	if err != nil {
+		c.spending.release(domain, amount, now)
+		return "", err
+	}
+	return header, nil
+}
+
+func (c *Client) drawAndSign(ctx context.Context, requirements *x402.PaymentRequirements, amount *big.Int, now time.Time) (string, error) {
+	creditLineID, err := c.credit.Find(ctx, c.Address(), amount)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := c.credit.Draw(ctx, creditLineID, amount); err != nil {
+		return "", fmt.Errorf("failed to draw on credit line %s: %w", creditLineID, err)
+	}
+
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	timeout := requirements.MaxTimeoutSeconds
+	if timeout <= 0 {
+		timeout = 60
+	}
+	auth := &x402.Authorization{
+		From:        c.Address().Hex(),
+		To:          requirements.PayTo,
+		Value:       amount.String(),
+		ValidAfter:  strconv.FormatInt(now.Add(-time.Minute).Unix(), 10), // Tolerate clock skew
+		ValidBefore: strconv.FormatInt(now.Add(time.Duration(timeout)*time.Second).Unix(), 10),
+		Nonce:       hexutil.Encode(nonce),
+	}
+
+	domain, err := x402.DomainFor(requirements)
+	if err != nil {
+		return "", err
+	}
+	signature, err := x402.SignAuthorization(c.key, domain, auth)
+	if err != nil {
+		return "", err
+	}
+
+	return x402.EncodePayment(&x402.PaymentPayload{
+		X402Version: x402.Version,
+		Scheme:      requirements.Scheme,
+		Network:     requirements.Network,
+		Payload:     x402.ExactPayload{Signature: signature, Authorization: auth},
+	})
+}
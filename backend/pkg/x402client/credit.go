@@ -0,0 +1,258 @@
+package x402client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNoCreditLine is returned when the borrower has no credit line able to cover a payment
+var ErrNoCreditLine = errors.New("no credit line with enough available credit")
+
+// Credit finds and draws on the borrower's x402 credit lines
+type Credit interface {
+	// Find returns the ID of a credit line of borrower with at least amount available
+	Find(ctx context.Context, borrower common.Address, amount *big.Int) (*big.Int, error)
+	// Draw draws amount on the credit line and returns the transaction hash
+	Draw(ctx context.Context, creditLineID *big.Int, amount *big.Int) (string, error)
+}
+
+// LineLookup lists the IDs of the credit lines held by a borrower
+type LineLookup func(ctx context.Context, borrower common.Address) ([]*big.Int, error)
+
+// APILineLookup lists a borrower's credit lines through the aqua402 API
+// (GET {baseURL}/borrowers/{address}/obligations), e.g. baseURL "http://localhost:8080/api/v1"
+func APILineLookup(baseURL string, httpClient *http.Client) LineLookup {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	return func(ctx context.Context, borrower common.Address) ([]*big.Int, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/borrowers/"+borrower.Hex()+"/obligations", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get obligations: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("obligations request returned status %d", resp.StatusCode)
+		}
+
+		var obligations struct {
+			CreditLines []struct {
+				CreditLineID uint64 `json:"credit_line_id"`
+			} `json:"credit_lines"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&obligations); err != nil {
+			return nil, fmt.Errorf("failed to decode obligations: %w", err)
+		}
+
+		ids := make([]*big.Int, 0, len(obligations.CreditLines))
+		for _, line := range obligations.CreditLines {
+			ids = append(ids, new(big.Int).SetUint64(line.CreditLineID))
+		}
+		return ids, nil
+	}
+}
+
+// creditABI covers the IX402Credit functions used to pay with credit
+const creditABI = `[
+	{"type":"function","name":"draw","stateMutability":"nonpayable",
+	 "inputs":[{"name":"creditLineId","type":"uint256"},{"name":"amount","type":"uint256"}],
+	 "outputs":[]},
+	{"type":"function","name":"getCreditLine","stateMutability":"view",
+	 "inputs":[{"name":"creditLineId","type":"uint256"}],
+	 "outputs":[{"name":"","type":"tuple","components":[
+		{"name":"borrower","type":"address"},
+		{"name":"lender","type":"address"},
+		{"name":"limit","type":"uint256"},
+		{"name":"drawn","type":"uint256"},
+		{"name":"rateBps","type":"uint256"},
+		{"name":"createdAt","type":"uint256"},
+		{"name":"expiresAt","type":"uint256"},
+		{"name":"active","type":"bool"}]}]}
+]`
+
+type creditLineData struct {
+	Borrower  common.Address
+	Lender    common.Address
+	Limit     *big.Int
+	Drawn     *big.Int
+	RateBps   *big.Int
+	CreatedAt *big.Int
+	ExpiresAt *big.Int
+	Active    bool
+}
+
+// Backend is the subset of an EVM client needed to draw on a credit line; *evm.Client satisfies it
+type Backend interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestFees(ctx context.Context, urgency evm.Urgency) (*evm.Fees, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// ContractCredit draws on credit lines of an IX402Credit contract, signing as the borrower
+type ContractCredit struct {
+	backend       Backend
+	creditAddress common.Address
+	key           *ecdsa.PrivateKey
+	lookup        LineLookup
+	abi           abi.ABI
+}
+
+// NewContractCredit creates a Credit backed by the IX402Credit contract at creditAddress.
+// lookup supplies candidate credit line IDs, which are checked on-chain before drawing.
+func NewContractCredit(backend Backend, creditAddress string, key *ecdsa.PrivateKey, lookup LineLookup) (*ContractCredit, error) {
+	parsed, err := abi.JSON(strings.NewReader(creditABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse credit ABI: %w", err)
+	}
+	return &ContractCredit{
+		backend:       backend,
+		creditAddress: common.HexToAddress(creditAddress),
+		key:           key,
+		lookup:        lookup,
+		abi:           parsed,
+	}, nil
+}
+
+// Find returns the first active, unexpired credit line of borrower with enough undrawn limit
+func (c *ContractCredit) Find(ctx context.Context, borrower common.Address, amount *big.Int) (*big.Int, error) {
+	ids, err := c.lookup(ctx, borrower)
+	if err != nil {
+		return nil, err
+	}
+
+	now := big.NewInt(time.Now().Unix())
+	for _, id := range ids {
+		line, err := c.getCreditLine(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !line.Active || line.Borrower != borrower || line.ExpiresAt.Cmp(now) <= 0 {
+			continue
+		}
+		available := new(big.Int).Sub(line.Limit, line.Drawn)
+		if available.Cmp(amount) >= 0 {
+			return id, nil
+		}
+	}
+
+	return nil, ErrNoCreditLine
+}
+
+func (c *ContractCredit) getCreditLine(ctx context.Context, id *big.Int) (*creditLineData, error) {
+	data, err := c.abi.Pack("getCreditLine", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack getCreditLine: %w", err)
+	}
+
+	result, err := c.backend.CallContract(ctx, ethereum.CallMsg{To: &c.creditAddress, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getCreditLine: %w", err)
+	}
+
+	values, err := c.abi.Unpack("getCreditLine", result)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("failed to unpack getCreditLine: %w", err)
+	}
+
+	var line creditLineData
+	abi.ConvertType(values[0], &line)
+	return &line, nil
+}
+
+// Draw sends IX402Credit.draw and waits until the transaction is mined
+func (c *ContractCredit) Draw(ctx context.Context, creditLineID *big.Int, amount *big.Int) (string, error) {
+	data, err := c.abi.Pack("draw", creditLineID, amount)
+	if err != nil {
+		return "", fmt.Errorf("failed to pack draw: %w", err)
+	}
+
+	from := crypto.PubkeyToAddress(c.key.PublicKey)
+
+	chainID, err := c.backend.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	nonce, err := c.backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	// A payment is waiting on the draw, so it pays to be included quickly
+	fees, err := c.backend.SuggestFees(ctx, evm.UrgencyFast)
+	if err != nil {
+		return "", fmt.Errorf("failed to get fees: %w", err)
+	}
+
+	gasLimit, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &c.creditAddress, Data: data})
+	if err != nil {
+		return "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	tx := evm.NewTx(chainID, nonce, c.creditAddress, big.NewInt(0), gasLimit, fees, data)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), c.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	if err := c.backend.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	receipt, err := c.waitMined(ctx, signedTx.Hash())
+	if err != nil {
+		return "", err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("draw transaction %s reverted", signedTx.Hash().Hex())
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
+
+func (c *ContractCredit) waitMined(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := c.backend.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("failed to get draw receipt: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for draw transaction %s: %w", hash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
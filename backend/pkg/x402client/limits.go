@@ -0,0 +1,106 @@
+package x402client
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is returned when paying would exceed a spending limit
+var ErrLimitExceeded = errors.New("x402 spending limit exceeded")
+
+// Limits caps what the client spends per UTC day. Nil limits are unlimited.
+type Limits struct {
+	// PerDay caps the total spent across all domains
+	PerDay *big.Int
+	// PerDomain caps the amount spent on a single domain
+	PerDomain *big.Int
+	// Domains overrides PerDomain for specific hosts
+	Domains map[string]*big.Int
+}
+
+// spendTracker keeps the amounts spent during the current UTC day
+type spendTracker struct {
+	mu       sync.Mutex
+	limits   Limits
+	day      string
+	total    *big.Int
+	byDomain map[string]*big.Int
+}
+
+func newSpendTracker(limits Limits) *spendTracker {
+	return &spendTracker{
+		limits:   limits,
+		total:    new(big.Int),
+		byDomain: make(map[string]*big.Int),
+	}
+}
+
+// reserve records amount as spent on domain, failing if a limit would be exceeded
+func (t *spendTracker) reserve(domain string, amount *big.Int, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollover(now)
+
+	total := new(big.Int).Add(t.total, amount)
+	if t.limits.PerDay != nil && total.Cmp(t.limits.PerDay) > 0 {
+		return fmt.Errorf("%w: daily limit %s, spent %s, requested %s", ErrLimitExceeded, t.limits.PerDay, t.total, amount)
+	}
+
+	spent := t.spent(domain)
+	domainTotal := new(big.Int).Add(spent, amount)
+	if limit := t.domainLimit(domain); limit != nil && domainTotal.Cmp(limit) > 0 {
+		return fmt.Errorf("%w: daily limit for %s %s, spent %s, requested %s", ErrLimitExceeded, domain, limit, spent, amount)
+	}
+
+	t.total = total
+	t.byDomain[domain] = domainTotal
+	return nil
+}
+
+// release gives back a reservation whose payment never happened
+func (t *spendTracker) release(domain string, amount *big.Int, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollover(now)
+	if spent := t.spent(domain); spent.Cmp(amount) >= 0 {
+		t.byDomain[domain] = new(big.Int).Sub(spent, amount)
+		t.total = new(big.Int).Sub(t.total, amount)
+	}
+}
+
+// snapshot returns the amount spent on domain and in total during the current UTC day
+func (t *spendTracker) snapshot(domain string, now time.Time) (*big.Int, *big.Int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollover(now)
+	return new(big.Int).Set(t.spent(domain)), new(big.Int).Set(t.total)
+}
+
+func (t *spendTracker) rollover(now time.Time) {
+	day := now.UTC().Format("2006-01-02")
+	if day != t.day {
+		t.day = day
+		t.total = new(big.Int)
+		t.byDomain = make(map[string]*big.Int)
+	}
+}
+
+func (t *spendTracker) spent(domain string) *big.Int {
+	if spent, ok := t.byDomain[domain]; ok {
+		return spent
+	}
+	return new(big.Int)
+}
+
+func (t *spendTracker) domainLimit(domain string) *big.Int {
+	if limit, ok := t.limits.Domains[domain]; ok {
+		return limit
+	}
+	return t.limits.PerDomain
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/accounts"
+	"github.com/Pagga-Wallet/aqua402/internal/services/credit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accountAddress = "0x1111111111111111111111111111111111111111"
+	accountOther   = "0x2222222222222222222222222222222222222222"
+)
+
+func TestAccountPortfolio(t *testing.T) {
+	now := int64(1_700_000_000)
+	day := int64(86400)
+
+	borrowedLine := credit.Position{
+		ChainID: 31337, CreditLineID: 10, BorrowerAddress: accountAddress, LenderAddress: accountOther,
+		Limit: "1000", RateBps: 500, CreatedAt: now - 30*day, ExpiresAt: now + 30*day,
+		Principal: "600", AccruedInterest: "4", TotalOwed: "604", InterestPaid: "3",
+	}
+	repaidLine := credit.Position{
+		ChainID: 31337, CreditLineID: 11, BorrowerAddress: accountAddress, LenderAddress: accountOther,
+		Limit: "500", RateBps: 400, CreatedAt: now - 90*day, ExpiresAt: now - 10*day,
+		Principal: "0", AccruedInterest: "0", TotalOwed: "0", InterestPaid: "7",
+	}
+	due := &credit.Installment{DueAt: now + 2*day, Principal: "100", Interest: "4", Total: "104"}
+	lentLine := credit.Position{
+		ChainID: 31337, CreditLineID: 20, BorrowerAddress: accountOther, LenderAddress: accountAddress,
+		Limit: "2000", RateBps: 650, CreatedAt: now - 5*day, ExpiresAt: now + 60*day,
+		Principal: "0", AccruedInterest: "0", TotalOwed: "0", InterestPaid: "12",
+	}
+
+	p := accounts.NewPortfolio(accounts.Holdings{
+		ChainID: 31337,
+		Address: accountAddress,
+		Requests: []*repositories.AccountRequestModel{
+			{Market: repositories.MarketRFQ, ID: 1, Amount: "1000", Duration: 3600, CreatedAt: now - day, Offers: 3},
+			{Market: repositories.MarketRFQ, ID: 2, Amount: "50", Duration: 3600, CreatedAt: now - day},
+			{Market: repositories.MarketAuction, ID: 3, Amount: "700", EndTime: now - 60, CreatedAt: now - 2*day, Offers: 2},
+			{Market: repositories.MarketAuction, ID: 4, Amount: "700", EndTime: now + 60, CreatedAt: now - 60, Offers: 1},
+			{Market: repositories.MarketRFQ, ID: 5, Amount: "1000", CreatedAt: now - 31*day, Offers: 2, Filled: true, CreditLineID: 10},
+		},
+		Offers: []*repositories.AccountOfferModel{
+			{Market: repositories.MarketRFQ, ID: 1, RequestID: 8, RateBps: 600, Limit: "300", CreatedAt: now - 3*day},
+			// Lost to another lender
+			{Market: repositories.MarketRFQ, ID: 2, RequestID: 9, RateBps: 700, Limit: "300", CreatedAt: now - 4*day, Filled: true, CreditLineID: 30},
+			// Won, quoted twice
+			{Market: repositories.MarketAuction, ID: 5, RequestID: 12, RateBps: 650, Limit: "2000", CreatedAt: now - 6*day, Filled: true, Won: true, CreditLineID: 20},
+			{Market: repositories.MarketAuction, ID: 6, RequestID: 12, RateBps: 650, Limit: "2000", CreatedAt: now - 6*day, Filled: true, Won: true, CreditLineID: 20},
+		},
+		Liquidity: &repositories.AccountLiquidityModel{Connected: "5000", Withdrawn: "1000", Reserved: "2000", Released: "500"},
+		Borrowed: &credit.Obligations{
+			TotalPrincipal: "600", TotalInterest: "4", TotalOwed: "604", NextDue: due,
+			CreditLines: []credit.Obligation{{Position: borrowedLine, NextDue: due}, {Position: repaidLine}},
+		},
+		Lent: []credit.Position{lentLine},
+	}, now)
+
+	// Open positions
+	require.Len(t, p.OpenPositions.Requests, 4)
+	assert.Equal(t, uint64(1), p.OpenPositions.Requests[0].ID)
+	require.Len(t, p.OpenPositions.Offers, 1)
+	assert.Equal(t, uint64(8), p.OpenPositions.Offers[0].RequestID)
+	require.Len(t, p.OpenPositions.Borrowed, 1, "repaid and expired lines are closed")
+	assert.Equal(t, uint64(10), p.OpenPositions.Borrowed[0].CreditLineID)
+	require.Len(t, p.OpenPositions.Lent, 1)
+	assert.Equal(t, accounts.Liquidity{Connected: "4000", Reserved: "1500", Available: "2500"}, p.OpenPositions.Liquidity)
+
+	// Fills, newest first
+	require.Len(t, p.Fills, 2)
+	assert.Equal(t, accounts.Fill{Role: accounts.RoleLender, Market: repositories.MarketAuction, RequestID: 12, CreditLineID: 20,
+		Counterparty: accountOther, Limit: "2000", RateBps: 650, FilledAt: now - 5*day}, p.Fills[0])
+	assert.Equal(t, accounts.Fill{Role: accounts.RoleBorrower, Market: repositories.MarketRFQ, RequestID: 5, CreditLineID: 10,
+		Counterparty: accountOther, Limit: "1000", RateBps: 500, FilledAt: now - 30*day}, p.Fills[1])
+
+	// Debt and realized interest
+	assert.Equal(t, "604", p.Debt.Total)
+	assert.Equal(t, due, p.Debt.NextDue)
+	assert.Equal(t, accounts.Interest{Paid: "10", Earned: "12"}, p.Interest)
+
+	// Repayments first, then the quotes to accept and the auction whose bidding ended
+	require.Len(t, p.PendingActions, 3)
+	assert.Equal(t, accounts.ActionRepayDue, p.PendingActions[0].Type)
+	assert.Equal(t, uint64(10), p.PendingActions[0].ID)
+	assert.Equal(t, "104", p.PendingActions[0].Amount)
+	assert.Equal(t, accounts.ActionAcceptQuote, p.PendingActions[1].Type)
+	assert.Equal(t, uint64(1), p.PendingActions[1].ID)
+	assert.Equal(t, accounts.ActionFinalizeAuction, p.PendingActions[2].Type)
+	assert.Equal(t, uint64(3), p.PendingActions[2].ID)
+}
+
+func TestAccountPortfolioEmpty(t *testing.T) {
+	p := accounts.NewPortfolio(accounts.Holdings{ChainID: 1, Address: accountAddress}, 1_700_000_000)
+
+	assert.Empty(t, p.OpenPositions.Requests)
+	assert.NotNil(t, p.OpenPositions.Requests, "lists are empty, not null")
+	assert.NotNil(t, p.Fills)
+	assert.NotNil(t, p.PendingActions)
+	assert.Equal(t, accounts.Liquidity{Connected: "0", Reserved: "0", Available: "0"}, p.OpenPositions.Liquidity)
+	assert.Equal(t, "0", p.Debt.Total)
+	assert.Equal(t, accounts.Interest{Paid: "0", Earned: "0"}, p.Interest)
+
+	// Due in more than DueSoon is not a pending action
+	due := &credit.Installment{DueAt: 1_700_000_000 + 30*86400, Total: "1"}
+	p = accounts.NewPortfolio(accounts.Holdings{Borrowed: &credit.Obligations{
+		CreditLines: []credit.Obligation{{Position: credit.Position{TotalOwed: "1"}, NextDue: due}},
+	}}, 1_700_000_000)
+	assert.Empty(t, p.PendingActions)
+}
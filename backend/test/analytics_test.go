@@ -0,0 +1,56 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsParseRange(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	r, err := analytics.ParseRange("", "", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Unix(), r.To)
+	assert.Equal(t, now.Unix()-int64(analytics.DefaultRange.Seconds()), r.From)
+	assert.Equal(t, analytics.GranularityDay, r.Granularity)
+
+	r, err = analytics.ParseRange("1699990000", "1700000000", "hour", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1699990000), r.From)
+	assert.Equal(t, analytics.GranularityHour, r.Granularity)
+
+	for _, tc := range []struct{ from, to, granularity string }{
+		{"", "", "minute"},
+		{"abc", "", "day"},
+		{"1700000000", "1700000000", "day"},
+		{"1600000000", "1700000000", "hour"}, // Too many hourly buckets
+	} {
+		_, err := analytics.ParseRange(tc.from, tc.to, tc.granularity, now)
+		assert.True(t, errors.Is(err, analytics.ErrInvalidQuery), "%+v", tc)
+	}
+}
+
+func TestAnalyticsLenderShares(t *testing.T) {
+	points := analytics.LenderShares([]*repositories.LenderVolumeRow{
+		{Bucket: 100, LenderAddress: "0xa", Lines: 3, Volume: "3000000000000000000000"},
+		{Bucket: 100, LenderAddress: "0xb", Lines: 1, Volume: "1000000000000000000000"},
+		{Bucket: 200, LenderAddress: "0xb", Lines: 1, Volume: "5"},
+	})
+
+	require.Len(t, points, 3)
+	assert.InDelta(t, 0.75, points[0].Share, 1e-9)
+	assert.InDelta(t, 0.25, points[1].Share, 1e-9)
+	assert.InDelta(t, 1.0, points[2].Share, 1e-9)
+}
+
+func TestAnalyticsUtilization(t *testing.T) {
+	assert.InDelta(t, 0.4, analytics.Utilization("400", "1000"), 1e-9)
+	assert.Equal(t, 0.0, analytics.Utilization("400", "0"))
+	assert.Equal(t, 0.0, analytics.Utilization("", ""))
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testRegistry(t *testing.T) *chains.Registry {
+	registry, err := chains.NewRegistry([]chains.Chain{
+		{ID: 1337, Name: "Hardhat"},
+		{ID: 80002, Name: "amoy", Confirmations: 5, RPCURLs: []string{"https://amoy.example/key"}},
+	}, "")
+	require.NoError(t, err)
+	return registry
+}
+
+func TestRegistryLookups(t *testing.T) {
+	registry := testRegistry(t)
+	assert.Equal(t, uint64(1337), registry.Default().ID)
+
+	for ref, id := range map[string]uint64{"": 1337, "hardhat": 1337, "HARDHAT": 1337, "80002": 80002, " amoy ": 80002} {
+		chain, err := registry.Get(ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, id, chain.ID, ref)
+	}
+	_, err := registry.Get("base-sepolia")
+	assert.ErrorIs(t, err, chains.ErrUnknownChain)
+	_, err = registry.ByID(84532)
+	assert.ErrorIs(t, err, chains.ErrUnknownChain)
+
+	// Unnamed chains are named by ID, native tokens default to ETH
+	amoy, err := registry.ByID(80002)
+	require.NoError(t, err)
+	assert.Equal(t, chains.Token{Symbol: "ETH", Decimals: 18}, amoy.NativeToken)
+	assert.Nil(t, amoy.Public().RPCURLs)
+	assert.Equal(t, []string{"https://amoy.example/key"}, amoy.ClientConfig(evm.ClientConfig{URLs: []string{"http://node"}}).URLs)
+}
+
+func TestNewRegistryRejectsInvalidChains(t *testing.T) {
+	for name, tc := range map[string]struct {
+		chains     []chains.Chain
+		defaultRef string
+	}{
+		"no chains":        {},
+		"missing ID":       {chains: []chains.Chain{{Name: "hardhat"}}},
+		"duplicate ID":     {chains: []chains.Chain{{ID: 1}, {ID: 1, Name: "mainnet"}}},
+		"duplicate name":   {chains: []chains.Chain{{ID: 1, Name: "a"}, {ID: 2, Name: "A"}}},
+		"numeric name":     {chains: []chains.Chain{{ID: 1, Name: "2"}}},
+		"bad contract":     {chains: []chains.Chain{{ID: 1, Contracts: chains.Contracts{RFQ: "0x1234"}}}},
+		"bad stable token": {chains: []chains.Chain{{ID: 1, StableToken: &chains.Token{Symbol: "USDC"}}}},
+		"unknown default":  {chains: []chains.Chain{{ID: 1}}, defaultRef: "2"},
+	} {
+		_, err := chains.NewRegistry(tc.chains, tc.defaultRef)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLoadRegistryFromEnv(t *testing.T) {
+	t.Setenv("RFQ_CONTRACT_ADDRESS", "0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	registry, err := chains.LoadFromEnv()
+	require.NoError(t, err)
+	chain := registry.Default()
+	assert.Equal(t, uint64(chains.HardhatChainID), chain.ID)
+	assert.Equal(t, "hardhat", chain.Name)
+	assert.Equal(t, "0x5FbDB2315678afecb367f032d93F642f64180aa3", chain.Contracts.RFQ)
+
+	t.Setenv("CHAIN_ID", "84532")
+	t.Setenv("CHAIN_CONFIRMATIONS", "3")
+	registry, err = chains.LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(84532), registry.Default().ID)
+	assert.Equal(t, "84532", registry.Default().Name)
+	assert.Equal(t, uint64(3), registry.Default().Confirmations)
+
+	t.Setenv("CHAIN_ID", "base")
+	_, err = chains.LoadFromEnv()
+	assert.Error(t, err)
+}
+
+func TestLoadRegistryFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chains.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"default": "amoy",
+		"chains": [
+			{"id": 1337, "name": "hardhat"},
+			{"id": 80002, "name": "amoy", "rpc_urls": ["https://amoy.example/${TEST_RPC_KEY}"],
+			 "native_token": {"symbol": "POL", "decimals": 18}}
+		]
+	}`), 0o600))
+	t.Setenv("CHAINS_FILE", path)
+	t.Setenv("TEST_RPC_KEY", "secret")
+
+	registry, err := chains.LoadFromEnv()
+	require.NoError(t, err)
+	require.Len(t, registry.All(), 2)
+	assert.Equal(t, uint64(80002), registry.Default().ID)
+	assert.Equal(t, []string{"https://amoy.example/secret"}, registry.Default().RPCURLs)
+	assert.Equal(t, "POL", registry.Default().NativeToken.Symbol)
+
+	t.Setenv("DEFAULT_CHAIN", "1337")
+	registry, err = chains.LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1337), registry.Default().ID)
+}
+
+func TestChainMiddleware(t *testing.T) {
+	registry := testRegistry(t)
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	api := e.Group("/api/v1", appmiddleware.ChainMiddleware(registry))
+	api.GET("/chain", func(c echo.Context) error {
+		chain, ok := chains.FromContext(c.Request().Context())
+		require.True(t, ok)
+		return c.JSON(http.StatusOK, chain.ID)
+	})
+	api.GET("/chains", handlers.NewChainHandler(registry).ListChains)
+
+	for query, id := range map[string]string{"": "1337", "?chain=amoy": "80002", "?chain=1337": "1337"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/chain"+query, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, query)
+		assert.JSONEq(t, id, rec.Body.String(), query)
+	}
+
+	p, rec := problemOf(t, e, http.MethodGet, "/api/v1/chain?chain=mainnet", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "chain", p.Errors[0].Field)
+
+	// The chain list leaves out RPC URLs, which may embed provider keys
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/chains", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "amoy.example")
+	var listed []chains.Chain
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed, 2)
+	assert.Equal(t, "hardhat", listed[0].Name)
+}
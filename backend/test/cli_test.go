@@ -0,0 +1,61 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLITableAlignsColumns(t *testing.T) {
+	table := cli.NewTable("ID", "STATUS", "ACTIVE", "CREATED")
+	table.Add(7, "open", true, cli.Time(1700000000))
+	table.Add(1234, "", false, cli.Time(0))
+
+	var out bytes.Buffer
+	require.NoError(t, table.Write(&out))
+	assert.Equal(t, ""+
+		"ID    STATUS  ACTIVE  CREATED\n"+
+		"7     open    yes     2023-11-14T22:13:20Z\n"+
+		"1234  -       no      -\n", out.String())
+}
+
+func TestCLICellFormats(t *testing.T) {
+	assert.Equal(t, "-", cli.Cell(nil))
+	assert.Equal(t, "reverted: not enough collateral", cli.Cell("reverted:\n\tnot enough  collateral"))
+	assert.Equal(t, "1m30s", cli.Cell(cli.Seconds(90)))
+	assert.Equal(t, "42", cli.Cell(uint64(42)))
+	assert.True(t, cli.Time(0).IsZero())
+	assert.Equal(t, time.Unix(60, 0), cli.Time(60))
+}
+
+func TestCLIPrint(t *testing.T) {
+	v := []struct {
+		ID     uint64
+		Status string
+	}{{ID: 1, Status: "open"}}
+
+	var out bytes.Buffer
+	require.NoError(t, cli.Print(&out, cli.FormatJSON, v, func() *cli.Table {
+		t.Fatal("table built for JSON output")
+		return nil
+	}))
+	assert.JSONEq(t, `[{"ID": 1, "Status": "open"}]`, out.String())
+
+	out.Reset()
+	require.NoError(t, cli.Print(&out, cli.FormatTable, v, func() *cli.Table {
+		table := cli.NewTable("ID", "STATUS")
+		table.Add(v[0].ID, v[0].Status)
+		return table
+	}))
+	assert.Equal(t, "ID  STATUS\n1   open\n", out.String())
+
+	format, err := cli.ParseFormat("json")
+	require.NoError(t, err)
+	assert.Equal(t, cli.FormatJSON, format)
+	_, err = cli.ParseFormat("yaml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/credit"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	day  = int64(24 * 60 * 60)
+	year = 365 * day
+)
+
+func TestCreditLedgerSimpleInterest(t *testing.T) {
+	line := &repositories.CreditLineModel{ID: 1, RateBps: 1000, CreatedAt: 0, ExpiresAt: 2 * year}
+	ledger := credit.NewLedger(line, credit.InterestSimple, day)
+
+	// Draw 1,000,000 at creation, 10% a year accrues 100,000 after a year
+	assert.NoError(t, ledger.Apply(&repositories.CreditEventModel{EventType: repositories.CreditEventDraw, Amount: "1000000", Timestamp: 0}))
+	ledger.AccrueTo(year)
+	assert.Equal(t, "1000000", ledger.Principal().String())
+	assert.Equal(t, "100000", ledger.AccruedInterest().String())
+
+	// Repayments settle interest before principal
+	assert.NoError(t, ledger.Apply(&repositories.CreditEventModel{EventType: repositories.CreditEventRepay, Amount: "150000", Timestamp: year}))
+	assert.Equal(t, "950000", ledger.Principal().String())
+	assert.Equal(t, "0", ledger.AccruedInterest().String())
+
+	assert.Error(t, ledger.Apply(&repositories.CreditEventModel{EventType: repositories.CreditEventDraw, Amount: "not-a-number", Timestamp: year}))
+}
+
+func TestCreditLedgerCompoundInterest(t *testing.T) {
+	line := &repositories.CreditLineModel{ID: 2, RateBps: 1000, CreatedAt: 0, ExpiresAt: 2 * year}
+	simple := credit.NewLedger(line, credit.InterestSimple, day)
+	compound := credit.NewLedger(line, credit.InterestCompound, day)
+
+	draw := &repositories.CreditEventModel{EventType: repositories.CreditEventDraw, Amount: "1000000000000000000", Timestamp: 0}
+	assert.NoError(t, simple.Apply(draw))
+	assert.NoError(t, compound.Apply(draw))
+	simple.AccrueTo(year)
+	compound.AccrueTo(year)
+
+	// Daily compounding of 10% a year is roughly 10.5%
+	assert.Equal(t, 1, compound.AccruedInterest().Cmp(simple.AccruedInterest()))
+	perMillion := new(big.Int).Quo(compound.AccruedInterest(), big.NewInt(1e12))
+	assert.InDelta(t, 105155, perMillion.Int64(), 2)
+}
+
+func TestCreditLedgerProjection(t *testing.T) {
+	line := &repositories.CreditLineModel{ID: 3, RateBps: 1200, CreatedAt: 0, ExpiresAt: 90 * day}
+	ledger := credit.NewLedger(line, credit.InterestSimple, day)
+	assert.NoError(t, ledger.Apply(&repositories.CreditEventModel{EventType: repositories.CreditEventDraw, Amount: "3650000", Timestamp: 0}))
+
+	installments := ledger.Project(0, 30*day)
+	if assert.Len(t, installments, 3) {
+		assert.Equal(t, 30*day, installments[0].DueAt)
+		assert.Equal(t, "0", installments[0].Principal)
+		assert.Equal(t, "36000", installments[0].Interest)
+		assert.Equal(t, 90*day, installments[2].DueAt)
+		assert.Equal(t, "3650000", installments[2].Principal)
+		assert.Equal(t, "3686000", installments[2].Total)
+	}
+
+	// Once expired, everything owed is due at expiry
+	overdue := ledger.Project(100*day, 30*day)
+	if assert.Len(t, overdue, 1) {
+		assert.True(t, overdue[0].Overdue)
+		assert.Equal(t, 90*day, overdue[0].DueAt)
+	}
+
+	// A fully repaid line has nothing left to schedule
+	empty := credit.NewLedger(line, credit.InterestSimple, day)
+	assert.Empty(t, empty.Project(0, 30*day))
+}
+
+func TestCreditScheduleStorageErrorIsNotNotFound(t *testing.T) {
+	// Nothing listens on port 1, every query fails
+	repo, err := repositories.NewRepository("clickhouse://default@127.0.0.1:1/pagga_data?dial_timeout=200ms")
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	handler := handlers.NewCreditHandler(credit.NewService(repositories.NewCreditRepository(repo), credit.Config{}, zap.NewNop()), zap.NewNop())
+
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	e.GET("/credit-lines/:id/schedule", handler.GetSchedule)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/credit-lines/1/schedule", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/services/events"
+	"github.com/Pagga-Wallet/aqua402/pkg/chains"
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEventMonitorConfigFromEnv(t *testing.T) {
+	cfg, err := events.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, events.Config{
+		Mode:               events.ModeAuto,
+		PollInterval:       5 * time.Second,
+		BatchSize:          1000,
+		ResubscribeDelay:   30 * time.Second,
+		CheckpointInterval: 30 * time.Second,
+	}, cfg)
+
+	t.Setenv("EVENT_MONITOR_MODE", "subscribe")
+	t.Setenv("EVENT_POLL_INTERVAL", "500ms")
+	t.Setenv("EVENT_BATCH_SIZE", "200")
+	cfg, err = events.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, events.ModeSubscribe, cfg.Mode)
+	assert.Equal(t, 500*time.Millisecond, cfg.PollInterval)
+	assert.Equal(t, uint64(200), cfg.BatchSize)
+
+	for key, value := range map[string]string{
+		"EVENT_MONITOR_MODE":        "stream",
+		"EVENT_POLL_INTERVAL":       "0s",
+		"EVENT_BATCH_SIZE":          "0",
+		"EVENT_RESUBSCRIBE_DELAY":   "soon",
+		"EVENT_CHECKPOINT_INTERVAL": "-1s",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			_, err := events.ConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEventMonitorReplayBatchesRange(t *testing.T) {
+	node := &fakeNode{head: 100}
+	client := dialPool(t, evm.ClientConfig{URLs: []string{newFakeNodeServer(t, node).URL}})
+	// The fake node's logs come from no contract, so they are skipped without publishing anything
+	chain := &chains.Chain{ID: 1337, Name: "fake", Contracts: chains.Contracts{
+		RFQ:     "0x0000000000000000000000000000000000000001",
+		Auction: "0x0000000000000000000000000000000000000002",
+		Credit:  "0x0000000000000000000000000000000000000003",
+		Finance: "0x0000000000000000000000000000000000000004",
+		Aqua:    "0x0000000000000000000000000000000000000005",
+	}}
+	monitor, err := events.NewMonitor(client, nil, nil, nil, chain, events.Config{
+		Mode:         events.ModePoll,
+		PollInterval: time.Second,
+		BatchSize:    10,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	var progress []uint64
+	result, err := monitor.Replay(context.Background(), 5, 34, func(r events.ReplayResult) {
+		progress = append(progress, r.To)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, events.ReplayResult{From: 5, To: 34, Processed: 30}, result)
+	assert.Equal(t, []uint64{14, 24, 34}, progress)
+	assert.Equal(t, int64(3), node.getLogs.Load())
+	// Replaying leaves the monitor's own progress alone
+	assert.Equal(t, uint64(100), monitor.Status().Processed)
+
+	_, err = monitor.Replay(context.Background(), 10, 9, nil)
+	assert.Error(t, err)
+}
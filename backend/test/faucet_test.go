@@ -0,0 +1,211 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/internal/services/faucet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+func TestFaucetConfigFromEnv(t *testing.T) {
+	cfg, err := faucet.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, ether(1), cfg.MaxAmount)
+	assert.Equal(t, ether(100), cfg.DailyBudget)
+	assert.Equal(t, 24*time.Hour, cfg.AddressCooldown)
+	assert.Nil(t, faucet.NewVerifier(cfg))
+
+	t.Setenv("FAUCET_MAX_AMOUNT", "0.5")
+	t.Setenv("FAUCET_CHALLENGE", "pow")
+	t.Setenv("FAUCET_POW_DIFFICULTY", "8")
+	cfg, err = faucet.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "500000000000000000", cfg.MaxAmount.String())
+	assert.Equal(t, faucet.ProofOfWork{Difficulty: 8}, faucet.NewVerifier(cfg))
+
+	t.Setenv("FAUCET_CHALLENGE", "captcha")
+	_, err = faucet.ConfigFromEnv()
+	assert.Error(t, err, "captcha needs a secret")
+
+	t.Setenv("FAUCET_CHALLENGE", "none")
+	t.Setenv("FAUCET_MAX_AMOUNT", "0")
+	_, err = faucet.ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestFaucetLimiter(t *testing.T) {
+	ctx := context.Background()
+	store := faucet.NewMemoryStore()
+	cfg := faucet.Config{
+		AddressCooldown: 24 * time.Hour,
+		IPCooldown:      time.Hour,
+		DailyBudget:     ether(3),
+	}
+	limiter := faucet.NewLimiter(cfg, store, 31337, faucet.NativeSymbol)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := limiter.Reserve(ctx, "0xA", "1.1.1.1", ether(1), now)
+	require.NoError(t, err)
+
+	// Same address from another IP waits out the address cooldown
+	_, err = limiter.Reserve(ctx, "0xA", "2.2.2.2", ether(1), now.Add(time.Hour))
+	require.Error(t, err)
+	assert.Equal(t, apperrors.KindRateLimited, apperrors.KindOf(err))
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 23*time.Hour, appErr.RetryAfter)
+
+	// Another address from the same IP waits out the IP cooldown
+	_, err = limiter.Reserve(ctx, "0xB", "1.1.1.1", ether(1), now.Add(30*time.Minute))
+	assert.Equal(t, apperrors.KindRateLimited, apperrors.KindOf(err))
+	_, err = limiter.Reserve(ctx, "0xB", "1.1.1.1", ether(1), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	// A released grant frees the address, the IP and the budget
+	grant, err := limiter.Reserve(ctx, "0xC", "3.3.3.3", ether(1), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, limiter.Release(ctx, grant))
+	grant, err = limiter.Reserve(ctx, "0xC", "3.3.3.3", ether(1), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, limiter.Sent(ctx, grant, "tx"))
+
+	// Another limiter on the same store, as on another replica, sees the grants: the budget of 3 is
+	// spent, it comes back at UTC midnight
+	replica := faucet.NewLimiter(cfg, store, 31337, faucet.NativeSymbol)
+	_, err = replica.Reserve(ctx, "0xD", "4.4.4.4", ether(1), now.Add(2*time.Hour))
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperrors.KindRateLimited, appErr.Kind)
+	assert.Equal(t, 10*time.Hour, appErr.RetryAfter)
+	_, err = replica.Reserve(ctx, "0xD", "4.4.4.4", ether(1), now.Add(12*time.Hour))
+	require.NoError(t, err)
+
+	// Each token has its own limits
+	tokens := faucet.NewLimiter(cfg, store, 31337, "USDC")
+	_, err = tokens.Reserve(ctx, "0xA", "1.1.1.1", ether(1), now.Add(time.Hour))
+	require.NoError(t, err)
+}
+
+func TestFaucetProofOfWork(t *testing.T) {
+	pow := faucet.ProofOfWork{Difficulty: 12}
+	address := "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	token := faucet.SolveProofOfWork(address, pow.Difficulty)
+
+	ctx := context.Background()
+	assert.NoError(t, pow.Verify(ctx, address, "", token))
+	assert.Equal(t, apperrors.KindForbidden, apperrors.KindOf(pow.Verify(ctx, address, "", "")))
+
+	// A token solved for one address does not work for another
+	other := "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	assert.Equal(t, apperrors.KindForbidden, apperrors.KindOf(pow.Verify(ctx, other, "", token)))
+}
+
+func TestFaucetCaptcha(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "1.1.1.1", r.PostForm.Get("remoteip"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("response") == "good" {
+			_, _ = w.Write([]byte(`{"success":true}`))
+		} else {
+			_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}
+	}))
+	defer server.Close()
+
+	captcha := &faucet.Captcha{VerifyURL: server.URL, Secret: "secret", Client: server.Client()}
+	ctx := context.Background()
+	assert.NoError(t, captcha.Verify(ctx, "0xA", "1.1.1.1", "good"))
+	assert.Equal(t, apperrors.KindForbidden, apperrors.KindOf(captcha.Verify(ctx, "0xA", "1.1.1.1", "bad")))
+	assert.Equal(t, apperrors.KindForbidden, apperrors.KindOf(captcha.Verify(ctx, "0xA", "1.1.1.1", "")))
+}
+
+func TestRateLimitedProblem(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	e.POST("/faucet", func(c echo.Context) error {
+		return apperrors.RateLimited(90*time.Second+time.Millisecond, "too many faucet requests from this IP")
+	})
+
+	p, rec := problemOf(t, e, http.MethodPost, "/faucet", "")
+	assert.Equal(t, http.StatusTooManyRequests, p.Status)
+	assert.Equal(t, "91", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "too many faucet requests from this IP", p.Detail)
+}
+
+func TestFaucetParseTokens(t *testing.T) {
+	tokens, err := faucet.ParseTokens(" usdc=0x5fbdb2315678afecb367f032d93f642f64180aa3:mint:1000:100000 ;" +
+		"DAI=0x70997970C51812dc3A010C7d01b50e0d17dc79C8:transfer:0.5;")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, faucet.TokenConfig{
+		Symbol:      "USDC",
+		Address:     common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3"),
+		Mode:        faucet.ModeMint,
+		MaxAmount:   "1000",
+		DailyBudget: "100000",
+	}, tokens[0])
+	assert.Equal(t, faucet.ModeTransfer, tokens[1].Mode)
+	assert.Empty(t, tokens[1].DailyBudget)
+
+	tokens, err = faucet.ParseTokens("")
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	for _, spec := range []string{
+		"USDC=0x5FbDB2315678afecb367f032d93F642f64180aa3:burn:1",
+		"USDC=0x5FbDB2315678afecb367f032d93F642f64180aa3:mint",
+		"USDC=nope:mint:1",
+		"ETH=0x5FbDB2315678afecb367f032d93F642f64180aa3:mint:1",
+		"A=0x5FbDB2315678afecb367f032d93F642f64180aa3:mint:1;a=0x70997970C51812dc3A010C7d01b50e0d17dc79C8:mint:1",
+		"0x5FbDB2315678afecb367f032d93F642f64180aa3:mint:1",
+	} {
+		_, err := faucet.ParseTokens(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestFaucetClientIPIgnoresSpoofedHeaders(t *testing.T) {
+	request := func(remoteAddr, forwardedFor string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/faucet", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		req.Header.Set(echo.HeaderXRealIP, "198.51.100.9")
+		return req
+	}
+
+	// Without trusted proxies the forwarded headers are ignored
+	direct := appmiddleware.IPExtractor(nil)
+	assert.Equal(t, "203.0.113.7", direct(request("203.0.113.7:4000", "198.51.100.1")))
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.5")
+	proxies, err := appmiddleware.TrustedProxiesFromEnv()
+	require.NoError(t, err)
+	require.Len(t, proxies, 2)
+
+	// Behind the proxies the client is the right-most address they did not add, whatever it prepended
+	behind := appmiddleware.IPExtractor(proxies)
+	assert.Equal(t, "203.0.113.7", behind(request("10.1.2.3:4000", "198.51.100.1, 203.0.113.7, 192.168.1.5")))
+	// A client reaching the API directly cannot claim another address
+	assert.Equal(t, "203.0.113.7", behind(request("203.0.113.7:4000", "198.51.100.1")))
+
+	t.Setenv("TRUSTED_PROXIES", "not-a-network")
+	_, err = appmiddleware.TrustedProxiesFromEnv()
+	assert.Error(t, err)
+}
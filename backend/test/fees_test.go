@@ -0,0 +1,91 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1_000_000_000))
+}
+
+func TestFeesFromHistory(t *testing.T) {
+	history := &ethereum.FeeHistory{
+		Reward:  [][]*big.Int{{gwei(1)}, {gwei(3)}, {big.NewInt(0)}, {gwei(2)}},
+		BaseFee: []*big.Int{gwei(8), gwei(9), gwei(10), gwei(10), gwei(12)},
+	}
+
+	fees, err := evm.FeesFromHistory(history, evm.UrgencyNormal, nil)
+	require.NoError(t, err)
+	assert.True(t, fees.Dynamic())
+	// Median of the non-zero tips, and twice the next base fee plus the tip
+	assert.Equal(t, gwei(2), fees.GasTipCap)
+	assert.Equal(t, gwei(26), fees.GasFeeCap)
+
+	fees, err = evm.FeesFromHistory(history, evm.UrgencyFast, gwei(5))
+	require.NoError(t, err)
+	assert.Equal(t, gwei(5), fees.GasTipCap)
+	assert.Equal(t, gwei(41), fees.GasFeeCap)
+
+	_, err = evm.FeesFromHistory(history, "urgent", nil)
+	assert.Error(t, err)
+	_, err = evm.FeesFromHistory(&ethereum.FeeHistory{}, evm.UrgencySlow, nil)
+	assert.Error(t, err)
+}
+
+func TestFeeConfigGuard(t *testing.T) {
+	cfg := evm.FeeConfig{MaxFeePerGas: map[uint64]*big.Int{1: gwei(20)}}
+	fees := &evm.Fees{GasTipCap: gwei(30), GasFeeCap: gwei(40)}
+
+	guarded, err := cfg.Guard(big.NewInt(1), fees, gwei(15))
+	require.NoError(t, err)
+	assert.Equal(t, gwei(20), guarded.GasFeeCap)
+	assert.Equal(t, gwei(20), guarded.GasTipCap)
+	assert.Equal(t, gwei(40), fees.GasFeeCap, "guard must not modify its input")
+
+	_, err = cfg.Guard(big.NewInt(1), fees, gwei(25))
+	assert.ErrorIs(t, err, evm.ErrFeeAboveMax)
+	_, err = cfg.Guard(big.NewInt(1), &evm.Fees{GasPrice: gwei(21)}, nil)
+	assert.ErrorIs(t, err, evm.ErrFeeAboveMax)
+
+	// Chains without a maximum are not guarded
+	guarded, err = cfg.Guard(big.NewInt(5), fees, gwei(100))
+	require.NoError(t, err)
+	assert.Equal(t, fees, guarded)
+}
+
+func TestFeeConfigFromEnv(t *testing.T) {
+	t.Setenv("EVM_FEE_URGENCY", "fast")
+	t.Setenv("EVM_MAX_FEE_PER_GAS", "1=200000000000; 31337=5000000000")
+	cfg, err := evm.FeeConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, evm.UrgencyFast, cfg.Urgency)
+	assert.Equal(t, gwei(200), cfg.MaxFeePerGas[1])
+	assert.Equal(t, gwei(5), cfg.MaxFeePerGas[31337])
+
+	t.Setenv("EVM_FEE_URGENCY", "asap")
+	_, err = evm.FeeConfigFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("EVM_FEE_URGENCY", "")
+	t.Setenv("EVM_MAX_FEE_PER_GAS", "mainnet=1")
+	_, err = evm.FeeConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestNewTxPicksTransactionType(t *testing.T) {
+	chainID := big.NewInt(1337)
+	dynamic := evm.NewTx(chainID, 1, txRecipient, big.NewInt(1), 21000, &evm.Fees{GasTipCap: gwei(1), GasFeeCap: gwei(3)}, nil)
+	assert.Equal(t, uint8(types.DynamicFeeTxType), dynamic.Type())
+	assert.Equal(t, gwei(3), dynamic.GasFeeCap())
+
+	legacy := evm.NewTx(chainID, 1, txRecipient, big.NewInt(1), 21000, &evm.Fees{GasPrice: gwei(2)}, nil)
+	assert.Equal(t, uint8(types.LegacyTxType), legacy.Type())
+	assert.Equal(t, gwei(2), legacy.GasPrice())
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readiness(t *testing.T, checker *health.Checker) (int, health.Report) {
+	rec := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func TestReadinessReportsEveryCheck(t *testing.T) {
+	var rpcErr error
+	checker := health.NewChecker(time.Second)
+	checker.Register("clickhouse", func(ctx context.Context) (health.Details, error) { return nil, nil })
+	checker.RegisterOptional("rpc:hardhat", func(ctx context.Context) (health.Details, error) {
+		return health.Details{"block_number": 12}, rpcErr
+	})
+
+	code, report := readiness(t, checker)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, float64(12), report.Checks["rpc:hardhat"].Details["block_number"])
+
+	// An optional dependency going down degrades the report without failing the probe
+	rpcErr = errors.New("connection refused")
+	code, report = readiness(t, checker)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["rpc:hardhat"].Error)
+
+	// The last error is still reported once the dependency recovers
+	rpcErr = nil
+	_, report = readiness(t, checker)
+	rpc := report.Checks["rpc:hardhat"]
+	assert.Equal(t, health.StatusOK, rpc.Status)
+	assert.Empty(t, rpc.Error)
+	assert.Equal(t, "connection refused", rpc.LastError)
+	assert.NotNil(t, rpc.LastErrorAt)
+}
+
+func TestReadinessFailsOnRequiredChecks(t *testing.T) {
+	checker := health.NewChecker(20 * time.Millisecond)
+	checker.Register("rabbitmq", health.RabbitMQ(nil, errors.New("dial tcp: connection refused")))
+	checker.Register("clickhouse", health.ClickHouse(nil, nil))
+	checker.Register("slow", func(ctx context.Context) (health.Details, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	code, report := readiness(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, "not connected: dial tcp: connection refused", report.Checks["rabbitmq"].Error)
+	assert.Equal(t, false, report.Checks["rabbitmq"].Details["connected"])
+	assert.Equal(t, "not configured", report.Checks["clickhouse"].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestLivenessChecksNothing(t *testing.T) {
+	rec := httptest.NewRecorder()
+	health.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestHealthConfigFromEnv(t *testing.T) {
+	t.Setenv("HEALTH_CHECK_TIMEOUT", "")
+	t.Setenv("HEALTH_MAX_BLOCK_LAG", "")
+	cfg, err := health.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, health.Config{Timeout: 2 * time.Second, MaxBlockLag: 100}, cfg)
+
+	t.Setenv("HEALTH_CHECK_TIMEOUT", "500ms")
+	t.Setenv("HEALTH_MAX_BLOCK_LAG", "20")
+	cfg, err = health.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, health.Config{Timeout: 500 * time.Millisecond, MaxBlockLag: 20}, cfg)
+
+	t.Setenv("HEALTH_CHECK_TIMEOUT", "0s")
+	_, err = health.ConfigFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newIdempotentServer counts calls to POST /rfq, which answers 201 with the call number.
+// POST /flaky fails with 503 on its first call.
+func newIdempotentServer(calls *int32, release <-chan struct{}) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	idempotent := appmiddleware.IdempotencyMiddleware(appmiddleware.NewMemoryIdempotencyStore(), time.Hour, zap.NewNop())
+
+	e.POST("/rfq", func(c echo.Context) error {
+		n := atomic.AddInt32(calls, 1)
+		if release != nil {
+			<-release
+		}
+		return c.JSON(http.StatusCreated, map[string]int32{"id": n})
+	}, idempotent)
+	e.POST("/flaky", func(c echo.Context) error {
+		if atomic.AddInt32(calls, 1) == 1 {
+			return apperrors.Unavailable(errors.New("connection refused"), "failed to publish quote")
+		}
+		return c.JSON(http.StatusOK, map[string]string{"status": "success"})
+	}, idempotent)
+	return e
+}
+
+func idempotentPost(e *echo.Echo, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(appmiddleware.HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	var calls int32
+	e := newIdempotentServer(&calls, nil)
+
+	first := idempotentPost(e, "/rfq", "key-1", `{"amount":"1000"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(appmiddleware.HeaderIdempotentReplayed))
+
+	retry := idempotentPost(e, "/rfq", "key-1", `{"amount":"1000"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, first.Header().Get(echo.HeaderContentType), retry.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "true", retry.Header().Get(appmiddleware.HeaderIdempotentReplayed))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Without a key, or with another key, the request runs again
+	assert.Equal(t, http.StatusCreated, idempotentPost(e, "/rfq", "", `{"amount":"1000"}`).Code)
+	assert.Equal(t, http.StatusCreated, idempotentPost(e, "/rfq", "key-2", `{"amount":"1000"}`).Code)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotencyRejectsReuse(t *testing.T) {
+	var calls int32
+	e := newIdempotentServer(&calls, nil)
+
+	require.Equal(t, http.StatusCreated, idempotentPost(e, "/rfq", "key-1", `{"amount":"1000"}`).Code)
+	assert.Equal(t, http.StatusConflict, idempotentPost(e, "/rfq", "key-1", `{"amount":"2000"}`).Code)
+	assert.Equal(t, http.StatusConflict, idempotentPost(e, "/flaky", "key-1", `{"amount":"1000"}`).Code)
+	// The same body sent to another chain is another request
+	assert.Equal(t, http.StatusConflict, idempotentPost(e, "/rfq?chain=137", "key-1", `{"amount":"1000"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, idempotentPost(e, "/rfq", strings.Repeat("k", 256), `{}`).Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	e := newIdempotentServer(&calls, release)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentPost(e, "/rfq", "key-1", `{}`) }()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+
+	assert.Equal(t, http.StatusConflict, idempotentPost(e, "/rfq", "key-1", `{}`).Code)
+
+	close(release)
+	assert.Equal(t, http.StatusCreated, (<-done).Code)
+	assert.Equal(t, http.StatusCreated, idempotentPost(e, "/rfq", "key-1", `{}`).Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotencyRetriesServerErrors(t *testing.T) {
+	var calls int32
+	e := newIdempotentServer(&calls, nil)
+
+	first := idempotentPost(e, "/flaky", "key-1", `{}`)
+	assert.Equal(t, http.StatusServiceUnavailable, first.Code)
+	assert.Equal(t, handlers.MIMEProblemJSON, first.Header().Get(echo.HeaderContentType))
+
+	assert.Equal(t, http.StatusOK, idempotentPost(e, "/flaky", "key-1", `{}`).Code)
+	replay := idempotentPost(e, "/flaky", "key-1", `{}`)
+	assert.Equal(t, http.StatusOK, replay.Code)
+	assert.Equal(t, "true", replay.Header().Get(appmiddleware.HeaderIdempotentReplayed))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+// raceReserve reserves key concurrently through each store, several times, and returns how many
+// reservations were granted
+func raceReserve(t *testing.T, key string, stores ...appmiddleware.IdempotencyStore) int32 {
+	var granted int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	now := time.Now()
+	for _, store := range stores {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(store appmiddleware.IdempotencyStore, owner string) {
+				defer wg.Done()
+				<-start
+				existing, err := store.Reserve(context.Background(), &repositories.IdempotencyKeyModel{
+					Key:         key,
+					RequestHash: "hash",
+					Owner:       owner,
+					CreatedAt:   now.Unix(),
+					ExpiresAt:   now.Add(time.Minute).Unix(),
+				})
+				if !assert.NoError(t, err) {
+					return
+				}
+				if existing == nil {
+					atomic.AddInt32(&granted, 1)
+				} else {
+					assert.Equal(t, "hash", existing.RequestHash)
+				}
+			}(store, fmt.Sprintf("%p-%d", store, i))
+		}
+	}
+	close(start)
+	wg.Wait()
+	return granted
+}
+
+func TestIdempotencyReserveRace(t *testing.T) {
+	assert.Equal(t, int32(1), raceReserve(t, "key-1", appmiddleware.NewMemoryIdempotencyStore()))
+}
+
+// TestIdempotencyReserveRaceAcrossReplicas races two ClickHouse stores, as two API replicas would, on
+// the same key. It needs a migrated ClickHouse at TEST_CLICKHOUSE_DSN.
+func TestIdempotencyReserveRaceAcrossReplicas(t *testing.T) {
+	dsn := os.Getenv("TEST_CLICKHOUSE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_CLICKHOUSE_DSN is not set")
+	}
+	var stores []appmiddleware.IdempotencyStore
+	for i := 0; i < 2; i++ {
+		repo, err := repositories.NewRepository(dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = repo.Close() })
+		stores = append(stores, repositories.NewIdempotencyRepository(repo))
+	}
+
+	key := fmt.Sprintf("race-%d", time.Now().UnixNano())
+	assert.Equal(t, int32(1), raceReserve(t, key, stores...))
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// steps records what the components and hooks of a test manager did
+type steps struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (s *steps) add(step string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log = append(s.log, step)
+}
+
+func (s *steps) list() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.log...)
+}
+
+// blocking is a component that runs until stopped
+func blocking(s *steps, name string, started *sync.WaitGroup) func(ctx context.Context) error {
+	started.Add(1)
+	return func(ctx context.Context) error {
+		started.Done()
+		<-ctx.Done()
+		s.add("stop " + name)
+		return ctx.Err()
+	}
+}
+
+func TestLifecycleStopsInReverseOrder(t *testing.T) {
+	var s steps
+	var started sync.WaitGroup
+	manager := lifecycle.New(zap.NewNop(), time.Second)
+	manager.Go("monitor", blocking(&s, "monitor", &started))
+	manager.Go("consumer", blocking(&s, "consumer", &started))
+	manager.OnStop("queue", func(context.Context) error { s.add("close queue"); return nil })
+	manager.OnStop("checkpoint", func(context.Context) error { s.add("checkpoint"); return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		started.Wait()
+		cancel()
+	}()
+	require.NoError(t, manager.Run(ctx))
+
+	// Consumers added last stop first, then the hooks run, also in reverse
+	assert.Equal(t, []string{"stop consumer", "stop monitor", "checkpoint", "close queue"}, s.list())
+}
+
+func TestLifecycleComponentFailureStopsTheOthers(t *testing.T) {
+	var s steps
+	var started sync.WaitGroup
+	manager := lifecycle.New(zap.NewNop(), time.Second)
+	manager.Go("monitor", blocking(&s, "monitor", &started))
+	manager.Go("consumer", func(ctx context.Context) error {
+		started.Wait()
+		return errors.New("channel closed")
+	})
+	manager.OnStop("queue", func(context.Context) error { s.add("close queue"); return nil })
+
+	err := manager.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "consumer: channel closed")
+	assert.Equal(t, []string{"stop monitor", "close queue"}, s.list())
+}
+
+func TestLifecycleReportsSlowComponentsAndFailedHooks(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(1)
+	release := make(chan struct{})
+	defer close(release)
+
+	manager := lifecycle.New(zap.NewNop(), 50*time.Millisecond)
+	manager.Go("consumer", func(ctx context.Context) error {
+		started.Done()
+		<-release
+		return nil
+	})
+	hookCalled := false
+	manager.OnStop("checkpoint", func(ctx context.Context) error {
+		hookCalled = true
+		return errors.New("clickhouse unavailable")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		started.Wait()
+		cancel()
+	}()
+	err := manager.Run(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "consumer did not stop within 50ms")
+	assert.Contains(t, err.Error(), "checkpoint: clickhouse unavailable")
+	assert.True(t, hookCalled)
+}
+
+func TestLifecycleServe(t *testing.T) {
+	server := &http.Server{Addr: "127.0.0.1:0"}
+	manager := lifecycle.New(zap.NewNop(), time.Second)
+	manager.Serve("server", server.ListenAndServe, server.Shutdown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	// The server is shut down gracefully, ErrServerClosed is not reported
+	assert.NoError(t, manager.Run(ctx))
+}
+
+func TestLifecycleConfigFromEnv(t *testing.T) {
+	cfg, err := lifecycle.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, cfg.ShutdownTimeout)
+
+	t.Setenv("SHUTDOWN_TIMEOUT", "45s")
+	cfg, err = lifecycle.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 45*time.Second, cfg.ShutdownTimeout)
+
+	t.Setenv("SHUTDOWN_TIMEOUT", "0s")
+	_, err = lifecycle.ConfigFromEnv()
+	assert.Error(t, err)
+}
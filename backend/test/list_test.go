@@ -0,0 +1,95 @@
+package test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListQueryDefaults(t *testing.T) {
+	q, err := handlers.ParseListQuery(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, "", q.Sort)
+	assert.True(t, q.Desc)
+	assert.Equal(t, 0, q.Limit)
+	assert.Nil(t, q.After)
+	assert.Nil(t, q.MinDuration)
+	assert.Nil(t, q.CollateralType)
+}
+
+func TestParseListQueryFilters(t *testing.T) {
+	cursor := repositories.Cursor{Sort: "amount", Value: "1000000000000000000000", ID: 42}.Encode()
+	q, err := handlers.ParseListQuery(url.Values{
+		"status":          {"open"},
+		"borrower":        {"0xB0"},
+		"lender":          {"0xL1"},
+		"amount_min":      {"1000"},
+		"amount_max":      {"115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		"duration_min":    {"3600"},
+		"duration_max":    {"86400"},
+		"collateral_type": {"2"},
+		"created_from":    {"1700000000"},
+		"created_to":      {"1700086400"},
+		"sort":            {"amount"},
+		"limit":           {"50"},
+		"cursor":          {cursor},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "open", q.Status)
+	assert.Equal(t, "0xB0", q.Borrower)
+	assert.Equal(t, "0xL1", q.Lender)
+	assert.Equal(t, "1000", q.MinAmount)
+	require.NotNil(t, q.MinDuration)
+	assert.Equal(t, uint64(3600), *q.MinDuration)
+	require.NotNil(t, q.MaxDuration)
+	assert.Equal(t, uint64(86400), *q.MaxDuration)
+	require.NotNil(t, q.CollateralType)
+	assert.Equal(t, uint8(2), *q.CollateralType)
+	require.NotNil(t, q.CreatedFrom)
+	assert.Equal(t, int64(1700000000), *q.CreatedFrom)
+	require.NotNil(t, q.CreatedTo)
+	assert.Equal(t, int64(1700086400), *q.CreatedTo)
+	assert.Equal(t, "amount", q.Sort)
+	assert.False(t, q.Desc)
+	assert.Equal(t, 50, q.Limit)
+	require.NotNil(t, q.After)
+	assert.Equal(t, repositories.Cursor{Sort: "amount", Value: "1000000000000000000000", ID: 42}, *q.After)
+
+	q, err = handlers.ParseListQuery(url.Values{"sort": {"-duration"}})
+	require.NoError(t, err)
+	assert.Equal(t, "duration", q.Sort)
+	assert.True(t, q.Desc)
+}
+
+func TestParseListQueryRejectsInvalid(t *testing.T) {
+	for _, params := range []url.Values{
+		{"limit": {"0"}},
+		{"limit": {"1000000"}},
+		{"limit": {"abc"}},
+		{"amount_min": {"-5"}},
+		{"amount_max": {"1e18"}},
+		{"duration_min": {"-1"}},
+		{"collateral_type": {"256"}},
+		{"created_from": {"yesterday"}},
+		{"cursor": {"not a cursor"}},
+	} {
+		_, err := handlers.ParseListQuery(params)
+		assert.True(t, errors.Is(err, repositories.ErrInvalidListQuery), "%v", params)
+	}
+}
+
+func TestListCursorRoundTrip(t *testing.T) {
+	c := repositories.Cursor{Sort: "created_at", Desc: true, Value: "1700000000", ID: 7}
+	decoded, err := repositories.DecodeCursor(c.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, c, *decoded)
+
+	_, err = repositories.DecodeCursor("e30") // {}
+	assert.True(t, errors.Is(err, repositories.ErrInvalidListQuery))
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	"github.com/Pagga-Wallet/aqua402/internal/metrics"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMetricsMiddlewareRecordsRoutePatterns(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	e.Use(appmiddleware.MetricsMiddleware())
+	e.GET("/metrics-test/:id", func(c echo.Context) error {
+		if c.Param("id") == "missing" {
+			return apperrors.NotFound("thing not found")
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2", "/metrics-test/missing"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// IDs share the route's series, and handler errors are recorded with the status they render as
+	body := scrapeMetrics(t)
+	assert.Contains(t, body, `aqua402_http_request_duration_seconds_count{method="GET",route="/metrics-test/:id",status="204"} 2`)
+	assert.Contains(t, body, `aqua402_http_request_duration_seconds_count{method="GET",route="/metrics-test/:id",status="404"} 1`)
+}
+
+func TestMetricsHandlerServesWebSocketGauges(t *testing.T) {
+	metrics.ObserveWebSocket(func() (int, int) { return 3, 2 })
+	defer metrics.ObserveWebSocket(func() (int, int) { return 0, 0 })
+
+	body := scrapeMetrics(t)
+	assert.Contains(t, body, "aqua402_websocket_connections 3")
+	assert.Contains(t, body, "aqua402_websocket_topics 2")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func scrapeMetrics(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
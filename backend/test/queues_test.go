@@ -0,0 +1,92 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/queues"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAcknowledger records how a delivery was settled
+type fakeAcknowledger struct {
+	acked, nacked, requeued bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked, a.requeued = true, requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func newDelivery(ack amqp.Acknowledger) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: ack,
+		DeliveryTag:  1,
+		ContentType:  "application/json",
+		Headers:      amqp.Table{"traceparent": "00-trace"},
+		Timestamp:    time.Unix(1700000000, 0),
+		Body:         []byte(`{"type":"bid_placed"}`),
+	}
+}
+
+func TestSettleAcknowledgesHandledMessage(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	err := queues.Settle(newDelivery(ack), nil, func(amqp.Publishing) error {
+		t.Fatal("handled message parked")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, ack.acked)
+	assert.False(t, ack.nacked)
+}
+
+func TestSettleParksFailedMessage(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	var parked []amqp.Publishing
+	err := queues.Settle(newDelivery(ack), errors.New("auction 7 not found"), func(p amqp.Publishing) error {
+		parked = append(parked, p)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, parked, 1)
+	assert.Equal(t, []byte(`{"type":"bid_placed"}`), parked[0].Body)
+	assert.Equal(t, "application/json", parked[0].ContentType)
+	assert.Equal(t, time.Unix(1700000000, 0), parked[0].Timestamp)
+	assert.Equal(t, amqp.Persistent, parked[0].DeliveryMode)
+	assert.Equal(t, "00-trace", parked[0].Headers["traceparent"])
+	assert.Equal(t, "auction 7 not found", parked[0].Headers[queues.HeaderError])
+	assert.Contains(t, parked[0].Headers, queues.HeaderFailedAt)
+	// Parked, the message leaves the consumed queue
+	assert.True(t, ack.acked)
+	assert.False(t, ack.nacked)
+}
+
+func TestSettleDropsMessageThatCannotBeParked(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	err := queues.Settle(newDelivery(ack), errors.New("auction 7 not found"), func(amqp.Publishing) error {
+		return errors.New("channel closed")
+	})
+	assert.ErrorContains(t, err, "channel closed")
+	assert.False(t, ack.acked)
+	assert.True(t, ack.nacked)
+	assert.False(t, ack.requeued)
+}
+
+func TestParkedKeepsDeliveryHeaders(t *testing.T) {
+	d := newDelivery(&fakeAcknowledger{})
+	queues.Parked(d, errors.New("boom"), time.Now())
+	assert.NotContains(t, d.Headers, queues.HeaderError)
+	assert.Equal(t, "rfq.events.failed", queues.FailedQueue("rfq.events"))
+}
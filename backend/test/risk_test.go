@@ -0,0 +1,231 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/risk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const riskChainID = 1337
+
+func TestClassify(t *testing.T) {
+	thresholds := risk.Thresholds{GracePeriod: time.Hour, DefaultAfter: 24 * time.Hour}
+	expiresAt := int64(1_000_000)
+	owed := big.NewInt(100)
+
+	tests := []struct {
+		name string
+		now  int64
+		owed *big.Int
+		want string
+	}{
+		{"not expired", expiresAt - 1, owed, risk.StatusCurrent},
+		{"nothing owed", expiresAt + 48*3600, big.NewInt(0), risk.StatusCurrent},
+		{"unknown balance", expiresAt + 48*3600, nil, risk.StatusCurrent},
+		{"just expired", expiresAt, owed, risk.StatusGrace},
+		{"within grace", expiresAt + 3599, owed, risk.StatusGrace},
+		{"grace over", expiresAt + 3600, owed, risk.StatusDelinquent},
+		{"before default", expiresAt + 24*3600 - 1, owed, risk.StatusDelinquent},
+		{"defaulted", expiresAt + 24*3600, owed, risk.StatusDefaulted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, risk.Classify(expiresAt, tt.now, tt.owed, thresholds))
+		})
+	}
+}
+
+// saveLiquidationCase stores a case of credit line id in status, lent by the address id
+func saveLiquidationCase(t *testing.T, store risk.Store, id uint64, status string) {
+	require.NoError(t, store.SaveLiquidationCase(context.Background(), &repositories.LiquidationCaseModel{
+		ChainID:       riskChainID,
+		CreditLineID:  id,
+		LenderAddress: common.BigToAddress(new(big.Int).SetUint64(id)).Hex(),
+		ReleaseAmount: "500",
+		Action:        risk.ActionClaim,
+		Status:        status,
+		CreatedAt:     int64(id),
+	}))
+}
+
+func caseStatus(t *testing.T, store risk.Store, id uint64) string {
+	c, err := store.GetLiquidationCase(context.Background(), riskChainID, id)
+	require.NoError(t, err)
+	return c.Status
+}
+
+func TestRiskService_ApproveCase(t *testing.T) {
+	ctx := context.Background()
+	store := risk.NewMemoryStore()
+	service := risk.NewService(store, nil, zap.NewNop())
+	saveLiquidationCase(t, store, 1, risk.CaseStatusPendingApproval)
+
+	c, err := service.ApproveCase(ctx, riskChainID, 1, risk.ApproveRequest{Operator: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, risk.CaseStatusApproved, c.Status)
+	assert.Equal(t, risk.ActionClaim, c.Action)
+	assert.Equal(t, "alice", c.Operator)
+	assert.Equal(t, risk.CaseStatusApproved, caseStatus(t, store, 1))
+
+	_, err = service.ApproveCase(ctx, riskChainID, 1, risk.ApproveRequest{Operator: "alice"})
+	assert.ErrorIs(t, err, risk.ErrCaseNotPending)
+	_, err = service.RejectCase(ctx, riskChainID, 1, risk.RejectRequest{Operator: "alice"})
+	assert.ErrorIs(t, err, risk.ErrCaseNotPending)
+}
+
+func TestRiskService_ApproveCaseRejectsUnknownAction(t *testing.T) {
+	store := risk.NewMemoryStore()
+	service := risk.NewService(store, nil, zap.NewNop())
+	saveLiquidationCase(t, store, 1, risk.CaseStatusPendingApproval)
+
+	_, err := service.ApproveCase(context.Background(), riskChainID, 1, risk.ApproveRequest{Action: "release"})
+	assert.ErrorIs(t, err, risk.ErrInvalidAction)
+	assert.Equal(t, risk.CaseStatusPendingApproval, caseStatus(t, store, 1))
+}
+
+func TestRiskService_RejectCase(t *testing.T) {
+	ctx := context.Background()
+	store := risk.NewMemoryStore()
+	service := risk.NewService(store, nil, zap.NewNop())
+	saveLiquidationCase(t, store, 1, risk.CaseStatusPendingApproval)
+
+	c, err := service.RejectCase(ctx, riskChainID, 1, risk.RejectRequest{Operator: "bob", Reason: "borrower repaid off-chain"})
+	require.NoError(t, err)
+	assert.Equal(t, risk.CaseStatusRejected, c.Status)
+	assert.Equal(t, "borrower repaid off-chain", c.Error)
+	assert.Equal(t, risk.CaseStatusRejected, caseStatus(t, store, 1))
+
+	_, err = service.ApproveCase(ctx, riskChainID, 1, risk.ApproveRequest{})
+	assert.ErrorIs(t, err, risk.ErrCaseNotPending)
+}
+
+func TestRiskService_UnknownCase(t *testing.T) {
+	service := risk.NewService(risk.NewMemoryStore(), nil, zap.NewNop())
+
+	_, err := service.ApproveCase(context.Background(), riskChainID, 9, risk.ApproveRequest{})
+	assert.Equal(t, apperrors.KindNotFound, apperrors.KindOf(err))
+}
+
+// fakeReleaser records releases and reports the case status the store held when each one was sent
+type fakeReleaser struct {
+	mu             sync.Mutex
+	store          risk.Store
+	err            error
+	released       []uint64
+	statusesAtSend []string
+}
+
+func (r *fakeReleaser) ReleaseLiquidity(ctx context.Context, lender common.Address, amount *big.Int) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cases, err := r.store.ListLiquidationCases(ctx, riskChainID, "")
+	if err != nil {
+		return "", err
+	}
+	for _, c := range cases {
+		if common.HexToAddress(c.LenderAddress) == lender {
+			r.statusesAtSend = append(r.statusesAtSend, c.Status)
+		}
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+	r.released = append(r.released, amount.Uint64())
+	return "0xabc", nil
+}
+
+// failingOutcomeStore fails to save cases in status, as a ClickHouse outage between send and save would
+type failingOutcomeStore struct {
+	*risk.MemoryStore
+	status string
+}
+
+func (s *failingOutcomeStore) SaveLiquidationCase(ctx context.Context, c *repositories.LiquidationCaseModel) error {
+	if c.Status == s.status {
+		return errors.New("clickhouse unavailable")
+	}
+	return s.MemoryStore.SaveLiquidationCase(ctx, c)
+}
+
+func newRiskMonitor(store risk.Store, releaser risk.Releaser) *risk.Monitor {
+	contracts := map[uint64]risk.Contracts{riskChainID: {Releaser: releaser}}
+	return risk.NewMonitor(nil, store, nil, nil, contracts, risk.Config{}, zap.NewNop())
+}
+
+func TestMonitor_ExecuteApprovedCases(t *testing.T) {
+	ctx := context.Background()
+	store := risk.NewMemoryStore()
+	releaser := &fakeReleaser{store: store}
+	saveLiquidationCase(t, store, 1, risk.CaseStatusApproved)
+	saveLiquidationCase(t, store, 2, risk.CaseStatusPendingApproval)
+	saveLiquidationCase(t, store, 3, risk.CaseStatusExecuting)
+
+	monitor := newRiskMonitor(store, releaser)
+	require.NoError(t, monitor.ExecuteApprovedCases(ctx))
+
+	// Only the approved case is sent, and it is saved as executing first
+	assert.Equal(t, []uint64{500}, releaser.released)
+	assert.Equal(t, []string{risk.CaseStatusExecuting}, releaser.statusesAtSend)
+
+	c, err := store.GetLiquidationCase(ctx, riskChainID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, risk.CaseStatusExecuted, c.Status)
+	assert.Equal(t, "0xabc", c.TxHash)
+	assert.Equal(t, risk.CaseStatusPendingApproval, caseStatus(t, store, 2))
+	assert.Equal(t, risk.CaseStatusExecuting, caseStatus(t, store, 3))
+
+	// Executed cases are not sent again
+	require.NoError(t, monitor.ExecuteApprovedCases(ctx))
+	assert.Len(t, releaser.released, 1)
+}
+
+func TestMonitor_ExecuteApprovedCasesFailedSend(t *testing.T) {
+	ctx := context.Background()
+	store := risk.NewMemoryStore()
+	releaser := &fakeReleaser{store: store, err: errors.New("execution reverted")}
+	saveLiquidationCase(t, store, 1, risk.CaseStatusApproved)
+
+	require.NoError(t, newRiskMonitor(store, releaser).ExecuteApprovedCases(ctx))
+
+	c, err := store.GetLiquidationCase(ctx, riskChainID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, risk.CaseStatusFailed, c.Status)
+	assert.Equal(t, "execution reverted", c.Error)
+}
+
+func TestMonitor_ExecuteApprovedCasesSentOnceWhenOutcomeIsNotSaved(t *testing.T) {
+	ctx := context.Background()
+	store := &failingOutcomeStore{MemoryStore: risk.NewMemoryStore(), status: risk.CaseStatusExecuted}
+	releaser := &fakeReleaser{store: store}
+	saveLiquidationCase(t, store.MemoryStore, 1, risk.CaseStatusApproved)
+
+	monitor := newRiskMonitor(store, releaser)
+	require.NoError(t, monitor.ExecuteApprovedCases(ctx))
+	require.NoError(t, monitor.ExecuteApprovedCases(ctx))
+
+	assert.Len(t, releaser.released, 1)
+	assert.Equal(t, risk.CaseStatusExecuting, caseStatus(t, store, 1))
+}
+
+func TestMonitor_ExecuteApprovedCasesNotSentWhenExecutingIsNotSaved(t *testing.T) {
+	ctx := context.Background()
+	store := &failingOutcomeStore{MemoryStore: risk.NewMemoryStore(), status: risk.CaseStatusExecuting}
+	releaser := &fakeReleaser{store: store}
+	saveLiquidationCase(t, store.MemoryStore, 1, risk.CaseStatusApproved)
+
+	require.NoError(t, newRiskMonitor(store, releaser).ExecuteApprovedCases(ctx))
+
+	assert.Empty(t, releaser.released)
+	assert.Equal(t, risk.CaseStatusApproved, caseStatus(t, store, 1))
+}
@@ -0,0 +1,222 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNode serves the eth namespace methods the pool tests call, counting them
+type fakeNode struct {
+	head     uint64
+	maxRange uint64
+	chainID  atomic.Int64
+	receipts atomic.Int64
+	getLogs  atomic.Int64
+}
+
+// rangeError is how Infura rejects a getLogs range with too many results
+type rangeError struct{}
+
+func (rangeError) Error() string  { return "query returned more than 10000 results" }
+func (rangeError) ErrorCode() int { return -32005 }
+
+func (n *fakeNode) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(n.head)
+}
+
+func (n *fakeNode) ChainId() *hexutil.Big {
+	n.chainID.Add(1)
+	return (*hexutil.Big)(big.NewInt(1337))
+}
+
+func (n *fakeNode) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	n.receipts.Add(1)
+	return nil
+}
+
+func (n *fakeNode) GetLogs(args map[string]any) ([]types.Log, error) {
+	n.getLogs.Add(1)
+	from, err := hexutil.DecodeUint64(args["fromBlock"].(string))
+	if err != nil {
+		return nil, err
+	}
+	to, err := hexutil.DecodeUint64(args["toBlock"].(string))
+	if err != nil {
+		return nil, err
+	}
+	if n.maxRange > 0 && to-from+1 > n.maxRange {
+		return nil, rangeError{}
+	}
+	logs := []types.Log{}
+	for block := from; block <= to; block++ {
+		logs = append(logs, types.Log{BlockNumber: block, Topics: []common.Hash{}, Data: []byte{}})
+	}
+	return logs, nil
+}
+
+func newFakeNodeServer(t *testing.T, node *fakeNode) *httptest.Server {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+// newDownServer answers every request with 503 and counts them
+func newDownServer(t *testing.T, hits *atomic.Int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dialPool(t *testing.T, cfg evm.ClientConfig) *evm.Client {
+	if cfg.HealthCheckInterval == 0 {
+		cfg.HealthCheckInterval = time.Hour
+	}
+	client, err := evm.Dial(cfg)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClientFailsOverAndOpensBreaker(t *testing.T) {
+	var downHits atomic.Int64
+	down := newDownServer(t, &downHits)
+	up := newFakeNodeServer(t, &fakeNode{head: 42})
+	client := dialPool(t, evm.ClientConfig{
+		URLs:             []string{down.URL, up.URL},
+		FailureThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	})
+
+	for i := 0; i < 5; i++ {
+		head, err := client.BlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), head)
+	}
+	// Once the breaker is open the failing endpoint is no longer tried first
+	assert.LessOrEqual(t, downHits.Load(), int64(3))
+}
+
+func TestClientReturnsNodeErrorsWithoutFailover(t *testing.T) {
+	first, second := &fakeNode{}, &fakeNode{}
+	client := dialPool(t, evm.ClientConfig{
+		URLs: []string{newFakeNodeServer(t, first).URL, newFakeNodeServer(t, second).URL},
+	})
+
+	_, err := client.TransactionReceipt(context.Background(), common.HexToHash("0x01"))
+	assert.ErrorIs(t, err, ethereum.NotFound)
+	assert.Equal(t, int64(1), first.receipts.Load()+second.receipts.Load())
+}
+
+func TestClientSplitsLogRanges(t *testing.T) {
+	node := &fakeNode{maxRange: 10}
+	client := dialPool(t, evm.ClientConfig{URLs: []string{newFakeNodeServer(t, node).URL}})
+
+	logs, err := client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		ToBlock:   big.NewInt(100),
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 101)
+	for i, log := range logs {
+		assert.Equal(t, uint64(i), log.BlockNumber)
+	}
+	assert.Greater(t, node.getLogs.Load(), int64(10))
+}
+
+func TestClientSpillsOverRateLimitedEndpoints(t *testing.T) {
+	first, second := &fakeNode{}, &fakeNode{}
+	client := dialPool(t, evm.ClientConfig{
+		URLs:      []string{newFakeNodeServer(t, first).URL, newFakeNodeServer(t, second).URL},
+		RateLimit: 1,
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.ChainID(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(1), first.chainID.Load())
+	assert.Equal(t, int64(1), second.chainID.Load())
+}
+
+func TestClientConfigFromEnv(t *testing.T) {
+	t.Setenv("EVM_RPC_URL", "https://a.example, https://b.example,")
+	t.Setenv("EVM_RPC_RATE_LIMIT", "25")
+	cfg, err := evm.ClientConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.URLs)
+	assert.Equal(t, 25.0, cfg.RateLimit)
+	assert.Equal(t, 3, cfg.FailureThreshold)
+
+	t.Setenv("EVM_RPC_BREAKER_COOLDOWN", "soon")
+	_, err = evm.ClientConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// NewHeads serves an eth_subscribe("newHeads") subscription sending one header at the node's head
+func (n *fakeNode) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		header := &types.Header{Number: new(big.Int).SetUint64(n.head), Difficulty: big.NewInt(0)}
+		_ = notifier.Notify(sub.ID, header)
+	}()
+	return sub, nil
+}
+
+func TestClientSubscribesOverWebSocketEndpoint(t *testing.T) {
+	node := &fakeNode{head: 42}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	wsServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(wsServer.Close)
+	httpServer := newFakeNodeServer(t, node)
+
+	httpOnly, err := evm.NewClient(httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(httpOnly.Close)
+	assert.False(t, httpOnly.SupportsSubscriptions())
+
+	// The HTTP endpoint comes first and cannot serve subscriptions, the request moves on to the WebSocket one
+	client, err := evm.NewClient(httpServer.URL + ",ws" + wsServer.URL[len("http"):])
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	assert.True(t, client.SupportsSubscriptions())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	heads := make(chan *types.Header, 1)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	select {
+	case head := <-heads:
+		assert.Equal(t, uint64(42), head.Number.Uint64())
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-ctx.Done():
+		t.Fatal("no head received")
+	}
+}
@@ -0,0 +1,442 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/aqua"
+	"github.com/Pagga-Wallet/aqua402/internal/walletauth"
+	wsHub "github.com/Pagga-Wallet/aqua402/internal/websocket"
+	"github.com/Pagga-Wallet/aqua402/pkg/client"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var fastRetry = client.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// newClientServer serves the API group of e under /api/v1 and returns a client of it
+func newClientServer(t *testing.T, e *echo.Echo, opts ...client.Option) *client.Client {
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return client.New(server.URL+"/api/v1", append([]client.Option{client.WithRetry(fastRetry)}, opts...)...)
+}
+
+func TestSDKRetriesWithIdempotencyKey(t *testing.T) {
+	var calls int32
+	var keys []string
+	e := echo.New()
+	idempotent := appmiddleware.IdempotencyMiddleware(appmiddleware.NewMemoryIdempotencyStore(), time.Hour, zap.NewNop())
+	e.POST("/api/v1/rfq", func(c echo.Context) error {
+		keys = append(keys, c.Request().Header.Get(client.HeaderIdempotencyKey))
+		var req client.CreateRFQRequest
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return apperrors.Unavailable(errors.New("connection refused"), "failed to publish RFQ")
+		}
+		// Models are rendered with their Go field names
+		return c.JSON(http.StatusCreated, &repositories.RFQModel{
+			ChainID: 31337, ID: 7, BorrowerAddress: req.BorrowerAddress, Amount: req.Amount, Duration: req.Duration,
+			Status: "Open", CreatedAt: 1_700_000_000,
+		})
+	}, idempotent)
+	c := newClientServer(t, e)
+
+	rfq, err := c.CreateRFQ(context.Background(), client.CreateRFQRequest{
+		BorrowerAddress: accountAddress, Amount: "1000", Duration: 3600,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &client.RFQ{ChainID: 31337, ID: 7, BorrowerAddress: accountAddress, Amount: "1000", Duration: 3600,
+		Status: "Open", CreatedAt: 1_700_000_000}, rfq)
+
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1], "retries reuse the idempotency key")
+
+	// A key set by the caller is sent as is
+	_, err = c.CreateRFQ(client.WithIdempotencyKey(context.Background(), "rfq-1"), client.CreateRFQRequest{Amount: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "rfq-1", keys[2])
+}
+
+func TestSDKDoesNotRetryUnsafeRequests(t *testing.T) {
+	var calls int32
+	e := echo.New()
+	e.POST("/api/v1/auction/:id/finalize", func(c echo.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return apperrors.Unavailable(errors.New("connection refused"), "failed to finalize auction")
+	})
+	e.POST("/api/v1/auction/:id/bid", func(c echo.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return apperrors.Invalid("invalid request", apperrors.FieldError{Field: "rate_bps", Message: "must be at most 10000"})
+	})
+	c := newClientServer(t, e)
+
+	err := c.FinalizeAuction(context.Background(), 3)
+	assert.True(t, client.IsStatus(err, http.StatusServiceUnavailable))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "POSTs without an idempotency key are sent once")
+
+	err = c.PlaceBid(context.Background(), 3, client.BidRequest{RateBps: 20000})
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+	assert.Equal(t, []client.FieldError{{Field: "rate_bps", Message: "must be at most 10000"}}, apiErr.Errors)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "client errors are not retried")
+}
+
+func TestSDKDoesNotWaitOutLongRetryAfter(t *testing.T) {
+	var calls int32
+	e := echo.New()
+	e.GET("/api/v1/faucet/tokens", func(c echo.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return apperrors.RateLimited(time.Hour, "address cooldown")
+	})
+	c := newClientServer(t, e)
+
+	// A Retry-After beyond MaxDelay is returned to the caller instead of blocking it
+	_, err := c.FaucetTokens(context.Background())
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.Status)
+	assert.Equal(t, time.Hour, apiErr.RetryAfter)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestSDKListsPages(t *testing.T) {
+	e := echo.New()
+	e.GET("/api/v1/rfq/:id/quotes", func(c echo.Context) error {
+		assert.Equal(t, "5", c.Param("id"))
+		assert.Equal(t, "base", c.QueryParam("chain"))
+		assert.Equal(t, "-rate_bps", c.QueryParam("sort"))
+		assert.Equal(t, "2", c.QueryParam("limit"))
+		assert.Equal(t, "0", c.QueryParam("collateral_type"))
+		assert.Empty(t, c.QueryParam("status"), "zero options are left out")
+
+		c.Response().Header().Set(handlers.HeaderTotalCount, "3")
+		c.Response().Header().Set(handlers.HeaderNextCursor, "next")
+		return c.JSON(http.StatusOK, []*repositories.QuoteModel{
+			{ChainID: 8453, ID: 1, RFQID: 5, LenderAddress: accountOther, RateBps: 700, Limit: "10", Accepted: 1},
+			{ChainID: 8453, ID: 2, RFQID: 5, LenderAddress: accountOther, RateBps: 650, Limit: "20"},
+		})
+	})
+	c := newClientServer(t, e, client.WithChain("base"))
+
+	collateral := uint8(0)
+	quotes, err := c.ListQuotes(context.Background(), 5, client.ListOptions{Sort: "-rate_bps", Limit: 2, CollateralType: &collateral})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), quotes.Total)
+	assert.Equal(t, "next", quotes.NextCursor)
+	require.Len(t, quotes.Items, 2)
+	assert.Equal(t, client.Quote{ChainID: 8453, ID: 1, RFQID: 5, LenderAddress: accountOther, RateBps: 700, Limit: "10", Accepted: 1}, quotes.Items[0])
+}
+
+func TestSDKWalletAuth(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := client.NewWalletAuth(key)
+
+	e := echo.New()
+	e.POST("/api/v1/aqua/liquidity", func(c echo.Context) error {
+		var req client.ConnectLiquidityRequest
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		assert.Equal(t, "1000", req.Amount, "the body is readable after verification")
+		assert.Equal(t, wallet.Address().Hex(), c.Get("address"))
+		return c.JSON(http.StatusOK, map[string]string{"status": "success"})
+	}, appmiddleware.AuthMiddleware())
+	c := newClientServer(t, e, client.WithAuth(wallet))
+
+	require.NoError(t, c.ConnectLiquidity(context.Background(), client.ConnectLiquidityRequest{
+		LenderAddress: wallet.Address().Hex(), Amount: "1000",
+	}))
+
+	// Unsigned requests are rejected
+	err = newClientServer(t, e).ConnectLiquidity(context.Background(), client.ConnectLiquidityRequest{Amount: "1000"})
+	assert.True(t, client.IsStatus(err, http.StatusUnauthorized))
+
+	// A signature covers the method, URI, timestamp and body
+	now := time.Now()
+	signed := httptest.NewRequest(http.MethodPost, "/api/v1/aqua/liquidity?chain=1", nil)
+	require.NoError(t, wallet.Authenticate(signed, []byte(`{"amount":"1000"}`), now))
+	address, err := walletauth.Verify(signed, []byte(`{"amount":"1000"}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, wallet.Address(), address)
+
+	_, err = walletauth.Verify(signed, []byte(`{"amount":"9000"}`), now)
+	assert.ErrorIs(t, err, walletauth.ErrInvalidSignature)
+	_, err = walletauth.Verify(signed, []byte(`{"amount":"1000"}`), now.Add(client.MaxSignatureAge+time.Minute))
+	assert.ErrorIs(t, err, walletauth.ErrStaleSignature)
+	signed.Header.Set(client.HeaderAddress, accountOther)
+	_, err = walletauth.Verify(signed, []byte(`{"amount":"1000"}`), now)
+	assert.ErrorIs(t, err, walletauth.ErrInvalidSignature)
+}
+
+func TestSDKWalletAuthMustSignForTheAddress(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// The handler refuses a request signed by one wallet acting for another before it reaches the service,
+	// also on the routes that still accept unsigned requests
+	for name, auth := range map[string]echo.MiddlewareFunc{
+		"required": appmiddleware.AuthMiddleware(),
+		"optional": appmiddleware.OptionalAuthMiddleware(),
+	} {
+		e := echo.New()
+		aquaHandler := handlers.NewAquaHandler(aqua.NewService(nil, zap.NewNop()), zap.NewNop())
+		e.POST("/api/v1/aqua/liquidity", aquaHandler.ConnectLiquidity, auth)
+		c := newClientServer(t, e, client.WithAuth(client.NewWalletAuth(key)))
+
+		err = c.ConnectLiquidity(context.Background(), client.ConnectLiquidityRequest{
+			LenderAddress: accountOther, Amount: "1000", TokenAddress: accountAddress,
+		})
+		assert.True(t, client.IsStatus(err, http.StatusForbidden), name)
+	}
+}
+
+func TestSDKOptionalWalletAuth(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := client.NewWalletAuth(key)
+
+	var address interface{}
+	var unsigned bool
+	e := echo.New()
+	e.POST("/api/v1/aqua/liquidity", func(c echo.Context) error {
+		address = c.Get("address")
+		unsigned, _ = c.Get("unsigned").(bool)
+		return c.JSON(http.StatusOK, map[string]string{"status": "success"})
+	}, appmiddleware.OptionalAuthMiddleware())
+	req := client.ConnectLiquidityRequest{LenderAddress: wallet.Address().Hex(), Amount: "1000"}
+
+	// Unsigned requests are let through for now
+	require.NoError(t, newClientServer(t, e).ConnectLiquidity(context.Background(), req))
+	assert.True(t, unsigned)
+	assert.Nil(t, address)
+
+	// Signed ones are checked as on the routes requiring a signature
+	require.NoError(t, newClientServer(t, e, client.WithAuth(wallet)).ConnectLiquidity(context.Background(), req))
+	assert.False(t, unsigned)
+	assert.Equal(t, wallet.Address().Hex(), address)
+
+	body := []byte(`{"lender_address":"` + wallet.Address().Hex() + `","amount":"1000"}`)
+	expired := httptest.NewRequest(http.MethodPost, "/api/v1/aqua/liquidity", strings.NewReader(string(body)))
+	require.NoError(t, wallet.Authenticate(expired, body, time.Now().Add(-client.MaxSignatureAge-time.Minute)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, expired)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestSDKSignTypedData(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	data := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}, {Name: "chainId", Type: "uint256"}},
+			"Quote":        {{Name: "rfqId", Type: "uint256"}, {Name: "rateBps", Type: "uint16"}},
+		},
+		PrimaryType: "Quote",
+		Domain:      apitypes.TypedDataDomain{Name: "aqua402", ChainId: math.NewHexOrDecimal256(31337)},
+		Message:     apitypes.TypedDataMessage{"rfqId": big.NewInt(1).String(), "rateBps": "650"},
+	}
+	signature, err := client.SignTypedData(key, data)
+	require.NoError(t, err)
+	signer, err := client.RecoverTypedData(data, signature)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+
+	signature, err = client.SignMessage(key, []byte("hello"))
+	require.NoError(t, err)
+	signer, err = client.RecoverMessage([]byte("hello"), signature)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+	signer, err = client.RecoverMessage([]byte("hell0"), signature)
+	require.NoError(t, err)
+	assert.NotEqual(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+}
+
+func TestSDKSubscribe(t *testing.T) {
+	hub := wsHub.NewHub()
+	go hub.Run()
+	ws := handlers.NewWebSocketHandler(hub)
+
+	e := echo.New()
+	e.GET("/api/v1/ws/rfq/:id", ws.HandleRFQWebSocket)
+	c := newClientServer(t, e)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, err := c.SubscribeRFQ(ctx, 1)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		clients, _ := hub.Stats()
+		return clients == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// On-chain events carry IDs as strings
+	hub.Broadcast(map[string]interface{}{"type": client.EventQuoteSubmitted, "rfq_id": "2", "lender": accountOther})
+	hub.Broadcast(map[string]interface{}{"type": client.EventQuoteSubmitted, "rfq_id": "1", "lender": accountOther,
+		"rate_bps": 650, "limit": "1000", "tx_hash": "0xabc", "block_number": 12})
+
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, client.EventQuoteSubmitted, event.Type)
+		assert.Equal(t, uint64(1), event.RFQID, "events of other RFQs are filtered out")
+		assert.Equal(t, accountOther, event.Lender)
+		assert.Equal(t, uint64(650), event.RateBps)
+		assert.Equal(t, uint64(12), event.BlockNumber)
+
+		var raw struct {
+			TxHash string `json:"tx_hash"`
+		}
+		require.NoError(t, event.Decode(&raw))
+		assert.Equal(t, "0xabc", raw.TxHash)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	cancel()
+	for range sub.Events() {
+	}
+	assert.ErrorIs(t, sub.Err(), context.Canceled)
+}
+
+// swaggerSpec is the part of docs/swagger.json the client is checked against
+type swaggerSpec struct {
+	Paths map[string]map[string]struct {
+		Tags       []string `json:"tags"`
+		Parameters []struct {
+			Name string `json:"name"`
+			In   string `json:"in"`
+		} `json:"parameters"`
+	} `json:"paths"`
+	Definitions map[string]struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	} `json:"definitions"`
+}
+
+func TestSDKMatchesOpenAPISpec(t *testing.T) {
+	data, err := os.ReadFile("../docs/swagger.json")
+	require.NoError(t, err)
+	var spec swaggerSpec
+	require.NoError(t, json.Unmarshal(data, &spec))
+
+	// Every operation of the tags the client covers is a client route, with the same idempotency
+	covered := map[string]bool{"RFQ": true, "Auction": true, "Aqua": true, "Faucet": true, "Credit": true}
+	routes := make(map[string]client.Route)
+	for _, route := range client.Routes {
+		routes[route.Method+" "+route.Path] = route
+	}
+	var missing []string
+	for path, operations := range spec.Paths {
+		for method, op := range operations {
+			name := strings.ToUpper(method) + " " + path
+			route, ok := routes[name]
+			if !ok {
+				if len(op.Tags) > 0 && covered[op.Tags[0]] {
+					missing = append(missing, name)
+				}
+				continue
+			}
+			delete(routes, name)
+
+			idempotent := false
+			for _, param := range op.Parameters {
+				if param.In == "header" && param.Name == client.HeaderIdempotencyKey {
+					idempotent = true
+				}
+			}
+			assert.Equal(t, idempotent, route.Idempotent, "idempotency of %s", name)
+
+			signed := false
+			for _, param := range op.Parameters {
+				if param.In == "header" && param.Name == client.HeaderSignature {
+					signed = true
+				}
+			}
+			assert.Equal(t, signed, route.Signed, "wallet signature of %s", name)
+		}
+	}
+	sort.Strings(missing)
+	assert.Empty(t, missing, "operations missing from the client")
+	assert.Empty(t, routes, "client routes missing from the spec")
+
+	// Models have the properties of their definitions
+	const prefix = "github_com_Pagga-Wallet_aqua402_internal_"
+	models := map[string]interface{}{
+		"repositories.RFQModel":                  client.RFQ{},
+		"repositories.QuoteModel":                client.Quote{},
+		"repositories.AuctionModel":              client.Auction{},
+		"repositories.BidModel":                  client.Bid{},
+		"services_rfq.CreateRFQRequest":          client.CreateRFQRequest{},
+		"services_rfq.QuoteRequest":              client.QuoteRequest{},
+		"services_auction.CreateAuctionRequest":  client.CreateAuctionRequest{},
+		"services_auction.BidRequest":            client.BidRequest{},
+		"services_aqua.ConnectLiquidityRequest":  client.ConnectLiquidityRequest{},
+		"services_aqua.WithdrawLiquidityRequest": client.WithdrawLiquidityRequest{},
+		"services_faucet.RequestTokensRequest":   client.RequestTokensRequest{},
+		"services_faucet.TokenInfo":              client.TokenInfo{},
+		"services_credit.Installment":            client.Installment{},
+		"services_credit.Position":               client.Position{},
+		"services_credit.Obligation":             client.Obligation{},
+		"services_credit.Obligations":            client.Obligations{},
+		"services_credit.Schedule":               client.Schedule{},
+		"handlers.Problem":                       client.Error{},
+		"apperrors.FieldError":                   client.FieldError{},
+	}
+	for name, model := range models {
+		definition, ok := spec.Definitions[prefix+name]
+		if !ok {
+			// Types of package main's imports are named after their package path
+			definition, ok = spec.Definitions["internal_"+name]
+		}
+		require.True(t, ok, "definition %s", name)
+		want := make([]string, 0, len(definition.Properties))
+		for property := range definition.Properties {
+			want = append(want, property)
+		}
+		sort.Strings(want)
+		assert.Equal(t, want, jsonFields(reflect.TypeOf(model)), "properties of %s", name)
+	}
+}
+
+// jsonFields lists the JSON names of the fields of a struct, including embedded ones
+func jsonFields(typ reflect.Type) []string {
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous {
+			names = append(names, jsonFields(field.Type)...)
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "-" && name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
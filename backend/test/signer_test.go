@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const devAddress = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+func TestParseKeySignerAcceptsBothPrefixes(t *testing.T) {
+	withPrefix, err := evm.ParseKeySigner(evm.DevPrivateKey)
+	require.NoError(t, err)
+	withoutPrefix, err := evm.ParseKeySigner(evm.DevPrivateKey[2:])
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(devAddress), withPrefix.Address())
+	assert.Equal(t, withPrefix.Address(), withoutPrefix.Address())
+
+	_, err = evm.ParseKeySigner("0x1234")
+	assert.Error(t, err)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	key := &keystore.Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	encrypted, err := keystore.EncryptKey(key, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(path, encrypted, 0o600))
+
+	signer, err := evm.NewKeystoreSigner(path, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, key.Address, signer.Address())
+
+	_, err = evm.NewKeystoreSigner(path, "wrong")
+	assert.Error(t, err)
+}
+
+func TestSignerConfigFromEnv(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(evm.DevPrivateKey+"\n"), 0o600))
+	t.Setenv("TEST_PRIVATE_KEY_FILE", keyFile)
+
+	cfg, err := evm.SignerConfigFromEnv("TEST")
+	require.NoError(t, err)
+	assert.Equal(t, evm.SignerKey, cfg.Kind)
+	assert.Equal(t, evm.DevPrivateKey, cfg.PrivateKey)
+	assert.False(t, cfg.Production)
+
+	t.Setenv("TEST_SIGNER_URL", "http://signer:9000")
+	cfg, err = evm.SignerConfigFromEnv("TEST")
+	require.NoError(t, err)
+	assert.Equal(t, evm.SignerRemote, cfg.Kind)
+
+	t.Setenv("TEST_SIGNER", "hsm")
+	_, err = evm.SignerConfigFromEnv("TEST")
+	assert.Error(t, err)
+
+	cfg, err = evm.SignerConfigFromEnv("UNSET")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Kind)
+	_, err = evm.NewSigner(context.Background(), cfg)
+	assert.ErrorIs(t, err, evm.ErrSignerNotConfigured)
+}
+
+func TestNewSignerRefusesDevKeyInProduction(t *testing.T) {
+	t.Setenv("TEST_PRIVATE_KEY", evm.DevPrivateKey)
+
+	cfg, err := evm.SignerConfigFromEnv("TEST")
+	require.NoError(t, err)
+	_, err = evm.NewSigner(context.Background(), cfg)
+	assert.NoError(t, err)
+
+	t.Setenv("APP_ENV", "production")
+	cfg, err = evm.SignerConfigFromEnv("TEST")
+	require.NoError(t, err)
+	assert.True(t, cfg.Production)
+	_, err = evm.NewSigner(context.Background(), cfg)
+	assert.ErrorContains(t, err, "development account")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	t.Setenv("TEST_PRIVATE_KEY", common.Bytes2Hex(crypto.FromECDSA(key)))
+	cfg, err = evm.SignerConfigFromEnv("TEST")
+	require.NoError(t, err)
+	_, err = evm.NewSigner(context.Background(), cfg)
+	assert.NoError(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	local := evm.NewKeySigner(key)
+	server := httptest.NewServer(evm.NewSignerServer(local))
+	defer server.Close()
+	ctx := context.Background()
+	chainID := big.NewInt(1337)
+
+	// Without an address the signer's first account is used
+	remote, err := evm.NewRemoteSigner(ctx, server.URL, common.Address{}, "", server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, local.Address(), remote.Address())
+
+	for _, fees := range []*evm.Fees{
+		{GasTipCap: big.NewInt(1_000_000_000), GasFeeCap: big.NewInt(3_000_000_000)},
+		{GasPrice: big.NewInt(2_000_000_000)},
+	} {
+		tx := evm.NewTx(chainID, 3, txRecipient, big.NewInt(5), 21000, fees, []byte{0x01})
+		signed, err := remote.SignTx(ctx, tx, chainID)
+		require.NoError(t, err)
+		from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, err)
+		assert.Equal(t, local.Address(), from)
+		assert.Equal(t, tx.Nonce(), signed.Nonce())
+		assert.Equal(t, tx.Type(), signed.Type())
+	}
+
+	// The signer does not hold keys for other accounts
+	other, err := evm.NewRemoteSigner(ctx, server.URL, txRecipient, "", server.Client())
+	require.NoError(t, err)
+	_, err = other.SignTx(ctx, evm.NewTx(chainID, 0, txRecipient, big.NewInt(0), 21000, &evm.Fees{GasPrice: big.NewInt(1)}, nil), chainID)
+	assert.ErrorContains(t, err, "unknown account")
+}
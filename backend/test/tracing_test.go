@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/internal/tracing"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+var (
+	spanRecorderOnce sync.Once
+	spanRecorder     *tracetest.SpanRecorder
+)
+
+// recordSpans installs a tracer provider recording every span. The global provider can only be
+// delegated to once, so all tests share the recorder and look for their own spans.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	spanRecorderOnce.Do(func() {
+		_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone}, "test")
+		require.NoError(t, err)
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+	return spanRecorder
+}
+
+func findSpan(recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestTracingMiddlewareContinuesIncomingTraces(t *testing.T) {
+	recorder := recordSpans(t)
+
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	e.Use(appmiddleware.TracingMiddleware())
+	var handlerTraceID string
+	e.GET("/traced/:id", func(c echo.Context) error {
+		handlerTraceID = tracing.TraceID(c.Request().Context())
+		return errors.New("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/traced/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", rec.Header().Get(appmiddleware.HeaderTraceID))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", handlerTraceID)
+
+	span := findSpan(recorder, "GET /traced/:id")
+	require.NotNil(t, span)
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
+
+func TestTracingConfigFromEnv(t *testing.T) {
+	for value, exporter := range map[string]tracing.Exporter{
+		"":        tracing.ExporterNone,
+		"none":    tracing.ExporterNone,
+		"otlp":    tracing.ExporterOTLP,
+		"stdout":  tracing.ExporterStdout,
+		"console": tracing.ExporterStdout,
+	} {
+		t.Setenv("OTEL_TRACES_EXPORTER", value)
+		cfg, err := tracing.ConfigFromEnv()
+		require.NoError(t, err, value)
+		assert.Equal(t, exporter, cfg.Exporter, value)
+	}
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "jaeger")
+	_, err := tracing.ConfigFromEnv()
+	assert.Error(t, err)
+}
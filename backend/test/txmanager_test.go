@@ -0,0 +1,398 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/evm"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeChain is a TxBackend whose mempool is a list of sent transactions, mined on demand
+type fakeChain struct {
+	mu       sync.Mutex
+	mined    uint64
+	pending  uint64
+	tipCap   *big.Int
+	feeCap   *big.Int
+	sent     []*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	sendErr  error
+	// receiptGate, when set, holds receipt calls until it is closed, each one is announced on receiptCalls
+	receiptGate  chan struct{}
+	receiptCalls chan struct{}
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		tipCap:   big.NewInt(1_000_000_000),
+		feeCap:   big.NewInt(3_000_000_000),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (f *fakeChain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pending, nil
+}
+
+func (f *fakeChain) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mined, nil
+}
+
+func (f *fakeChain) SuggestFees(ctx context.Context, urgency evm.Urgency) (*evm.Fees, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &evm.Fees{GasTipCap: new(big.Int).Set(f.tipCap), GasFeeCap: new(big.Int).Set(f.feeCap)}, nil
+}
+
+func (f *fakeChain) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+func (f *fakeChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sendErr != nil {
+		err := f.sendErr
+		f.sendErr = nil
+		return err
+	}
+	if tx.Nonce() < f.mined {
+		return errors.New("nonce too low")
+	}
+	f.sent = append(f.sent, tx)
+	if tx.Nonce() >= f.pending {
+		f.pending = tx.Nonce() + 1
+	}
+	return nil
+}
+
+func (f *fakeChain) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if f.receiptGate != nil {
+		f.receiptCalls <- struct{}{}
+		<-f.receiptGate
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if receipt, ok := f.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// mine includes tx and advances the mined nonce past it
+func (f *fakeChain) mine(tx *types.Transaction, status uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.receipts[tx.Hash()] = &types.Receipt{Status: status, TxHash: tx.Hash(), BlockNumber: big.NewInt(int64(len(f.receipts) + 1))}
+	if tx.Nonce() >= f.mined {
+		f.mined = tx.Nonce() + 1
+	}
+}
+
+func (f *fakeChain) sentTxs() []*types.Transaction {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*types.Transaction(nil), f.sent...)
+}
+
+func newTestTxManager(t *testing.T, chain *fakeChain, store evm.TxStore, stuckAfter time.Duration) *evm.TxManager {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return evm.NewTxManager(chain, evm.NewKeySigner(key), store, evm.TxManagerConfig{
+		ChainID:    big.NewInt(1337),
+		StuckAfter: stuckAfter,
+	}, zap.NewNop())
+}
+
+var txRecipient = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+func TestTxManagerAllocatesNonces(t *testing.T) {
+	chain := newFakeChain()
+	chain.pending, chain.mined = 5, 5
+	txm := newTestTxManager(t, chain, evm.NewMemoryTxStore(), time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := txm.Send(context.Background(), evm.TxRequest{To: txRecipient, Value: big.NewInt(1)})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	nonces := make(map[uint64]bool)
+	for _, tx := range chain.sentTxs() {
+		nonces[tx.Nonce()] = true
+	}
+	assert.Len(t, nonces, 10)
+	for n := uint64(5); n < 15; n++ {
+		assert.True(t, nonces[n], "nonce %d", n)
+	}
+}
+
+func TestTxManagerConfirmsAndReverts(t *testing.T) {
+	chain := newFakeChain()
+	store := evm.NewMemoryTxStore()
+	txm := newTestTxManager(t, chain, store, time.Hour)
+	ctx := context.Background()
+
+	ok, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxPending, ok.Status)
+	failed, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+
+	sent := chain.sentTxs()
+	chain.mine(sent[0], types.ReceiptStatusSuccessful)
+	chain.mine(sent[1], types.ReceiptStatusFailed)
+	require.NoError(t, txm.Poll(ctx))
+
+	status, err := txm.Status(ctx, ok.ID)
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxConfirmed, status.Status)
+	assert.NotZero(t, status.BlockNumber)
+	status, err = txm.Status(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxReverted, status.Status)
+
+	_, err = txm.Status(ctx, "missing")
+	assert.ErrorIs(t, err, evm.ErrTxNotFound)
+}
+
+func TestTxManagerBumpsStuckTransactions(t *testing.T) {
+	chain := newFakeChain()
+	txm := newTestTxManager(t, chain, evm.NewMemoryTxStore(), time.Nanosecond)
+	ctx := context.Background()
+
+	record, err := txm.Send(ctx, evm.TxRequest{To: txRecipient, Value: big.NewInt(7)})
+	require.NoError(t, err)
+	require.NoError(t, txm.Poll(ctx))
+
+	sent := chain.sentTxs()
+	require.Len(t, sent, 2)
+	assert.Equal(t, sent[0].Nonce(), sent[1].Nonce())
+	assert.Equal(t, uint8(types.DynamicFeeTxType), sent[1].Type())
+	assert.Equal(t, big.NewInt(1_100_000_000), sent[1].GasTipCap())
+	assert.Equal(t, big.NewInt(3_300_000_000), sent[1].GasFeeCap())
+
+	// The original is mined after all, the record follows it
+	chain.mine(sent[0], types.ReceiptStatusSuccessful)
+	require.NoError(t, txm.Poll(ctx))
+	status, err := txm.Status(ctx, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxConfirmed, status.Status)
+	assert.Equal(t, sent[0].Hash(), status.Hash)
+	assert.Len(t, status.Hashes, 2)
+	assert.Equal(t, uint32(2), status.Attempts)
+}
+
+func TestTxManagerBumpsToSuggestedFeesUpToMax(t *testing.T) {
+	chain := newFakeChain()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	txm := evm.NewTxManager(chain, evm.NewKeySigner(key), evm.NewMemoryTxStore(), evm.TxManagerConfig{
+		ChainID:     big.NewInt(1337),
+		StuckAfter:  time.Nanosecond,
+		MaxGasPrice: big.NewInt(5_000_000_000),
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	_, err = txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+
+	// Fees spike past the max: the fee cap stops at it and the tip follows the market
+	chain.mu.Lock()
+	chain.tipCap, chain.feeCap = big.NewInt(2_000_000_000), big.NewInt(9_000_000_000)
+	chain.mu.Unlock()
+	require.NoError(t, txm.Poll(ctx))
+
+	sent := chain.sentTxs()
+	require.Len(t, sent, 2)
+	assert.Equal(t, big.NewInt(2_000_000_000), sent[1].GasTipCap())
+	assert.Equal(t, big.NewInt(5_000_000_000), sent[1].GasFeeCap())
+
+	// At the max the same transaction is rebroadcast
+	require.NoError(t, txm.Poll(ctx))
+	sent = chain.sentTxs()
+	require.Len(t, sent, 3)
+	assert.Equal(t, sent[1].Hash(), sent[2].Hash())
+}
+
+func TestTxManagerFillsNonceGaps(t *testing.T) {
+	chain := newFakeChain()
+	store := evm.NewMemoryTxStore()
+	txm := newTestTxManager(t, chain, store, time.Hour)
+	ctx := context.Background()
+
+	first, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	_, err = txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+
+	// The first transaction disappears from the store, e.g. it was sent before a restart without ClickHouse
+	lost, err := store.GetTx(ctx, first.ID)
+	require.NoError(t, err)
+	lost.Status = evm.TxFailed
+	require.NoError(t, store.SaveTx(ctx, lost))
+
+	require.NoError(t, txm.Poll(ctx))
+	sent := chain.sentTxs()
+	require.Len(t, sent, 3)
+	filler := sent[2]
+	assert.Equal(t, uint64(0), filler.Nonce())
+	assert.Equal(t, txm.From(), *filler.To())
+	assert.Zero(t, filler.Value().Sign())
+}
+
+func TestTxManagerResyncsAndDrops(t *testing.T) {
+	chain := newFakeChain()
+	txm := newTestTxManager(t, chain, evm.NewMemoryTxStore(), time.Hour)
+	ctx := context.Background()
+
+	record, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), record.Nonce)
+
+	// Someone else spends nonces 0 to 2 with the same key
+	chain.mu.Lock()
+	chain.mined, chain.pending = 3, 3
+	chain.mu.Unlock()
+
+	next, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), next.Nonce)
+	// The resync keeps the ID the caller follows
+	status, err := txm.Status(ctx, next.ID)
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxPending, status.Status)
+
+	require.NoError(t, txm.Poll(ctx))
+	status, err = txm.Status(ctx, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxDropped, status.Status)
+
+	// A rejected transaction keeps the nonce for the next one
+	chain.sendErr = errors.New("insufficient funds for gas * price + value")
+	_, err = txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.Error(t, err)
+	retry, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), retry.Nonce)
+}
+
+func TestTxManagerKeepsUncertainBroadcasts(t *testing.T) {
+	chain := newFakeChain()
+	txm := newTestTxManager(t, chain, evm.NewMemoryTxStore(), time.Hour)
+	ctx := context.Background()
+
+	// A timeout does not tell whether the node got the transaction, it keeps its nonce
+	chain.sendErr = errors.New("i/o timeout")
+	record, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxPending, record.Status)
+	assert.NotEqual(t, common.Hash{}, record.Hash)
+	assert.Empty(t, chain.sentTxs())
+
+	next, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), next.Nonce)
+
+	// The next poll sends it again, then settles it by its receipt
+	require.NoError(t, txm.Poll(ctx))
+	sent := chain.sentTxs()
+	require.Len(t, sent, 2)
+	assert.Equal(t, uint64(0), sent[1].Nonce())
+
+	chain.mine(sent[1], types.ReceiptStatusSuccessful)
+	require.NoError(t, txm.Poll(ctx))
+	status, err := txm.Status(ctx, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxConfirmed, status.Status)
+	assert.Equal(t, sent[1].Hash(), status.Hash)
+}
+
+func TestTxManagerSendsSubmittedTransactions(t *testing.T) {
+	chain := newFakeChain()
+	chain.pending, chain.mined = 2, 2
+	store := evm.NewMemoryTxStore()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	config := evm.TxManagerConfig{ChainID: big.NewInt(1337), StuckAfter: time.Hour}
+	// An API replica submits, the worker runs the manager of the same key over the same store
+	submitter := evm.NewTxManager(chain, evm.NewKeySigner(key), store, config, zap.NewNop())
+	txm := evm.NewTxManager(chain, evm.NewKeySigner(key), store, config, zap.NewNop())
+	ctx := context.Background()
+
+	first, err := submitter.Submit(ctx, evm.TxRequest{To: txRecipient, Value: big.NewInt(1)})
+	require.NoError(t, err)
+	assert.Equal(t, evm.TxRequested, first.Status)
+	second, err := submitter.Submit(ctx, evm.TxRequest{To: txRecipient, Value: big.NewInt(2)})
+	require.NoError(t, err)
+	assert.Empty(t, chain.sentTxs())
+
+	sent, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), sent.Nonce)
+
+	require.NoError(t, txm.Poll(ctx))
+	require.Len(t, chain.sentTxs(), 3)
+	for i, id := range []string{first.ID, second.ID} {
+		status, err := submitter.Status(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, evm.TxPending, status.Status)
+		assert.Equal(t, uint64(3+i), status.Nonce)
+		assert.NotEqual(t, common.Hash{}, status.Hash)
+	}
+
+	// Sent transactions are not sent again
+	require.NoError(t, txm.Poll(ctx))
+	assert.Len(t, chain.sentTxs(), 3)
+}
+
+func TestTxManagerSendsWhilePolling(t *testing.T) {
+	chain := newFakeChain()
+	txm := newTestTxManager(t, chain, evm.NewMemoryTxStore(), time.Hour)
+	ctx := context.Background()
+
+	_, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+	require.NoError(t, err)
+
+	// Hold the poll in its receipt call
+	chain.receiptGate, chain.receiptCalls = make(chan struct{}), make(chan struct{}, 1)
+	polled := make(chan error, 1)
+	go func() { polled <- txm.Poll(ctx) }()
+	<-chain.receiptCalls
+
+	sent := make(chan error, 1)
+	go func() {
+		_, err := txm.Send(ctx, evm.TxRequest{To: txRecipient})
+		sent <- err
+	}()
+	select {
+	case err := <-sent:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Send waited for the poll's RPC calls")
+	}
+
+	close(chain.receiptGate)
+	require.NoError(t, <-polled)
+	assert.Len(t, chain.sentTxs(), 2)
+}
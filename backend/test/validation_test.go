@@ -0,0 +1,178 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/internal/services/rfq"
+	"github.com/Pagga-Wallet/aqua402/internal/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseAddress(t *testing.T) {
+	checksummed := "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+
+	for _, s := range []string{checksummed, strings.ToLower(checksummed), "0x" + strings.ToUpper(checksummed[2:])} {
+		address, err := validation.ParseAddress(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, checksummed, address.Hex())
+	}
+
+	for _, s := range []string{
+		"0x5fbDB2315678afecb367f032d93F642f64180aa3", // Bad checksum
+		"5FbDB2315678afecb367f032d93F642f64180aa3",
+		"0x5FbDB2315678afecb367f032d93F642f64180aa",
+		"0xZZbDB2315678afecb367f032d93F642f64180aa3",
+		"0x0000000000000000000000000000000000000000",
+		"garbage",
+	} {
+		_, err := validation.ParseAddress(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for _, tc := range []struct {
+		s        string
+		decimals uint8
+		want     string
+	}{
+		{"1000", 0, "1000"},
+		{"1.5", 6, "1500000"},
+		{"1.0", 18, "1000000000000000000"},
+		{"0.000001", 6, "1"},
+		{"2.50", 1, "25"},
+		{"0", 18, "0"},
+	} {
+		v, err := validation.ParseAmount(tc.s, tc.decimals)
+		require.NoError(t, err, tc.s)
+		assert.Equal(t, tc.want, v.String(), tc.s)
+	}
+
+	for _, tc := range []struct {
+		s        string
+		decimals uint8
+	}{
+		{"", 18},
+		{"-1", 18},
+		{"1e18", 18},
+		{"1.5", 0},
+		{"0.0000001", 6},
+		{".5", 6},
+		{"1.", 6},
+		{"1,5", 6},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639936", 0}, // 2^256
+	} {
+		_, err := validation.ParseAmount(tc.s, tc.decimals)
+		assert.Error(t, err, tc.s)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	req := rfq.CreateRFQRequest{
+		BorrowerAddress: "0x5fbdb2315678afecb367f032d93f642f64180aa3",
+		Amount:          "1000000000000000000000",
+		Duration:        30 * 24 * 3600,
+	}
+	require.NoError(t, req.Validate())
+	assert.Equal(t, "0x5FbDB2315678afecb367f032d93F642f64180aa3", req.BorrowerAddress)
+
+	bad := rfq.CreateRFQRequest{BorrowerAddress: "0x5fbDB2315678afecb367f032d93F642f64180aa3", Amount: "1.5", Duration: 60}
+	err := bad.Validate()
+	require.Error(t, err)
+	assert.Equal(t, apperrors.KindInvalid, apperrors.KindOf(err))
+
+	var fields []string
+	for _, f := range apperrors.FieldsOf(err) {
+		fields = append(fields, f.Field)
+	}
+	assert.Equal(t, []string{"borrower_address", "amount", "duration"}, fields)
+
+	quote := rfq.QuoteRequest{LenderAddress: "0x5FbDB2315678afecb367f032d93F642f64180aa3", RateBps: 10_001, Limit: "1"}
+	err = quote.Validate()
+	require.Len(t, apperrors.FieldsOf(err), 1)
+	assert.Equal(t, "rate_bps", apperrors.FieldsOf(err)[0].Field)
+}
+
+func newProblemServer() *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	e.POST("/rfq", func(c echo.Context) error {
+		var req rfq.CreateRFQRequest
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		return req.Validate()
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return apperrors.NotFound("RFQ %d not found", 7)
+	})
+	e.GET("/conflict", func(c echo.Context) error {
+		return apperrors.Conflict("liquidation case is not pending approval")
+	})
+	e.GET("/unavailable", func(c echo.Context) error {
+		return apperrors.Unavailable(errors.New("dial tcp 10.0.0.1:5672: connection refused"), "failed to publish quote")
+	})
+	e.GET("/internal", func(c echo.Context) error {
+		return errors.New("clickhouse: code 47: missing columns")
+	})
+	e.GET("/operator", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, appmiddleware.OperatorMiddleware("secret"))
+	return e
+}
+
+func problemOf(t *testing.T, e *echo.Echo, method, path, body string) (*handlers.Problem, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, handlers.MIMEProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	p := new(handlers.Problem)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), p))
+	assert.Equal(t, rec.Code, p.Status)
+	return p, rec
+}
+
+func TestProblemResponses(t *testing.T) {
+	e := newProblemServer()
+
+	p, _ := problemOf(t, e, http.MethodPost, "/rfq", `{"borrower_address":"nope","amount":"-1","duration":3600}`)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, "/rfq", p.Instance)
+	require.Len(t, p.Errors, 2)
+	assert.Equal(t, "borrower_address", p.Errors[0].Field)
+	assert.Equal(t, "amount", p.Errors[1].Field)
+
+	p, _ = problemOf(t, e, http.MethodGet, "/missing", "")
+	assert.Equal(t, http.StatusNotFound, p.Status)
+	assert.Equal(t, "RFQ 7 not found", p.Detail)
+
+	p, _ = problemOf(t, e, http.MethodGet, "/conflict", "")
+	assert.Equal(t, http.StatusConflict, p.Status)
+
+	p, _ = problemOf(t, e, http.MethodGet, "/unavailable", "")
+	assert.Equal(t, http.StatusServiceUnavailable, p.Status)
+	assert.Equal(t, "failed to publish quote", p.Detail)
+
+	p, _ = problemOf(t, e, http.MethodGet, "/internal", "")
+	assert.Equal(t, http.StatusInternalServerError, p.Status)
+	assert.Empty(t, p.Detail, "internal errors must not leak")
+
+	p, _ = problemOf(t, e, http.MethodGet, "/no-such-route", "")
+	assert.Equal(t, http.StatusNotFound, p.Status)
+
+	p, _ = problemOf(t, e, http.MethodGet, "/operator", "")
+	assert.Equal(t, http.StatusUnauthorized, p.Status)
+}
@@ -0,0 +1,494 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	appmiddleware "github.com/Pagga-Wallet/aqua402/internal/middleware"
+	"github.com/Pagga-Wallet/aqua402/internal/repositories"
+	"github.com/Pagga-Wallet/aqua402/internal/services/webhooks"
+	"github.com/Pagga-Wallet/aqua402/pkg/client"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookBorrower = "0x1111111111111111111111111111111111111111"
+	webhookLender   = "0x2222222222222222222222222222222222222222"
+)
+
+// webhookSink is a local subscriber recording the deliveries it receives and answering with status
+type webhookSink struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookSink(t *testing.T, status int) (*webhookSink, *httptest.Server) {
+	sink := &webhookSink{status: status}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sink.mu.Lock()
+		sink.requests = append(sink.requests, r)
+		sink.bodies = append(sink.bodies, body)
+		status := sink.status
+		sink.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return sink, server
+}
+
+func (s *webhookSink) received() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+func (s *webhookSink) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// webhookLookup resolves auction 7 to webhookBorrower
+type webhookLookup struct{}
+
+func (webhookLookup) RFQParties(ctx context.Context, chainID, id uint64) ([]string, error) {
+	return nil, nil
+}
+
+func (webhookLookup) AuctionParties(ctx context.Context, chainID, id uint64) ([]string, error) {
+	if id == 7 {
+		return []string{"0x1111111111111111111111111111111111111111"}, nil
+	}
+	return nil, nil
+}
+
+func (webhookLookup) CreditLineParties(ctx context.Context, chainID, id uint64) ([]string, error) {
+	return nil, nil
+}
+
+func webhookConfig() webhooks.Config {
+	return webhooks.Config{
+		MaxAttempts:      3,
+		RetryBase:        time.Second,
+		RetryMax:         time.Minute,
+		Timeout:          2 * time.Second,
+		PollInterval:     10 * time.Millisecond,
+		Concurrency:      2,
+		AllowPrivateURLs: true,
+	}
+}
+
+func bidPlaced(txHash string) []byte {
+	body, _ := json.Marshal(map[string]interface{}{
+		"type":       "bid_placed",
+		"auction_id": "7",
+		"lender":     "0x2222222222222222222222222222222222222222",
+		"amount":     "1000",
+		"tx_hash":    txHash,
+		"log_index":  2,
+		"chain_id":   31337,
+	})
+	return body
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	now := time.Unix(1735689600, 0)
+	header := webhooks.Sign("whsec_test", now, body)
+
+	assert.NoError(t, webhooks.Verify("whsec_test", header, body, 5*time.Minute, now.Add(time.Minute)))
+	assert.ErrorIs(t, webhooks.Verify("whsec_other", header, body, 5*time.Minute, now), webhooks.ErrInvalidSignature)
+	assert.ErrorIs(t, webhooks.Verify("whsec_test", header, []byte(`{"id":"2"}`), 5*time.Minute, now), webhooks.ErrInvalidSignature)
+	assert.ErrorIs(t, webhooks.Verify("whsec_test", header, body, 5*time.Minute, now.Add(10*time.Minute)), webhooks.ErrInvalidSignature)
+	assert.ErrorIs(t, webhooks.Verify("whsec_test", "v1=abc", body, 5*time.Minute, now), webhooks.ErrInvalidSignature)
+}
+
+func TestWebhookConfig(t *testing.T) {
+	cfg, err := webhooks.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(8), cfg.MaxAttempts)
+	assert.False(t, cfg.AllowPrivateURLs)
+
+	assert.Equal(t, 30*time.Second, cfg.Backoff(1))
+	assert.Equal(t, time.Minute, cfg.Backoff(2))
+	assert.Equal(t, 4*time.Minute, cfg.Backoff(4))
+	assert.Equal(t, time.Hour, cfg.Backoff(20))
+
+	t.Setenv("WEBHOOK_RETRY_BASE", "2h")
+	_, err = webhooks.ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestWebhookSubscribeValidation(t *testing.T) {
+	service := webhooks.NewService(webhooks.NewMemoryStore(), nil, webhooks.Config{}, zap.NewNop())
+	ctx := context.Background()
+
+	for name, req := range map[string]webhooks.SubscribeRequest{
+		"address":    {Address: "nope", URL: "https://example.com/hook"},
+		"url":        {Address: webhookBorrower, URL: "ftp://example.com/hook"},
+		"localhost":  {Address: webhookBorrower, URL: "http://localhost:8123/"},
+		"loopback":   {Address: webhookBorrower, URL: "http://127.0.0.1:15672/api/users"},
+		"metadata":   {Address: webhookBorrower, URL: "http://169.254.169.254/latest/meta-data/"},
+		"private":    {Address: webhookBorrower, URL: "https://10.0.0.8/hook"},
+		"ipv6":       {Address: webhookBorrower, URL: "http://[::1]/hook"},
+		"mapped":     {Address: webhookBorrower, URL: "http://[::ffff:127.0.0.1]/hook"},
+		"cgnat":      {Address: webhookBorrower, URL: "http://100.100.100.200/latest/meta-data/"},
+		"event type": {Address: webhookBorrower, URL: "https://example.com/hook", EventTypes: []string{"rfq_deleted"}},
+	} {
+		_, err := service.Subscribe(ctx, req)
+		assert.True(t, apperrors.Is(err, apperrors.KindInvalid), name)
+	}
+
+	sub, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookBorrower, URL: "https://example.com/hook"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, sub.Secret)
+
+	got, err := service.GetSubscription(ctx, sub.ID, webhookBorrower)
+	require.NoError(t, err)
+	assert.Empty(t, got.Secret, "the secret is only returned on creation")
+
+	_, err = service.GetSubscription(ctx, "unknown", webhookBorrower)
+	assert.True(t, apperrors.Is(err, apperrors.KindNotFound))
+}
+
+func TestWebhookSubscriptionsBelongToTheirAddress(t *testing.T) {
+	service := webhooks.NewService(webhooks.NewMemoryStore(), nil, webhookConfig(), zap.NewNop())
+	ctx := context.Background()
+
+	sub, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookBorrower, URL: "https://example.com/hook"})
+	require.NoError(t, err)
+
+	// Other wallets can't tell the subscription exists
+	_, err = service.GetSubscription(ctx, sub.ID, webhookLender)
+	assert.True(t, apperrors.Is(err, apperrors.KindNotFound))
+	_, err = service.ListDeliveries(ctx, sub.ID, webhookLender, 0)
+	assert.True(t, apperrors.Is(err, apperrors.KindNotFound))
+	_, err = service.Redeliver(ctx, sub.ID, webhookLender, "unknown")
+	assert.True(t, apperrors.Is(err, apperrors.KindNotFound))
+	assert.True(t, apperrors.Is(service.Unsubscribe(ctx, sub.ID, webhookLender), apperrors.KindNotFound))
+	assert.True(t, apperrors.Is(service.Unsubscribe(ctx, sub.ID, ""), apperrors.KindNotFound))
+
+	got, err := service.GetSubscription(ctx, sub.ID, webhookBorrower)
+	require.NoError(t, err)
+	assert.True(t, got.Active)
+}
+
+func TestWebhookDeliversSignedEvent(t *testing.T) {
+	sink, server := newWebhookSink(t, http.StatusOK)
+	store := webhooks.NewMemoryStore()
+	service := webhooks.NewService(store, webhookLookup{}, webhookConfig(), zap.NewNop())
+	dispatcher := webhooks.NewDispatcher(store, webhookConfig(), zap.NewNop())
+	ctx := context.Background()
+
+	// The borrower is found through the auction, the lender is carried by the event
+	borrower, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookBorrower, URL: server.URL})
+	require.NoError(t, err)
+	lender, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookLender, URL: server.URL,
+		EventTypes: []string{"bid_placed"}})
+	require.NoError(t, err)
+	// Neither the event type nor the chain match
+	_, err = service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookLender, URL: server.URL,
+		EventTypes: []string{"rfq_created"}})
+	require.NoError(t, err)
+	_, err = service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 1, Address: webhookLender, URL: server.URL})
+	require.NoError(t, err)
+
+	n, err := service.Enqueue(ctx, bidPlaced("0xABC"), 31337)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// The same event consumed again is not delivered twice, API submissions without a tx_hash not at all
+	n, err = service.Enqueue(ctx, bidPlaced("0xabc"), 31337)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	n, err = service.Enqueue(ctx, []byte(`{"type":"bid_placed","auction_id":"7","lender":"`+webhookLender+`"}`), 31337)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	require.NoError(t, dispatcher.DispatchDue(ctx))
+	require.Equal(t, 2, sink.received())
+
+	secrets := map[string]string{borrower.ID: borrower.Secret, lender.ID: lender.Secret}
+	owners := map[string]string{borrower.ID: webhookBorrower, lender.ID: webhookLender}
+	for i, req := range sink.requests {
+		body := sink.bodies[i]
+		var event webhooks.Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, "31337-0xabc-2", event.ID)
+		assert.Equal(t, "bid_placed", event.Type)
+		assert.Equal(t, "7", event.Data["auction_id"])
+		assert.Equal(t, event.ID, req.Header.Get(webhooks.HeaderEventID))
+		assert.Equal(t, "bid_placed", req.Header.Get(webhooks.HeaderEvent))
+
+		delivery := req.Header.Get(webhooks.HeaderDelivery)
+		var subscriptionID string
+		for id := range secrets {
+			deliveries, err := service.ListDeliveries(ctx, id, owners[id], 0)
+			require.NoError(t, err)
+			if len(deliveries) == 1 && deliveries[0].ID == delivery {
+				subscriptionID = id
+				assert.Equal(t, webhooks.DeliveryDelivered, deliveries[0].Status)
+				assert.Equal(t, uint32(1), deliveries[0].Attempts)
+				assert.Equal(t, uint16(http.StatusOK), deliveries[0].ResponseStatus)
+			}
+		}
+		require.NotEmpty(t, subscriptionID)
+		assert.NoError(t, webhooks.Verify(secrets[subscriptionID], req.Header.Get(webhooks.HeaderSignature), body, time.Minute, time.Now()))
+	}
+
+	// Nothing is left to send
+	require.NoError(t, dispatcher.DispatchDue(ctx))
+	assert.Equal(t, 2, sink.received())
+}
+
+func TestWebhookDeliversRiskEvents(t *testing.T) {
+	store := webhooks.NewMemoryStore()
+	service := webhooks.NewService(store, webhookLookup{}, webhookConfig(), zap.NewNop())
+	ctx := context.Background()
+
+	lender, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookLender, URL: "https://example.com/hook",
+		EventTypes: []string{"liquidation_case_opened"}})
+	require.NoError(t, err)
+
+	// Risk events have no transaction, they are identified by their event_id
+	body, _ := json.Marshal(map[string]interface{}{
+		"type":           "liquidation_case_opened",
+		"event_id":       "liquidation-5-pending_approval",
+		"chain_id":       31337,
+		"credit_line_id": 5,
+		"borrower":       webhookBorrower,
+		"lender":         webhookLender,
+		"status":         "pending_approval",
+		"tx_hash":        "",
+	})
+	n, err := service.Enqueue(ctx, body, 31337)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	n, err = service.Enqueue(ctx, body, 31337)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	deliveries, err := service.ListDeliveries(ctx, lender.ID, webhookLender, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "31337-liquidation-5-pending_approval", deliveries[0].EventID)
+	assert.Equal(t, "liquidation_case_opened", deliveries[0].EventType)
+}
+
+func TestWebhookRetriesThenFails(t *testing.T) {
+	sink, server := newWebhookSink(t, http.StatusInternalServerError)
+	store := webhooks.NewMemoryStore()
+	service := webhooks.NewService(store, webhookLookup{}, webhookConfig(), zap.NewNop())
+	cfg := webhookConfig()
+	// Attempts are scheduled in whole seconds
+	cfg.RetryMax = cfg.RetryBase
+	dispatcher := webhooks.NewDispatcher(store, cfg, zap.NewNop())
+	ctx := context.Background()
+
+	sub, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookLender, URL: server.URL})
+	require.NoError(t, err)
+	_, err = service.Enqueue(ctx, bidPlaced("0xdef"), 31337)
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.DispatchDue(ctx))
+	deliveries, err := service.ListDeliveries(ctx, sub.ID, webhookLender, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	first := deliveries[0]
+	assert.Equal(t, webhooks.DeliveryPending, first.Status)
+	assert.Equal(t, uint32(1), first.Attempts)
+	assert.Equal(t, uint16(http.StatusInternalServerError), first.ResponseStatus)
+	assert.NotEmpty(t, first.Error)
+	assert.Greater(t, first.NextAttemptAt, first.LastAttemptAt, "the retry waits for the backoff")
+
+	// Not due yet
+	require.NoError(t, dispatcher.DispatchDue(ctx))
+	assert.Equal(t, 1, sink.received())
+
+	// The remaining attempts once due, the last marks the delivery failed
+	for sink.received() < int(cfg.MaxAttempts) {
+		time.Sleep(cfg.RetryBase / 4)
+		require.NoError(t, dispatcher.DispatchDue(ctx))
+		if time.Since(time.Unix(first.CreatedAt, 0)) > 10*time.Second {
+			t.Fatal("retries were not sent")
+		}
+	}
+	deliveries, err = service.ListDeliveries(ctx, sub.ID, webhookLender, 0)
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.DeliveryFailed, deliveries[0].Status)
+	assert.Equal(t, cfg.MaxAttempts, deliveries[0].Attempts)
+
+	// A manual redelivery is a new delivery of the same event
+	sink.setStatus(http.StatusNoContent)
+	redelivery, err := service.Redeliver(ctx, sub.ID, webhookLender, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, redelivery.RedeliveryOf)
+	assert.Equal(t, first.EventID, redelivery.EventID)
+	require.NoError(t, dispatcher.DispatchDue(ctx))
+	assert.Equal(t, int(cfg.MaxAttempts)+1, sink.received())
+
+	deliveries, err = service.ListDeliveries(ctx, sub.ID, webhookLender, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, d := range deliveries {
+		if d.ID == redelivery.ID {
+			assert.Equal(t, webhooks.DeliveryDelivered, d.Status)
+		}
+	}
+
+	_, err = service.Redeliver(ctx, sub.ID, webhookLender, "unknown")
+	assert.True(t, apperrors.Is(err, apperrors.KindNotFound))
+}
+
+func TestWebhookStopsAfterUnsubscribe(t *testing.T) {
+	sink, server := newWebhookSink(t, http.StatusOK)
+	store := webhooks.NewMemoryStore()
+	service := webhooks.NewService(store, nil, webhookConfig(), zap.NewNop())
+	dispatcher := webhooks.NewDispatcher(store, webhookConfig(), zap.NewNop())
+	ctx := context.Background()
+
+	sub, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookLender, URL: server.URL})
+	require.NoError(t, err)
+	_, err = service.Enqueue(ctx, bidPlaced("0x123"), 31337)
+	require.NoError(t, err)
+	require.NoError(t, service.Unsubscribe(ctx, sub.ID, webhookLender))
+
+	require.NoError(t, dispatcher.DispatchDue(ctx))
+	assert.Zero(t, sink.received())
+	deliveries, err := service.ListDeliveries(ctx, sub.ID, webhookLender, 0)
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.DeliveryFailed, deliveries[0].Status)
+
+	n, err := service.Enqueue(ctx, bidPlaced("0x456"), 31337)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+// unreachableSubscriptions fails to read subscriptions and counts the polls for due deliveries
+type unreachableSubscriptions struct {
+	*webhooks.MemoryStore
+	polls int
+}
+
+func (s *unreachableSubscriptions) GetSubscription(ctx context.Context, id string) (*repositories.WebhookSubscriptionModel, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (s *unreachableSubscriptions) DueDeliveries(ctx context.Context, now int64, limit int) ([]*repositories.WebhookDeliveryModel, error) {
+	s.polls++
+	return s.MemoryStore.DueDeliveries(ctx, now, limit)
+}
+
+func TestWebhookDispatchWaitsOutStoreFailures(t *testing.T) {
+	sink, server := newWebhookSink(t, http.StatusOK)
+	store := &unreachableSubscriptions{MemoryStore: webhooks.NewMemoryStore()}
+	service := webhooks.NewService(store.MemoryStore, nil, webhookConfig(), zap.NewNop())
+	dispatcher := webhooks.NewDispatcher(store, webhookConfig(), zap.NewNop())
+	ctx := context.Background()
+
+	sub, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookLender, URL: server.URL})
+	require.NoError(t, err)
+	_, err = service.Enqueue(ctx, bidPlaced("0xabc"), 31337)
+	require.NoError(t, err)
+
+	// The delivery stays due, it is left for the next poll rather than picked up again at once
+	assert.Error(t, dispatcher.DispatchDue(ctx))
+	assert.Equal(t, 1, store.polls)
+	assert.Zero(t, sink.received())
+	deliveries, err := service.ListDeliveries(ctx, sub.ID, webhookLender, 0)
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.DeliveryPending, deliveries[0].Status)
+
+	// A delivery whose subscription is gone fails for good
+	require.NoError(t, store.MemoryStore.SaveDelivery(ctx, &repositories.WebhookDeliveryModel{
+		ID: "orphan", SubscriptionID: "missing", ChainID: 31337, Status: webhooks.DeliveryPending,
+	}))
+	require.NoError(t, webhooks.NewDispatcher(store.MemoryStore, webhookConfig(), zap.NewNop()).DispatchDue(ctx))
+	orphan, err := store.MemoryStore.GetDelivery(ctx, "orphan")
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.DeliveryFailed, orphan.Status)
+	assert.Equal(t, 1, sink.received())
+}
+
+func TestWebhookRefusesPrivateAddresses(t *testing.T) {
+	sink, server := newWebhookSink(t, http.StatusOK)
+	store := webhooks.NewMemoryStore()
+	// The subscription is accepted as a name resolving to a private address would be, the dispatcher
+	// checks the address it connects to
+	service := webhooks.NewService(store, nil, webhookConfig(), zap.NewNop())
+	cfg := webhookConfig()
+	cfg.AllowPrivateURLs = false
+	dispatcher := webhooks.NewDispatcher(store, cfg, zap.NewNop())
+	ctx := context.Background()
+
+	sub, err := service.Subscribe(ctx, webhooks.SubscribeRequest{ChainID: 31337, Address: webhookLender, URL: server.URL})
+	require.NoError(t, err)
+	_, err = service.Enqueue(ctx, bidPlaced("0x789"), 31337)
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.DispatchDue(ctx))
+	assert.Zero(t, sink.received())
+	deliveries, err := service.ListDeliveries(ctx, sub.ID, webhookLender, 0)
+	require.NoError(t, err)
+	assert.Contains(t, deliveries[0].Error, "private address")
+}
+
+func TestWebhookRoutesRequireTheOwnersSignature(t *testing.T) {
+	owner, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ownerAuth, otherAuth := client.NewWalletAuth(owner), client.NewWalletAuth(other)
+
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	webhookHandler := handlers.NewWebhookHandler(webhooks.NewService(webhooks.NewMemoryStore(), nil, webhooks.Config{}, zap.NewNop()), zap.NewNop())
+	e.POST("/api/v1/webhooks", webhookHandler.Subscribe, appmiddleware.AuthMiddleware())
+	e.GET("/api/v1/webhooks/:id", webhookHandler.GetSubscription, appmiddleware.AuthMiddleware())
+
+	do := func(auth client.Auth, method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if auth != nil {
+			require.NoError(t, auth.Authenticate(req, body, time.Now()))
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	subscribe := func(address, url string) []byte {
+		body, _ := json.Marshal(webhooks.SubscribeRequest{Address: address, URL: url})
+		return body
+	}
+
+	ownerAddress := ownerAuth.Address().Hex()
+	assert.Equal(t, http.StatusUnauthorized, do(nil, http.MethodPost, "/api/v1/webhooks", subscribe(ownerAddress, "https://example.com/hook")).Code)
+	assert.Equal(t, http.StatusForbidden, do(otherAuth, http.MethodPost, "/api/v1/webhooks", subscribe(ownerAddress, "https://example.com/hook")).Code)
+	assert.Equal(t, http.StatusBadRequest, do(ownerAuth, http.MethodPost, "/api/v1/webhooks", subscribe(ownerAddress, "http://169.254.169.254/")).Code)
+
+	rec := do(ownerAuth, http.MethodPost, "/api/v1/webhooks", subscribe(ownerAddress, "https://example.com/hook"))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var sub webhooks.Subscription
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sub))
+
+	assert.Equal(t, http.StatusUnauthorized, do(nil, http.MethodGet, "/api/v1/webhooks/"+sub.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, do(otherAuth, http.MethodGet, "/api/v1/webhooks/"+sub.ID, nil).Code)
+	assert.Equal(t, http.StatusOK, do(ownerAuth, http.MethodGet, "/api/v1/webhooks/"+sub.ID, nil).Code)
+}
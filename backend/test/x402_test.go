@@ -0,0 +1,284 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Pagga-Wallet/aqua402/internal/apperrors"
+	"github.com/Pagga-Wallet/aqua402/internal/handlers"
+	"github.com/Pagga-Wallet/aqua402/pkg/x402"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	x402Asset = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	x402PayTo = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+)
+
+func newPaidServer() *echo.Echo {
+	return newPaidServerWith(x402.NewLocalFacilitator())
+}
+
+func newPaidServerWith(facilitator x402.Facilitator) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(zap.NewNop())
+	e.Use(x402.Middleware(x402.Config{
+		Facilitator:       facilitator,
+		Network:           "hardhat",
+		Asset:             x402Asset,
+		AssetName:         "USD Coin",
+		AssetVersion:      "2",
+		PayTo:             x402PayTo,
+		MaxTimeoutSeconds: 60,
+		Routes: map[string]x402.Price{
+			"GET /paid/:id":    {Amount: "1000"},
+			"GET /missing/:id": {Amount: "1000"},
+			"GET /broken/:id":  {Amount: "1000"},
+		},
+	}))
+	e.GET("/paid/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"payer": c.Get(x402.ContextKeyPayer)})
+	})
+	e.GET("/missing/:id", func(c echo.Context) error {
+		return apperrors.NotFound("thing %s not found", c.Param("id"))
+	})
+	e.GET("/broken/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "broken"})
+	})
+	e.GET("/free", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	return e
+}
+
+func paymentRequirements(t *testing.T, e *echo.Echo) x402.PaymentRequirements {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/paid/1", nil))
+	require.Equal(t, http.StatusPaymentRequired, rec.Code)
+
+	var body x402.PaymentRequiredResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Accepts, 1)
+	return body.Accepts[0]
+}
+
+func signedAuthorization(t *testing.T, key *ecdsa.PrivateKey, req x402.PaymentRequirements, value string) string {
+	nonce := make([]byte, 32)
+	nonce[31] = 1
+
+	auth := &x402.Authorization{
+		From:        crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		To:          req.PayTo,
+		Value:       value,
+		ValidAfter:  "0",
+		ValidBefore: strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10),
+		Nonce:       hexutil.Encode(nonce),
+	}
+	domain, err := x402.DomainFor(&req)
+	require.NoError(t, err)
+	signature, err := x402.SignAuthorization(key, domain, auth)
+	require.NoError(t, err)
+
+	header, err := x402.EncodePayment(&x402.PaymentPayload{
+		X402Version: x402.Version,
+		Scheme:      req.Scheme,
+		Network:     req.Network,
+		Payload:     x402.ExactPayload{Signature: signature, Authorization: auth},
+	})
+	require.NoError(t, err)
+	return header
+}
+
+func paidRequest(e *echo.Echo, header string) *httptest.ResponseRecorder {
+	return paidRequestTo(e, "/paid/1", header)
+}
+
+func paidRequestTo(e *echo.Echo, path, header string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(x402.HeaderPayment, header)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestX402PaymentRequired(t *testing.T) {
+	e := newPaidServer()
+
+	req := paymentRequirements(t, e)
+	assert.Equal(t, x402.SchemeExact, req.Scheme)
+	assert.Equal(t, "1000", req.MaxAmountRequired)
+	assert.Equal(t, x402PayTo, req.PayTo)
+	assert.Equal(t, x402Asset, req.Asset)
+	assert.Equal(t, "hardhat", req.Network)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/free", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestX402AuthorizationSettlesOnce(t *testing.T) {
+	e := newPaidServer()
+	req := paymentRequirements(t, e)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	header := signedAuthorization(t, key, req, "1000")
+
+	rec := paidRequest(e, header)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	receipt, err := x402.DecodeSettleResponse(rec.Header().Get(x402.HeaderPaymentResponse))
+	require.NoError(t, err)
+	assert.True(t, receipt.Success)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), receipt.Payer)
+	assert.NotEmpty(t, receipt.Transaction)
+
+	// Replaying the same authorization is rejected
+	rec = paidRequest(e, header)
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+	assert.Contains(t, rec.Body.String(), x402.ReasonNonceUsed)
+}
+
+func TestX402SettlesOnlySuccessfulResponses(t *testing.T) {
+	e := newPaidServer()
+	req := paymentRequirements(t, e)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	header := signedAuthorization(t, key, req, "1000")
+
+	// Failed handlers answer as they would unpaid and leave the authorization unspent
+	rec := paidRequestTo(e, "/missing/1", header)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get(x402.HeaderPaymentResponse))
+
+	rec = paidRequestTo(e, "/broken/1", header)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"error":"broken"}`, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(x402.HeaderPaymentResponse))
+
+	rec = paidRequest(e, header)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(x402.HeaderPaymentResponse))
+	assert.Contains(t, rec.Body.String(), crypto.PubkeyToAddress(key.PublicKey).Hex())
+}
+
+// failingSettlement verifies every payment and fails to settle it
+type failingSettlement struct {
+	x402.Facilitator
+}
+
+func (f failingSettlement) Settle(ctx context.Context, payment *x402.PaymentPayload, requirements *x402.PaymentRequirements) (*x402.SettleResponse, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestX402FacilitatorUnavailable(t *testing.T) {
+	e := newPaidServerWith(failingSettlement{Facilitator: x402.NewLocalFacilitator()})
+	req := paymentRequirements(t, e)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// The handler's response is dropped for the error, nothing was charged
+	rec := paidRequest(e, signedAuthorization(t, key, req, "1000"))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "payment facilitator unavailable")
+	assert.NotContains(t, rec.Body.String(), "payer")
+	assert.Empty(t, rec.Header().Get(x402.HeaderPaymentResponse))
+}
+
+func TestX402RejectsInvalidPayments(t *testing.T) {
+	e := newPaidServer()
+	req := paymentRequirements(t, e)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	rec := paidRequest(e, signedAuthorization(t, key, req, "999"))
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+	assert.Contains(t, rec.Body.String(), x402.ReasonInsufficientAmount)
+
+	wrongNetwork := req
+	wrongNetwork.Network = "base-sepolia"
+	rec = paidRequest(e, signedAuthorization(t, key, wrongNetwork, "1000"))
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+	assert.Contains(t, rec.Body.String(), x402.ReasonInvalidNetwork)
+
+	rec = paidRequest(e, "not-a-payment")
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+}
+
+func TestX402Permit(t *testing.T) {
+	e := newPaidServer()
+	req := paymentRequirements(t, e)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	permit := &x402.Permit{
+		Owner:    crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		Spender:  req.PayTo,
+		Value:    "1000",
+		Nonce:    "0",
+		Deadline: strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10),
+	}
+	domain, err := x402.DomainFor(&req)
+	require.NoError(t, err)
+	signature, err := x402.SignPermit(key, domain, permit)
+	require.NoError(t, err)
+
+	header, err := x402.EncodePayment(&x402.PaymentPayload{
+		X402Version: x402.Version,
+		Scheme:      req.Scheme,
+		Network:     req.Network,
+		Payload:     x402.ExactPayload{Signature: signature, Permit: permit},
+	})
+	require.NoError(t, err)
+
+	rec := paidRequest(e, header)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(x402.HeaderPaymentResponse))
+}
+
+func TestX402ConfigFromEnvFacilitator(t *testing.T) {
+	t.Setenv("X402_ROUTES", "GET /api/v1/borrowers/:address/obligations=10000")
+	t.Setenv("X402_ASSET", x402Asset)
+	t.Setenv("X402_PAY_TO", x402PayTo)
+	t.Setenv("X402_FACILITATOR_URL", "")
+	t.Setenv("X402_FACILITATOR", "")
+	t.Setenv("APP_ENV", "")
+
+	// Paid routes never fall back to the local facilitator silently
+	_, err := x402.ConfigFromEnv()
+	assert.ErrorContains(t, err, "X402_ROUTES requires X402_FACILITATOR_URL")
+
+	t.Setenv("X402_FACILITATOR", "local")
+	cfg, err := x402.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &x402.LocalFacilitator{}, cfg.Facilitator)
+
+	t.Setenv("APP_ENV", "production")
+	_, err = x402.ConfigFromEnv()
+	assert.ErrorContains(t, err, "refused in production")
+
+	t.Setenv("X402_FACILITATOR_URL", "https://facilitator.example.com")
+	cfg, err = x402.ConfigFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &x402.HTTPFacilitator{}, cfg.Facilitator)
+
+	t.Setenv("X402_FACILITATOR", "remote")
+	_, err = x402.ConfigFromEnv()
+	assert.ErrorContains(t, err, "invalid X402_FACILITATOR")
+}
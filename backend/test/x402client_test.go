@@ -0,0 +1,114 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Pagga-Wallet/aqua402/pkg/x402"
+	"github.com/Pagga-Wallet/aqua402/pkg/x402client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCredit is a single in-memory credit line
+type fakeCredit struct {
+	limit *big.Int
+	drawn *big.Int
+	draws int
+}
+
+func (f *fakeCredit) Find(ctx context.Context, borrower common.Address, amount *big.Int) (*big.Int, error) {
+	if new(big.Int).Sub(f.limit, f.drawn).Cmp(amount) < 0 {
+		return nil, x402client.ErrNoCreditLine
+	}
+	return big.NewInt(1), nil
+}
+
+func (f *fakeCredit) Draw(ctx context.Context, creditLineID *big.Int, amount *big.Int) (string, error) {
+	f.drawn.Add(f.drawn, amount)
+	f.draws++
+	return "0x01", nil
+}
+
+func TestX402ClientPaysWithCredit(t *testing.T) {
+	server := httptest.NewServer(newPaidServer())
+	defer server.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	credit := &fakeCredit{limit: big.NewInt(10000), drawn: new(big.Int)}
+	client := x402client.New(server.Client(), key, credit, x402client.Limits{})
+
+	resp, err := client.Get(server.URL + "/paid/1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, credit.draws)
+	assert.Equal(t, "1000", credit.drawn.String())
+
+	receipt, err := x402.DecodeSettleResponse(resp.Header.Get(x402.HeaderPaymentResponse))
+	require.NoError(t, err)
+	assert.Equal(t, client.Address().Hex(), receipt.Payer)
+
+	// Free routes are not paid for
+	resp, err = client.Get(server.URL + "/free")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, credit.draws)
+}
+
+func TestX402ClientSpendingLimits(t *testing.T) {
+	server := httptest.NewServer(newPaidServer())
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	credit := &fakeCredit{limit: big.NewInt(100000), drawn: new(big.Int)}
+	client := x402client.New(server.Client(), key, credit, x402client.Limits{
+		PerDay:    big.NewInt(5000),
+		PerDomain: big.NewInt(2000),
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/paid/1")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	_, err = client.Get(server.URL + "/paid/1")
+	assert.ErrorIs(t, err, x402client.ErrLimitExceeded)
+	assert.Equal(t, 2, credit.draws)
+
+	domainSpent, totalSpent := client.Spent(serverURL.Hostname())
+	assert.Equal(t, "2000", domainSpent.String())
+	assert.Equal(t, "2000", totalSpent.String())
+}
+
+func TestX402ClientWithoutCredit(t *testing.T) {
+	server := httptest.NewServer(newPaidServer())
+	defer server.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	credit := &fakeCredit{limit: big.NewInt(500), drawn: new(big.Int)}
+	client := x402client.New(server.Client(), key, credit, x402client.Limits{})
+
+	_, err = client.Get(server.URL + "/paid/1")
+	assert.ErrorIs(t, err, x402client.ErrNoCreditLine)
+
+	// Nothing was spent
+	_, total := client.Spent(strings.TrimPrefix(server.URL, "http://"))
+	assert.Equal(t, "0", total.String())
+}
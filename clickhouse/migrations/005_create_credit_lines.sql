@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS credit_lines
+(
+    id UInt64,
+    borrower_address String,
+    lender_address String,
+    limit String,
+    rate_bps UInt64,
+    created_at Int64,
+    expires_at Int64,
+    tx_hash String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS credit_lines;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS credit_events
+(
+    credit_line_id UInt64,
+    event_type String,
+    amount String,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32,
+    timestamp Int64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (credit_line_id, block_number, log_index)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS credit_events;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS credit_line_snapshots
+(
+    credit_line_id UInt64,
+    borrower_address String,
+    principal String,
+    accrued_interest String,
+    total_owed String,
+    interest_paid String,
+    interest_mode String,
+    snapshot_at Int64
+)
+ENGINE = MergeTree()
+ORDER BY (credit_line_id, snapshot_at)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS credit_line_snapshots;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS credit_line_sources
+(
+    credit_line_id UInt64,
+    source String,
+    source_id UInt64,
+    tx_hash String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY credit_line_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS credit_line_sources;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS credit_line_risk
+(
+    credit_line_id UInt64,
+    borrower_address String,
+    lender_address String,
+    status String,
+    total_owed String,
+    expires_at Int64,
+    updated_at Int64
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY credit_line_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS credit_line_risk;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS liquidation_cases
+(
+    credit_line_id UInt64,
+    rfq_id UInt64,
+    borrower_address String,
+    lender_address String,
+    collateral_type UInt8,
+    collateral_amount String,
+    outstanding String,
+    release_amount String,
+    action String,
+    status String,
+    operator String,
+    tx_hash String,
+    error String,
+    created_at Int64,
+    updated_at Int64
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY credit_line_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS liquidation_cases;
+-- +goose StatementEnd
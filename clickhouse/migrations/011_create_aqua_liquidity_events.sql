@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS aqua_liquidity_events
+(
+    lender_address String,
+    event_type String,
+    amount String,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32,
+    timestamp Int64
+)
+ENGINE = MergeTree()
+ORDER BY (timestamp, lender_address)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS aqua_liquidity_events;
+-- +goose StatementEnd
@@ -0,0 +1,288 @@
+-- Hourly read-side aggregates for the analytics API.
+-- Every target table is bucketed by hour so queries can roll up to any coarser granularity.
+-- Each view is followed by a backfill of the rows stored before it existed.
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS analytics_volume_hourly
+(
+    bucket DateTime('UTC'),
+    market LowCardinality(String),
+    count UInt64,
+    volume UInt256
+)
+ENGINE = SummingMergeTree()
+ORDER BY (market, bucket)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_volume_rfqs_mv TO analytics_volume_hourly AS
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'rfq' AS market, toUInt64(1) AS count, toUInt256OrZero(amount) AS volume
+FROM rfqs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_volume_auctions_mv TO analytics_volume_hourly AS
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'auction' AS market, toUInt64(1) AS count, toUInt256OrZero(amount) AS volume
+FROM auctions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_volume_credit_lines_mv TO analytics_volume_hourly AS
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'credit_line' AS market, toUInt64(1) AS count, toUInt256OrZero(limit) AS volume
+FROM credit_lines;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_volume_hourly
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')), 'rfq', 1, toUInt256OrZero(amount) FROM rfqs
+UNION ALL
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')), 'auction', 1, toUInt256OrZero(amount) FROM auctions
+UNION ALL
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')), 'credit_line', 1, toUInt256OrZero(limit) FROM credit_lines FINAL;
+-- +goose StatementEnd
+
+-- Clearing rates are the rates credit lines were actually opened at
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS analytics_clearing_rates_hourly
+(
+    bucket DateTime('UTC'),
+    duration_bucket LowCardinality(String),
+    lines UInt64,
+    rate_bps_sum UInt64
+)
+ENGINE = SummingMergeTree()
+ORDER BY (duration_bucket, bucket)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_clearing_rates_mv TO analytics_clearing_rates_hourly AS
+SELECT
+    toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket,
+    multiIf(expires_at - created_at < 7 * 86400, '0-7d',
+            expires_at - created_at < 30 * 86400, '7-30d',
+            expires_at - created_at < 90 * 86400, '30-90d',
+            '90d+') AS duration_bucket,
+    toUInt64(1) AS lines,
+    rate_bps AS rate_bps_sum
+FROM credit_lines;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_clearing_rates_hourly
+SELECT
+    toStartOfHour(toDateTime(created_at, 'UTC')),
+    multiIf(expires_at - created_at < 7 * 86400, '0-7d',
+            expires_at - created_at < 30 * 86400, '7-30d',
+            expires_at - created_at < 90 * 86400, '30-90d',
+            '90d+'),
+    1,
+    rate_bps
+FROM credit_lines FINAL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS analytics_lender_volume_hourly
+(
+    bucket DateTime('UTC'),
+    lender_address String,
+    lines UInt64,
+    volume UInt256
+)
+ENGINE = SummingMergeTree()
+ORDER BY (bucket, lender_address)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_lender_volume_mv TO analytics_lender_volume_hourly AS
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, lower(lender_address) AS lender_address, toUInt64(1) AS lines, toUInt256OrZero(limit) AS volume
+FROM credit_lines;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_lender_volume_hourly
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')), lower(lender_address), 1, toUInt256OrZero(limit)
+FROM credit_lines FINAL;
+-- +goose StatementEnd
+
+-- Requests, quotes (bids for auctions) and fills per market.
+-- credit_line_sources has no timestamp, fills are bucketed at the time the worker links them.
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS analytics_market_activity_hourly
+(
+    bucket DateTime('UTC'),
+    market LowCardinality(String),
+    requests UInt64,
+    quotes UInt64,
+    fills UInt64
+)
+ENGINE = SummingMergeTree()
+ORDER BY (market, bucket)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_activity_rfqs_mv TO analytics_market_activity_hourly AS
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'rfq' AS market, toUInt64(1) AS requests, toUInt64(0) AS quotes, toUInt64(0) AS fills
+FROM rfqs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_activity_quotes_mv TO analytics_market_activity_hourly AS
+SELECT toStartOfHour(toDateTime(submitted_at, 'UTC')) AS bucket, 'rfq' AS market, toUInt64(0) AS requests, toUInt64(1) AS quotes, toUInt64(0) AS fills
+FROM quotes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_activity_auctions_mv TO analytics_market_activity_hourly AS
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'auction' AS market, toUInt64(1) AS requests, toUInt64(0) AS quotes, toUInt64(0) AS fills
+FROM auctions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_activity_bids_mv TO analytics_market_activity_hourly AS
+SELECT toStartOfHour(toDateTime(timestamp, 'UTC')) AS bucket, 'auction' AS market, toUInt64(0) AS requests, toUInt64(1) AS quotes, toUInt64(0) AS fills
+FROM bids;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_activity_fills_mv TO analytics_market_activity_hourly AS
+SELECT toStartOfHour(now('UTC')) AS bucket, source AS market, toUInt64(0) AS requests, toUInt64(0) AS quotes, toUInt64(1) AS fills
+FROM credit_line_sources;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_market_activity_hourly
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')), 'rfq', 1, 0, 0 FROM rfqs
+UNION ALL
+SELECT toStartOfHour(toDateTime(submitted_at, 'UTC')), 'rfq', 0, 1, 0 FROM quotes
+UNION ALL
+SELECT toStartOfHour(toDateTime(created_at, 'UTC')), 'auction', 1, 0, 0 FROM auctions
+UNION ALL
+SELECT toStartOfHour(toDateTime(timestamp, 'UTC')), 'auction', 0, 1, 0 FROM bids
+UNION ALL
+SELECT toStartOfHour(toDateTime(l.created_at, 'UTC')), s.source, 0, 0, 1
+FROM credit_line_sources AS s FINAL
+INNER JOIN credit_lines AS l FINAL ON l.id = s.credit_line_id;
+-- +goose StatementEnd
+
+-- First quote per RFQ, joined with rfqs.created_at for time to first quote
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS analytics_rfq_first_quotes
+(
+    rfq_id UInt64,
+    first_quote_at SimpleAggregateFunction(min, Int64),
+    quotes SimpleAggregateFunction(sum, UInt64)
+)
+ENGINE = AggregatingMergeTree()
+ORDER BY rfq_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_rfq_first_quotes_mv TO analytics_rfq_first_quotes AS
+SELECT rfq_id, min(submitted_at) AS first_quote_at, count() AS quotes
+FROM quotes
+GROUP BY rfq_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_rfq_first_quotes
+SELECT rfq_id, min(submitted_at), count() FROM quotes GROUP BY rfq_id;
+-- +goose StatementEnd
+
+-- Connected and reserved Aqua liquidity movements; utilization is derived from running totals
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS analytics_aqua_liquidity_hourly
+(
+    bucket DateTime('UTC'),
+    connected Int256,
+    withdrawn Int256,
+    reserved Int256,
+    released Int256
+)
+ENGINE = SummingMergeTree()
+ORDER BY bucket
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS analytics_aqua_liquidity_mv TO analytics_aqua_liquidity_hourly AS
+SELECT
+    toStartOfHour(toDateTime(timestamp, 'UTC')) AS bucket,
+    if(event_type = 'liquidity_connected', toInt256OrZero(amount), toInt256(0)) AS connected,
+    if(event_type = 'liquidity_withdrawn', toInt256OrZero(amount), toInt256(0)) AS withdrawn,
+    if(event_type = 'liquidity_reserved', toInt256OrZero(amount), toInt256(0)) AS reserved,
+    if(event_type = 'liquidity_released', toInt256OrZero(amount), toInt256(0)) AS released
+FROM aqua_liquidity_events;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_aqua_liquidity_hourly
+SELECT
+    toStartOfHour(toDateTime(timestamp, 'UTC')),
+    if(event_type = 'liquidity_connected', toInt256OrZero(amount), toInt256(0)),
+    if(event_type = 'liquidity_withdrawn', toInt256OrZero(amount), toInt256(0)),
+    if(event_type = 'liquidity_reserved', toInt256OrZero(amount), toInt256(0)),
+    if(event_type = 'liquidity_released', toInt256OrZero(amount), toInt256(0))
+FROM aqua_liquidity_events;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_aqua_liquidity_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_aqua_liquidity_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_rfq_first_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_rfq_first_quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_fills_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_bids_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_market_activity_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_lender_volume_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_lender_volume_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_clearing_rates_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_clearing_rates_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_credit_lines_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_volume_hourly;
+-- +goose StatementEnd
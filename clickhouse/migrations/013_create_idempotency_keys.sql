@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    key String,
+    request_hash String,
+    owner String,
+    status_code UInt16,
+    content_type String,
+    body String,
+    created_at Int64,
+    expires_at Int64,
+    version UInt64
+)
+ENGINE = ReplacingMergeTree(version)
+ORDER BY key
+TTL toDateTime(expires_at) + INTERVAL 1 DAY
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_keys;
+-- +goose StatementEnd
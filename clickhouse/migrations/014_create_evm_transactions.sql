@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS evm_transactions
+(
+    id String,
+    from_address String,
+    to_address String,
+    value String,
+    data String,
+    nonce UInt64,
+    gas_limit UInt64,
+    gas_price String,
+    hash String,
+    hashes Array(String),
+    status LowCardinality(String),
+    attempts UInt32,
+    error String,
+    block_number UInt64,
+    created_at Int64,
+    updated_at Int64,
+    last_sent_at Int64,
+    version UInt64
+)
+ENGINE = ReplacingMergeTree(version)
+ORDER BY id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS evm_transactions;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE evm_transactions
+    ADD COLUMN IF NOT EXISTS gas_tip_cap String DEFAULT '' AFTER gas_price,
+    ADD COLUMN IF NOT EXISTS gas_fee_cap String DEFAULT '' AFTER gas_tip_cap,
+    ADD COLUMN IF NOT EXISTS urgency LowCardinality(String) DEFAULT '' AFTER gas_fee_cap;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE evm_transactions
+    DROP COLUMN IF EXISTS urgency,
+    DROP COLUMN IF EXISTS gas_fee_cap,
+    DROP COLUMN IF EXISTS gas_tip_cap;
+-- +goose StatementEnd
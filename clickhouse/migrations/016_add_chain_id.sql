@@ -0,0 +1,684 @@
+-- Every row records the chain it was read from or sent to, see pkg/chains.
+-- Rows stored before this migration belong to the local Hardhat chain (1337).
+-- ClickHouse cannot add a column with a default to a sorting key, so the ReplacingMergeTree tables,
+-- whose sorting key identifies a row, are rebuilt with chain_id in the key. The analytics views read
+-- those tables: they are dropped first and recreated per chain with a backfill, as in 012.
+-- Stop the worker while this runs, rows written during the rebuild are not copied.
+-- idempotency_keys are per API request, not per chain, and are left as they are.
+
+-- +goose Up
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_credit_lines_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_clearing_rates_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_lender_volume_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_bids_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_fills_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_rfq_first_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_aqua_liquidity_mv;
+-- +goose StatementEnd
+
+-- Append-only tables only need the column
+-- +goose StatementBegin
+ALTER TABLE rfqs ADD COLUMN IF NOT EXISTS chain_id UInt64 DEFAULT 1337 FIRST;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS chain_id UInt64 DEFAULT 1337 FIRST;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS chain_id UInt64 DEFAULT 1337 FIRST;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS chain_id UInt64 DEFAULT 1337 FIRST;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE credit_line_snapshots ADD COLUMN IF NOT EXISTS chain_id UInt64 DEFAULT 1337 FIRST;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE aqua_liquidity_events ADD COLUMN IF NOT EXISTS chain_id UInt64 DEFAULT 1337 FIRST;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_lines_by_chain
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    borrower_address String,
+    lender_address String,
+    limit String,
+    rate_bps UInt64,
+    created_at Int64,
+    expires_at Int64,
+    tx_hash String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, id)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_lines_by_chain (id, borrower_address, lender_address, limit, rate_bps, created_at, expires_at, tx_hash)
+SELECT id, borrower_address, lender_address, limit, rate_bps, created_at, expires_at, tx_hash FROM credit_lines;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_lines TO credit_lines_without_chain, credit_lines_by_chain TO credit_lines;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_lines_without_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_events_by_chain
+(
+    chain_id UInt64 DEFAULT 1337,
+    credit_line_id UInt64,
+    event_type String,
+    amount String,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32,
+    timestamp Int64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, credit_line_id, block_number, log_index)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_events_by_chain (credit_line_id, event_type, amount, tx_hash, block_number, log_index, timestamp)
+SELECT credit_line_id, event_type, amount, tx_hash, block_number, log_index, timestamp FROM credit_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_events TO credit_events_without_chain, credit_events_by_chain TO credit_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_events_without_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_line_sources_by_chain
+(
+    chain_id UInt64 DEFAULT 1337,
+    credit_line_id UInt64,
+    source String,
+    source_id UInt64,
+    tx_hash String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, credit_line_id)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_line_sources_by_chain (credit_line_id, source, source_id, tx_hash)
+SELECT credit_line_id, source, source_id, tx_hash FROM credit_line_sources;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_line_sources TO credit_line_sources_without_chain, credit_line_sources_by_chain TO credit_line_sources;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_line_sources_without_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_line_risk_by_chain
+(
+    chain_id UInt64 DEFAULT 1337,
+    credit_line_id UInt64,
+    borrower_address String,
+    lender_address String,
+    status String,
+    total_owed String,
+    expires_at Int64,
+    updated_at Int64
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY (chain_id, credit_line_id)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_line_risk_by_chain (credit_line_id, borrower_address, lender_address, status, total_owed, expires_at, updated_at)
+SELECT credit_line_id, borrower_address, lender_address, status, total_owed, expires_at, updated_at FROM credit_line_risk;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_line_risk TO credit_line_risk_without_chain, credit_line_risk_by_chain TO credit_line_risk;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_line_risk_without_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE liquidation_cases_by_chain
+(
+    chain_id UInt64 DEFAULT 1337,
+    credit_line_id UInt64,
+    rfq_id UInt64,
+    borrower_address String,
+    lender_address String,
+    collateral_type UInt8,
+    collateral_amount String,
+    outstanding String,
+    release_amount String,
+    action String,
+    status String,
+    operator String,
+    tx_hash String,
+    error String,
+    created_at Int64,
+    updated_at Int64
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY (chain_id, credit_line_id)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO liquidation_cases_by_chain (credit_line_id, rfq_id, borrower_address, lender_address, collateral_type, collateral_amount,
+    outstanding, release_amount, action, status, operator, tx_hash, error, created_at, updated_at)
+SELECT credit_line_id, rfq_id, borrower_address, lender_address, collateral_type, collateral_amount,
+    outstanding, release_amount, action, status, operator, tx_hash, error, created_at, updated_at
+FROM liquidation_cases;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE liquidation_cases TO liquidation_cases_without_chain, liquidation_cases_by_chain TO liquidation_cases;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE liquidation_cases_without_chain;
+-- +goose StatementEnd
+
+-- Transaction IDs are random, the chain only scopes nonces: it is kept out of the key
+-- +goose StatementBegin
+ALTER TABLE evm_transactions ADD COLUMN IF NOT EXISTS chain_id UInt64 DEFAULT 1337 FIRST;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_volume_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE analytics_volume_hourly
+(
+    chain_id UInt64,
+    bucket DateTime('UTC'),
+    market LowCardinality(String),
+    count UInt64,
+    volume UInt256
+)
+ENGINE = SummingMergeTree()
+ORDER BY (chain_id, market, bucket)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_volume_rfqs_mv TO analytics_volume_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'rfq' AS market, toUInt64(1) AS count, toUInt256OrZero(amount) AS volume
+FROM rfqs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_volume_auctions_mv TO analytics_volume_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'auction' AS market, toUInt64(1) AS count, toUInt256OrZero(amount) AS volume
+FROM auctions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_volume_credit_lines_mv TO analytics_volume_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'credit_line' AS market, toUInt64(1) AS count, toUInt256OrZero(limit) AS volume
+FROM credit_lines;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_volume_hourly
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')), 'rfq', 1, toUInt256OrZero(amount) FROM rfqs
+UNION ALL
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')), 'auction', 1, toUInt256OrZero(amount) FROM auctions
+UNION ALL
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')), 'credit_line', 1, toUInt256OrZero(limit) FROM credit_lines FINAL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_clearing_rates_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE analytics_clearing_rates_hourly
+(
+    chain_id UInt64,
+    bucket DateTime('UTC'),
+    duration_bucket LowCardinality(String),
+    lines UInt64,
+    rate_bps_sum UInt64
+)
+ENGINE = SummingMergeTree()
+ORDER BY (chain_id, duration_bucket, bucket)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_clearing_rates_mv TO analytics_clearing_rates_hourly AS
+SELECT
+    chain_id,
+    toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket,
+    multiIf(expires_at - created_at < 7 * 86400, '0-7d',
+            expires_at - created_at < 30 * 86400, '7-30d',
+            expires_at - created_at < 90 * 86400, '30-90d',
+            '90d+') AS duration_bucket,
+    toUInt64(1) AS lines,
+    rate_bps AS rate_bps_sum
+FROM credit_lines;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_clearing_rates_hourly
+SELECT
+    chain_id,
+    toStartOfHour(toDateTime(created_at, 'UTC')),
+    multiIf(expires_at - created_at < 7 * 86400, '0-7d',
+            expires_at - created_at < 30 * 86400, '7-30d',
+            expires_at - created_at < 90 * 86400, '30-90d',
+            '90d+'),
+    1,
+    rate_bps
+FROM credit_lines FINAL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_lender_volume_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE analytics_lender_volume_hourly
+(
+    chain_id UInt64,
+    bucket DateTime('UTC'),
+    lender_address String,
+    lines UInt64,
+    volume UInt256
+)
+ENGINE = SummingMergeTree()
+ORDER BY (chain_id, bucket, lender_address)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_lender_volume_mv TO analytics_lender_volume_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, lower(lender_address) AS lender_address, toUInt64(1) AS lines, toUInt256OrZero(limit) AS volume
+FROM credit_lines;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_lender_volume_hourly
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')), lower(lender_address), 1, toUInt256OrZero(limit)
+FROM credit_lines FINAL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_market_activity_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE analytics_market_activity_hourly
+(
+    chain_id UInt64,
+    bucket DateTime('UTC'),
+    market LowCardinality(String),
+    requests UInt64,
+    quotes UInt64,
+    fills UInt64
+)
+ENGINE = SummingMergeTree()
+ORDER BY (chain_id, market, bucket)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_activity_rfqs_mv TO analytics_market_activity_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'rfq' AS market, toUInt64(1) AS requests, toUInt64(0) AS quotes, toUInt64(0) AS fills
+FROM rfqs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_activity_quotes_mv TO analytics_market_activity_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(submitted_at, 'UTC')) AS bucket, 'rfq' AS market, toUInt64(0) AS requests, toUInt64(1) AS quotes, toUInt64(0) AS fills
+FROM quotes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_activity_auctions_mv TO analytics_market_activity_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')) AS bucket, 'auction' AS market, toUInt64(1) AS requests, toUInt64(0) AS quotes, toUInt64(0) AS fills
+FROM auctions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_activity_bids_mv TO analytics_market_activity_hourly AS
+SELECT chain_id, toStartOfHour(toDateTime(timestamp, 'UTC')) AS bucket, 'auction' AS market, toUInt64(0) AS requests, toUInt64(1) AS quotes, toUInt64(0) AS fills
+FROM bids;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_activity_fills_mv TO analytics_market_activity_hourly AS
+SELECT chain_id, toStartOfHour(now('UTC')) AS bucket, source AS market, toUInt64(0) AS requests, toUInt64(0) AS quotes, toUInt64(1) AS fills
+FROM credit_line_sources;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_market_activity_hourly
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')), 'rfq', 1, 0, 0 FROM rfqs
+UNION ALL
+SELECT chain_id, toStartOfHour(toDateTime(submitted_at, 'UTC')), 'rfq', 0, 1, 0 FROM quotes
+UNION ALL
+SELECT chain_id, toStartOfHour(toDateTime(created_at, 'UTC')), 'auction', 1, 0, 0 FROM auctions
+UNION ALL
+SELECT chain_id, toStartOfHour(toDateTime(timestamp, 'UTC')), 'auction', 0, 1, 0 FROM bids
+UNION ALL
+SELECT s.chain_id, toStartOfHour(toDateTime(l.created_at, 'UTC')), s.source, 0, 0, 1
+FROM credit_line_sources AS s FINAL
+INNER JOIN credit_lines AS l FINAL ON l.chain_id = s.chain_id AND l.id = s.credit_line_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_rfq_first_quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE analytics_rfq_first_quotes
+(
+    chain_id UInt64,
+    rfq_id UInt64,
+    first_quote_at SimpleAggregateFunction(min, Int64),
+    quotes SimpleAggregateFunction(sum, UInt64)
+)
+ENGINE = AggregatingMergeTree()
+ORDER BY (chain_id, rfq_id)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_rfq_first_quotes_mv TO analytics_rfq_first_quotes AS
+SELECT chain_id, rfq_id, min(submitted_at) AS first_quote_at, count() AS quotes
+FROM quotes
+GROUP BY chain_id, rfq_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_rfq_first_quotes
+SELECT chain_id, rfq_id, min(submitted_at), count() FROM quotes GROUP BY chain_id, rfq_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_aqua_liquidity_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE analytics_aqua_liquidity_hourly
+(
+    chain_id UInt64,
+    bucket DateTime('UTC'),
+    connected Int256,
+    withdrawn Int256,
+    reserved Int256,
+    released Int256
+)
+ENGINE = SummingMergeTree()
+ORDER BY (chain_id, bucket)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW analytics_aqua_liquidity_mv TO analytics_aqua_liquidity_hourly AS
+SELECT
+    chain_id,
+    toStartOfHour(toDateTime(timestamp, 'UTC')) AS bucket,
+    if(event_type = 'liquidity_connected', toInt256OrZero(amount), toInt256(0)) AS connected,
+    if(event_type = 'liquidity_withdrawn', toInt256OrZero(amount), toInt256(0)) AS withdrawn,
+    if(event_type = 'liquidity_reserved', toInt256OrZero(amount), toInt256(0)) AS reserved,
+    if(event_type = 'liquidity_released', toInt256OrZero(amount), toInt256(0)) AS released
+FROM aqua_liquidity_events;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO analytics_aqua_liquidity_hourly
+SELECT
+    chain_id,
+    toStartOfHour(toDateTime(timestamp, 'UTC')),
+    if(event_type = 'liquidity_connected', toInt256OrZero(amount), toInt256(0)),
+    if(event_type = 'liquidity_withdrawn', toInt256OrZero(amount), toInt256(0)),
+    if(event_type = 'liquidity_reserved', toInt256OrZero(amount), toInt256(0)),
+    if(event_type = 'liquidity_released', toInt256OrZero(amount), toInt256(0))
+FROM aqua_liquidity_events;
+-- +goose StatementEnd
+
+-- Rolling back keeps every row: IDs shared by several chains collapse into one in the rebuilt tables.
+-- The analytics views are dropped, run 012 again to recreate them without chain_id.
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_credit_lines_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_clearing_rates_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_lender_volume_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_bids_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_fills_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_rfq_first_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_aqua_liquidity_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_volume_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_clearing_rates_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_lender_volume_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_market_activity_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_rfq_first_quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_aqua_liquidity_hourly;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE evm_transactions DROP COLUMN IF EXISTS chain_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE liquidation_cases_without_chain
+(
+    credit_line_id UInt64,
+    rfq_id UInt64,
+    borrower_address String,
+    lender_address String,
+    collateral_type UInt8,
+    collateral_amount String,
+    outstanding String,
+    release_amount String,
+    action String,
+    status String,
+    operator String,
+    tx_hash String,
+    error String,
+    created_at Int64,
+    updated_at Int64
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY credit_line_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO liquidation_cases_without_chain
+SELECT credit_line_id, rfq_id, borrower_address, lender_address, collateral_type, collateral_amount,
+    outstanding, release_amount, action, status, operator, tx_hash, error, created_at, updated_at
+FROM liquidation_cases;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE liquidation_cases TO liquidation_cases_by_chain, liquidation_cases_without_chain TO liquidation_cases;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE liquidation_cases_by_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_line_risk_without_chain
+(
+    credit_line_id UInt64,
+    borrower_address String,
+    lender_address String,
+    status String,
+    total_owed String,
+    expires_at Int64,
+    updated_at Int64
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY credit_line_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_line_risk_without_chain
+SELECT credit_line_id, borrower_address, lender_address, status, total_owed, expires_at, updated_at FROM credit_line_risk;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_line_risk TO credit_line_risk_by_chain, credit_line_risk_without_chain TO credit_line_risk;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_line_risk_by_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_line_sources_without_chain
+(
+    credit_line_id UInt64,
+    source String,
+    source_id UInt64,
+    tx_hash String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY credit_line_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_line_sources_without_chain
+SELECT credit_line_id, source, source_id, tx_hash FROM credit_line_sources;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_line_sources TO credit_line_sources_by_chain, credit_line_sources_without_chain TO credit_line_sources;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_line_sources_by_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_events_without_chain
+(
+    credit_line_id UInt64,
+    event_type String,
+    amount String,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32,
+    timestamp Int64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (credit_line_id, block_number, log_index)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_events_without_chain
+SELECT credit_line_id, event_type, amount, tx_hash, block_number, log_index, timestamp FROM credit_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_events TO credit_events_by_chain, credit_events_without_chain TO credit_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_events_by_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE credit_lines_without_chain
+(
+    id UInt64,
+    borrower_address String,
+    lender_address String,
+    limit String,
+    rate_bps UInt64,
+    created_at Int64,
+    expires_at Int64,
+    tx_hash String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO credit_lines_without_chain
+SELECT id, borrower_address, lender_address, limit, rate_bps, created_at, expires_at, tx_hash FROM credit_lines;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE credit_lines TO credit_lines_by_chain, credit_lines_without_chain TO credit_lines;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE credit_lines_by_chain;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE aqua_liquidity_events DROP COLUMN IF EXISTS chain_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE credit_line_snapshots DROP COLUMN IF EXISTS chain_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE bids DROP COLUMN IF EXISTS chain_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE auctions DROP COLUMN IF EXISTS chain_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE quotes DROP COLUMN IF EXISTS chain_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE rfqs DROP COLUMN IF EXISTS chain_id;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- The last block the event monitor of each chain processed, so a restarted worker resumes from it
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS monitor_checkpoints
+(
+    chain_id UInt64,
+    last_block UInt64,
+    updated_at Int64
+)
+ENGINE = ReplacingMergeTree(updated_at)
+ORDER BY chain_id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS monitor_checkpoints;
+-- +goose StatementEnd
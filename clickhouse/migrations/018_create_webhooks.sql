@@ -0,0 +1,54 @@
+-- Webhook subscriptions of integrators and the log of deliveries made to them.
+-- Both tables keep every version of a row, the one with the highest version wins.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id String,
+    chain_id UInt64,
+    address String,
+    event_types Array(String),
+    url String,
+    secret String,
+    active UInt8,
+    created_at Int64,
+    updated_at Int64,
+    version UInt64
+)
+ENGINE = ReplacingMergeTree(version)
+ORDER BY id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id String,
+    subscription_id String,
+    chain_id UInt64,
+    event_id String,
+    event_type String,
+    payload String,
+    status LowCardinality(String),
+    attempts UInt32,
+    next_attempt_at Int64,
+    last_attempt_at Int64,
+    response_status UInt16,
+    error String,
+    redelivery_of String,
+    created_at Int64,
+    updated_at Int64,
+    version UInt64
+)
+ENGINE = ReplacingMergeTree(version)
+ORDER BY id
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_subscriptions;
+-- +goose StatementEnd
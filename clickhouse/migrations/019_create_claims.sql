@@ -0,0 +1,21 @@
+-- Exclusive, expiring claims on keys, such as Idempotency-Keys being handled. KeeperMap keeps the rows in
+-- ZooKeeper, shared by every node, and with keeper_map_strict_mode an insert of a key already present
+-- fails, so of the API replicas racing for a key exactly one gets it. The nodes need
+-- <keeper_map_path_prefix> in their configuration, see config/config-*.xml.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS claims
+(
+    key String,
+    owner String,
+    value String,
+    expires_at Int64
+)
+ENGINE = KeeperMap('/pagga_data/claims')
+PRIMARY KEY key;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS claims;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- Grants of the faucet, shared by every API replica to enforce the cooldowns and daily budgets.
+-- A grant is reserved while its transaction is built, then sent or released; released ones do not count.
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS faucet_grants
+(
+    chain_id UInt64,
+    id String,
+    token LowCardinality(String),
+    address String,
+    ip String,
+    amount String,
+    tx_id String,
+    status LowCardinality(String),
+    created_at Int64,
+    version UInt64
+)
+ENGINE = ReplacingMergeTree(version)
+ORDER BY (chain_id, id)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS faucet_grants;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- The analytics views of 012 and 016 summed every row inserted into their sources, so events delivered
+-- again by the queue or replayed were counted twice, and fills were bucketed at the time they were
+-- consumed. The aggregates are computed when queried instead, from the deduplicated source tables,
+-- see repositories.AnalyticsRepository.
+
+-- +goose Up
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_volume_credit_lines_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_clearing_rates_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_lender_volume_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_rfqs_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_auctions_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_bids_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_activity_fills_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_rfq_first_quotes_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP VIEW IF EXISTS analytics_aqua_liquidity_mv;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_volume_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_clearing_rates_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_lender_volume_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_market_activity_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_rfq_first_quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS analytics_aqua_liquidity_hourly;
+-- +goose StatementEnd
+
+-- Rolling back does not bring the views back, run the analytics statements of 016 again to recreate them
+-- +goose Down
@@ -0,0 +1,290 @@
+-- The worker stores each on-chain event once however often it is delivered: a message redelivered by
+-- RabbitMQ or a block range replayed with `aqua402ctl replay` writes the same row again, and the
+-- ReplacingMergeTree tables below keep one of them. Reads use FINAL.
+-- RFQs and auctions are identified by their ID and creation time, as the API stores an RFQ it submits
+-- before the worker stores it again once seen on-chain. Quotes, bids and liquidity events are identified
+-- by the log that emitted them; quotes and bids stored before this migration have no log and keep their
+-- own row each, under a tx_hash of "legacy:<id>".
+-- Stop the worker while this runs, rows written during the rebuild are not copied.
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE rfqs_replacing
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    borrower_address String,
+    amount String,
+    duration UInt64,
+    collateral_type UInt8,
+    flow_description String,
+    status String,
+    created_at Int64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, id, created_at)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO rfqs_replacing (chain_id, id, borrower_address, amount, duration, collateral_type, flow_description, status, created_at)
+SELECT chain_id, id, borrower_address, amount, duration, collateral_type, flow_description, status, created_at FROM rfqs;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE rfqs TO rfqs_appended, rfqs_replacing TO rfqs;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE rfqs_appended;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE auctions_replacing
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    borrower_address String,
+    amount String,
+    duration UInt64,
+    end_time Int64,
+    status String,
+    created_at Int64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, id, created_at)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO auctions_replacing (chain_id, id, borrower_address, amount, duration, end_time, status, created_at)
+SELECT chain_id, id, borrower_address, amount, duration, end_time, status, created_at FROM auctions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE auctions TO auctions_appended, auctions_replacing TO auctions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE auctions_appended;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE quotes_replacing
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    rfq_id UInt64,
+    lender_address String,
+    rate_bps UInt16,
+    limit String,
+    collateral_required String,
+    submitted_at Int64,
+    accepted UInt8,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, rfq_id, tx_hash, log_index)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO quotes_replacing (chain_id, id, rfq_id, lender_address, rate_bps, "limit", collateral_required, submitted_at, accepted, tx_hash)
+SELECT chain_id, id, rfq_id, lender_address, rate_bps, "limit", collateral_required, submitted_at, accepted, concat('legacy:', toString(id)) FROM quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE quotes TO quotes_appended, quotes_replacing TO quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE quotes_appended;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE bids_replacing
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    auction_id UInt64,
+    lender_address String,
+    rate_bps UInt16,
+    limit String,
+    timestamp Int64,
+    is_winning UInt8,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, auction_id, tx_hash, log_index)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO bids_replacing (chain_id, id, auction_id, lender_address, rate_bps, "limit", timestamp, is_winning, tx_hash)
+SELECT chain_id, id, auction_id, lender_address, rate_bps, "limit", timestamp, is_winning, concat('legacy:', toString(id)) FROM bids;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE bids TO bids_appended, bids_replacing TO bids;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE bids_appended;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE aqua_liquidity_events_replacing
+(
+    chain_id UInt64 DEFAULT 1337,
+    lender_address String,
+    event_type String,
+    amount String,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32,
+    timestamp Int64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (chain_id, tx_hash, log_index)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO aqua_liquidity_events_replacing (chain_id, lender_address, event_type, amount, tx_hash, block_number, log_index, timestamp)
+SELECT chain_id, lender_address, event_type, amount, tx_hash, block_number, log_index, timestamp FROM aqua_liquidity_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE aqua_liquidity_events TO aqua_liquidity_events_appended, aqua_liquidity_events_replacing TO aqua_liquidity_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE aqua_liquidity_events_appended;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE aqua_liquidity_events_appended
+(
+    chain_id UInt64 DEFAULT 1337,
+    lender_address String,
+    event_type String,
+    amount String,
+    tx_hash String,
+    block_number UInt64,
+    log_index UInt32,
+    timestamp Int64
+)
+ENGINE = MergeTree()
+ORDER BY (timestamp, lender_address)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO aqua_liquidity_events_appended (chain_id, lender_address, event_type, amount, tx_hash, block_number, log_index, timestamp)
+SELECT chain_id, lender_address, event_type, amount, tx_hash, block_number, log_index, timestamp FROM aqua_liquidity_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE aqua_liquidity_events TO aqua_liquidity_events_replacing, aqua_liquidity_events_appended TO aqua_liquidity_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE aqua_liquidity_events_replacing;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE bids_appended
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    auction_id UInt64,
+    lender_address String,
+    rate_bps UInt16,
+    limit String,
+    timestamp Int64,
+    is_winning UInt8
+)
+ENGINE = MergeTree()
+ORDER BY (auction_id, timestamp)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO bids_appended (chain_id, id, auction_id, lender_address, rate_bps, "limit", timestamp, is_winning)
+SELECT chain_id, id, auction_id, lender_address, rate_bps, "limit", timestamp, is_winning FROM bids;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE bids TO bids_replacing, bids_appended TO bids;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE bids_replacing;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE quotes_appended
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    rfq_id UInt64,
+    lender_address String,
+    rate_bps UInt16,
+    limit String,
+    collateral_required String,
+    submitted_at Int64,
+    accepted UInt8
+)
+ENGINE = MergeTree()
+ORDER BY (rfq_id, submitted_at)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO quotes_appended (chain_id, id, rfq_id, lender_address, rate_bps, "limit", collateral_required, submitted_at, accepted)
+SELECT chain_id, id, rfq_id, lender_address, rate_bps, "limit", collateral_required, submitted_at, accepted FROM quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE quotes TO quotes_replacing, quotes_appended TO quotes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE quotes_replacing;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE auctions_appended
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    borrower_address String,
+    amount String,
+    duration UInt64,
+    end_time Int64,
+    status String,
+    created_at Int64
+)
+ENGINE = MergeTree()
+ORDER BY (id, created_at)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO auctions_appended (chain_id, id, borrower_address, amount, duration, end_time, status, created_at)
+SELECT chain_id, id, borrower_address, amount, duration, end_time, status, created_at FROM auctions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE auctions TO auctions_replacing, auctions_appended TO auctions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE auctions_replacing;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE rfqs_appended
+(
+    chain_id UInt64 DEFAULT 1337,
+    id UInt64,
+    borrower_address String,
+    amount String,
+    duration UInt64,
+    collateral_type UInt8,
+    flow_description String,
+    status String,
+    created_at Int64
+)
+ENGINE = MergeTree()
+ORDER BY (id, created_at)
+SETTINGS index_granularity = 8192;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO rfqs_appended (chain_id, id, borrower_address, amount, duration, collateral_type, flow_description, status, created_at)
+SELECT chain_id, id, borrower_address, amount, duration, collateral_type, flow_description, status, created_at FROM rfqs;
+-- +goose StatementEnd
+-- +goose StatementBegin
+RENAME TABLE rfqs TO rfqs_replacing, rfqs_appended TO rfqs;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE rfqs_replacing;
+-- +goose StatementEnd
@@ -17,6 +17,17 @@ interface IX402Credit {
         bool active;
     }
 
+    event CreditLineOpened(
+        uint256 indexed creditLineId,
+        address indexed borrower,
+        address indexed lender,
+        uint256 limit,
+        uint256 rateBps,
+        uint256 expiresAt
+    );
+    event CreditDrawn(uint256 indexed creditLineId, uint256 amount);
+    event CreditRepaid(uint256 indexed creditLineId, uint256 amount);
+
     function openCreditLine(
         address borrower,
         address lender,
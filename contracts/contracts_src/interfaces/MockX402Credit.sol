@@ -29,6 +29,7 @@ contract MockX402Credit is IX402Credit {
             expiresAt: expiresAt,
             active: true
         });
+        emit CreditLineOpened(creditLineId, borrower, lender, limit, rateBps, expiresAt);
         return creditLineId;
     }
 
@@ -39,12 +40,14 @@ contract MockX402Credit is IX402Credit {
             "Exceeds limit"
         );
         creditLines[creditLineId].drawn += amount;
+        emit CreditDrawn(creditLineId, amount);
     }
 
     function repay(uint256 creditLineId, uint256 amount) external override {
         require(creditLines[creditLineId].active, "Credit line not active");
         require(creditLines[creditLineId].drawn >= amount, "Repay exceeds drawn");
         creditLines[creditLineId].drawn -= amount;
+        emit CreditRepaid(creditLineId, amount);
     }
 
     function getCreditLine(
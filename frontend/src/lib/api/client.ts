@@ -23,9 +23,14 @@ export class APIClient {
       // Try to extract error message from response
       let errorMessage = `API error: ${response.statusText}`
       try {
-        const errorData = await response.json()
-        if (errorData.error) {
-          errorMessage = errorData.error
+        // Errors are RFC 7807 problem details, validation errors list the rejected fields
+        const problem = await response.json()
+        if (problem.errors?.length) {
+          errorMessage = problem.errors
+            .map((e: { field: string; message: string }) => `${e.field} ${e.message}`)
+            .join(', ')
+        } else if (problem.detail || problem.title) {
+          errorMessage = problem.detail || problem.title
         }
       } catch {
         // If response is not JSON, use status text
@@ -138,9 +138,9 @@ export const Faucet = () => {
         }),
       })
 
-      // Check if response is JSON
+      // Check if response is JSON (errors are application/problem+json)
       const contentType = response.headers.get('content-type')
-      if (!contentType || !contentType.includes('application/json')) {
+      if (!contentType || !contentType.includes('json')) {
         const text = await response.text()
         throw new Error(`Server returned ${response.status}: ${text.substring(0, 100)}`)
       }
@@ -148,7 +148,8 @@ export const Faucet = () => {
       const data = await response.json()
 
       if (!response.ok) {
-        throw new Error(data.error || `Server error: ${response.status}`)
+        // 429 is a cooldown or the daily budget, detail says how long to wait
+        throw new Error(data.detail || data.title || `Server error: ${response.status}`)
       }
 
       const receivedTxHash = data.tx_hash || data.txHash
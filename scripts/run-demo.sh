@@ -166,12 +166,14 @@ deploy_contracts() {
     AUCTION_ADDRESS=$(echo "$DEPLOY_OUTPUT" | grep "Auction deployed to:" | awk '{print $4}')
     AQUA_ADDRESS=$(echo "$DEPLOY_OUTPUT" | grep "AquaIntegration deployed to:" | awk '{print $4}')
     AGENT_FINANCE_ADDRESS=$(echo "$DEPLOY_OUTPUT" | grep "AgentFinance deployed to:" | awk '{print $4}')
+    X402_CREDIT_ADDRESS=$(echo "$DEPLOY_OUTPUT" | grep "MockX402Credit deployed to:" | awk '{print $4}')
     
     echo -e "${GREEN}Contracts deployed:${NC}"
     echo "  RFQ: $RFQ_ADDRESS"
     echo "  Auction: $AUCTION_ADDRESS"
     echo "  Aqua: $AQUA_ADDRESS"
     echo "  AgentFinance: $AGENT_FINANCE_ADDRESS"
+    echo "  x402Credit: $X402_CREDIT_ADDRESS"
     
     # Save addresses to .env.demo
     cd "$PROJECT_ROOT"
@@ -183,6 +185,9 @@ VITE_AGENT_FINANCE_ADDRESS=$AGENT_FINANCE_ADDRESS
 VITE_API_URL=https://aquax402.pagga.io/api/v1
 RFQ_CONTRACT_ADDRESS=$RFQ_ADDRESS
 AUCTION_CONTRACT_ADDRESS=$AUCTION_ADDRESS
+X402_CREDIT_CONTRACT_ADDRESS=$X402_CREDIT_ADDRESS
+AQUA_CONTRACT_ADDRESS=$AQUA_ADDRESS
+AGENT_FINANCE_CONTRACT_ADDRESS=$AGENT_FINANCE_ADDRESS
 EOF
     
     echo -e "${GREEN}Contract addresses saved to .env.demo${NC}"